@@ -29,6 +29,12 @@ var (
 	ErrResourceNotFound = errors.New("Resource not found in Alert")
 	// ErrSubscribedRaise raised if unable to raise a subscribed alert
 	ErrSubscribedRaise = errors.New("Could not raise alert and its subscribed alerts")
+	// ErrSinkNotFound raised if an alert sink does not exist.
+	ErrSinkNotFound = errors.New("Alert sink not found")
+	// ErrSilenceNotFound raised if an alert silence does not exist.
+	ErrSilenceNotFound = errors.New("Alert silence not found")
+	// ErrInvalidToken raised if a continuation token is not valid.
+	ErrInvalidToken = errors.New("Invalid continuation token")
 
 	instances = make(map[string]Alert)
 	drivers   = make(map[string]InitFunc)
@@ -75,6 +81,9 @@ type Alert interface {
 		resourceType api.ResourceType,
 	) ([]*api.Alert, error)
 
+	// EnumerateWithFilter returns a page of the alerts that match filter.
+	EnumerateWithFilter(filter *api.AlertFilter) (*api.AlertList, error)
+
 	// Erase erases an Alert.
 	Erase(resourceType api.ResourceType, alertID int64) error
 
@@ -93,6 +102,34 @@ type Alert interface {
 	// options provided while creating the alertClient object to access this
 	// cluster
 	Watch(clusterID string, alertWatcher AlertWatcherFunc) error
+
+	// Acknowledge records that user has taken note of an Alert.
+	Acknowledge(
+		resourceType api.ResourceType,
+		alertID int64,
+		user string,
+		comment string,
+	) error
+
+	// EnumerateSilences enumerates the silences that have not expired.
+	EnumerateSilences() ([]*api.AlertSilence, error)
+
+	// PutSilence creates or replaces the silence with the name of silence.
+	// Alerts that match an active silence are stored but are not sent to
+	// watchers.
+	PutSilence(silence *api.AlertSilence) error
+
+	// DeleteSilence deletes a silence.
+	DeleteSilence(name string) error
+
+	// EnumerateSinks enumerates the sinks alert events are sent to.
+	EnumerateSinks() ([]*api.AlertSink, error)
+
+	// PutSink creates or replaces the sink with the name of sink.
+	PutSink(sink *api.AlertSink) error
+
+	// DeleteSink deletes a sink.
+	DeleteSink(name string) error
 }
 
 // Shutdown the alert instance.
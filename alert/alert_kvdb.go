@@ -7,6 +7,8 @@ import (
 	"github.com/libopenstorage/openstorage/pkg/proto/time"
 	"github.com/portworx/kvdb"
 	"go.pedge.io/dlog"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,6 +24,12 @@ const (
 	alertKey         = "alert/"
 	subscriptionsKey = "subscriptions"
 	nextAlertIDKey   = "nextAlertId"
+	sinksKey         = "sinks/"
+	silencesKey      = "silences/"
+	notifiedKey      = "notified/"
+	resourcesKey     = "resources/"
+	idsKey           = "ids/"
+	indexedKey       = "indexed"
 	clusterKey       = "cluster/"
 	volumeKey        = "volume/"
 	nodeKey          = "node/"
@@ -38,6 +46,26 @@ const (
 )
 
 var (
+	// ignoredKeys are the keys under alertKey that do not hold alerts.
+	ignoredKeys = []string{
+		nextAlertIDKey,
+		subscriptionsKey,
+		sinksKey,
+		silencesKey,
+		notifiedKey,
+		resourcesKey,
+		idsKey,
+		indexedKey,
+	}
+
+	// resourceTypes are the resource types alerts are raised on.
+	resourceTypes = []api.ResourceType{
+		api.ResourceType_RESOURCE_TYPE_NODE,
+		api.ResourceType_RESOURCE_TYPE_VOLUME,
+		api.ResourceType_RESOURCE_TYPE_CLUSTER,
+		api.ResourceType_RESOURCE_TYPE_DRIVE,
+	}
+
 	kvdbMap     = make(map[string]kvdb.Kvdb)
 	watcherMap  = make(map[string]*watcher)
 	watchErrors int
@@ -80,11 +108,15 @@ func (kva *KvAlert) GetKvdbInstance() kvdb.Kvdb {
 // Init initializes a AlertClient interface implementation.
 func Init(kv kvdb.Kvdb, clusterID string) (Alert, error) {
 	kvdbLock.Lock()
-	defer kvdbLock.Unlock()
 	if _, ok := kvdbMap[clusterID]; !ok {
 		kvdbMap[clusterID] = kv
 	}
-	return &KvAlert{clusterID}, nil
+	kvdbLock.Unlock()
+	kva := &KvAlert{clusterID}
+	if err := kva.index(); err != nil {
+		dlog.Warnf("Failed to index alerts by resource: %v", err)
+	}
+	return kva, nil
 }
 
 // Raise raises an Alert.
@@ -147,8 +179,16 @@ func (kva *KvAlert) Erase(resourceType api.ResourceType, alertID int64) error {
 	if resourceType == api.ResourceType_RESOURCE_TYPE_NONE {
 		return ErrResourceNotFound
 	}
-	_, err := kv.Delete(getResourceKey(resourceType) + strconv.FormatInt(alertID, 10))
-	return err
+	kvp, err := kv.Delete(getResourceKey(resourceType) + strconv.FormatInt(alertID, 10))
+	if err != nil {
+		return err
+	}
+	kv.Delete(getIDKey(resourceType) + strconv.FormatInt(alertID, 10))
+	var alert api.Alert
+	if err := json.Unmarshal(kvp.Value, &alert); err == nil && alert.ResourceId != "" {
+		kv.Delete(getResourceIDKey(resourceType, alert.ResourceId) + strconv.FormatInt(alertID, 10))
+	}
+	return nil
 }
 
 // Clear clears an alert.
@@ -173,6 +213,112 @@ func (kva *KvAlert) Enumerate(filter *api.Alert) ([]*api.Alert, error) {
 	return kva.enumerate(kv, filter)
 }
 
+// EnumerateWithFilter returns a page of the alerts that match filter. Only
+// the alerts of the resource type of the filter are looked at, and only
+// those of its resource ID if it is set. In ID order, the default, the IDs
+// of these alerts are enumerated from their index and only the alerts up to
+// the end of the page are read. Other orders read all of these alerts, so
+// they are O(n) in their number whatever the limit and the other criteria
+// of the filter.
+func (kva *KvAlert) EnumerateWithFilter(filter *api.AlertFilter) (*api.AlertList, error) {
+	if filter == nil {
+		filter = &api.AlertFilter{}
+	}
+	resources := resourceTypes
+	if filter.Resource != api.ResourceType_RESOURCE_TYPE_NONE {
+		resources = []api.ResourceType{filter.Resource}
+	}
+	kv := kva.GetKvdbInstance()
+	if sortBy, err := api.AlertSortValueOf(string(filter.SortBy)); err == nil &&
+		sortBy == api.AlertSortID {
+		return kva.listByIndex(kv, resources, filter)
+	}
+
+	var alerts []*api.Alert
+	for _, resourceType := range resources {
+		var (
+			resourceAlerts []*api.Alert
+			err            error
+		)
+		if filter.ResourceId != "" {
+			resourceAlerts, err = kva.getResourceIDAlerts(resourceType, filter.ResourceId, kv)
+		} else {
+			resourceAlerts, err = kva.getResourceSpecificAlerts(resourceType, kv)
+		}
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, resourceAlerts...)
+	}
+	return List(alerts, filter)
+}
+
+// listByIndex returns the page of the alerts of resources that match filter
+// in ID order. The IDs are enumerated from the index of the alerts by
+// resource type, or by resource ID if filter has one, whose entries are
+// empty, and only the alerts up to the end of the page are read.
+func (kva *KvAlert) listByIndex(
+	kv kvdb.Kvdb,
+	resources []api.ResourceType,
+	filter *api.AlertFilter,
+) (*api.AlertList, error) {
+	after, err := decodeToken(filter.Token, api.AlertSortID, filter.Descending)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := &alertPositions{descending: filter.Descending}
+	resourceOf := make(map[int64]api.ResourceType)
+	indexKeys := make(map[int64]string)
+	for _, resourceType := range resources {
+		prefix := getIDKey(resourceType)
+		if filter.ResourceId != "" {
+			prefix = getResourceIDKey(resourceType, filter.ResourceId)
+		}
+		kvp, err := kv.Enumerate(prefix)
+		if err != nil && err != kvdb.ErrNotFound {
+			return nil, err
+		}
+		for _, v := range kvp {
+			id, err := strconv.ParseInt(v.Key[strings.LastIndex(v.Key, "/")+1:], 10, 64)
+			if err != nil {
+				continue
+			}
+			p := alertPosition{Value: id, ID: id}
+			if after != nil && !after.before(&p, filter.Descending) {
+				continue
+			}
+			positions.list = append(positions.list, p)
+			resourceOf[id] = resourceType
+			indexKeys[id] = v.Key
+		}
+	}
+	sort.Sort(positions)
+
+	list := &api.AlertList{Alerts: make([]*api.Alert, 0)}
+	for i, p := range positions.list {
+		if filter.Limit > 0 && len(list.Alerts) == filter.Limit {
+			// Continue after the last alert looked at.
+			list.NextToken = encodeToken(&positions.list[i-1], api.AlertSortID,
+				filter.Descending)
+			break
+		}
+		var alert api.Alert
+		key := getResourceKey(resourceOf[p.ID]) + strconv.FormatInt(p.ID, 10)
+		if _, err := kv.GetVal(key, &alert); err == kvdb.ErrNotFound {
+			// The alert expired or was erased.
+			kv.Delete(indexKeys[p.ID])
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if filter.Matches(&alert) {
+			list.Alerts = append(list.Alerts, &alert)
+		}
+	}
+	return list, nil
+}
+
 // EnumerateWithinTimeRange enumerates alert between timeStart and timeEnd.
 func (kva *KvAlert) EnumerateWithinTimeRange(
 	timeStart time.Time,
@@ -196,7 +342,7 @@ func (kva *KvAlert) EnumerateWithinTimeRange(
 		}
 	}
 	for _, v := range resourceAlerts {
-		alertTime := prototime.TimestampToTime(v.Timestamp)
+		alertTime := lastSeen(v)
 		if alertTime.Before(timeEnd) && alertTime.After(timeStart) {
 			allAlerts = append(allAlerts, v)
 		}
@@ -226,6 +372,99 @@ func (kva *KvAlert) Watch(clusterID string, alertWatcherFunc AlertWatcherFunc) e
 	return nil
 }
 
+// Acknowledge records that user has taken note of an alert.
+func (kva *KvAlert) Acknowledge(
+	resourceType api.ResourceType,
+	alertID int64,
+	user string,
+	comment string,
+) error {
+	if resourceType == api.ResourceType_RESOURCE_TYPE_NONE {
+		return ErrResourceNotFound
+	}
+	key := getResourceKey(resourceType) + strconv.FormatInt(alertID, 10)
+	_, err := kva.update(key, func(alert *api.Alert) bool {
+		alert.Acknowledged = true
+		alert.AckUser = user
+		alert.AckComment = comment
+		alert.AckTime = prototime.Now()
+		return true
+	})
+	return err
+}
+
+// EnumerateSilences enumerates the silences that have not expired.
+func (kva *KvAlert) EnumerateSilences() ([]*api.AlertSilence, error) {
+	return enumerateSilences(kva.GetKvdbInstance())
+}
+
+// PutSilence creates or replaces the silence with the name of silence. It
+// is removed once it expires.
+func (kva *KvAlert) PutSilence(silence *api.AlertSilence) error {
+	if err := silence.Validate(); err != nil {
+		return err
+	}
+	kv := kva.GetKvdbInstance()
+	ttl := uint64(silence.Expiry.Sub(time.Now())/time.Second) + 1
+	_, err := kv.Put(alertKey+silencesKey+silence.Name, silence, ttl)
+	return err
+}
+
+// DeleteSilence deletes a silence.
+func (kva *KvAlert) DeleteSilence(name string) error {
+	kv := kva.GetKvdbInstance()
+	if _, err := kv.Delete(alertKey + silencesKey + name); err != nil {
+		if err == kvdb.ErrNotFound {
+			return ErrSilenceNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// EnumerateSinks enumerates the sinks alert events are sent to.
+func (kva *KvAlert) EnumerateSinks() ([]*api.AlertSink, error) {
+	kv := kva.GetKvdbInstance()
+	kvp, err := kv.Enumerate(alertKey + sinksKey)
+	if err != nil {
+		if err == kvdb.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sinks := make([]*api.AlertSink, 0, len(kvp))
+	for _, v := range kvp {
+		var sink api.AlertSink
+		if err := json.Unmarshal(v.Value, &sink); err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, &sink)
+	}
+	return sinks, nil
+}
+
+// PutSink creates or replaces the sink with the name of sink.
+func (kva *KvAlert) PutSink(sink *api.AlertSink) error {
+	if err := sink.Validate(); err != nil {
+		return err
+	}
+	kv := kva.GetKvdbInstance()
+	_, err := kv.Put(alertKey+sinksKey+sink.Name, sink, 0)
+	return err
+}
+
+// DeleteSink deletes a sink.
+func (kva *KvAlert) DeleteSink(name string) error {
+	kv := kva.GetKvdbInstance()
+	if _, err := kv.Delete(alertKey + sinksKey + name); err != nil {
+		if err == kvdb.ErrNotFound {
+			return ErrSinkNotFound
+		}
+		return err
+	}
+	return nil
+}
+
 // Shutdown shutdown
 func (kva *KvAlert) Shutdown() {
 }
@@ -248,6 +487,20 @@ func getResourceKey(resourceType api.ResourceType) string {
 	return alertKey + driveKey
 }
 
+// getIDKey returns the prefix of the keys that index the alerts of
+// resourceType by ID.
+func getIDKey(resourceType api.ResourceType) string {
+	return alertKey + idsKey + strings.TrimPrefix(getResourceKey(resourceType), alertKey)
+}
+
+// getResourceIDKey returns the prefix of the keys that index the alerts of
+// resourceType raised on resourceID.
+func getResourceIDKey(resourceType api.ResourceType, resourceID string) string {
+	return alertKey + resourcesKey +
+		strings.TrimPrefix(getResourceKey(resourceType), alertKey) +
+		url.PathEscape(resourceID) + "/"
+}
+
 func getNextAlertIDKey() string {
 	return alertKey + nextAlertIDKey
 }
@@ -268,10 +521,22 @@ func (kva *KvAlert) raise(a *api.Alert) error {
 	// TODO(pedge): when this is changed to a pointer, we need to rethink this.
 	a.Id = alertID
 	a.Timestamp = prototime.Now()
+	a.FirstSeen = a.Timestamp
+	a.LastSeen = a.Timestamp
+	a.Count = 1
 	a.Cleared = false
 	_, err = kv.Create(getResourceKey(a.Resource)+strconv.FormatInt(a.Id, 10), a, a.Ttl)
+	if err != nil {
+		return err
+	}
+	// The index entries are added after the alert so that they are never
+	// taken for the entries of an alert that is gone.
+	_, err = kv.Put(getIDKey(a.Resource)+strconv.FormatInt(a.Id, 10), "", 0)
+	if err != nil || a.ResourceId == "" {
+		return err
+	}
+	_, err = kv.Put(getResourceIDKey(a.Resource, a.ResourceId)+strconv.FormatInt(a.Id, 10), "", 0)
 	return err
-
 }
 
 func (kva *KvAlert) raiseIfNotExist(a *api.Alert) error {
@@ -291,16 +556,15 @@ func (kva *KvAlert) raiseIfNotExist(a *api.Alert) error {
 	}
 	defer kv.Unlock(kvp)
 
-	alerts, err := kva.getResourceSpecificAlerts(a.Resource, kv)
+	alerts, err := kva.getResourceIDAlerts(a.Resource, a.ResourceId, kv)
 	if err != nil {
 		dlog.Infof("Failed to get alerts of type %s, error: %s",
 			a.Resource, err.Error())
 		return err
 	}
 	for _, alert := range alerts {
-		if alert.ResourceId == a.ResourceId && alert.UniqueTag == a.UniqueTag {
-			a.Id = alert.Id
-			return nil
+		if alert.UniqueTag == a.UniqueTag {
+			return kva.recur(a, alert.Id)
 		}
 	}
 
@@ -308,6 +572,89 @@ func (kva *KvAlert) raiseIfNotExist(a *api.Alert) error {
 	return kva.raise(a)
 }
 
+// recur records another occurrence a of the alert alertID. Occurrences
+// within the rate limit of a are dropped, and a cleared alert is raised again.
+func (kva *KvAlert) recur(a *api.Alert, alertID int64) error {
+	key := getResourceKey(a.Resource) + strconv.FormatInt(alertID, 10)
+	stored, err := kva.update(key, func(alert *api.Alert) bool {
+		now := time.Now()
+		rateLimit := time.Duration(a.RateLimit) * time.Second
+		if !alert.Cleared && now.Sub(lastSeen(alert)) < rateLimit {
+			return false
+		}
+		if alert.Count == 0 {
+			// Raised before occurrences were counted.
+			alert.Count = 1
+			alert.FirstSeen = alert.Timestamp
+		}
+		alert.Count++
+		alert.LastSeen = prototime.TimeToTimestamp(now)
+		alert.RateLimit = a.RateLimit
+		if alert.Cleared {
+			// It is a new incident that nobody has acknowledged yet.
+			alert.Cleared = false
+			alert.Acknowledged = false
+			alert.AckUser = ""
+			alert.AckComment = ""
+			alert.AckTime = nil
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	copyOccurrences(a, stored)
+	return nil
+}
+
+// update applies fn to the alert stored at key and writes it back, unless
+// fn returns false. fn is applied again if the alert changed in between.
+func (kva *KvAlert) update(key string, fn func(alert *api.Alert) bool) (*api.Alert, error) {
+	kv := kva.GetKvdbInstance()
+	for {
+		var alert api.Alert
+		kvp, err := kv.GetVal(key, &alert)
+		if err != nil {
+			return nil, err
+		}
+		if !fn(&alert) {
+			return &alert, nil
+		}
+		value, err := json.Marshal(&alert)
+		if err != nil {
+			return nil, err
+		}
+		newKvp := *kvp
+		newKvp.Value = value
+		_, err = kv.CompareAndSet(&newKvp, kvdb.KVFlags(0), kvp.Value)
+		if err == kvdb.ErrValueMismatch {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &alert, nil
+	}
+}
+
+// copyOccurrences copies the ID and occurrences of stored into a.
+func copyOccurrences(a *api.Alert, stored *api.Alert) {
+	a.Id = stored.Id
+	a.Timestamp = stored.Timestamp
+	a.Count = stored.Count
+	a.FirstSeen = stored.FirstSeen
+	a.LastSeen = stored.LastSeen
+	a.Cleared = stored.Cleared
+}
+
+// lastSeen returns when a last occurred.
+func lastSeen(a *api.Alert) time.Time {
+	if a.LastSeen != nil {
+		return prototime.TimestampToTime(a.LastSeen)
+	}
+	return prototime.TimestampToTime(a.Timestamp)
+}
+
 func (kva *KvAlert) ClearByUniqueTag(
 	resourceType api.ResourceType,
 	resourceId string,
@@ -330,14 +677,18 @@ func (kva *KvAlert) ClearByUniqueTag(
 	}
 	defer kv.Unlock(kvp)
 
-	alerts, err := kva.getResourceSpecificAlerts(resourceType, kv)
+	alerts, err := kva.getResourceIDAlerts(resourceType, resourceId, kv)
 	if err != nil {
 		dlog.Infof("Failed to get alerts of type %s, error: %s",
 			resourceType, err.Error())
 		return err
 	}
 	for _, alert := range alerts {
-		if resourceId == alert.ResourceId && uniqueTag == alert.UniqueTag {
+		if uniqueTag == alert.UniqueTag {
+			if alert.Cleared && ttl == 0 {
+				// Do not notify watchers again.
+				return nil
+			}
 			return kva.clear(resourceType, alert.Id, ttl)
 		}
 	}
@@ -397,6 +748,70 @@ func (kva *KvAlert) getResourceSpecificAlerts(resourceType api.ResourceType, kv
 	return allAlerts, nil
 }
 
+// getResourceIDAlerts returns the alerts of resourceType raised on
+// resourceID, as listed by the index of alerts by resource.
+func (kva *KvAlert) getResourceIDAlerts(
+	resourceType api.ResourceType,
+	resourceID string,
+	kv kvdb.Kvdb,
+) ([]*api.Alert, error) {
+	prefix := getResourceIDKey(resourceType, resourceID)
+	kvp, err := kv.Enumerate(prefix)
+	if err != nil {
+		return nil, err
+	}
+	alerts := make([]*api.Alert, 0, len(kvp))
+	for _, v := range kvp {
+		var alert api.Alert
+		id := v.Key[strings.LastIndex(v.Key, "/")+1:]
+		_, err := kv.GetVal(getResourceKey(resourceType)+id, &alert)
+		if err == kvdb.ErrNotFound {
+			// The alert expired or was erased.
+			kv.Delete(v.Key)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, &alert)
+	}
+	return alerts, nil
+}
+
+// index adds the alerts raised before alerts were indexed by ID and
+// resource to the indexes.
+func (kva *KvAlert) index() error {
+	kv := kva.GetKvdbInstance()
+	if kv == nil {
+		return ErrNotInitialized
+	}
+	if _, err := kv.Get(alertKey + indexedKey); err == nil {
+		return nil
+	} else if err != kvdb.ErrNotFound {
+		return err
+	}
+	for _, resourceType := range resourceTypes {
+		alerts, err := kva.getResourceSpecificAlerts(resourceType, kv)
+		if err != nil {
+			return err
+		}
+		for _, alert := range alerts {
+			id := strconv.FormatInt(alert.Id, 10)
+			if _, err := kv.Put(getIDKey(resourceType)+id, "", 0); err != nil {
+				return err
+			}
+			if alert.ResourceId == "" {
+				continue
+			}
+			key := getResourceIDKey(resourceType, alert.ResourceId) + id
+			if _, err := kv.Put(key, "", 0); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := kv.Put(alertKey+indexedKey, "", 0)
+	return err
+}
+
 func (kva *KvAlert) getAllAlerts(kv kvdb.Kvdb) ([]*api.Alert, error) {
 	allAlerts := []*api.Alert{}
 	clusterAlerts := []*api.Alert{}
@@ -457,6 +872,44 @@ func (kva *KvAlert) enumerate(kv kvdb.Kvdb, filter *api.Alert) ([]*api.Alert, er
 	return allAlerts, err
 }
 
+func enumerateSilences(kv kvdb.Kvdb) ([]*api.AlertSilence, error) {
+	kvp, err := kv.Enumerate(alertKey + silencesKey)
+	if err != nil {
+		if err == kvdb.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	now := time.Now()
+	silences := make([]*api.AlertSilence, 0, len(kvp))
+	for _, v := range kvp {
+		var silence api.AlertSilence
+		if err := json.Unmarshal(v.Value, &silence); err != nil {
+			return nil, err
+		}
+		if now.Before(silence.Expiry) {
+			silences = append(silences, &silence)
+		}
+	}
+	return silences, nil
+}
+
+// silenced returns true if a silence in kv is active and matches a.
+func silenced(kv kvdb.Kvdb, a *api.Alert) bool {
+	silences, err := enumerateSilences(kv)
+	if err != nil {
+		dlog.Warnf("Failed to enumerate alert silences: %v", err)
+		return false
+	}
+	now := time.Now()
+	for _, silence := range silences {
+		if silence.Active(now) && silence.Matches(a) {
+			return true
+		}
+	}
+	return false
+}
+
 func (kva *KvAlert) getKvdbForCluster(clusterID string) (kvdb.Kvdb, error) {
 	kvdbLock.Lock()
 	defer kvdbLock.Unlock()
@@ -489,16 +942,16 @@ func kvdbWatch(prefix string, opaque interface{}, kvp *kvdb.KVPair, err error) e
 	lock.Lock()
 	defer lock.Unlock()
 
-	watcherKey := strings.Split(prefix, "/")[1]
+	watcherKey, _ := opaque.(string)
 
 	if err != nil {
 		return processWatchError(err, watcherKey, prefix)
 	}
 
-	if strings.HasSuffix(kvp.Key, nextAlertIDKey) || strings.Contains(kvp.Key, subscriptionsKey) {
-		// Ignore write on this key
-		// Todo : Add a map of ignore keys
-		return nil
+	for _, ignored := range ignoredKeys {
+		if strings.Contains(kvp.Key, alertKey+ignored) {
+			return nil
+		}
 	}
 	w := watcherMap[watcherKey]
 	w.watchErrors = 0
@@ -512,6 +965,9 @@ func kvdbWatch(prefix string, opaque interface{}, kvp *kvdb.KVPair, err error) e
 	if err := json.Unmarshal(kvp.Value, &alert); err != nil {
 		return fmt.Errorf("Failed to unmarshal Alert")
 	}
+	if silenced(w.kvdb, &alert) {
+		return nil
+	}
 
 	switch kvp.Action {
 	case kvdb.KVCreate:
@@ -533,7 +989,7 @@ func subscribeWatch(key string) error {
 	}
 
 	kv := w.kvdb
-	if err := kv.WatchTree(alertKey, uint64(watchIndex), nil, w.kvcb); err != nil {
+	if err := kv.WatchTree(alertKey, uint64(watchIndex), key, w.kvcb); err != nil {
 		return err
 	}
 	return nil
@@ -39,6 +39,7 @@ func TestAll(t *testing.T) {
 	clear(t)
 	clearWithTTL(t)
 	enumerate(t)
+	recurrences(t)
 	watch(t)
 }
 
@@ -374,6 +375,71 @@ func enumerate(t *testing.T) {
 	require.Contains(t, err.Error(), "not found", "Expecting cleanup completed successfully")
 }
 
+func recurrences(t *testing.T) {
+	raise := func(rateLimit uint64) *api.Alert {
+		a := &api.Alert{
+			Resource:   api.ResourceType_RESOURCE_TYPE_DRIVE,
+			Severity:   api.SeverityType_SEVERITY_TYPE_WARNING,
+			ResourceId: "drive1",
+			UniqueTag:  "flapping",
+			RateLimit:  rateLimit,
+		}
+		require.NoError(t, kva.RaiseIfNotExist(a), "Failed in raising an alert")
+		return a
+	}
+
+	first := raise(0)
+	require.Equal(t, int64(1), first.Count)
+	require.Equal(t, first.FirstSeen, first.LastSeen)
+
+	second := raise(0)
+	require.Equal(t, first.Id, second.Id, "repeats are counted on the same alert")
+	require.Equal(t, int64(2), second.Count)
+	require.Equal(t, first.FirstSeen, second.FirstSeen)
+	require.False(t, prototime.TimestampToTime(second.LastSeen).Before(
+		prototime.TimestampToTime(first.LastSeen)))
+
+	// Repeats within the rate limit are dropped.
+	third := raise(60)
+	require.Equal(t, int64(2), third.Count)
+	stored, err := kva.Retrieve(api.ResourceType_RESOURCE_TYPE_DRIVE, first.Id)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), stored.Count)
+
+	// But a cleared alert is raised again.
+	require.NoError(t, kva.ClearByUniqueTag(api.ResourceType_RESOURCE_TYPE_DRIVE,
+		"drive1", "flapping", 0))
+	fourth := raise(60)
+	require.Equal(t, first.Id, fourth.Id)
+	require.Equal(t, int64(3), fourth.Count)
+	require.False(t, fourth.Cleared)
+
+	// Time ranges match when an alert was last seen.
+	kv := kva.GetKvdbInstance()
+	now := time.Now()
+	var oldAlertID int64 = 200
+	_, err = kv.Put(getResourceKey(api.ResourceType_RESOURCE_TYPE_DRIVE)+strconv.FormatInt(oldAlertID, 10),
+		&api.Alert{
+			Id:        oldAlertID,
+			Resource:  api.ResourceType_RESOURCE_TYPE_DRIVE,
+			Timestamp: prototime.TimeToTimestamp(now.Add(-2 * time.Hour)),
+			FirstSeen: prototime.TimeToTimestamp(now.Add(-2 * time.Hour)),
+			LastSeen:  prototime.TimeToTimestamp(now),
+			Count:     7,
+		}, 0)
+	require.NoError(t, err)
+	enAlerts, err := kva.EnumerateWithinTimeRange(now.Add(-10*time.Second), now.Add(time.Second),
+		api.ResourceType_RESOURCE_TYPE_DRIVE)
+	require.NoError(t, err)
+	require.Len(t, enAlerts, 2)
+
+	require.NoError(t, kva.Erase(api.ResourceType_RESOURCE_TYPE_DRIVE, first.Id))
+	require.NoError(t, kva.Erase(api.ResourceType_RESOURCE_TYPE_DRIVE, oldAlertID))
+
+	// Let the kvdb callbacks of these changes run before the watch test.
+	time.Sleep(time.Second)
+}
+
 func testAlertWatcher(alert *api.Alert, action api.AlertActionType, prefix string, key string) error {
 	// A dummy callback function
 	// Setting the global variables so that we can check them in our unit tests
@@ -448,3 +514,352 @@ func watch(t *testing.T) {
 
 	err = kva.Erase(api.ResourceType_RESOURCE_TYPE_NODE, raiseAlertNew.Id)
 }
+
+func TestAcknowledge(t *testing.T) {
+	a := newClusterAlert(t, "acknowledge")
+
+	raised := &api.Alert{
+		Resource:   api.ResourceType_RESOURCE_TYPE_NODE,
+		Severity:   api.SeverityType_SEVERITY_TYPE_ALARM,
+		ResourceId: "node1",
+		UniqueTag:  "offline",
+	}
+	require.NoError(t, a.RaiseIfNotExist(raised))
+	require.NoError(t, a.Acknowledge(raised.Resource, raised.Id, "alice", "rebooting it"))
+	require.Error(t, a.Acknowledge(raised.Resource, raised.Id+1, "alice", ""))
+
+	stored, err := a.Retrieve(raised.Resource, raised.Id)
+	require.NoError(t, err)
+	require.True(t, stored.Acknowledged)
+	require.Equal(t, "alice", stored.AckUser)
+	require.Equal(t, "rebooting it", stored.AckComment)
+	require.NotNil(t, stored.AckTime)
+
+	// A cleared alert that is raised again needs a new acknowledgement.
+	require.NoError(t, a.ClearByUniqueTag(raised.Resource, "node1", "offline", 0))
+	require.NoError(t, a.RaiseIfNotExist(raised))
+	stored, err = a.Retrieve(raised.Resource, raised.Id)
+	require.NoError(t, err)
+	require.False(t, stored.Acknowledged)
+	require.Empty(t, stored.AckUser)
+}
+
+func TestSilences(t *testing.T) {
+	a := newClusterAlert(t, "silences")
+
+	now := time.Now()
+	require.Error(t, a.PutSilence(&api.AlertSilence{Name: "expired", Expiry: now}))
+	require.NoError(t, a.PutSilence(&api.AlertSilence{
+		Name:       "maintenance",
+		Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+		ResourceId: "vol1",
+		Expiry:     now.Add(time.Hour),
+	}))
+	require.NoError(t, a.PutSilence(&api.AlertSilence{
+		Name:   "later",
+		Start:  now.Add(time.Hour),
+		Expiry: now.Add(2 * time.Hour),
+	}))
+	silences, err := a.EnumerateSilences()
+	require.NoError(t, err)
+	require.Len(t, silences, 2)
+
+	keys := make(chan string, 10)
+	require.NoError(t, a.Watch("silences",
+		func(alert *api.Alert, action api.AlertActionType, prefix string, key string) error {
+			keys <- key
+			return nil
+		}))
+
+	silent := &api.Alert{
+		Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+		Severity:   api.SeverityType_SEVERITY_TYPE_ALARM,
+		ResourceId: "vol1",
+	}
+	require.NoError(t, a.Raise(silent))
+	loud := &api.Alert{
+		Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+		Severity:   api.SeverityType_SEVERITY_TYPE_ALARM,
+		ResourceId: "vol2",
+	}
+	require.NoError(t, a.Raise(loud))
+
+	select {
+	case key := <-keys:
+		require.Equal(t, getResourceKey(loud.Resource)+strconv.FormatInt(loud.Id, 10), key)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watcher was not called")
+	}
+	select {
+	case key := <-keys:
+		t.Fatalf("Silenced alert %v was sent to the watcher", key)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Silenced alerts are still stored.
+	_, err = a.Retrieve(silent.Resource, silent.Id)
+	require.NoError(t, err)
+
+	require.NoError(t, a.DeleteSilence("maintenance"))
+	require.Equal(t, ErrSilenceNotFound, a.DeleteSilence("maintenance"))
+	require.NoError(t, a.Clear(silent.Resource, silent.Id, 0))
+	select {
+	case key := <-keys:
+		require.Equal(t, getResourceKey(silent.Resource)+strconv.FormatInt(silent.Id, 10), key)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watcher was not called once the silence was deleted")
+	}
+}
+
+func TestEnumerateWithFilter(t *testing.T) {
+	a := newClusterAlert(t, "filter")
+
+	raised := []*api.Alert{
+		{
+			Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+			Severity:   api.SeverityType_SEVERITY_TYPE_ALARM,
+			ResourceId: "vol1",
+			AlertType:  1,
+			Message:    "Volume is DOWN",
+			UniqueTag:  "down",
+		},
+		{
+			Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+			Severity:   api.SeverityType_SEVERITY_TYPE_NOTIFY,
+			ResourceId: "vol1",
+			AlertType:  2,
+			Message:    "volume resized",
+		},
+		{
+			Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+			Severity:   api.SeverityType_SEVERITY_TYPE_WARNING,
+			ResourceId: "pool/vol2",
+			AlertType:  1,
+			Message:    "volume degraded",
+		},
+		{
+			Resource:   api.ResourceType_RESOURCE_TYPE_NODE,
+			Severity:   api.SeverityType_SEVERITY_TYPE_WARNING,
+			ResourceId: "vol1",
+			AlertType:  3,
+			Message:    "node is offline",
+		},
+	}
+	for _, alert := range raised {
+		require.NoError(t, a.Raise(alert))
+	}
+	require.NoError(t, a.RaiseIfNotExist(raised[0]))
+	require.NoError(t, a.Clear(raised[1].Resource, raised[1].Id, 0))
+
+	ids := func(filter *api.AlertFilter) []int64 {
+		list, err := a.EnumerateWithFilter(filter)
+		require.NoError(t, err)
+		require.Empty(t, list.NextToken)
+		ids := make([]int64, 0)
+		for _, alert := range list.Alerts {
+			ids = append(ids, alert.Id)
+		}
+		return ids
+	}
+	cleared, active := true, false
+	require.Equal(t, []int64{raised[0].Id, raised[1].Id, raised[2].Id, raised[3].Id}, ids(nil))
+	require.Equal(t, []int64{raised[0].Id, raised[1].Id}, ids(&api.AlertFilter{
+		Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+		ResourceId: "vol1",
+	}))
+	require.Equal(t, []int64{raised[0].Id, raised[1].Id, raised[3].Id},
+		ids(&api.AlertFilter{ResourceId: "vol1"}))
+	require.Equal(t, []int64{raised[2].Id},
+		ids(&api.AlertFilter{ResourceId: "pool/vol2"}))
+	require.Equal(t, []int64{raised[0].Id, raised[2].Id, raised[3].Id},
+		ids(&api.AlertFilter{Severity: api.SeverityType_SEVERITY_TYPE_WARNING}))
+	require.Equal(t, []int64{raised[1].Id}, ids(&api.AlertFilter{Cleared: &cleared}))
+	require.Equal(t, []int64{raised[0].Id, raised[2].Id, raised[3].Id},
+		ids(&api.AlertFilter{Cleared: &active}))
+	require.Equal(t, []int64{raised[0].Id, raised[2].Id}, ids(&api.AlertFilter{AlertType: 1}))
+	require.Equal(t, []int64{raised[0].Id}, ids(&api.AlertFilter{Text: "down"}))
+	require.Empty(t, ids(&api.AlertFilter{TimeEnd: time.Now().Add(-time.Hour)}))
+	require.Equal(t, []int64{raised[0].Id, raised[3].Id, raised[2].Id, raised[1].Id},
+		ids(&api.AlertFilter{SortBy: api.AlertSortSeverity, Descending: true}))
+	require.Equal(t, []int64{raised[1].Id, raised[2].Id, raised[3].Id, raised[0].Id},
+		ids(&api.AlertFilter{SortBy: api.AlertSortSeverity}))
+	require.Equal(t, []int64{raised[0].Id, raised[3].Id, raised[2].Id, raised[1].Id},
+		ids(&api.AlertFilter{SortBy: api.AlertSortCount, Descending: true}))
+
+	// Pages continue after the last alert of the previous page.
+	filter := &api.AlertFilter{Limit: 3, Descending: true}
+	list, err := a.EnumerateWithFilter(filter)
+	require.NoError(t, err)
+	require.Len(t, list.Alerts, 3)
+	require.Equal(t, raised[3].Id, list.Alerts[0].Id)
+	require.NotEmpty(t, list.NextToken)
+	filter.Token = list.NextToken
+	list, err = a.EnumerateWithFilter(filter)
+	require.NoError(t, err)
+	require.Len(t, list.Alerts, 1)
+	require.Equal(t, raised[0].Id, list.Alerts[0].Id)
+	require.Empty(t, list.NextToken)
+
+	// A token only continues the order it was returned for.
+	filter.Descending = false
+	_, err = a.EnumerateWithFilter(filter)
+	require.Equal(t, ErrInvalidToken, err)
+	_, err = a.EnumerateWithFilter(&api.AlertFilter{Token: "garbage"})
+	require.Equal(t, ErrInvalidToken, err)
+	_, err = a.EnumerateWithFilter(&api.AlertFilter{SortBy: "color"})
+	require.Error(t, err)
+
+	// Erased alerts are gone from the index.
+	require.NoError(t, a.Erase(raised[0].Resource, raised[0].Id))
+	require.Equal(t, []int64{raised[1].Id, raised[3].Id},
+		ids(&api.AlertFilter{ResourceId: "vol1"}))
+	kvp, err := a.GetKvdbInstance().Enumerate(getResourceIDKey(raised[0].Resource, "vol1"))
+	require.NoError(t, err)
+	require.Len(t, kvp, 1)
+}
+
+func TestIndex(t *testing.T) {
+	kv, err := kvdb.New(mem.Name, kvdbDomain+"/index", []string{}, nil, dlog.Panicf)
+	require.NoError(t, err)
+	// An alert stored before alerts were indexed by resource.
+	old := &api.Alert{
+		Id:         1,
+		Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+		ResourceId: "vol1",
+		UniqueTag:  "down",
+	}
+	_, err = kv.Put(getResourceKey(old.Resource)+"1", old, 0)
+	require.NoError(t, err)
+
+	a, err := New(Name, "index", kv)
+	require.NoError(t, err)
+	list, err := a.EnumerateWithFilter(&api.AlertFilter{ResourceId: "vol1"})
+	require.NoError(t, err)
+	require.Len(t, list.Alerts, 1)
+
+	// It is found again instead of raising another alert.
+	again := *old
+	again.Id = 0
+	require.NoError(t, a.RaiseIfNotExist(&again))
+	require.Equal(t, old.Id, again.Id)
+}
+
+func TestRaiseType(t *testing.T) {
+	a := newClusterAlert(t, "types")
+
+	require.NoError(t, RaiseType(a, TypeVolumeDown, "vol1", "Volume vol1 is down"))
+	// Another node sees the volume go down as well.
+	require.NoError(t, RaiseType(a, TypeVolumeDown, "vol1", "Volume vol1 is down"))
+	alerts, err := a.Enumerate(&api.Alert{Resource: api.ResourceType_RESOURCE_TYPE_VOLUME})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	raised := alerts[0]
+	require.Equal(t, TypeVolumeDown, raised.AlertType)
+	require.Equal(t, api.SeverityType_SEVERITY_TYPE_ALARM, raised.Severity)
+	require.Equal(t, "volume_down", raised.UniqueTag)
+	require.Equal(t, int64(1), raised.Count)
+	require.False(t, raised.Cleared)
+
+	require.NoError(t, ClearType(a, TypeVolumeDown, "vol1"))
+	cleared, err := a.Retrieve(raised.Resource, raised.Id)
+	require.NoError(t, err)
+	require.True(t, cleared.Cleared)
+
+	// Clearing it again does not touch it.
+	key := getResourceKey(raised.Resource) + strconv.FormatInt(raised.Id, 10)
+	kvp, err := a.GetKvdbInstance().Get(key)
+	require.NoError(t, err)
+	require.NoError(t, ClearType(a, TypeVolumeDown, "vol1"))
+	again, err := a.GetKvdbInstance().Get(key)
+	require.NoError(t, err)
+	require.Equal(t, kvp.ModifiedIndex, again.ModifiedIndex)
+
+	// There is nothing to clear on a resource without alerts.
+	require.NoError(t, ClearType(a, TypeNodeOffline, "node1"))
+	require.Equal(t, ErrIllegal, RaiseType(a, 0, "vol1", ""))
+	require.Equal(t, ErrIllegal, ClearType(a, 0, "vol1"))
+}
+
+func TestEnumerateWithFilterIndex(t *testing.T) {
+	kv, err := kvdb.New(mem.Name, kvdbDomain+"/ids", []string{}, nil, dlog.Panicf)
+	require.NoError(t, err)
+	a, err := New(Name, "ids", kv)
+	require.NoError(t, err)
+
+	var raised []*api.Alert
+	for i := 0; i < 5; i++ {
+		alert := &api.Alert{
+			Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+			Severity:   api.SeverityType_SEVERITY_TYPE_WARNING,
+			ResourceId: "vol" + strconv.Itoa(i%2),
+			AlertType:  int64(i),
+		}
+		require.NoError(t, a.Raise(alert))
+		raised = append(raised, alert)
+	}
+	require.NoError(t, a.Raise(&api.Alert{
+		Resource:   api.ResourceType_RESOURCE_TYPE_NODE,
+		Severity:   api.SeverityType_SEVERITY_TYPE_ALARM,
+		ResourceId: "node0",
+	}))
+
+	// Other criteria are matched on the alerts read.
+	list, err := a.EnumerateWithFilter(&api.AlertFilter{AlertType: 3})
+	require.NoError(t, err)
+	require.Len(t, list.Alerts, 1)
+	require.Equal(t, raised[3].Id, list.Alerts[0].Id)
+
+	// Other orders read all the alerts.
+	list, err = a.EnumerateWithFilter(&api.AlertFilter{
+		SortBy:     api.AlertSortSeverity,
+		Descending: true,
+		Limit:      1,
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Alerts, 1)
+	require.Equal(t, api.ResourceType_RESOURCE_TYPE_NODE, list.Alerts[0].Resource)
+
+	// Only the alerts up to the end of the page are read, so an alert after
+	// the page which cannot be read does not fail it.
+	last := getResourceKey(raised[4].Resource) + strconv.FormatInt(raised[4].Id, 10)
+	_, err = kv.Put(last, "not an alert", 0)
+	require.NoError(t, err)
+	filter := &api.AlertFilter{Resource: api.ResourceType_RESOURCE_TYPE_VOLUME, Limit: 2}
+	list, err = a.EnumerateWithFilter(filter)
+	require.NoError(t, err)
+	require.Len(t, list.Alerts, 2)
+	require.Equal(t, raised[0].Id, list.Alerts[0].Id)
+	require.Equal(t, raised[1].Id, list.Alerts[1].Id)
+	require.NotEmpty(t, list.NextToken)
+
+	filter.Token = list.NextToken
+	list, err = a.EnumerateWithFilter(filter)
+	require.NoError(t, err)
+	require.Len(t, list.Alerts, 2)
+	require.Equal(t, raised[2].Id, list.Alerts[0].Id)
+	require.Equal(t, raised[3].Id, list.Alerts[1].Id)
+
+	filter.Token = list.NextToken
+	_, err = a.EnumerateWithFilter(filter)
+	require.Error(t, err)
+
+	// The index of a resource ID is paged in the same way.
+	list, err = a.EnumerateWithFilter(&api.AlertFilter{
+		Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+		ResourceId: "vol1",
+		Descending: true,
+		Limit:      1,
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Alerts, 1)
+	require.Equal(t, raised[3].Id, list.Alerts[0].Id)
+
+	// The index entries of alerts that expired are removed when read.
+	_, err = kv.Delete(last)
+	require.NoError(t, err)
+	list, err = a.EnumerateWithFilter(&api.AlertFilter{Resource: api.ResourceType_RESOURCE_TYPE_VOLUME})
+	require.NoError(t, err)
+	require.Len(t, list.Alerts, 4)
+	_, err = kv.Get(getIDKey(raised[4].Resource) + strconv.FormatInt(raised[4].Id, 10))
+	require.Equal(t, kvdb.ErrNotFound, err)
+}
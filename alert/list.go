@@ -0,0 +1,149 @@
+package alert
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+
+	"github.com/libopenstorage/openstorage/api"
+	prototime "github.com/libopenstorage/openstorage/pkg/proto/time"
+)
+
+// List returns the page of the alerts that filter selects, in the order of
+// the filter.
+func List(alerts []*api.Alert, filter *api.AlertFilter) (*api.AlertList, error) {
+	if filter == nil {
+		filter = &api.AlertFilter{}
+	}
+	sortBy, err := api.AlertSortValueOf(string(filter.SortBy))
+	if err != nil {
+		return nil, err
+	}
+	after, err := decodeToken(filter.Token, sortBy, filter.Descending)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := &alertPositions{
+		list:       make([]alertPosition, 0, len(alerts)),
+		descending: filter.Descending,
+	}
+	for _, a := range alerts {
+		if !filter.Matches(a) {
+			continue
+		}
+		p := alertPosition{Value: sortValue(a, sortBy), ID: a.Id, alert: a}
+		if after != nil && !after.before(&p, filter.Descending) {
+			continue
+		}
+		positions.list = append(positions.list, p)
+	}
+	sort.Sort(positions)
+
+	list := &api.AlertList{Alerts: make([]*api.Alert, 0)}
+	for i := range positions.list {
+		if filter.Limit > 0 && len(list.Alerts) == filter.Limit {
+			last := positions.list[i-1]
+			list.NextToken = encodeToken(&last, sortBy, filter.Descending)
+			break
+		}
+		list.Alerts = append(list.Alerts, positions.list[i].alert)
+	}
+	return list, nil
+}
+
+// alertPosition is where an alert is in the order of a filter. Alerts with
+// the same sort value are ordered by ID.
+type alertPosition struct {
+	Value int64
+	ID    int64
+	alert *api.Alert
+}
+
+// before returns true if p comes before q.
+func (p *alertPosition) before(q *alertPosition, descending bool) bool {
+	if p.Value == q.Value {
+		if descending {
+			return p.ID > q.ID
+		}
+		return p.ID < q.ID
+	}
+	if descending {
+		return p.Value > q.Value
+	}
+	return p.Value < q.Value
+}
+
+type alertPositions struct {
+	list       []alertPosition
+	descending bool
+}
+
+func (p *alertPositions) Len() int      { return len(p.list) }
+func (p *alertPositions) Swap(i, j int) { p.list[i], p.list[j] = p.list[j], p.list[i] }
+func (p *alertPositions) Less(i, j int) bool {
+	return p.list[i].before(&p.list[j], p.descending)
+}
+
+// sortValue returns the value a is ordered by.
+func sortValue(a *api.Alert, sortBy api.AlertSort) int64 {
+	switch sortBy {
+	case api.AlertSortLastSeen:
+		return lastSeen(a).UnixNano()
+	case api.AlertSortFirstSeen:
+		if a.FirstSeen != nil {
+			return prototime.TimestampToTime(a.FirstSeen).UnixNano()
+		}
+		return prototime.TimestampToTime(a.Timestamp).UnixNano()
+	case api.AlertSortSeverity:
+		if a.Severity == api.SeverityType_SEVERITY_TYPE_NONE {
+			return 0
+		}
+		// The most severe type has the highest value.
+		return int64(api.SeverityType_SEVERITY_TYPE_NOTIFY-a.Severity) + 1
+	case api.AlertSortCount:
+		if a.Count == 0 {
+			// Raised before occurrences were counted.
+			return 1
+		}
+		return a.Count
+	}
+	return a.Id
+}
+
+// token is the position of the last alert of a page along with the order it
+// is in, so that it is not used to continue in another order.
+type token struct {
+	SortBy     api.AlertSort `json:"s"`
+	Descending bool          `json:"d,omitempty"`
+	Value      int64         `json:"v"`
+	ID         int64         `json:"i"`
+}
+
+// encodeToken returns the continuation token for the alerts after p.
+func encodeToken(p *alertPosition, sortBy api.AlertSort, descending bool) string {
+	b, _ := json.Marshal(&token{
+		SortBy:     sortBy,
+		Descending: descending,
+		Value:      p.Value,
+		ID:         p.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeToken returns the position in s, or nil for an empty token.
+func decodeToken(s string, sortBy api.AlertSort, descending bool) (*alertPosition, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var t token
+	if err := json.Unmarshal(b, &t); err != nil ||
+		t.SortBy != sortBy || t.Descending != descending {
+		return nil, ErrInvalidToken
+	}
+	return &alertPosition{Value: t.Value, ID: t.ID}, nil
+}
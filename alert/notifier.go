@@ -0,0 +1,177 @@
+package alert
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/portworx/kvdb"
+	"go.pedge.io/dlog"
+)
+
+const (
+	// notifiedTTL is how long, in seconds, the claim on sending an event to
+	// a sink is kept before the event is sent. The claim is kept for as long
+	// again as sending may take.
+	notifiedTTL = 3600
+	// sinkQueueSize is how many events may wait to be sent to a sink.
+	sinkQueueSize = 64
+)
+
+// Notifier sends the alert events of a cluster to the sinks stored with its
+// alerts. Every node of the cluster may run a notifier; an event is sent to
+// a sink by the first notifier that claims it in kvdb. Events are sent to a
+// sink one at a time, in order.
+type Notifier struct {
+	alert     Alert
+	clusterID string
+	lock      sync.Mutex
+	queues    map[string]chan *delivery
+}
+
+// delivery is an event waiting to be sent to a sink.
+type delivery struct {
+	event  *Event
+	sender sender
+	// expires is when the claim on the event expires.
+	expires time.Time
+}
+
+// NewNotifier returns a notifier for the alerts of clusterID in a.
+func NewNotifier(a Alert, clusterID string) *Notifier {
+	return &Notifier{
+		alert:     a,
+		clusterID: clusterID,
+		queues:    make(map[string]chan *delivery),
+	}
+}
+
+// Start watches the alerts of the cluster and sends their events.
+func (n *Notifier) Start() error {
+	return n.alert.Watch(n.clusterID, n.notify)
+}
+
+func (n *Notifier) notify(
+	a *api.Alert,
+	action api.AlertActionType,
+	prefix string,
+	key string,
+) error {
+	event := newEvent(n.clusterID, a, action)
+	if event == nil {
+		return nil
+	}
+	sinks, err := n.alert.EnumerateSinks()
+	if err != nil {
+		// Failing the watch would stop it, skip this event instead.
+		dlog.Warnf("Failed to enumerate alert sinks: %v", err)
+		return nil
+	}
+	for _, sink := range sinks {
+		if !sink.Matches(a) {
+			continue
+		}
+		s, err := newSender(sink)
+		if err != nil {
+			dlog.Warnf("Invalid alert sink %v: %v", sink.Name, err)
+			continue
+		}
+		ttl := notifiedTTL + uint64(s.window()/time.Second)
+		if !n.claim(sink.Name, event, ttl) {
+			continue
+		}
+		if !n.enqueue(sink.Name, &delivery{
+			event:   event,
+			sender:  s,
+			expires: time.Now().Add(time.Duration(ttl) * time.Second),
+		}) {
+			// Let another notifier send the event instead.
+			n.release(sink.Name, event)
+		}
+	}
+	return nil
+}
+
+// enqueue queues d to be sent to the sink. It returns false, dropping d, if
+// the queue of the sink is full.
+func (n *Notifier) enqueue(sink string, d *delivery) bool {
+	n.lock.Lock()
+	q, ok := n.queues[sink]
+	if !ok {
+		q = make(chan *delivery, sinkQueueSize)
+		n.queues[sink] = q
+		go n.run(sink, q)
+	}
+	n.lock.Unlock()
+
+	select {
+	case q <- d:
+		return true
+	default:
+		dlog.Warnf("Alert queue of sink %v is full, dropping alert %v",
+			sink, d.event.Alert.Id)
+		return false
+	}
+}
+
+// run sends the events queued for the sink. Events that could still be
+// sending when their claim expires are dropped, since another notifier
+// could then claim them again.
+func (n *Notifier) run(sink string, q chan *delivery) {
+	for d := range q {
+		if time.Now().Add(d.sender.window()).After(d.expires) {
+			dlog.Warnf("Claim on alert %v for sink %v expired, dropping it",
+				d.event.Alert.Id, sink)
+			continue
+		}
+		if err := d.sender.send(d.event); err != nil {
+			dlog.Warnf("Failed to send alert %v to sink %v: %v",
+				d.event.Alert.Id, sink, err)
+		}
+	}
+}
+
+// claim returns true if this notifier is the one to send event to the sink.
+// The claim is kept for ttl seconds.
+func (n *Notifier) claim(sink string, event *Event, ttl uint64) bool {
+	key, err := claimKey(sink, event)
+	if err != nil {
+		return false
+	}
+	_, err = n.alert.GetKvdbInstance().Create(key, "", ttl)
+	if err == kvdb.ErrExist {
+		return false
+	}
+	if err != nil {
+		// Rather send the event twice than not at all.
+		dlog.Warnf("Failed to claim alert %v for sink %v: %v",
+			event.Alert.Id, sink, err)
+	}
+	return true
+}
+
+// release gives up the claim on sending event to the sink.
+func (n *Notifier) release(sink string, event *Event) {
+	key, err := claimKey(sink, event)
+	if err != nil {
+		return
+	}
+	_, err = n.alert.GetKvdbInstance().Delete(key)
+	if err != nil && err != kvdb.ErrNotFound {
+		dlog.Warnf("Failed to release alert %v for sink %v: %v",
+			event.Alert.Id, sink, err)
+	}
+}
+
+// claimKey returns the key of the claim on sending event to the sink.
+func claimKey(sink string, event *Event) (string, error) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(b)
+	return alertKey + notifiedKey + sink + "/" + hex.EncodeToString(digest[:]), nil
+}
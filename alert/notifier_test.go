@@ -0,0 +1,280 @@
+package alert
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/portworx/kvdb"
+	"github.com/portworx/kvdb/mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.pedge.io/dlog"
+)
+
+const notifierCluster = "notifier"
+
+// newClusterAlert returns alerts of clusterID in a kvdb of their own.
+func newClusterAlert(t *testing.T, clusterID string) Alert {
+	kv, err := kvdb.New(mem.Name, kvdbDomain+"/"+clusterID, []string{}, nil, dlog.Panicf)
+	require.NoError(t, err)
+	a, err := New(Name, clusterID, kv)
+	require.NoError(t, err)
+	return a
+}
+
+func TestSinks(t *testing.T) {
+	a := newClusterAlert(t, "sinks")
+
+	sinks, err := a.EnumerateSinks()
+	require.NoError(t, err)
+	assert.Empty(t, sinks)
+
+	assert.Error(t, a.PutSink(&api.AlertSink{Name: "bad", Type: api.AlertSinkWebhook}))
+	sink := &api.AlertSink{
+		Name:    "hook",
+		Type:    api.AlertSinkWebhook,
+		Webhook: &api.WebhookSinkConfig{URL: "http://localhost/hook", Secret: "secret"},
+	}
+	require.NoError(t, a.PutSink(sink))
+	sinks, err = a.EnumerateSinks()
+	require.NoError(t, err)
+	require.Len(t, sinks, 1)
+	assert.Equal(t, sink, sinks[0])
+
+	require.NoError(t, a.DeleteSink("hook"))
+	assert.Equal(t, ErrSinkNotFound, a.DeleteSink("hook"))
+}
+
+func TestNotifier(t *testing.T) {
+	webhookBackoff = time.Millisecond
+	a := newClusterAlert(t, notifierCluster)
+
+	events := make(chan *Event, 10)
+	failures := 1
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			// The first post is retried.
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, Signature("secret", body), r.Header.Get(SignatureHeader))
+		var e Event
+		assert.NoError(t, json.Unmarshal(body, &e))
+		events <- &e
+	}))
+	defer ts.Close()
+
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udp.Close()
+
+	require.NoError(t, a.PutSink(&api.AlertSink{
+		Name:      "hook",
+		Type:      api.AlertSinkWebhook,
+		Severity:  api.SeverityType_SEVERITY_TYPE_WARNING,
+		Resources: []api.ResourceType{api.ResourceType_RESOURCE_TYPE_VOLUME},
+		Webhook:   &api.WebhookSinkConfig{URL: ts.URL, Secret: "secret", Retries: 2},
+	}))
+	require.NoError(t, a.PutSink(&api.AlertSink{
+		Name:   "syslog",
+		Type:   api.AlertSinkSyslog,
+		Syslog: &api.SyslogSinkConfig{Network: "udp", Address: udp.LocalAddr().String()},
+	}))
+
+	n := NewNotifier(a, notifierCluster)
+	require.NoError(t, n.Start())
+
+	// Neither the notice nor the node alarm match the webhook sink.
+	require.NoError(t, a.Raise(&api.Alert{
+		Resource: api.ResourceType_RESOURCE_TYPE_VOLUME,
+		Severity: api.SeverityType_SEVERITY_TYPE_NOTIFY,
+	}))
+	require.NoError(t, a.Raise(&api.Alert{
+		Resource: api.ResourceType_RESOURCE_TYPE_NODE,
+		Severity: api.SeverityType_SEVERITY_TYPE_ALARM,
+	}))
+	alarm := &api.Alert{
+		Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+		Severity:   api.SeverityType_SEVERITY_TYPE_ALARM,
+		ResourceId: "vol1",
+		Message:    "volume is down",
+	}
+	require.NoError(t, a.Raise(alarm))
+
+	select {
+	case e := <-events:
+		assert.Equal(t, EventCreate, e.Action)
+		assert.Equal(t, notifierCluster, e.ClusterID)
+		assert.Equal(t, alarm.Id, e.Alert.Id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Alert was not posted to the webhook")
+	}
+
+	require.NoError(t, a.Clear(alarm.Resource, alarm.Id, 0))
+	select {
+	case e := <-events:
+		assert.Equal(t, EventClear, e.Action)
+		assert.Equal(t, alarm.Id, e.Alert.Id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Cleared alert was not posted to the webhook")
+	}
+	select {
+	case e := <-events:
+		t.Fatalf("Unexpected event %v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// All events go to the syslog sink.
+	buf := make([]byte, 4096)
+	received := 0
+	udp.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for received < 4 {
+		size, _, err := udp.ReadFrom(buf)
+		require.NoError(t, err)
+		msg := string(buf[:size])
+		assert.True(t, strings.HasPrefix(msg, "<"), msg)
+		assert.Contains(t, msg, ">1 ")
+		assert.Contains(t, msg, " osd ")
+		received++
+	}
+
+	// An event is sent to a sink once even if several notifiers see it.
+	event := newEvent(notifierCluster, alarm, api.AlertActionType_ALERT_ACTION_TYPE_UPDATE)
+	assert.True(t, n.claim("hook", event, notifiedTTL))
+	other := NewNotifier(a, notifierCluster)
+	assert.False(t, other.claim("hook", event, notifiedTTL))
+	// An event dropped by the notifier that claimed it may be sent by another.
+	n.release("hook", event)
+	assert.True(t, other.claim("hook", event, notifiedTTL))
+}
+
+// blockingSender blocks sending events until it is released.
+type blockingSender struct {
+	sending chan struct{}
+	release chan struct{}
+	sent    chan *Event
+}
+
+func (b *blockingSender) send(e *Event) error {
+	b.sending <- struct{}{}
+	<-b.release
+	b.sent <- e
+	return nil
+}
+
+func (b *blockingSender) window() time.Duration {
+	return time.Minute
+}
+
+func TestSinkQueue(t *testing.T) {
+	n := NewNotifier(nil, notifierCluster)
+	s := &blockingSender{
+		sending: make(chan struct{}, 2*sinkQueueSize),
+		release: make(chan struct{}),
+		sent:    make(chan *Event, 2*sinkQueueSize),
+	}
+	expires := time.Now().Add(time.Hour)
+
+	// Events past the size of the queue, besides the one being sent, are
+	// dropped.
+	assert.True(t, n.enqueue("blocking", &delivery{
+		event:   &Event{Alert: &api.Alert{Id: 0}},
+		sender:  s,
+		expires: expires,
+	}))
+	<-s.sending
+	for i := 1; i < sinkQueueSize+5; i++ {
+		queued := n.enqueue("blocking", &delivery{
+			event:   &Event{Alert: &api.Alert{Id: int64(i)}},
+			sender:  s,
+			expires: expires,
+		})
+		assert.Equal(t, i <= sinkQueueSize, queued, "event %v", i)
+	}
+	// Events whose claim expires while they could still be sending are
+	// dropped.
+	n.enqueue("expiring", &delivery{
+		event:   &Event{Alert: &api.Alert{Id: 1}},
+		sender:  s,
+		expires: time.Now().Add(time.Second),
+	})
+	close(s.release)
+
+	var ids []int64
+	timeout := time.After(5 * time.Second)
+	for len(ids) <= sinkQueueSize {
+		select {
+		case e := <-s.sent:
+			ids = append(ids, e.Alert.Id)
+		case <-timeout:
+			t.Fatalf("Only %v events were sent", len(ids))
+		}
+	}
+	for i, id := range ids {
+		assert.Equal(t, int64(i), id, "events are sent in order")
+	}
+	select {
+	case e := <-s.sent:
+		t.Fatalf("Unexpected event %v", e.Alert.Id)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	sink := &api.AlertSink{
+		Name:    "hook",
+		Type:    api.AlertSinkWebhook,
+		Webhook: &api.WebhookSinkConfig{URL: "http://localhost/hook", Retries: api.MaxWebhookRetries + 1},
+	}
+	_, err := newSender(sink)
+	assert.Error(t, err)
+
+	sink.Webhook.Retries = 2
+	s, err := newSender(sink)
+	require.NoError(t, err)
+	assert.Equal(t, 3*sendTimeout+3*webhookBackoff, s.window())
+}
+
+func TestSyslogMessage(t *testing.T) {
+	now := time.Date(2018, 3, 1, 10, 20, 30, 123456000, time.UTC)
+	msg, err := syslogMessage(&Event{
+		Action: EventCreate,
+		Alert:  &api.Alert{Id: 7, Severity: api.SeverityType_SEVERITY_TYPE_WARNING},
+	}, now)
+	require.NoError(t, err)
+	fields := strings.SplitN(string(msg), " ", 8)
+	require.Len(t, fields, 8)
+	assert.Equal(t, "<28>1", fields[0])
+	assert.Equal(t, "2018-03-01T10:20:30.123456Z", fields[1])
+	assert.Equal(t, "osd", fields[3])
+	assert.Equal(t, EventCreate, fields[5])
+	assert.Equal(t, "-", fields[6])
+	assert.True(t, strings.HasPrefix(fields[7], "{"))
+}
+
+func TestMailMessage(t *testing.T) {
+	msg, err := mailMessage(&Event{
+		ClusterID: "c1",
+		Action:    EventCreate,
+		Alert: &api.Alert{
+			Id:         7,
+			Severity:   api.SeverityType_SEVERITY_TYPE_ALARM,
+			Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+			ResourceId: "vol1",
+			Message:    "volume\nis down",
+		},
+	}, "osd@example.com", []string{"a@example.com", "b@example.com"}, time.Now())
+	require.NoError(t, err)
+	assert.Contains(t, string(msg), "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, string(msg), "Subject: [c1] create: ALARM alert 7 on volume vol1: volume is down\r\n")
+}
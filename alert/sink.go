@@ -0,0 +1,314 @@
+package alert
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/libopenstorage/openstorage/api"
+)
+
+const (
+	// EventCreate is the action of an event for a new alert.
+	EventCreate = "create"
+	// EventUpdate is the action of an event for a changed alert.
+	EventUpdate = "update"
+	// EventClear is the action of an event for a cleared alert.
+	EventClear = "clear"
+	// SignatureHeader is the header of webhook posts that holds the
+	// signature of the body.
+	SignatureHeader = "X-OSD-Signature"
+
+	sendTimeout = 10 * time.Second
+	// syslogDaemon is the syslog facility of alert messages.
+	syslogDaemon   = 3
+	syslogAppName  = "osd"
+	rfc5424TimeFmt = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// webhookBackoff is the wait before the first retry of a webhook post. It
+// doubles with every retry.
+var webhookBackoff = time.Second
+
+// Event is a change to an alert as it is sent to sinks.
+type Event struct {
+	// ClusterID is the cluster the alert was raised in.
+	ClusterID string `json:"cluster_id"`
+	// Action is EventCreate, EventUpdate or EventClear.
+	Action string `json:"action"`
+	// Alert as it is after the change.
+	Alert *api.Alert `json:"alert"`
+}
+
+// newEvent returns the event for a change seen by a watch on alerts, or
+// nil if it is not sent to sinks.
+func newEvent(clusterID string, a *api.Alert, action api.AlertActionType) *Event {
+	if a == nil {
+		return nil
+	}
+	e := &Event{ClusterID: clusterID, Alert: a}
+	switch {
+	case action == api.AlertActionType_ALERT_ACTION_TYPE_CREATE:
+		e.Action = EventCreate
+	case action == api.AlertActionType_ALERT_ACTION_TYPE_UPDATE && a.Cleared:
+		e.Action = EventClear
+	case action == api.AlertActionType_ALERT_ACTION_TYPE_UPDATE:
+		e.Action = EventUpdate
+	default:
+		return nil
+	}
+	return e
+}
+
+// Signature returns the value of SignatureHeader for a webhook body
+// signed with secret.
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// sender delivers events to a sink.
+type sender interface {
+	send(e *Event) error
+	// window is the longest send may take, retries included.
+	window() time.Duration
+}
+
+func newSender(sink *api.AlertSink) (sender, error) {
+	if err := sink.Validate(); err != nil {
+		return nil, err
+	}
+	switch sink.Type {
+	case api.AlertSinkWebhook:
+		return &webhookSender{
+			config: *sink.Webhook,
+			client: &http.Client{Timeout: sendTimeout},
+		}, nil
+	case api.AlertSinkSyslog:
+		return &syslogSender{config: *sink.Syslog}, nil
+	case api.AlertSinkSMTP:
+		return &smtpSender{config: *sink.SMTP}, nil
+	}
+	return nil, ErrNotSupported
+}
+
+type webhookSender struct {
+	config api.WebhookSinkConfig
+	client *http.Client
+}
+
+func (w *webhookSender) send(e *Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	backoff := webhookBackoff
+	for retry := 0; ; retry++ {
+		err = w.post(body)
+		if err == nil || retry >= w.config.Retries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (w *webhookSender) window() time.Duration {
+	posts := time.Duration(w.config.Retries+1) * sendTimeout
+	backoff := webhookBackoff * time.Duration(1<<uint(w.config.Retries)-1)
+	return posts + backoff
+}
+
+func (w *webhookSender) post(body []byte) error {
+	req, err := http.NewRequest("POST", w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.config.Secret != "" {
+		req.Header.Set(SignatureHeader, Signature(w.config.Secret, body))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Webhook %v returned %v", w.config.URL, resp.Status)
+	}
+	return nil
+}
+
+type syslogSender struct {
+	config api.SyslogSinkConfig
+}
+
+func (s *syslogSender) send(e *Event) error {
+	network, address := s.config.Network, s.config.Address
+	if network == "" && address == "" {
+		network, address = "unixgram", "/dev/log"
+	}
+	msg, err := syslogMessage(e, time.Now())
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(network, "tcp") {
+		// Octet counting framing of RFC 6587.
+		msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+	}
+	conn, err := net.DialTimeout(network, address, sendTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(sendTimeout))
+	_, err = conn.Write(msg)
+	return err
+}
+
+func (s *syslogSender) window() time.Duration {
+	return 2 * sendTimeout
+}
+
+// syslogMessage formats e as an RFC 5424 message with the event as JSON in
+// its body and the action as its message ID.
+func syslogMessage(e *Event, now time.Time) ([]byte, error) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return []byte(fmt.Sprintf("<%d>1 %s %s %s %d %s - %s",
+		syslogDaemon*8+syslogSeverity(e.Alert.Severity),
+		now.Format(rfc5424TimeFmt),
+		hostname,
+		syslogAppName,
+		os.Getpid(),
+		e.Action,
+		body,
+	)), nil
+}
+
+func syslogSeverity(severity api.SeverityType) int {
+	switch severity {
+	case api.SeverityType_SEVERITY_TYPE_ALARM:
+		return 2 // critical
+	case api.SeverityType_SEVERITY_TYPE_WARNING:
+		return 4 // warning
+	case api.SeverityType_SEVERITY_TYPE_NOTIFY:
+		return 5 // notice
+	}
+	return 6 // informational
+}
+
+type smtpSender struct {
+	config api.SMTPSinkConfig
+}
+
+func (s *smtpSender) send(e *Event) error {
+	host, _, err := net.SplitHostPort(s.config.Address)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, host)
+	}
+	from := s.config.From
+	if from == "" {
+		hostname, _ := os.Hostname()
+		from = syslogAppName + "@" + hostname
+	}
+	msg, err := mailMessage(e, from, s.config.To, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// smtp.SendMail has no timeout, so the session is held to sendTimeout.
+	conn, err := net.DialTimeout("tcp", s.config.Address, sendTimeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(sendTimeout))
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, to := range s.config.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (s *smtpSender) window() time.Duration {
+	return 2 * sendTimeout
+}
+
+// mailMessage formats e as a plain text mail with a one line summary as
+// its subject and the event as JSON in its body.
+func mailMessage(e *Event, from string, to []string, now time.Time) ([]byte, error) {
+	body, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	subject := fmt.Sprintf("[%s] %s: %s alert %d on %s %s: %s",
+		e.ClusterID,
+		e.Action,
+		strings.TrimPrefix(e.Alert.Severity.String(), "SEVERITY_TYPE_"),
+		e.Alert.Id,
+		strings.ToLower(strings.TrimPrefix(e.Alert.Resource.String(), "RESOURCE_TYPE_")),
+		e.Alert.ResourceId,
+		e.Alert.Message,
+	)
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.Write(body)
+	msg.WriteString("\r\n")
+	return msg.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+package alert
+
+import (
+	"github.com/libopenstorage/openstorage/api"
+)
+
+// Types of the alerts osd raises on its own. They are stored with the
+// alerts, so their values must not change.
+const (
+	// TypeNodeOffline is raised on a node that the other nodes see as down.
+	TypeNodeOffline int64 = 1
+	// TypeNodeNotInQuorum is raised by a node that lost the quorum.
+	TypeNodeNotInQuorum int64 = 2
+	// TypeVolumeDown is raised on a volume whose status is down.
+	TypeVolumeDown int64 = 3
+	// TypeVolumeDegraded is raised on a volume whose status is degraded.
+	TypeVolumeDegraded int64 = 4
+	// TypeVolumeAttachFailed is raised on a volume that failed to attach.
+	TypeVolumeAttachFailed int64 = 5
+	// TypeVolumeMountFailed is raised on a volume that failed to mount.
+	TypeVolumeMountFailed int64 = 6
+	// TypeVolumeUnmountFailed is raised on a volume that failed to unmount.
+	TypeVolumeUnmountFailed int64 = 7
+	// TypeVolumeUsageHigh is raised on a volume that is filling up.
+	TypeVolumeUsageHigh int64 = 8
+	// TypeVolumeUsageCritical is raised on a volume that is almost full.
+	TypeVolumeUsageCritical int64 = 9
+)
+
+// stateRateLimit is the rate limit, in seconds, of the alerts on the state
+// of a resource. Every node may see the same state change and raise the
+// same alert; only the first of them is counted.
+const stateRateLimit = 60
+
+// typeInfo is how alerts of a type are raised.
+type typeInfo struct {
+	resource  api.ResourceType
+	severity  api.SeverityType
+	uniqueTag string
+	rateLimit uint64
+}
+
+var typeInfos = map[int64]typeInfo{
+	TypeNodeOffline: {
+		resource:  api.ResourceType_RESOURCE_TYPE_NODE,
+		severity:  api.SeverityType_SEVERITY_TYPE_ALARM,
+		uniqueTag: "node_offline",
+		rateLimit: stateRateLimit,
+	},
+	TypeNodeNotInQuorum: {
+		resource:  api.ResourceType_RESOURCE_TYPE_NODE,
+		severity:  api.SeverityType_SEVERITY_TYPE_ALARM,
+		uniqueTag: "node_not_in_quorum",
+		rateLimit: stateRateLimit,
+	},
+	TypeVolumeDown: {
+		resource:  api.ResourceType_RESOURCE_TYPE_VOLUME,
+		severity:  api.SeverityType_SEVERITY_TYPE_ALARM,
+		uniqueTag: "volume_down",
+		rateLimit: stateRateLimit,
+	},
+	TypeVolumeDegraded: {
+		resource:  api.ResourceType_RESOURCE_TYPE_VOLUME,
+		severity:  api.SeverityType_SEVERITY_TYPE_WARNING,
+		uniqueTag: "volume_degraded",
+		rateLimit: stateRateLimit,
+	},
+	TypeVolumeAttachFailed: {
+		resource:  api.ResourceType_RESOURCE_TYPE_VOLUME,
+		severity:  api.SeverityType_SEVERITY_TYPE_ALARM,
+		uniqueTag: "volume_attach_failed",
+	},
+	TypeVolumeMountFailed: {
+		resource:  api.ResourceType_RESOURCE_TYPE_VOLUME,
+		severity:  api.SeverityType_SEVERITY_TYPE_ALARM,
+		uniqueTag: "volume_mount_failed",
+	},
+	TypeVolumeUnmountFailed: {
+		resource:  api.ResourceType_RESOURCE_TYPE_VOLUME,
+		severity:  api.SeverityType_SEVERITY_TYPE_WARNING,
+		uniqueTag: "volume_unmount_failed",
+	},
+	TypeVolumeUsageHigh: {
+		resource:  api.ResourceType_RESOURCE_TYPE_VOLUME,
+		severity:  api.SeverityType_SEVERITY_TYPE_WARNING,
+		uniqueTag: "volume_usage_high",
+		rateLimit: stateRateLimit,
+	},
+	TypeVolumeUsageCritical: {
+		resource:  api.ResourceType_RESOURCE_TYPE_VOLUME,
+		severity:  api.SeverityType_SEVERITY_TYPE_ALARM,
+		uniqueTag: "volume_usage_critical",
+		rateLimit: stateRateLimit,
+	},
+}
+
+// RaiseType raises the alert of alertType, one of the types osd raises, on
+// resourceID. If the alert is raised already, this is counted as another
+// occurrence of it.
+func RaiseType(a Alert, alertType int64, resourceID string, message string) error {
+	info, ok := typeInfos[alertType]
+	if !ok {
+		return ErrIllegal
+	}
+	return a.RaiseIfNotExist(&api.Alert{
+		AlertType:  alertType,
+		Resource:   info.resource,
+		ResourceId: resourceID,
+		Severity:   info.severity,
+		Message:    message,
+		UniqueTag:  info.uniqueTag,
+		RateLimit:  info.rateLimit,
+	})
+}
+
+// ClearType clears the alert of alertType on resourceID if it is raised.
+func ClearType(a Alert, alertType int64, resourceID string) error {
+	info, ok := typeInfos[alertType]
+	if !ok {
+		return ErrIllegal
+	}
+	return a.ClearByUniqueTag(info.resource, resourceID, info.uniqueTag, 0)
+}
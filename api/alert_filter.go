@@ -0,0 +1,117 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlertSort is the order of the alerts returned by EnumerateWithFilter.
+type AlertSort string
+
+const (
+	// AlertSortID orders alerts by ID, which is the order they were raised in.
+	AlertSortID AlertSort = "id"
+	// AlertSortLastSeen orders alerts by the last time they occurred.
+	AlertSortLastSeen AlertSort = "last_seen"
+	// AlertSortFirstSeen orders alerts by the first time they occurred.
+	AlertSortFirstSeen AlertSort = "first_seen"
+	// AlertSortSeverity orders alerts from the least to the most severe.
+	AlertSortSeverity AlertSort = "severity"
+	// AlertSortCount orders alerts by how often they occurred.
+	AlertSortCount AlertSort = "count"
+)
+
+// AlertSortValueOf returns the alert sort named s, AlertSortID if s is empty.
+func AlertSortValueOf(s string) (AlertSort, error) {
+	switch sort := AlertSort(strings.ToLower(s)); sort {
+	case "":
+		return AlertSortID, nil
+	case AlertSortID, AlertSortLastSeen, AlertSortFirstSeen,
+		AlertSortSeverity, AlertSortCount:
+		return sort, nil
+	}
+	return "", fmt.Errorf("Invalid alert sort %q", s)
+}
+
+// AlertFilter selects the alerts returned by EnumerateWithFilter. Fields
+// that are left empty match every alert.
+type AlertFilter struct {
+	// Resource must equal the resource type of the alert.
+	Resource ResourceType
+	// ResourceId must equal the resource of the alert.
+	ResourceId string
+	// Severity selects alerts of this severity and more severe ones.
+	Severity SeverityType
+	// Cleared, if set, must equal whether the alert is cleared.
+	Cleared *bool
+	// AlertType must equal the type of the alert.
+	AlertType int64
+	// TimeStart and TimeEnd bound the last time the alert occurred.
+	TimeStart time.Time
+	TimeEnd   time.Time
+	// Text must be part of the message of the alert, ignoring case.
+	Text string
+	// SortBy orders the alerts, by ID if it is not set.
+	SortBy AlertSort
+	// Descending reverses the order of the alerts.
+	Descending bool
+	// Limit is the largest number of alerts to return, 0 for no limit.
+	Limit int
+	// Token continues an enumeration from the NextToken of an AlertList
+	// returned for the same filter.
+	Token string
+}
+
+// Matches returns true if a meets all conditions of f. The order and
+// paging fields are ignored.
+func (f *AlertFilter) Matches(a *Alert) bool {
+	if f.Resource != ResourceType_RESOURCE_TYPE_NONE && a.Resource != f.Resource {
+		return false
+	}
+	if f.ResourceId != "" && a.ResourceId != f.ResourceId {
+		return false
+	}
+	if f.Severity != SeverityType_SEVERITY_TYPE_NONE &&
+		(a.Severity == SeverityType_SEVERITY_TYPE_NONE || a.Severity > f.Severity) {
+		return false
+	}
+	if f.Cleared != nil && a.Cleared != *f.Cleared {
+		return false
+	}
+	if f.AlertType != 0 && a.AlertType != f.AlertType {
+		return false
+	}
+	if !f.TimeStart.IsZero() || !f.TimeEnd.IsZero() {
+		ts := a.GetLastSeen()
+		if ts == nil {
+			ts = a.GetTimestamp()
+		}
+		if ts == nil {
+			return false
+		}
+		lastSeen := time.Unix(ts.GetSeconds(), int64(ts.GetNanos()))
+		if !f.TimeStart.IsZero() && !lastSeen.After(f.TimeStart) {
+			return false
+		}
+		if !f.TimeEnd.IsZero() && !lastSeen.Before(f.TimeEnd) {
+			return false
+		}
+	}
+	if f.Text != "" &&
+		!strings.Contains(strings.ToLower(a.Message), strings.ToLower(f.Text)) {
+		return false
+	}
+	return true
+}
+
+// AlertList is a page of alerts returned by EnumerateWithFilter.
+//
+// swagger:model
+type AlertList struct {
+	// Alerts on this page.
+	Alerts []*Alert
+	// NextToken continues the enumeration after this page. It is empty on
+	// the last page.
+	NextToken string
+}
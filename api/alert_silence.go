@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlertAcknowledgement acknowledges an alert.
+// swagger:model
+type AlertAcknowledgement struct {
+	// User is who acknowledges the alert. The REST server uses the subject
+	// of the token of the request instead when authentication is on.
+	User string
+	// Comment is a note for whoever looks at the alert next.
+	Comment string
+}
+
+// AlertSilence keeps the alerts it matches from being sent to watchers
+// while it is active, for instance during planned maintenance. The alerts
+// are still stored. Fields that are not set match every alert.
+// swagger:model
+type AlertSilence struct {
+	// Name identifies the silence.
+	Name string
+	// Resource is the resource type of the alerts silenced.
+	Resource ResourceType
+	// ResourceId is the resource of the alerts silenced.
+	ResourceId string
+	// AlertType is the type of the alerts silenced.
+	AlertType int64
+	// Severity silences alerts of this severity and less severe ones.
+	Severity SeverityType
+	// Start is when the silence begins, right away if it is not set.
+	Start time.Time
+	// Expiry is when the silence ends.
+	Expiry time.Time
+	// User and Comment tell who silenced the alerts and why.
+	User    string
+	Comment string
+}
+
+// Validate returns an error if the silence has no name or has expired.
+func (s *AlertSilence) Validate() error {
+	if s.Name == "" || strings.Contains(s.Name, "/") {
+		return fmt.Errorf("Invalid alert silence name %q", s.Name)
+	}
+	if !s.Expiry.After(time.Now()) || !s.Expiry.After(s.Start) {
+		return fmt.Errorf("Alert silence %v expires before it starts", s.Name)
+	}
+	return nil
+}
+
+// Active returns true if the silence applies at now.
+func (s *AlertSilence) Active(now time.Time) bool {
+	return !now.Before(s.Start) && now.Before(s.Expiry)
+}
+
+// Matches returns true if the silence applies to a.
+func (s *AlertSilence) Matches(a *Alert) bool {
+	if s.Resource != ResourceType_RESOURCE_TYPE_NONE && s.Resource != a.Resource {
+		return false
+	}
+	if s.ResourceId != "" && s.ResourceId != a.ResourceId {
+		return false
+	}
+	if s.AlertType != 0 && s.AlertType != a.AlertType {
+		return false
+	}
+	return s.Severity == SeverityType_SEVERITY_TYPE_NONE || a.Severity >= s.Severity
+}
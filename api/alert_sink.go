@@ -0,0 +1,133 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AlertSinkType is the protocol an AlertSink delivers alerts with.
+type AlertSinkType string
+
+const (
+	// AlertSinkWebhook posts alerts as JSON to an HTTP endpoint.
+	AlertSinkWebhook AlertSinkType = "webhook"
+	// AlertSinkSyslog sends alerts as RFC 5424 syslog messages.
+	AlertSinkSyslog AlertSinkType = "syslog"
+	// AlertSinkSMTP mails alerts.
+	AlertSinkSMTP AlertSinkType = "smtp"
+
+	// MaxWebhookRetries is the most times a failed webhook post is retried.
+	MaxWebhookRetries = 5
+)
+
+// AlertSink is a destination alert events are sent to.
+// swagger:model
+type AlertSink struct {
+	// Name identifies the sink.
+	Name string
+	// Type selects which of the configurations below is used.
+	Type AlertSinkType
+	// Severity is the least severe alert that is sent. All alerts are sent
+	// if it is not set.
+	Severity SeverityType
+	// Resources are the resource types whose alerts are sent. Alerts on
+	// all resources are sent if it is empty.
+	Resources []ResourceType
+	Webhook   *WebhookSinkConfig `json:",omitempty"`
+	Syslog    *SyslogSinkConfig  `json:",omitempty"`
+	SMTP      *SMTPSinkConfig    `json:",omitempty"`
+}
+
+// WebhookSinkConfig configures an AlertSinkWebhook sink.
+type WebhookSinkConfig struct {
+	// URL the events are posted to.
+	URL string
+	// Secret, if set, is the key of the HMAC-SHA256 signature of the body
+	// sent in the X-OSD-Signature header.
+	Secret string `json:",omitempty"`
+	// Retries is the number of times a failed post is retried, at most
+	// MaxWebhookRetries.
+	Retries int
+}
+
+// SyslogSinkConfig configures an AlertSinkSyslog sink.
+type SyslogSinkConfig struct {
+	// Network is udp, tcp or unixgram.
+	Network string
+	// Address of the syslog server. Messages go to the local syslog
+	// daemon if Network and Address are not set.
+	Address string
+}
+
+// SMTPSinkConfig configures an AlertSinkSMTP sink.
+type SMTPSinkConfig struct {
+	// Address is the host:port of the mail server.
+	Address string
+	From    string
+	To      []string
+	// Username and Password, if set, are used for PLAIN authentication.
+	Username string `json:",omitempty"`
+	Password string `json:",omitempty"`
+}
+
+// Validate returns an error if the sink is missing the configuration of
+// its type.
+func (s *AlertSink) Validate() error {
+	if s.Name == "" || strings.Contains(s.Name, "/") {
+		return fmt.Errorf("Invalid alert sink name %q", s.Name)
+	}
+	switch s.Type {
+	case AlertSinkWebhook:
+		if s.Webhook == nil || s.Webhook.URL == "" {
+			return fmt.Errorf("Alert sink %v needs a webhook URL", s.Name)
+		}
+		if s.Webhook.Retries < 0 || s.Webhook.Retries > MaxWebhookRetries {
+			return fmt.Errorf("Alert sink %v retries must be between 0 and %v",
+				s.Name, MaxWebhookRetries)
+		}
+	case AlertSinkSyslog:
+		if s.Syslog == nil {
+			return fmt.Errorf("Alert sink %v needs a syslog configuration", s.Name)
+		}
+	case AlertSinkSMTP:
+		if s.SMTP == nil || s.SMTP.Address == "" || len(s.SMTP.To) == 0 {
+			return fmt.Errorf("Alert sink %v needs a mail server and recipients", s.Name)
+		}
+	default:
+		return fmt.Errorf("Unknown alert sink type %q", s.Type)
+	}
+	return nil
+}
+
+// Matches returns true if alerts like a are sent to the sink.
+func (s *AlertSink) Matches(a *Alert) bool {
+	if s.Severity != SeverityType_SEVERITY_TYPE_NONE &&
+		(a.Severity == SeverityType_SEVERITY_TYPE_NONE || a.Severity > s.Severity) {
+		return false
+	}
+	if len(s.Resources) == 0 {
+		return true
+	}
+	for _, r := range s.Resources {
+		if r == a.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy of the sink without its secrets.
+func (s *AlertSink) Redacted() *AlertSink {
+	c := *s
+	if s.Webhook != nil {
+		webhook := *s.Webhook
+		webhook.Secret = ""
+		c.Webhook = &webhook
+	}
+	if s.SMTP != nil {
+		smtp := *s.SMTP
+		smtp.Password = ""
+		c.SMTP = &smtp
+	}
+	return &c
+}
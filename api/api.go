@@ -58,12 +58,55 @@ const (
 	OptLabel = "Label"
 	// OptConfigLabel query parameter used to lookup volume by set of labels.
 	OptConfigLabel = "ConfigLabel"
+	// OptSelector query parameter used to lookup volumes by label selector.
+	OptSelector = "Selector"
+	// OptState query parameter used to lookup volumes by state.
+	OptState = "State"
+	// OptStatus query parameter used to lookup volumes by status.
+	OptStatus = "Status"
+	// OptAttachedOn query parameter used to lookup volumes by attached node.
+	OptAttachedOn = "AttachedOn"
+	// OptCreatedAfter query parameter used to lookup volumes created after
+	// a time in RFC 3339 format.
+	OptCreatedAfter = "CreatedAfter"
+	// OptCreatedBefore query parameter used to lookup volumes created before
+	// a time in RFC 3339 format.
+	OptCreatedBefore = "CreatedBefore"
+	// OptLimit query parameter used to limit the number of volumes returned.
+	OptLimit = "Limit"
+	// OptToken query parameter used to continue an enumeration.
+	OptToken = "Token"
+	// OptResource query parameter used to lookup alerts by resource type.
+	OptResource = "Resource"
+	// OptResourceID query parameter used to lookup alerts by resource.
+	OptResourceID = "ResourceID"
+	// OptSeverity query parameter used to lookup alerts of a severity and
+	// more severe ones.
+	OptSeverity = "Severity"
+	// OptCleared query parameter used to lookup cleared or active alerts.
+	OptCleared = "Cleared"
+	// OptAlertType query parameter used to lookup alerts by type.
+	OptAlertType = "AlertType"
+	// OptTimeStart query parameter used to lookup alerts last seen after
+	// a time in RFC 3339 format.
+	OptTimeStart = "TimeStart"
+	// OptTimeEnd query parameter used to lookup alerts last seen before
+	// a time in RFC 3339 format.
+	OptTimeEnd = "TimeEnd"
+	// OptText query parameter used to lookup alerts by message text.
+	OptText = "Text"
+	// OptSortBy query parameter used to order alerts.
+	OptSortBy = "SortBy"
+	// OptDescending query parameter used to reverse the order of alerts.
+	OptDescending = "Descending"
 	// OptCumulative query parameter used to request cumulative stats.
 	OptCumulative = "Cumulative"
 	// OptTimeout query parameter used to indicate timeout seconds
 	OptTimeoutSec = "TimeoutSec"
 	// OptQuiesceID query parameter use for quiesce
 	OptQuiesceID = "QuiesceID"
+	// OptCascade query parameter used to delete a snapshot with its descendants.
+	OptCascade = "Cascade"
 	// OptCredUUID is the UUID of the credential
 	OptCredUUID = "CredUUID"
 	// OptCredType  indicates type of credential
@@ -224,6 +267,17 @@ type StatPoint struct {
 	Timestamp int64
 }
 
+// SnapshotTree is a volume together with the snapshots and clones that
+// descend from it.
+//
+// swagger:model
+type SnapshotTree struct {
+	// Volume at the root of this tree.
+	Volume *Volume
+	// Children are the trees of snapshots and clones whose parent is Volume.
+	Children []*SnapshotTree
+}
+
 // DriverTypeSimpleValueOf returns the string format of DriverType
 func DriverTypeSimpleValueOf(s string) (DriverType, error) {
 	obj, err := simpleValueOf("driver_type", DriverType_value, s)
@@ -315,6 +369,28 @@ func (x IoProfile) SimpleString() string {
 	return simpleString("io_profile", IoProfile_name, int32(x))
 }
 
+// SeverityTypeSimpleValueOf returns the string format of SeverityType
+func SeverityTypeSimpleValueOf(s string) (SeverityType, error) {
+	obj, err := simpleValueOf("severity_type", SeverityType_value, s)
+	return SeverityType(obj), err
+}
+
+// SimpleString returns the string format of SeverityType
+func (x SeverityType) SimpleString() string {
+	return simpleString("severity_type", SeverityType_name, int32(x))
+}
+
+// ResourceTypeSimpleValueOf returns the string format of ResourceType
+func ResourceTypeSimpleValueOf(s string) (ResourceType, error) {
+	obj, err := simpleValueOf("resource_type", ResourceType_value, s)
+	return ResourceType(obj), err
+}
+
+// SimpleString returns the string format of ResourceType
+func (x ResourceType) SimpleString() string {
+	return simpleString("resource_type", ResourceType_name, int32(x))
+}
+
 func simpleValueOf(typeString string, valueMap map[string]int32, s string) (int32, error) {
 	obj, ok := valueMap[strings.ToUpper(fmt.Sprintf("%s_%s", typeString, s))]
 	if !ok {
@@ -1421,6 +1421,23 @@ type Alert struct {
 	Ttl uint64 `protobuf:"varint,9,opt,name=ttl" json:"ttl,omitempty"`
 	// UniqueTag helps identify a unique alert for a given resouce
 	UniqueTag string `protobuf:"bytes,10,opt,name=unique_tag,json=uniqueTag" json:"unique_tag,omitempty"`
+	// Count of the occurrences of the Alert
+	Count int64 `protobuf:"varint,11,opt,name=count" json:"count,omitempty"`
+	// FirstSeen is when the Alert first occured
+	FirstSeen *google_protobuf.Timestamp `protobuf:"bytes,12,opt,name=first_seen,json=firstSeen" json:"first_seen,omitempty"`
+	// LastSeen is when the Alert last occured
+	LastSeen *google_protobuf.Timestamp `protobuf:"bytes,13,opt,name=last_seen,json=lastSeen" json:"last_seen,omitempty"`
+	// RateLimit in seconds drops occurrences of an Alert with a UniqueTag
+	// that are less than RateLimit after its LastSeen
+	RateLimit uint64 `protobuf:"varint,14,opt,name=rate_limit,json=rateLimit" json:"rate_limit,omitempty"`
+	// Acknowledged is set once a user has taken note of the Alert
+	Acknowledged bool `protobuf:"varint,15,opt,name=acknowledged" json:"acknowledged,omitempty"`
+	// AckUser is who acknowledged the Alert
+	AckUser string `protobuf:"bytes,16,opt,name=ack_user,json=ackUser" json:"ack_user,omitempty"`
+	// AckComment is the note left when the Alert was acknowledged
+	AckComment string `protobuf:"bytes,17,opt,name=ack_comment,json=ackComment" json:"ack_comment,omitempty"`
+	// AckTime is when the Alert was acknowledged
+	AckTime *google_protobuf.Timestamp `protobuf:"bytes,18,opt,name=ack_time,json=ackTime" json:"ack_time,omitempty"`
 }
 
 func (m *Alert) Reset()                    { *m = Alert{} }
@@ -1498,6 +1515,62 @@ func (m *Alert) GetUniqueTag() string {
 	return ""
 }
 
+func (m *Alert) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Alert) GetFirstSeen() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.FirstSeen
+	}
+	return nil
+}
+
+func (m *Alert) GetLastSeen() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.LastSeen
+	}
+	return nil
+}
+
+func (m *Alert) GetRateLimit() uint64 {
+	if m != nil {
+		return m.RateLimit
+	}
+	return 0
+}
+
+func (m *Alert) GetAcknowledged() bool {
+	if m != nil {
+		return m.Acknowledged
+	}
+	return false
+}
+
+func (m *Alert) GetAckUser() string {
+	if m != nil {
+		return m.AckUser
+	}
+	return ""
+}
+
+func (m *Alert) GetAckComment() string {
+	if m != nil {
+		return m.AckComment
+	}
+	return ""
+}
+
+func (m *Alert) GetAckTime() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.AckTime
+	}
+	return nil
+}
+
 // Alerts is an array of Alert objects
 // swagger:model
 type Alerts struct {
@@ -1936,194 +2009,201 @@ func init() {
 func init() { proto.RegisterFile("api/api.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 3012 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0x4b, 0x73, 0xe3, 0xc6,
-	0xb5, 0x1e, 0xf0, 0xcd, 0x43, 0x91, 0x82, 0x7a, 0x34, 0x12, 0x46, 0xf3, 0x92, 0x59, 0xd7, 0xb6,
-	0x8a, 0xd7, 0x57, 0xe3, 0xd2, 0xb5, 0x7d, 0xc7, 0x73, 0xf3, 0xa2, 0x48, 0x50, 0x62, 0xcc, 0x87,
-	0xdc, 0x80, 0x34, 0x33, 0x4e, 0xa5, 0x50, 0x18, 0xb2, 0x25, 0xc1, 0x43, 0x01, 0x18, 0x00, 0x54,
-	0x4a, 0x5e, 0x64, 0x9b, 0x4d, 0x2a, 0x59, 0x25, 0x29, 0xaf, 0xf2, 0x03, 0xbc, 0xca, 0x3a, 0x8b,
-	0x2c, 0xb2, 0xf2, 0x26, 0xbf, 0x24, 0x9b, 0xfc, 0x83, 0xd4, 0xe9, 0x6e, 0x50, 0x00, 0x29, 0xcd,
-	0x8c, 0x2a, 0x5e, 0xa9, 0xfb, 0x3b, 0x8f, 0x3e, 0x7d, 0xfa, 0xbc, 0x40, 0x41, 0xd5, 0xf6, 0x9d,
-	0xc7, 0xb6, 0xef, 0x6c, 0xfb, 0x81, 0x17, 0x79, 0x64, 0xd9, 0xf3, 0x99, 0x1b, 0x46, 0x5e, 0x60,
-	0x9f, 0xb0, 0x6d, 0xdb, 0x77, 0x36, 0x1e, 0x9d, 0x78, 0xde, 0xc9, 0x84, 0x3d, 0xe6, 0xe4, 0x97,
-	0xd3, 0xe3, 0xc7, 0x91, 0x73, 0xc6, 0xc2, 0xc8, 0x3e, 0xf3, 0x85, 0x44, 0xfd, 0x5f, 0x19, 0x58,
-	0x36, 0x84, 0x00, 0x65, 0xa1, 0x37, 0x0d, 0x46, 0x8c, 0xd4, 0x20, 0xe3, 0x8c, 0x35, 0x65, 0x53,
-	0xd9, 0x2a, 0xd3, 0x8c, 0x33, 0x26, 0x04, 0x72, 0xbe, 0x1d, 0x9d, 0x6a, 0x19, 0x8e, 0xf0, 0x35,
-	0xf9, 0x0c, 0x0a, 0x67, 0x6c, 0xec, 0x4c, 0xcf, 0xb4, 0xec, 0xa6, 0xb2, 0x55, 0xdb, 0x79, 0xb8,
-	0x3d, 0x77, 0xf4, 0xb6, 0xd4, 0xda, 0xe7, 0x5c, 0x54, 0x72, 0x93, 0x35, 0x28, 0x78, 0xee, 0xc4,
-	0x71, 0x99, 0x96, 0xdb, 0x54, 0xb6, 0x4a, 0x54, 0xee, 0xf0, 0x0c, 0xc7, 0xf3, 0x43, 0x2d, 0xbf,
-	0xa9, 0x6c, 0xe5, 0x28, 0x5f, 0x93, 0x7b, 0x50, 0x0e, 0xd9, 0x6b, 0xeb, 0x57, 0x81, 0x13, 0x31,
-	0xad, 0xb0, 0xa9, 0x6c, 0x29, 0xb4, 0x14, 0xb2, 0xd7, 0xcf, 0x70, 0x4f, 0xee, 0x02, 0xae, 0xad,
-	0x80, 0xd9, 0x63, 0xad, 0xc8, 0x69, 0xc5, 0x90, 0xbd, 0xa6, 0xcc, 0x1e, 0xe3, 0x19, 0x81, 0xed,
-	0x8e, 0xe9, 0x33, 0xad, 0xc4, 0x09, 0x72, 0x87, 0x67, 0x84, 0xce, 0x37, 0x4c, 0x2b, 0x8b, 0x33,
-	0x70, 0x8d, 0xd8, 0x34, 0x64, 0x63, 0x0d, 0x04, 0x86, 0x6b, 0xf2, 0x3e, 0xd4, 0x02, 0x2f, 0xb2,
-	0x23, 0xc7, 0x73, 0xad, 0xd0, 0x67, 0x6c, 0xac, 0x55, 0xf8, 0xcd, 0xab, 0x31, 0x6a, 0x20, 0x48,
-	0xfe, 0x0f, 0xca, 0x13, 0x3b, 0x8c, 0xac, 0x70, 0x64, 0xbb, 0xda, 0xd2, 0xa6, 0xb2, 0x55, 0xd9,
-	0xd9, 0xd8, 0x16, 0xfe, 0xde, 0x8e, 0xfd, 0xbd, 0x6d, 0xc6, 0xfe, 0xa6, 0x25, 0x64, 0x36, 0x46,
-	0xb6, 0x5b, 0xff, 0x47, 0x06, 0x2a, 0xd2, 0x3b, 0x07, 0x9e, 0x37, 0x41, 0x7f, 0x77, 0xdb, 0xdc,
-	0xdf, 0x79, 0x9a, 0xe9, 0xb6, 0x49, 0x03, 0xb2, 0x2d, 0x2f, 0xe4, 0xee, 0xae, 0xed, 0x68, 0x0b,
-	0x8e, 0x6d, 0x79, 0xa1, 0x79, 0xe1, 0x33, 0x8a, 0x4c, 0xf8, 0x0e, 0xfd, 0x1b, 0xbd, 0x83, 0xf8,
-	0x4b, 0xee, 0x43, 0x99, 0xda, 0xce, 0xb8, 0xc7, 0xce, 0xd9, 0x84, 0x3f, 0x45, 0x99, 0x5e, 0x02,
-	0x48, 0x35, 0xbd, 0xc8, 0x9e, 0x18, 0xe8, 0xae, 0x22, 0x77, 0xcd, 0x25, 0x80, 0x3e, 0x3b, 0x44,
-	0x9f, 0x95, 0x84, 0xcf, 0x70, 0x4d, 0x7e, 0x06, 0x85, 0x89, 0xfd, 0x92, 0x4d, 0x42, 0xad, 0xbc,
-	0x99, 0xdd, 0xaa, 0xec, 0x6c, 0x5d, 0x67, 0x07, 0xde, 0x78, 0xbb, 0xc7, 0x59, 0x75, 0x37, 0x0a,
-	0x2e, 0xa8, 0x94, 0xdb, 0xf8, 0x1c, 0x2a, 0x09, 0x98, 0xa8, 0x90, 0x7d, 0xc5, 0x2e, 0x64, 0x14,
-	0xe2, 0x92, 0xac, 0x42, 0xfe, 0xdc, 0x9e, 0x4c, 0x99, 0x8c, 0x43, 0xb1, 0x79, 0x9a, 0x79, 0xa2,
-	0xd4, 0xff, 0xaa, 0x40, 0xf5, 0xc8, 0x9b, 0x4c, 0xcf, 0x58, 0xcf, 0x1b, 0xd9, 0x91, 0x17, 0xa0,
-	0x89, 0xae, 0x7d, 0xc6, 0xa4, 0x38, 0x5f, 0x93, 0x43, 0xa8, 0x9e, 0x73, 0x26, 0x4b, 0x5a, 0x9a,
-	0xe1, 0x96, 0x7e, 0xbc, 0x60, 0x69, 0x4a, 0x55, 0xbc, 0x4b, 0x58, 0xbc, 0x74, 0x9e, 0x80, 0x36,
-	0x7e, 0x0a, 0x2b, 0x0b, 0x2c, 0x37, 0xb2, 0xfe, 0x13, 0x28, 0x18, 0x22, 0xf1, 0xd6, 0xa0, 0xe0,
-	0xdb, 0x01, 0x73, 0x23, 0x29, 0x28, 0x77, 0x3c, 0x70, 0x31, 0x0c, 0x65, 0x02, 0xe2, 0xba, 0xbe,
-	0x0e, 0xf9, 0xbd, 0xc0, 0x9b, 0xfa, 0xf3, 0xd9, 0x5a, 0xff, 0x7b, 0x11, 0x40, 0x18, 0x64, 0xf8,
-	0x6c, 0x84, 0x4f, 0xc9, 0xfc, 0x53, 0x76, 0xc6, 0x02, 0x7b, 0xc2, 0xb9, 0x4a, 0xf4, 0x12, 0x98,
-	0xa5, 0x44, 0x26, 0x91, 0x12, 0x8f, 0xa1, 0x70, 0xec, 0x05, 0x67, 0x76, 0x24, 0x43, 0x6a, 0x7d,
-	0xc1, 0x41, 0x1d, 0x83, 0x07, 0xa0, 0x64, 0x23, 0x0f, 0x00, 0x5e, 0x4e, 0xbc, 0xd1, 0x2b, 0x8b,
-	0xab, 0xc2, 0x60, 0xca, 0xd2, 0x32, 0x47, 0x78, 0xb8, 0xdc, 0x85, 0xd2, 0xa9, 0x6d, 0x4d, 0x78,
-	0xa4, 0xe5, 0x39, 0xb1, 0x78, 0x6a, 0x8b, 0x38, 0x6b, 0x40, 0x76, 0xe4, 0x85, 0x3c, 0xb7, 0xdf,
-	0x18, 0xe9, 0x23, 0x2f, 0x24, 0x9f, 0x03, 0x38, 0x9e, 0xe5, 0x07, 0xde, 0xb1, 0x33, 0x11, 0x41,
-	0x59, 0xdb, 0xd9, 0x58, 0x10, 0xe9, 0x7a, 0x07, 0x82, 0x83, 0x96, 0x9d, 0x78, 0x89, 0x7e, 0x1d,
-	0xb3, 0xf1, 0xd4, 0x67, 0x3c, 0x64, 0x4b, 0x54, 0xee, 0xc8, 0x7f, 0xc3, 0x4a, 0xe8, 0xda, 0x7e,
-	0x78, 0xea, 0x45, 0x96, 0xe3, 0x46, 0x2c, 0x38, 0xb7, 0x27, 0xbc, 0x3a, 0x54, 0xa9, 0x1a, 0x13,
-	0xba, 0x12, 0x27, 0x74, 0x3e, 0x7c, 0x80, 0x87, 0xcf, 0xff, 0x5c, 0x13, 0x3e, 0xe8, 0xfc, 0xb7,
-	0xc5, 0x0e, 0x1a, 0x16, 0x9e, 0xda, 0x81, 0xac, 0x30, 0x25, 0x2a, 0x77, 0xe4, 0x47, 0x50, 0x09,
-	0x98, 0x3f, 0x71, 0x46, 0xb6, 0x15, 0xb2, 0x48, 0x16, 0x97, 0x7b, 0x0b, 0x27, 0x51, 0xc1, 0x63,
-	0xb0, 0x88, 0x42, 0x30, 0x5b, 0xe3, 0xb5, 0xec, 0x93, 0x93, 0x80, 0x9d, 0x88, 0x12, 0x26, 0x3c,
-	0x5f, 0x15, 0xd7, 0x4a, 0x10, 0x66, 0xa9, 0xce, 0xdc, 0x51, 0x70, 0xe1, 0x47, 0x6c, 0xac, 0xd5,
-	0x64, 0x7c, 0xc4, 0x00, 0x79, 0x08, 0xe0, 0xdb, 0x61, 0xe8, 0x9f, 0x06, 0x76, 0xc8, 0xb4, 0x65,
-	0x1e, 0x64, 0x09, 0x24, 0xe5, 0xc1, 0x70, 0x74, 0xca, 0xc6, 0xd3, 0x09, 0xd3, 0x54, 0xce, 0x36,
-	0xf3, 0xa0, 0x21, 0x71, 0x4c, 0x81, 0x70, 0x64, 0x4f, 0x98, 0xb6, 0xc2, 0x6d, 0x11, 0x1b, 0xee,
-	0x83, 0xc8, 0x19, 0xbd, 0xba, 0xd0, 0x88, 0xf4, 0x01, 0xdf, 0x91, 0x8f, 0x20, 0x7f, 0x82, 0x01,
-	0xae, 0xdd, 0xe1, 0xb7, 0x5f, 0x5b, 0xb8, 0x3d, 0x0f, 0x7f, 0x2a, 0x98, 0xb0, 0x66, 0xf3, 0x85,
-	0xc5, 0xdc, 0x63, 0x2f, 0x18, 0xb1, 0xb1, 0xb6, 0xc6, 0xb5, 0x55, 0x39, 0xaa, 0x4b, 0x10, 0xef,
-	0x33, 0xf2, 0xce, 0xfc, 0x80, 0x85, 0x58, 0xc0, 0xd6, 0x39, 0x4b, 0x02, 0x21, 0x1b, 0x50, 0x1a,
-	0xd9, 0xe1, 0xc8, 0x1e, 0xb3, 0xb1, 0xa6, 0x71, 0xea, 0x6c, 0x4f, 0x34, 0x28, 0x7e, 0xed, 0x4d,
-	0x03, 0xd7, 0x9e, 0x68, 0x77, 0x39, 0x29, 0xde, 0x62, 0xb6, 0xbb, 0xc7, 0xa1, 0xb6, 0xc1, 0x51,
-	0x5c, 0xfe, 0xe7, 0x45, 0xa1, 0x0e, 0x70, 0xf9, 0xba, 0xc8, 0xe7, 0x7a, 0x63, 0x16, 0x6a, 0xca,
-	0x66, 0x16, 0xf9, 0xf8, 0xa6, 0xfe, 0x9d, 0x02, 0xcb, 0x74, 0xea, 0x62, 0x4b, 0x37, 0x22, 0x3b,
-	0x62, 0x7d, 0xdb, 0x27, 0xcf, 0xa0, 0x1a, 0x08, 0xc8, 0x0a, 0x11, 0xe3, 0x12, 0x95, 0x9d, 0x9d,
-	0xc5, 0xd8, 0x49, 0x0b, 0xa6, 0xf6, 0x32, 0x54, 0x83, 0x04, 0x84, 0x37, 0x5a, 0x60, 0xb9, 0xd1,
-	0x8d, 0xfe, 0x54, 0x82, 0x82, 0xf0, 0xc9, 0xc2, 0x80, 0xf1, 0x18, 0x0a, 0x62, 0xf4, 0xe0, 0x52,
-	0x95, 0x2b, 0x2a, 0x8e, 0x28, 0x90, 0x54, 0xb2, 0x5d, 0xc6, 0x46, 0xf6, 0x5d, 0x62, 0x63, 0x03,
-	0x4a, 0x38, 0x26, 0x78, 0xee, 0xe4, 0x42, 0x4e, 0x1d, 0xb3, 0x3d, 0x79, 0x02, 0xc5, 0x89, 0x28,
-	0xf4, 0xbc, 0x36, 0x55, 0xae, 0x68, 0xa0, 0xa9, 0x76, 0x40, 0x63, 0x76, 0xf2, 0x31, 0xe4, 0x47,
-	0xe8, 0x0e, 0x5e, 0xbd, 0xde, 0xdc, 0xfa, 0x05, 0x23, 0x79, 0x0c, 0xb9, 0xd0, 0x67, 0x23, 0x5e,
-	0xbb, 0xae, 0x4a, 0xe7, 0xcb, 0xc2, 0x41, 0x39, 0x23, 0x3a, 0x73, 0x1a, 0xda, 0x27, 0x4c, 0x76,
-	0x5a, 0xb1, 0x49, 0xcf, 0x1d, 0xe5, 0x77, 0x9f, 0x3b, 0x12, 0x85, 0x1d, 0xde, 0xad, 0xb0, 0x7f,
-	0x8a, 0xa9, 0x69, 0x47, 0xd3, 0x90, 0x97, 0xa7, 0xda, 0xce, 0x83, 0xeb, 0x4c, 0xe6, 0x4c, 0x54,
-	0x32, 0x93, 0x1d, 0xc8, 0x8b, 0xd8, 0x5b, 0xe2, 0x52, 0xf7, 0xdf, 0x20, 0xc5, 0xa8, 0x60, 0x25,
-	0x8f, 0xa0, 0x62, 0x47, 0x91, 0x8d, 0xa5, 0xc2, 0xf2, 0x5c, 0x5e, 0xad, 0xca, 0x14, 0x62, 0x68,
-	0xe8, 0x92, 0x16, 0xd4, 0x66, 0x0c, 0x42, 0x7b, 0xed, 0x1a, 0xed, 0x4d, 0xce, 0x26, 0xb4, 0x57,
-	0x63, 0x19, 0x23, 0x3e, 0x65, 0xcc, 0xce, 0x9d, 0x11, 0xb3, 0xf8, 0x40, 0x2b, 0xeb, 0x99, 0x80,
-	0x0e, 0x70, 0xac, 0xfd, 0x08, 0x48, 0xc8, 0x46, 0xd3, 0x80, 0x59, 0x49, 0xbe, 0xb8, 0xa0, 0x71,
-	0x4a, 0xfb, 0x92, 0x7b, 0x66, 0xb4, 0x60, 0x5b, 0xe1, 0xc9, 0x29, 0x8d, 0xe6, 0x0c, 0xfb, 0x33,
-	0x06, 0xc7, 0x3d, 0xf6, 0x34, 0xc2, 0x73, 0xf1, 0xc3, 0x6b, 0xfc, 0x21, 0x0d, 0xef, 0xba, 0xc7,
-	0x9e, 0x48, 0x40, 0xa9, 0x09, 0x01, 0xf2, 0x13, 0x58, 0x4a, 0x74, 0x84, 0x50, 0xbb, 0xcd, 0x55,
-	0xbd, 0xb1, 0x25, 0x54, 0x2e, 0x5b, 0x42, 0x48, 0xf4, 0xf9, 0xba, 0xb0, 0xca, 0x15, 0x6c, 0xbe,
-	0xad, 0x2e, 0xa4, 0xab, 0x00, 0x46, 0x24, 0x0b, 0x02, 0x2f, 0xe0, 0x45, 0xb9, 0x4c, 0xc5, 0x66,
-	0xe3, 0xc7, 0xb0, 0x3c, 0x67, 0xfb, 0x8d, 0x2a, 0xc3, 0x9f, 0x33, 0x90, 0x47, 0xf5, 0x21, 0xf2,
-	0x60, 0x66, 0x86, 0x5c, 0x2e, 0x47, 0xc5, 0x86, 0xac, 0x43, 0x11, 0x17, 0xd6, 0x59, 0x28, 0xe7,
-	0x94, 0x02, 0x6e, 0xfb, 0x21, 0x0e, 0x1e, 0x9c, 0xf0, 0xf2, 0x22, 0x62, 0x21, 0xaf, 0x05, 0x39,
-	0x5a, 0x46, 0x64, 0x17, 0x01, 0xec, 0x2c, 0xfc, 0xdb, 0x21, 0xe4, 0x59, 0x9f, 0xa3, 0x72, 0x87,
-	0x03, 0x09, 0x5f, 0xa1, 0x42, 0xf1, 0xbd, 0x51, 0xe4, 0xfb, 0x7e, 0x88, 0x2f, 0x2a, 0x48, 0x42,
-	0x65, 0x81, 0x53, 0x81, 0x43, 0x42, 0xe7, 0x23, 0xa8, 0x88, 0x29, 0xe4, 0x04, 0x3b, 0x86, 0x9c,
-	0x8d, 0x81, 0x8f, 0x1a, 0x1c, 0x21, 0xb7, 0x21, 0xef, 0x78, 0xa8, 0xb9, 0x14, 0x7f, 0xc9, 0x08,
-	0x43, 0xb9, 0x42, 0x8b, 0x7f, 0x6b, 0x88, 0xef, 0x8f, 0x32, 0x47, 0xf8, 0xf0, 0x8c, 0x4a, 0xe5,
-	0x98, 0x81, 0x92, 0x20, 0x95, 0x4a, 0xa8, 0x1f, 0xd6, 0xff, 0x99, 0x81, 0x7c, 0x73, 0xc2, 0x82,
-	0x28, 0x51, 0x3a, 0xb3, 0xbc, 0x74, 0x7e, 0x8e, 0x9f, 0x41, 0xe7, 0x2c, 0x70, 0xa2, 0x0b, 0xf9,
-	0xc1, 0xb0, 0x98, 0xa4, 0x86, 0x64, 0xe0, 0xb9, 0x3d, 0x63, 0x47, 0xa3, 0x6c, 0xd4, 0x69, 0x45,
-	0x17, 0x3e, 0xe3, 0xde, 0xcb, 0xd2, 0x32, 0x47, 0x90, 0x11, 0xdb, 0xdd, 0x19, 0x0b, 0x79, 0xf9,
-	0x11, 0xdf, 0x07, 0xf1, 0x96, 0x3c, 0x81, 0xf2, 0xec, 0x33, 0x52, 0x56, 0xcd, 0x37, 0x15, 0xa0,
-	0x4b, 0x66, 0xbc, 0x68, 0x20, 0xbf, 0x32, 0x2d, 0x67, 0xcc, 0xdd, 0x5b, 0xc6, 0xd1, 0x45, 0x40,
-	0x5d, 0x7e, 0x9d, 0x78, 0x27, 0x47, 0xbc, 0x07, 0x57, 0x84, 0xb8, 0x60, 0x10, 0xd7, 0x89, 0xd9,
-	0xd1, 0xde, 0xd1, 0x84, 0xf1, 0x61, 0x4a, 0x4c, 0x79, 0xf1, 0x16, 0x63, 0x31, 0x8a, 0x26, 0xd2,
-	0xed, 0xb8, 0xc4, 0xab, 0x4f, 0x5d, 0xe7, 0xf5, 0x94, 0x59, 0x91, 0x7d, 0xc2, 0xfd, 0x5d, 0xa6,
-	0x65, 0x81, 0x98, 0xf6, 0x49, 0xfd, 0x33, 0x28, 0x70, 0x6f, 0x87, 0xd8, 0x68, 0xb8, 0x47, 0x64,
-	0x1b, 0x5d, 0x6c, 0x34, 0x9c, 0x8f, 0x0a, 0xa6, 0xfa, 0x5f, 0x14, 0xb8, 0x2d, 0x72, 0xb9, 0x15,
-	0x30, 0x2c, 0x3f, 0xec, 0xf5, 0x94, 0x85, 0x51, 0xb2, 0xc9, 0x28, 0x37, 0x6b, 0x32, 0x37, 0xee,
-	0x8c, 0x71, 0x8f, 0xc9, 0xbe, 0x63, 0x8f, 0xa9, 0x7f, 0x00, 0x35, 0x81, 0x51, 0x16, 0xfa, 0x9e,
-	0x1b, 0x26, 0x72, 0x5c, 0x49, 0xe4, 0x78, 0xdd, 0x87, 0xd5, 0xf4, 0xd5, 0x24, 0xf7, 0x7c, 0x2f,
-	0xdf, 0x87, 0x65, 0x39, 0x26, 0x07, 0x92, 0x45, 0x9a, 0xfe, 0xe8, 0x1a, 0x5b, 0x62, 0x4d, 0xb4,
-	0x76, 0x9e, 0xda, 0xd7, 0xbf, 0x57, 0xe2, 0x21, 0x8a, 0xd7, 0x9e, 0xe6, 0x08, 0x87, 0x56, 0xf2,
-	0x14, 0x0a, 0xa2, 0x2c, 0xf2, 0x33, 0x6b, 0x3b, 0xf5, 0x6b, 0xd4, 0x0a, 0xf6, 0x03, 0x3b, 0xb0,
-	0xcf, 0xa8, 0x94, 0x20, 0x4f, 0x20, 0x7f, 0xe6, 0x4d, 0xdd, 0x48, 0x66, 0xca, 0xbb, 0x88, 0x0a,
-	0x01, 0x0c, 0x18, 0xbe, 0x10, 0x85, 0x3e, 0x2b, 0x02, 0x86, 0x23, 0x71, 0x23, 0x48, 0xf6, 0x8b,
-	0xdc, 0x7c, 0x5f, 0xa9, 0xff, 0x2d, 0x03, 0xaa, 0xbc, 0x0b, 0x8b, 0x7e, 0x88, 0xb0, 0x10, 0xaf,
-	0x9c, 0x79, 0xd7, 0x49, 0x02, 0xbd, 0xc6, 0x6f, 0x25, 0x03, 0xa3, 0xfe, 0xa6, 0x9e, 0x2c, 0xee,
-	0x4f, 0xa5, 0x04, 0xd9, 0x87, 0xa2, 0xe7, 0xe3, 0x0a, 0xeb, 0x28, 0x66, 0xc1, 0xf6, 0x75, 0xc2,
-	0xb3, 0xab, 0x6d, 0x0f, 0x85, 0x80, 0xe8, 0x63, 0xb1, 0xf8, 0xc6, 0x53, 0x58, 0x4a, 0x12, 0x6e,
-	0xd4, 0x24, 0x7e, 0x77, 0x19, 0x0d, 0x78, 0x8c, 0x8c, 0xbe, 0xc7, 0x50, 0x10, 0x51, 0x23, 0x3d,
-	0xb8, 0x7e, 0x5d, 0x90, 0x49, 0xb6, 0x1f, 0x30, 0x3c, 0x2f, 0x60, 0xc5, 0x70, 0x6d, 0x3f, 0x9d,
-	0xe9, 0xf3, 0xd9, 0x90, 0x78, 0xe2, 0xcc, 0xcd, 0x9e, 0x38, 0x39, 0xb4, 0x66, 0xd3, 0x43, 0x6b,
-	0xfd, 0x35, 0x90, 0xe4, 0xd1, 0xd2, 0x17, 0xbf, 0x80, 0x35, 0x79, 0xb5, 0x11, 0x27, 0x5c, 0xde,
-	0x50, 0xf8, 0xe6, 0xfd, 0x6b, 0x8e, 0x4e, 0xab, 0xa1, 0xab, 0xe7, 0x57, 0xa0, 0xf5, 0x28, 0xfe,
-	0x51, 0x81, 0x4f, 0x23, 0xf7, 0xa0, 0x2c, 0x8f, 0x9a, 0xdd, 0xb6, 0x24, 0x80, 0xee, 0xd5, 0x3f,
-	0x17, 0x7e, 0x0a, 0x45, 0x79, 0xf0, 0xbb, 0x54, 0xa6, 0x98, 0xb7, 0x3e, 0x06, 0xb2, 0x17, 0xd8,
-	0xfe, 0x69, 0x3b, 0x70, 0xce, 0x59, 0xd0, 0x3a, 0xb5, 0xdd, 0x13, 0x16, 0xce, 0x0e, 0x50, 0x12,
-	0x07, 0x3c, 0x85, 0xdc, 0x2b, 0xc7, 0x1d, 0xcb, 0xcc, 0xfe, 0xe0, 0x8a, 0x0f, 0x82, 0x39, 0x35,
-	0xbc, 0x7b, 0x70, 0x99, 0xfa, 0x87, 0xb0, 0xdc, 0x9a, 0x4c, 0xc3, 0x88, 0x05, 0x6f, 0xa9, 0x81,
-	0x7f, 0x54, 0xa0, 0x8a, 0xc9, 0x71, 0x3e, 0x7b, 0xef, 0x7d, 0x28, 0x51, 0xf6, 0x9a, 0x85, 0xd1,
-	0x17, 0x47, 0xb2, 0x45, 0x7c, 0xb4, 0xd8, 0x22, 0x92, 0x12, 0xdb, 0x31, 0xbb, 0x48, 0x8d, 0x99,
-	0xf4, 0xc6, 0xff, 0x43, 0x35, 0x45, 0x4a, 0x26, 0x47, 0xf6, 0x6d, 0xc9, 0xf1, 0x0d, 0xd4, 0x52,
-	0xa7, 0x84, 0xa4, 0x0e, 0x4b, 0x72, 0xdd, 0xe2, 0x15, 0x4f, 0xa8, 0x49, 0x61, 0xa4, 0x3d, 0x77,
-	0x1b, 0xf9, 0x83, 0xd8, 0xc3, 0x37, 0xdf, 0x80, 0xa6, 0x85, 0x1a, 0xdf, 0x67, 0xa0, 0x20, 0x3e,
-	0x00, 0xc8, 0x32, 0x54, 0x0c, 0xb3, 0x69, 0x1e, 0x1a, 0xd6, 0x60, 0x38, 0xd0, 0xd5, 0x5b, 0x09,
-	0xa0, 0x3b, 0xe8, 0x9a, 0xaa, 0x42, 0xaa, 0x50, 0x96, 0xc0, 0xf0, 0x0b, 0x35, 0x43, 0x08, 0xd4,
-	0xe2, 0x6d, 0xa7, 0xd3, 0xeb, 0x0e, 0x74, 0x35, 0x4b, 0x54, 0x58, 0x92, 0x98, 0x4e, 0xe9, 0x90,
-	0xaa, 0x39, 0xa2, 0xc1, 0xea, 0x4c, 0xad, 0x69, 0x75, 0x07, 0xd6, 0x97, 0x87, 0x43, 0x7a, 0xd8,
-	0x57, 0xf3, 0x64, 0x1d, 0x6e, 0x4b, 0x4a, 0x5b, 0x6f, 0x0d, 0xfb, 0xfd, 0xae, 0x61, 0x74, 0x87,
-	0x03, 0xb5, 0x40, 0xd6, 0x80, 0x48, 0x42, 0xbf, 0xd9, 0x1d, 0x98, 0xfa, 0xa0, 0x39, 0x68, 0xe9,
-	0x6a, 0x31, 0x21, 0x60, 0x98, 0x43, 0xda, 0xdc, 0xd3, 0xad, 0xf6, 0xf0, 0xd9, 0x40, 0x2d, 0x91,
-	0x7b, 0xb0, 0x3e, 0x4f, 0xd0, 0xf7, 0x68, 0xb3, 0xad, 0xb7, 0xd5, 0x72, 0x42, 0x6a, 0xa0, 0xeb,
-	0x6d, 0xc3, 0xa2, 0xfa, 0xee, 0x70, 0x68, 0xaa, 0x40, 0xee, 0x83, 0x36, 0x27, 0x45, 0xf5, 0xdd,
-	0x66, 0x8f, 0x1f, 0x56, 0x21, 0x9b, 0x70, 0x7f, 0x5e, 0x27, 0xed, 0x1e, 0x21, 0xcf, 0x41, 0xaf,
-	0xd9, 0xd2, 0xd5, 0x25, 0x52, 0x03, 0x98, 0x99, 0xf9, 0x5c, 0xad, 0x36, 0xbe, 0x55, 0x00, 0x44,
-	0x90, 0xf2, 0x11, 0x6c, 0x15, 0x54, 0x2e, 0x41, 0x2d, 0xf3, 0xc5, 0x81, 0x1e, 0x3b, 0x75, 0x0e,
-	0xed, 0x74, 0x7b, 0xba, 0xaa, 0x90, 0x3b, 0xb0, 0x92, 0x44, 0x77, 0x7b, 0xc3, 0x16, 0x7a, 0x78,
-	0x0d, 0x48, 0x12, 0x1e, 0xee, 0xfe, 0x5c, 0x6f, 0x99, 0x6a, 0x96, 0xdc, 0x85, 0x3b, 0x49, 0xbc,
-	0xd5, 0x3b, 0x34, 0x4c, 0x9d, 0xea, 0x6d, 0x35, 0x37, 0xaf, 0x69, 0x8f, 0x36, 0x0f, 0xf6, 0xd5,
-	0x7c, 0xe3, 0x0f, 0x0a, 0x14, 0xc4, 0xf7, 0x21, 0x3e, 0x51, 0xc7, 0x48, 0xd9, 0xb4, 0x02, 0xd5,
-	0x18, 0xd9, 0x35, 0x69, 0xc7, 0x50, 0x95, 0x24, 0x93, 0xfe, 0xdc, 0xfc, 0x44, 0xcd, 0x24, 0x91,
-	0xce, 0xa1, 0x81, 0x6f, 0xbd, 0x0c, 0x95, 0x99, 0xa2, 0x8e, 0xa1, 0xe6, 0x92, 0xc0, 0x51, 0xc7,
-	0x50, 0xf3, 0x49, 0xe0, 0x79, 0xc7, 0x50, 0x0b, 0x49, 0xe0, 0xab, 0x8e, 0xa1, 0x16, 0x1b, 0xdf,
-	0x29, 0x70, 0xe7, 0xca, 0xec, 0x26, 0xef, 0xc1, 0x03, 0x6e, 0xbc, 0x25, 0xaf, 0xd3, 0xda, 0x6f,
-	0x0e, 0xf6, 0xf4, 0x94, 0xdd, 0xef, 0xc3, 0x7b, 0xd7, 0xb2, 0xf4, 0x87, 0xed, 0x6e, 0xa7, 0xab,
-	0xb7, 0x55, 0x85, 0xd4, 0xe1, 0xe1, 0xb5, 0x6c, 0xcd, 0x36, 0x06, 0x49, 0x86, 0xfc, 0x17, 0x6c,
-	0x5e, 0xcb, 0xd3, 0xd6, 0x7b, 0xba, 0xa9, 0xb7, 0xd5, 0x6c, 0x23, 0x82, 0xa5, 0xe4, 0x38, 0xce,
-	0x03, 0x55, 0x3f, 0xd2, 0x69, 0xd7, 0x7c, 0x91, 0x32, 0x0c, 0x43, 0x2e, 0x85, 0x37, 0x7b, 0x4d,
-	0xda, 0x57, 0x15, 0x7c, 0xb8, 0x34, 0xe1, 0x59, 0x93, 0x0e, 0xba, 0x83, 0x3d, 0x35, 0xc3, 0xf3,
-	0x64, 0x4e, 0x97, 0xd9, 0xed, 0xbc, 0x50, 0xb3, 0x8d, 0xdf, 0x2a, 0x58, 0x0e, 0x2e, 0xc7, 0x66,
-	0x3c, 0x96, 0xea, 0xc6, 0xf0, 0x90, 0xb6, 0xd2, 0xfe, 0xd0, 0x60, 0x35, 0x8d, 0x1f, 0x0d, 0x7b,
-	0x87, 0x7d, 0x8c, 0xaf, 0x2b, 0x24, 0xda, 0xba, 0x9a, 0x41, 0x7b, 0xd2, 0xb8, 0x0c, 0x25, 0x35,
-	0x8b, 0x77, 0x48, 0x93, 0xb8, 0x67, 0xd4, 0x5c, 0xe3, 0x37, 0x0a, 0x2c, 0xf3, 0xc1, 0x59, 0x4c,
-	0x1a, 0xdc, 0xa2, 0x0d, 0x58, 0x6b, 0xf6, 0x74, 0x6a, 0x5a, 0xcd, 0x96, 0xd9, 0x1d, 0x0e, 0x52,
-	0x56, 0xdd, 0x07, 0x6d, 0x91, 0x26, 0x7c, 0xaa, 0x2a, 0x57, 0x53, 0x5b, 0x54, 0x6f, 0x9a, 0x68,
-	0xdf, 0x95, 0xd4, 0xc3, 0x83, 0x36, 0x52, 0xb3, 0x8d, 0xaf, 0xe3, 0xa1, 0x22, 0x31, 0xf3, 0xa1,
-	0x88, 0xb8, 0x76, 0x2c, 0x73, 0xd0, 0xa4, 0xcd, 0x7e, 0x6c, 0xcc, 0x3d, 0x58, 0xbf, 0x8a, 0x3a,
-	0xec, 0x74, 0x54, 0x05, 0x6f, 0x71, 0x25, 0x71, 0xa0, 0x66, 0x1a, 0x3b, 0x50, 0x94, 0x3f, 0x68,
-	0x93, 0x12, 0xe4, 0xa4, 0xb6, 0x22, 0x64, 0x7b, 0xc3, 0x67, 0xaa, 0x42, 0x00, 0x0a, 0x7d, 0xbd,
-	0xdd, 0x3d, 0xec, 0xab, 0x19, 0x24, 0xef, 0x77, 0xf7, 0xf6, 0xd5, 0x6c, 0xe3, 0xd7, 0x50, 0x9e,
-	0xfd, 0xa2, 0x8d, 0xae, 0xee, 0x0e, 0xad, 0x03, 0x3a, 0xc4, 0x94, 0xb7, 0x0c, 0xfd, 0xcb, 0x43,
-	0x7d, 0x60, 0x76, 0x9b, 0x3d, 0xf5, 0x16, 0xe6, 0x6c, 0x82, 0x44, 0x9b, 0x83, 0xf6, 0x10, 0x83,
-	0x65, 0x05, 0xaa, 0x09, 0xb8, 0xbd, 0x2b, 0x82, 0x24, 0x05, 0x59, 0x54, 0xef, 0x0f, 0xd1, 0x17,
-	0x58, 0x8c, 0x13, 0x94, 0x56, 0xdf, 0x50, 0x73, 0x8d, 0x6f, 0x33, 0x50, 0x49, 0x4c, 0x86, 0x78,
-	0x8e, 0xbc, 0x1f, 0xd6, 0xad, 0x64, 0xd8, 0xa4, 0xe0, 0x03, 0x7d, 0xd0, 0xc6, 0x98, 0x4c, 0x3a,
-	0x44, 0x50, 0x9a, 0x47, 0xcd, 0x6e, 0xaf, 0xb9, 0xdb, 0x93, 0xa1, 0x93, 0xa6, 0x99, 0x66, 0xb3,
-	0xb5, 0x8f, 0x69, 0xb2, 0x40, 0x6a, 0xeb, 0x92, 0x94, 0x4b, 0xf8, 0xff, 0x92, 0x64, 0xb6, 0xf6,
-	0xf1, 0xb8, 0x3c, 0x46, 0x69, 0x8a, 0x28, 0x5a, 0x48, 0x61, 0xc1, 0xc0, 0x38, 0x21, 0x8b, 0xe4,
-	0x21, 0x6c, 0xa4, 0x28, 0x26, 0x7d, 0x21, 0x4f, 0x43, 0x8d, 0xa5, 0x05, 0x49, 0xaa, 0x63, 0x31,
-	0xd7, 0xd5, 0x72, 0xe3, 0xf7, 0x0a, 0x2c, 0x25, 0x7f, 0xff, 0x9a, 0x3b, 0xfc, 0xb2, 0x0b, 0x3e,
-	0x80, 0xbb, 0xf3, 0xb8, 0x69, 0x1d, 0x50, 0xdd, 0xd0, 0x07, 0xd8, 0x13, 0x57, 0x41, 0x4d, 0x93,
-	0x0f, 0x0f, 0x44, 0xe1, 0x4e, 0xa3, 0xbc, 0x51, 0x65, 0xe7, 0x1c, 0xca, 0x3b, 0x9f, 0xec, 0x53,
-	0xb9, 0xc6, 0x2f, 0xa1, 0x9a, 0xfa, 0x6f, 0x9f, 0xe8, 0x6a, 0xa2, 0xf5, 0x88, 0xe0, 0xb2, 0xfa,
-	0xcd, 0xbd, 0x81, 0x6e, 0x76, 0x5b, 0xea, 0x2d, 0xd1, 0x23, 0x53, 0x44, 0xc3, 0xc0, 0x62, 0xc7,
-	0xbb, 0x5d, 0x0a, 0x1f, 0x1c, 0xf5, 0x75, 0x35, 0xd3, 0xd8, 0x82, 0xaa, 0x9c, 0x93, 0x06, 0x5e,
-	0xe4, 0x1c, 0x5f, 0x20, 0xa7, 0xcc, 0x76, 0x59, 0x6a, 0x84, 0x91, 0xb7, 0x1a, 0x0c, 0x2a, 0x89,
-	0x5f, 0xe1, 0xf0, 0x35, 0xc5, 0xdb, 0xc6, 0xaf, 0xf2, 0xdc, 0xd4, 0xe9, 0x80, 0x07, 0xee, 0x3c,
-	0x09, 0x9b, 0x35, 0x27, 0x29, 0xd8, 0x3e, 0xaf, 0x24, 0x59, 0xc6, 0xb3, 0xae, 0xd9, 0xda, 0x57,
-	0x33, 0x0d, 0x13, 0x6a, 0x43, 0x9f, 0x05, 0xfc, 0xbf, 0x19, 0x9d, 0x89, 0x7d, 0x12, 0xa2, 0x2f,
-	0x87, 0x07, 0x56, 0xa7, 0xd7, 0xdc, 0x33, 0xac, 0xc3, 0xc1, 0x17, 0x03, 0x6e, 0x0e, 0xa6, 0xc1,
-	0x0c, 0xe5, 0x6f, 0xc2, 0xcb, 0xe8, 0x0c, 0x12, 0xcf, 0x6d, 0x75, 0x86, 0xb4, 0xa5, 0xab, 0x99,
-	0xdd, 0xfb, 0x70, 0x7b, 0xe4, 0x9d, 0xcd, 0x0f, 0x41, 0x07, 0xca, 0x57, 0x59, 0xdb, 0x77, 0x5e,
-	0x16, 0xf8, 0x2f, 0x1c, 0xff, 0xfb, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0x50, 0xea, 0xd0, 0xb2,
-	0x7a, 0x1f, 0x00, 0x00,
+	// 3126 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0x4b, 0x73, 0xe3, 0x48,
+	0x72, 0x1e, 0xf0, 0xcd, 0xa4, 0x48, 0x41, 0xd5, 0x1a, 0x35, 0x5a, 0xdd, 0xd3, 0xa3, 0x65, 0x78,
+	0x76, 0x15, 0xf4, 0x58, 0xbd, 0x21, 0xef, 0xac, 0x67, 0xc6, 0x4f, 0x8a, 0x04, 0x25, 0x7a, 0xf8,
+	0xd0, 0x16, 0x40, 0x75, 0xf7, 0x3a, 0x1c, 0x08, 0x34, 0x58, 0x92, 0xb0, 0x22, 0x09, 0x34, 0x00,
+	0x6a, 0x43, 0x7b, 0xf0, 0xd5, 0x17, 0x87, 0x7d, 0xb2, 0x1d, 0x7b, 0xf2, 0x0f, 0x58, 0x5f, 0x7c,
+	0xf6, 0xc1, 0x07, 0x9f, 0xf6, 0xe2, 0xff, 0xe2, 0x7f, 0xe0, 0xc8, 0xaa, 0x02, 0x09, 0x90, 0xe2,
+	0x48, 0x0a, 0xcf, 0x49, 0x55, 0x5f, 0x3e, 0x2a, 0x2b, 0x91, 0xaf, 0xa2, 0xa0, 0x6a, 0xfb, 0xee,
+	0x1b, 0xdb, 0x77, 0x8f, 0xfc, 0xc0, 0x8b, 0x3c, 0xb2, 0xed, 0xf9, 0x6c, 0x16, 0x46, 0x5e, 0x60,
+	0x5f, 0xb1, 0x23, 0xdb, 0x77, 0xf7, 0x3f, 0xbf, 0xf2, 0xbc, 0xab, 0x09, 0x7b, 0xc3, 0xc9, 0x1f,
+	0xe6, 0x97, 0x6f, 0x22, 0x77, 0xca, 0xc2, 0xc8, 0x9e, 0xfa, 0x42, 0xa2, 0xfe, 0xbf, 0x19, 0xd8,
+	0x36, 0x84, 0x00, 0x65, 0xa1, 0x37, 0x0f, 0x1c, 0x46, 0x6a, 0x90, 0x71, 0xc7, 0x9a, 0x72, 0xa0,
+	0x1c, 0x96, 0x69, 0xc6, 0x1d, 0x13, 0x02, 0x39, 0xdf, 0x8e, 0xae, 0xb5, 0x0c, 0x47, 0xf8, 0x9a,
+	0xfc, 0x1c, 0x0a, 0x53, 0x36, 0x76, 0xe7, 0x53, 0x2d, 0x7b, 0xa0, 0x1c, 0xd6, 0x8e, 0x5f, 0x1f,
+	0xad, 0x1c, 0x7d, 0x24, 0xb5, 0xf6, 0x39, 0x17, 0x95, 0xdc, 0x64, 0x0f, 0x0a, 0xde, 0x6c, 0xe2,
+	0xce, 0x98, 0x96, 0x3b, 0x50, 0x0e, 0x4b, 0x54, 0xee, 0xf0, 0x0c, 0xd7, 0xf3, 0x43, 0x2d, 0x7f,
+	0xa0, 0x1c, 0xe6, 0x28, 0x5f, 0x93, 0x97, 0x50, 0x0e, 0xd9, 0x47, 0xeb, 0xd7, 0x81, 0x1b, 0x31,
+	0xad, 0x70, 0xa0, 0x1c, 0x2a, 0xb4, 0x14, 0xb2, 0x8f, 0x6f, 0x71, 0x4f, 0x5e, 0x00, 0xae, 0xad,
+	0x80, 0xd9, 0x63, 0xad, 0xc8, 0x69, 0xc5, 0x90, 0x7d, 0xa4, 0xcc, 0x1e, 0xe3, 0x19, 0x81, 0x3d,
+	0x1b, 0xd3, 0xb7, 0x5a, 0x89, 0x13, 0xe4, 0x0e, 0xcf, 0x08, 0xdd, 0xdf, 0x30, 0xad, 0x2c, 0xce,
+	0xc0, 0x35, 0x62, 0xf3, 0x90, 0x8d, 0x35, 0x10, 0x18, 0xae, 0xc9, 0x17, 0x50, 0x0b, 0xbc, 0xc8,
+	0x8e, 0x5c, 0x6f, 0x66, 0x85, 0x3e, 0x63, 0x63, 0xad, 0xc2, 0x6f, 0x5e, 0x8d, 0x51, 0x03, 0x41,
+	0xf2, 0x27, 0x50, 0x9e, 0xd8, 0x61, 0x64, 0x85, 0x8e, 0x3d, 0xd3, 0xb6, 0x0e, 0x94, 0xc3, 0xca,
+	0xf1, 0xfe, 0x91, 0xf0, 0xf7, 0x51, 0xec, 0xef, 0x23, 0x33, 0xf6, 0x37, 0x2d, 0x21, 0xb3, 0xe1,
+	0xd8, 0xb3, 0xfa, 0xff, 0x64, 0xa0, 0x22, 0xbd, 0x73, 0xee, 0x79, 0x13, 0xf4, 0x77, 0xb7, 0xcd,
+	0xfd, 0x9d, 0xa7, 0x99, 0x6e, 0x9b, 0x34, 0x20, 0xdb, 0xf2, 0x42, 0xee, 0xee, 0xda, 0xb1, 0xb6,
+	0xe6, 0xd8, 0x96, 0x17, 0x9a, 0x77, 0x3e, 0xa3, 0xc8, 0x84, 0xdf, 0xa1, 0xff, 0xa4, 0xef, 0x20,
+	0xfe, 0x92, 0x57, 0x50, 0xa6, 0xb6, 0x3b, 0xee, 0xb1, 0x5b, 0x36, 0xe1, 0x9f, 0xa2, 0x4c, 0x97,
+	0x00, 0x52, 0x4d, 0x2f, 0xb2, 0x27, 0x06, 0xba, 0xab, 0xc8, 0x5d, 0xb3, 0x04, 0xd0, 0x67, 0x23,
+	0xf4, 0x59, 0x49, 0xf8, 0x0c, 0xd7, 0xe4, 0xaf, 0xa0, 0x30, 0xb1, 0x3f, 0xb0, 0x49, 0xa8, 0x95,
+	0x0f, 0xb2, 0x87, 0x95, 0xe3, 0xc3, 0x4d, 0x76, 0xe0, 0x8d, 0x8f, 0x7a, 0x9c, 0x55, 0x9f, 0x45,
+	0xc1, 0x1d, 0x95, 0x72, 0xfb, 0xdf, 0x40, 0x25, 0x01, 0x13, 0x15, 0xb2, 0x37, 0xec, 0x4e, 0x46,
+	0x21, 0x2e, 0xc9, 0x2e, 0xe4, 0x6f, 0xed, 0xc9, 0x9c, 0xc9, 0x38, 0x14, 0x9b, 0x6f, 0x33, 0x5f,
+	0x2b, 0xf5, 0xff, 0x54, 0xa0, 0x7a, 0xe1, 0x4d, 0xe6, 0x53, 0xd6, 0xf3, 0x1c, 0x3b, 0xf2, 0x02,
+	0x34, 0x71, 0x66, 0x4f, 0x99, 0x14, 0xe7, 0x6b, 0x32, 0x82, 0xea, 0x2d, 0x67, 0xb2, 0xa4, 0xa5,
+	0x19, 0x6e, 0xe9, 0x4f, 0xd7, 0x2c, 0x4d, 0xa9, 0x8a, 0x77, 0x09, 0x8b, 0xb7, 0x6e, 0x13, 0xd0,
+	0xfe, 0x5f, 0xc2, 0xce, 0x1a, 0xcb, 0x93, 0xac, 0xff, 0x19, 0x14, 0x0c, 0x91, 0x78, 0x7b, 0x50,
+	0xf0, 0xed, 0x80, 0xcd, 0x22, 0x29, 0x28, 0x77, 0x3c, 0x70, 0x31, 0x0c, 0x65, 0x02, 0xe2, 0xba,
+	0xfe, 0x1c, 0xf2, 0xa7, 0x81, 0x37, 0xf7, 0x57, 0xb3, 0xb5, 0xfe, 0xdf, 0x45, 0x00, 0x61, 0x90,
+	0xe1, 0x33, 0x07, 0x3f, 0x25, 0xf3, 0xaf, 0xd9, 0x94, 0x05, 0xf6, 0x84, 0x73, 0x95, 0xe8, 0x12,
+	0x58, 0xa4, 0x44, 0x26, 0x91, 0x12, 0x6f, 0xa0, 0x70, 0xe9, 0x05, 0x53, 0x3b, 0x92, 0x21, 0xf5,
+	0x7c, 0xcd, 0x41, 0x1d, 0x83, 0x07, 0xa0, 0x64, 0x23, 0x9f, 0x01, 0x7c, 0x98, 0x78, 0xce, 0x8d,
+	0xc5, 0x55, 0x61, 0x30, 0x65, 0x69, 0x99, 0x23, 0x3c, 0x5c, 0x5e, 0x40, 0xe9, 0xda, 0xb6, 0x26,
+	0x3c, 0xd2, 0xf2, 0x9c, 0x58, 0xbc, 0xb6, 0x45, 0x9c, 0x35, 0x20, 0xeb, 0x78, 0xa1, 0x56, 0x78,
+	0x28, 0xd2, 0x1d, 0x2f, 0x24, 0xdf, 0x00, 0xb8, 0x9e, 0xe5, 0x07, 0xde, 0xa5, 0x3b, 0x11, 0x41,
+	0x59, 0x3b, 0xde, 0x5f, 0x13, 0xe9, 0x7a, 0xe7, 0x82, 0x83, 0x96, 0xdd, 0x78, 0x89, 0x7e, 0x1d,
+	0xb3, 0xf1, 0xdc, 0x67, 0x3c, 0x64, 0x4b, 0x54, 0xee, 0xc8, 0x1f, 0xc2, 0x4e, 0x38, 0xb3, 0xfd,
+	0xf0, 0xda, 0x8b, 0x2c, 0x77, 0x16, 0xb1, 0xe0, 0xd6, 0x9e, 0xf0, 0xea, 0x50, 0xa5, 0x6a, 0x4c,
+	0xe8, 0x4a, 0x9c, 0xd0, 0xd5, 0xf0, 0x01, 0x1e, 0x3e, 0x7f, 0xb4, 0x21, 0x7c, 0xd0, 0xf9, 0x0f,
+	0xc5, 0x0e, 0x1a, 0x16, 0x5e, 0xdb, 0x81, 0xac, 0x30, 0x25, 0x2a, 0x77, 0xe4, 0xcf, 0xa0, 0x12,
+	0x30, 0x7f, 0xe2, 0x3a, 0xb6, 0x15, 0xb2, 0x48, 0x16, 0x97, 0x97, 0x6b, 0x27, 0x51, 0xc1, 0x63,
+	0xb0, 0x88, 0x42, 0xb0, 0x58, 0xe3, 0xb5, 0xec, 0xab, 0xab, 0x80, 0x5d, 0x89, 0x12, 0x26, 0x3c,
+	0x5f, 0x15, 0xd7, 0x4a, 0x10, 0x16, 0xa9, 0xce, 0x66, 0x4e, 0x70, 0xe7, 0x47, 0x6c, 0xac, 0xd5,
+	0x64, 0x7c, 0xc4, 0x00, 0x79, 0x0d, 0xe0, 0xdb, 0x61, 0xe8, 0x5f, 0x07, 0x76, 0xc8, 0xb4, 0x6d,
+	0x1e, 0x64, 0x09, 0x24, 0xe5, 0xc1, 0xd0, 0xb9, 0x66, 0xe3, 0xf9, 0x84, 0x69, 0x2a, 0x67, 0x5b,
+	0x78, 0xd0, 0x90, 0x38, 0xa6, 0x40, 0xe8, 0xd8, 0x13, 0xa6, 0xed, 0x70, 0x5b, 0xc4, 0x86, 0xfb,
+	0x20, 0x72, 0x9d, 0x9b, 0x3b, 0x8d, 0x48, 0x1f, 0xf0, 0x1d, 0xf9, 0x12, 0xf2, 0x57, 0x18, 0xe0,
+	0xda, 0xa7, 0xfc, 0xf6, 0x7b, 0x6b, 0xb7, 0xe7, 0xe1, 0x4f, 0x05, 0x13, 0xd6, 0x6c, 0xbe, 0xb0,
+	0xd8, 0xec, 0xd2, 0x0b, 0x1c, 0x36, 0xd6, 0xf6, 0xb8, 0xb6, 0x2a, 0x47, 0x75, 0x09, 0xe2, 0x7d,
+	0x1c, 0x6f, 0xea, 0x07, 0x2c, 0xc4, 0x02, 0xf6, 0x9c, 0xb3, 0x24, 0x10, 0xb2, 0x0f, 0x25, 0xc7,
+	0x0e, 0x1d, 0x7b, 0xcc, 0xc6, 0x9a, 0xc6, 0xa9, 0x8b, 0x3d, 0xd1, 0xa0, 0xf8, 0x2b, 0x6f, 0x1e,
+	0xcc, 0xec, 0x89, 0xf6, 0x82, 0x93, 0xe2, 0x2d, 0x66, 0xfb, 0xec, 0x32, 0xd4, 0xf6, 0x39, 0x8a,
+	0xcb, 0xff, 0x7f, 0x51, 0xa8, 0x03, 0x2c, 0xbf, 0x2e, 0xf2, 0xcd, 0xbc, 0x31, 0x0b, 0x35, 0xe5,
+	0x20, 0x8b, 0x7c, 0x7c, 0x53, 0xff, 0x9d, 0x02, 0xdb, 0x74, 0x3e, 0xc3, 0x96, 0x6e, 0x44, 0x76,
+	0xc4, 0xfa, 0xb6, 0x4f, 0xde, 0x42, 0x35, 0x10, 0x90, 0x15, 0x22, 0xc6, 0x25, 0x2a, 0xc7, 0xc7,
+	0xeb, 0xb1, 0x93, 0x16, 0x4c, 0xed, 0x65, 0xa8, 0x06, 0x09, 0x08, 0x6f, 0xb4, 0xc6, 0xf2, 0xa4,
+	0x1b, 0xfd, 0x6b, 0x09, 0x0a, 0xc2, 0x27, 0x6b, 0x03, 0xc6, 0x1b, 0x28, 0x88, 0xd1, 0x83, 0x4b,
+	0x55, 0xee, 0xa9, 0x38, 0xa2, 0x40, 0x52, 0xc9, 0xb6, 0x8c, 0x8d, 0xec, 0x63, 0x62, 0x63, 0x1f,
+	0x4a, 0x38, 0x26, 0x78, 0xb3, 0xc9, 0x9d, 0x9c, 0x3a, 0x16, 0x7b, 0xf2, 0x35, 0x14, 0x27, 0xa2,
+	0xd0, 0xf3, 0xda, 0x54, 0xb9, 0xa7, 0x81, 0xa6, 0xda, 0x01, 0x8d, 0xd9, 0xc9, 0x4f, 0x21, 0xef,
+	0xa0, 0x3b, 0xb4, 0xc2, 0x83, 0xad, 0x5f, 0x30, 0x92, 0x37, 0x90, 0x0b, 0x7d, 0xe6, 0x68, 0xc5,
+	0x0d, 0xe9, 0xbc, 0x2c, 0x1c, 0x94, 0x33, 0xa2, 0x33, 0xe7, 0xa1, 0x7d, 0xc5, 0x64, 0xa7, 0x15,
+	0x9b, 0xf4, 0xdc, 0x51, 0x7e, 0xfc, 0xdc, 0x91, 0x28, 0xec, 0xf0, 0xb8, 0xc2, 0xfe, 0x15, 0xa6,
+	0xa6, 0x1d, 0xcd, 0x43, 0x5e, 0x9e, 0x6a, 0xc7, 0x9f, 0x6d, 0x32, 0x99, 0x33, 0x51, 0xc9, 0x4c,
+	0x8e, 0x21, 0x2f, 0x62, 0x6f, 0x8b, 0x4b, 0xbd, 0xfa, 0x1e, 0x29, 0x46, 0x05, 0x2b, 0xf9, 0x1c,
+	0x2a, 0x76, 0x14, 0xd9, 0x58, 0x2a, 0x2c, 0x6f, 0xc6, 0xab, 0x55, 0x99, 0x42, 0x0c, 0x0d, 0x67,
+	0xa4, 0x05, 0xb5, 0x05, 0x83, 0xd0, 0x5e, 0xdb, 0xa0, 0xbd, 0xc9, 0xd9, 0x84, 0xf6, 0x6a, 0x2c,
+	0x63, 0xc4, 0xa7, 0x8c, 0xd9, 0xad, 0xeb, 0x30, 0x8b, 0x0f, 0xb4, 0xb2, 0x9e, 0x09, 0xe8, 0x1c,
+	0xc7, 0xda, 0x2f, 0x81, 0x84, 0xcc, 0x99, 0x07, 0xcc, 0x4a, 0xf2, 0xc5, 0x05, 0x8d, 0x53, 0xda,
+	0x4b, 0xee, 0x85, 0xd1, 0x82, 0x6d, 0xe7, 0x20, 0xbb, 0x34, 0x9a, 0x33, 0x9c, 0x2d, 0x18, 0xdc,
+	0xd9, 0xa5, 0xa7, 0x11, 0x9e, 0x8b, 0x3f, 0xd9, 0xe0, 0x0f, 0x69, 0x78, 0x77, 0x76, 0xe9, 0x89,
+	0x04, 0x04, 0x7b, 0x01, 0x90, 0xbf, 0x80, 0xad, 0x44, 0x47, 0x08, 0xb5, 0x67, 0x07, 0xd9, 0x7b,
+	0x63, 0x28, 0xd1, 0x12, 0x2a, 0xcb, 0x96, 0x10, 0x12, 0x7d, 0xb5, 0x2e, 0xec, 0x72, 0x05, 0x07,
+	0x0f, 0xd5, 0x85, 0x74, 0x15, 0xc0, 0x88, 0x64, 0x41, 0xe0, 0x05, 0xbc, 0x28, 0x97, 0xa9, 0xd8,
+	0xec, 0xff, 0x39, 0x6c, 0xaf, 0xd8, 0xfe, 0xa4, 0xca, 0xf0, 0x6f, 0x19, 0xc8, 0xa3, 0xfa, 0x10,
+	0x79, 0x30, 0x33, 0x43, 0x2e, 0x97, 0xa3, 0x62, 0x43, 0x9e, 0x43, 0x11, 0x17, 0xd6, 0x34, 0x94,
+	0x73, 0x4a, 0x01, 0xb7, 0xfd, 0x10, 0x07, 0x0f, 0x4e, 0xf8, 0x70, 0x17, 0xb1, 0x90, 0xd7, 0x82,
+	0x1c, 0x2d, 0x23, 0x72, 0x82, 0x00, 0x76, 0x16, 0xfe, 0x76, 0x08, 0x79, 0xd6, 0xe7, 0xa8, 0xdc,
+	0xe1, 0x40, 0xc2, 0x57, 0xa8, 0x50, 0xbc, 0x37, 0x8a, 0x7c, 0xdf, 0x0f, 0xf1, 0x8b, 0x0a, 0x92,
+	0x50, 0x59, 0xe0, 0x54, 0xe0, 0x90, 0xd0, 0xf9, 0x39, 0x54, 0xc4, 0x14, 0x72, 0x85, 0x1d, 0x43,
+	0xce, 0xc6, 0xc0, 0x47, 0x0d, 0x8e, 0x90, 0x67, 0x90, 0x77, 0x3d, 0xd4, 0x5c, 0x8a, 0x5f, 0x32,
+	0xc2, 0x50, 0xae, 0xd0, 0xe2, 0x6f, 0x0d, 0xf1, 0xfe, 0x28, 0x73, 0x84, 0x0f, 0xcf, 0xa8, 0x54,
+	0x8e, 0x19, 0x28, 0x09, 0x52, 0xa9, 0x84, 0xfa, 0x61, 0xfd, 0xdf, 0xf3, 0x90, 0x6f, 0x4e, 0x58,
+	0x10, 0x25, 0x4a, 0x67, 0x96, 0x97, 0xce, 0x6f, 0xf0, 0x19, 0x74, 0xcb, 0x02, 0x37, 0xba, 0xd3,
+	0x32, 0x1b, 0x92, 0xd4, 0x90, 0x0c, 0x3c, 0xb7, 0x17, 0xec, 0x68, 0x94, 0x8d, 0x3a, 0xad, 0xe8,
+	0xce, 0x67, 0xdc, 0x7b, 0x59, 0x5a, 0xe6, 0x08, 0x32, 0x62, 0xbb, 0x9b, 0xb2, 0x90, 0x97, 0x1f,
+	0xf1, 0x3e, 0x88, 0xb7, 0xe4, 0x6b, 0x28, 0x2f, 0x9e, 0x91, 0x5a, 0xfe, 0xc1, 0x02, 0xb4, 0x64,
+	0xc6, 0x8b, 0x06, 0xf2, 0x95, 0x69, 0xb9, 0x63, 0xee, 0xde, 0x32, 0x85, 0x18, 0xea, 0xf2, 0xeb,
+	0xc4, 0x3b, 0xad, 0xb8, 0xe1, 0x3a, 0xf1, 0x3b, 0x55, 0x5c, 0x27, 0x66, 0x47, 0x7b, 0x9d, 0x09,
+	0xe3, 0xc3, 0x94, 0x98, 0xf2, 0xe2, 0x2d, 0xc6, 0x62, 0x14, 0x4d, 0xa4, 0xdb, 0x71, 0x89, 0x57,
+	0x9f, 0xcf, 0xdc, 0x8f, 0x73, 0x66, 0x45, 0xf6, 0x15, 0xf7, 0x77, 0x99, 0x96, 0x05, 0x62, 0xda,
+	0x57, 0x18, 0x86, 0x8e, 0x37, 0x9f, 0x45, 0xbc, 0xec, 0x65, 0xa9, 0xd8, 0xe0, 0x00, 0x7a, 0xe9,
+	0x06, 0x58, 0x78, 0x19, 0x7b, 0xcc, 0x83, 0xaf, 0xcc, 0xb9, 0x0d, 0xc6, 0x66, 0xcb, 0x92, 0xcd,
+	0x98, 0xa8, 0x6d, 0x8f, 0x29, 0xd9, 0x28, 0x88, 0x11, 0x6e, 0x47, 0xcc, 0x9a, 0xb8, 0x53, 0x37,
+	0xd2, 0x6a, 0x32, 0xc2, 0xed, 0x88, 0xf5, 0x10, 0x20, 0x75, 0xd8, 0xb2, 0x9d, 0x9b, 0x99, 0xf7,
+	0xeb, 0x09, 0x1b, 0x5f, 0xb1, 0x31, 0x2f, 0x68, 0x25, 0x9a, 0xc2, 0x30, 0xda, 0x6d, 0xe7, 0x06,
+	0x23, 0x2f, 0x90, 0x85, 0xac, 0x68, 0x3b, 0x37, 0xa3, 0x90, 0x05, 0xbc, 0x7e, 0x39, 0x37, 0x96,
+	0xe3, 0x4d, 0xa7, 0xf8, 0xe8, 0xd8, 0x91, 0x45, 0xd7, 0xb9, 0x69, 0x09, 0x84, 0x7c, 0x25, 0x64,
+	0x79, 0x9b, 0x23, 0x0f, 0x9a, 0x8d, 0x7a, 0x71, 0x57, 0xff, 0x39, 0x14, 0x78, 0xb4, 0x86, 0xd8,
+	0xa8, 0x79, 0x44, 0xc9, 0x31, 0x64, 0xbd, 0x51, 0x73, 0x3e, 0x2a, 0x98, 0xea, 0xff, 0xa1, 0xc0,
+	0x33, 0x51, 0x0b, 0x5b, 0x01, 0xc3, 0xf2, 0xcd, 0x3e, 0xce, 0x59, 0x18, 0x25, 0x9b, 0xb4, 0xf2,
+	0xb4, 0x26, 0xfd, 0xe4, 0xc9, 0x22, 0xee, 0xd1, 0xd9, 0x47, 0xf6, 0xe8, 0xfa, 0x8f, 0xa1, 0x26,
+	0x30, 0xca, 0x42, 0xdf, 0x9b, 0x85, 0x89, 0x1a, 0xa9, 0x24, 0x6a, 0x64, 0xdd, 0x87, 0xdd, 0xf4,
+	0xd5, 0x24, 0xf7, 0xea, 0x2c, 0x74, 0x06, 0xdb, 0xf2, 0x99, 0x11, 0x48, 0x16, 0x69, 0xfa, 0xe7,
+	0x1b, 0x6c, 0x89, 0x35, 0xd1, 0xda, 0x6d, 0x6a, 0x5f, 0xff, 0xbd, 0x12, 0x0f, 0xa1, 0xbc, 0x76,
+	0x37, 0x1d, 0x1c, 0xfa, 0xc9, 0xb7, 0x50, 0x10, 0x6d, 0x85, 0x9f, 0x59, 0x3b, 0xae, 0x6f, 0x50,
+	0x2b, 0xd8, 0xcf, 0xed, 0xc0, 0x9e, 0x52, 0x29, 0x41, 0xbe, 0x86, 0xfc, 0x94, 0xe7, 0x45, 0xe6,
+	0xd1, 0xa2, 0x42, 0x00, 0xe3, 0x98, 0x2f, 0x44, 0xa3, 0xcc, 0x8a, 0x84, 0xe3, 0x48, 0xdc, 0x48,
+	0x93, 0xfd, 0x36, 0xb7, 0xda, 0x97, 0xeb, 0xff, 0x95, 0x01, 0x55, 0xde, 0x85, 0x45, 0x3f, 0x44,
+	0x58, 0x88, 0xaf, 0x9c, 0x79, 0xec, 0x24, 0x86, 0x5e, 0xe3, 0xb7, 0x92, 0x81, 0x51, 0xff, 0xbe,
+	0x99, 0x46, 0xdc, 0x9f, 0x4a, 0x09, 0x72, 0x06, 0x45, 0xcf, 0xc7, 0x15, 0xf6, 0x21, 0xcc, 0x82,
+	0xa3, 0x4d, 0xc2, 0x8b, 0xab, 0x1d, 0x0d, 0x85, 0x80, 0x98, 0x03, 0x62, 0xf1, 0xfd, 0x6f, 0x61,
+	0x2b, 0x49, 0x78, 0x52, 0x93, 0xfd, 0xc7, 0x65, 0x34, 0xb0, 0x28, 0x8e, 0x11, 0xcc, 0x0f, 0x11,
+	0x35, 0x9a, 0xb2, 0x21, 0x3f, 0x64, 0x90, 0x49, 0xb6, 0x1f, 0x30, 0x3c, 0xef, 0x60, 0xc7, 0x98,
+	0xd9, 0x7e, 0x3a, 0xd3, 0x57, 0xb3, 0x21, 0xf1, 0x89, 0x33, 0x4f, 0xfb, 0xc4, 0xc9, 0xa1, 0x3f,
+	0x9b, 0x1e, 0xfa, 0xeb, 0x1f, 0x81, 0x24, 0x8f, 0x96, 0xbe, 0xf8, 0x1b, 0xd8, 0x93, 0x57, 0x73,
+	0x38, 0x61, 0x79, 0x43, 0xe1, 0x9b, 0x2f, 0x36, 0x1c, 0x9d, 0x56, 0x43, 0x77, 0x6f, 0xef, 0x41,
+	0xeb, 0x51, 0xfc, 0xa3, 0x0c, 0x9f, 0xe6, 0x5e, 0x42, 0x59, 0x1e, 0xb5, 0xb8, 0x6d, 0x49, 0x00,
+	0xdd, 0xfb, 0x7f, 0x6e, 0xfd, 0x0a, 0x8a, 0xf2, 0xe0, 0xc7, 0x54, 0xa6, 0x98, 0xb7, 0x3e, 0x06,
+	0x72, 0x1a, 0xd8, 0xfe, 0x75, 0x3b, 0x70, 0x6f, 0x59, 0xd0, 0xba, 0xb6, 0x67, 0x57, 0x2c, 0x5c,
+	0x1c, 0xa0, 0x24, 0x0e, 0xf8, 0x16, 0x72, 0x37, 0xee, 0x6c, 0x2c, 0x33, 0xfb, 0xc7, 0xf7, 0x3c,
+	0xa8, 0x56, 0xd4, 0xf0, 0xee, 0xcb, 0x65, 0xea, 0x3f, 0x81, 0xed, 0xd6, 0x64, 0x1e, 0x46, 0x2c,
+	0x78, 0xa0, 0x06, 0xfe, 0x8b, 0x02, 0x55, 0x4c, 0x8e, 0xdb, 0xc5, 0xf7, 0x3e, 0x83, 0x12, 0x65,
+	0x1f, 0x59, 0x18, 0x7d, 0x77, 0x21, 0x5b, 0xc4, 0x97, 0xeb, 0x2d, 0x22, 0x29, 0x71, 0x14, 0xb3,
+	0x8b, 0xd4, 0x58, 0x48, 0xef, 0xff, 0x29, 0x54, 0x53, 0xa4, 0x64, 0x72, 0x64, 0x1f, 0x4a, 0x8e,
+	0xdf, 0x40, 0x2d, 0x75, 0x4a, 0x88, 0x9d, 0x55, 0xae, 0x5b, 0xbc, 0xe2, 0x09, 0x35, 0x29, 0x8c,
+	0xb4, 0x57, 0x6e, 0x23, 0x7f, 0x50, 0x7c, 0xfd, 0xfd, 0x37, 0xa0, 0x69, 0xa1, 0xc6, 0xef, 0x33,
+	0x50, 0x10, 0x0f, 0x28, 0xb2, 0x0d, 0x15, 0xc3, 0x6c, 0x9a, 0x23, 0xc3, 0x1a, 0x0c, 0x07, 0xba,
+	0xfa, 0x49, 0x02, 0xe8, 0x0e, 0xba, 0xa6, 0xaa, 0x90, 0x2a, 0x94, 0x25, 0x30, 0xfc, 0x4e, 0xcd,
+	0x10, 0x02, 0xb5, 0x78, 0xdb, 0xe9, 0xf4, 0xba, 0x03, 0x5d, 0xcd, 0x12, 0x15, 0xb6, 0x24, 0xa6,
+	0x53, 0x3a, 0xa4, 0x6a, 0x8e, 0x68, 0xb0, 0xbb, 0x50, 0x6b, 0x5a, 0xdd, 0x81, 0xf5, 0x8b, 0xd1,
+	0x90, 0x8e, 0xfa, 0x6a, 0x9e, 0x3c, 0x87, 0x67, 0x92, 0xd2, 0xd6, 0x5b, 0xc3, 0x7e, 0xbf, 0x6b,
+	0x18, 0xdd, 0xe1, 0x40, 0x2d, 0x90, 0x3d, 0x20, 0x92, 0xd0, 0x6f, 0x76, 0x07, 0xa6, 0x3e, 0x68,
+	0x0e, 0x5a, 0xba, 0x5a, 0x4c, 0x08, 0x18, 0xe6, 0x90, 0x36, 0x4f, 0x75, 0xab, 0x3d, 0x7c, 0x3b,
+	0x50, 0x4b, 0xe4, 0x25, 0x3c, 0x5f, 0x25, 0xe8, 0xa7, 0xb4, 0xd9, 0xd6, 0xdb, 0x6a, 0x39, 0x21,
+	0x35, 0xd0, 0xf5, 0xb6, 0x61, 0x51, 0xfd, 0x64, 0x38, 0x34, 0x55, 0x20, 0xaf, 0x40, 0x5b, 0x91,
+	0xa2, 0xfa, 0x49, 0xb3, 0xc7, 0x0f, 0xab, 0x90, 0x03, 0x78, 0xb5, 0xaa, 0x93, 0x76, 0x2f, 0x90,
+	0xe7, 0xbc, 0xd7, 0x6c, 0xe9, 0xea, 0x16, 0xa9, 0x01, 0x2c, 0xcc, 0x7c, 0xa7, 0x56, 0x1b, 0xbf,
+	0x55, 0x00, 0x44, 0x90, 0xf2, 0x11, 0x76, 0x17, 0x54, 0x2e, 0x41, 0x2d, 0xf3, 0xfd, 0xb9, 0x1e,
+	0x3b, 0x75, 0x05, 0xed, 0x74, 0x7b, 0xba, 0xaa, 0x90, 0x4f, 0x61, 0x27, 0x89, 0x9e, 0xf4, 0x86,
+	0x2d, 0xf4, 0xf0, 0x1e, 0x90, 0x24, 0x3c, 0x3c, 0xf9, 0x6b, 0xbd, 0x65, 0xaa, 0x59, 0xf2, 0x02,
+	0x3e, 0x4d, 0xe2, 0xad, 0xde, 0xc8, 0x30, 0x75, 0xaa, 0xb7, 0xd5, 0xdc, 0xaa, 0xa6, 0x53, 0xda,
+	0x3c, 0x3f, 0x53, 0xf3, 0x8d, 0x7f, 0x56, 0xa0, 0x20, 0xde, 0xd7, 0xf8, 0x89, 0x3a, 0x46, 0xca,
+	0xa6, 0x1d, 0xa8, 0xc6, 0xc8, 0x89, 0x49, 0x3b, 0x86, 0xaa, 0x24, 0x99, 0xf4, 0x77, 0xe6, 0xcf,
+	0xd4, 0x4c, 0x12, 0xe9, 0x8c, 0x0c, 0xfc, 0xd6, 0xdb, 0x50, 0x59, 0x28, 0xea, 0x18, 0x6a, 0x2e,
+	0x09, 0x5c, 0x74, 0x0c, 0x35, 0x9f, 0x04, 0xde, 0x75, 0x0c, 0xb5, 0x90, 0x04, 0x7e, 0xd9, 0x31,
+	0xd4, 0x62, 0xe3, 0x77, 0x0a, 0x7c, 0x7a, 0x6f, 0x76, 0x93, 0x1f, 0xc1, 0x67, 0xdc, 0x78, 0x4b,
+	0x5e, 0xa7, 0x75, 0xd6, 0x1c, 0x9c, 0xea, 0x29, 0xbb, 0xbf, 0x80, 0x1f, 0x6d, 0x64, 0xe9, 0x0f,
+	0xdb, 0xdd, 0x4e, 0x57, 0x6f, 0xab, 0x0a, 0xa9, 0xc3, 0xeb, 0x8d, 0x6c, 0xcd, 0x36, 0x06, 0x49,
+	0x86, 0xfc, 0x01, 0x1c, 0x6c, 0xe4, 0x69, 0xeb, 0x3d, 0xdd, 0xd4, 0xdb, 0x6a, 0xb6, 0x11, 0xc1,
+	0x56, 0xf2, 0x39, 0xc3, 0x03, 0x55, 0xbf, 0xd0, 0x69, 0xd7, 0x7c, 0x9f, 0x32, 0x0c, 0x43, 0x2e,
+	0x85, 0x37, 0x7b, 0x4d, 0xda, 0x57, 0x15, 0xfc, 0x70, 0x69, 0xc2, 0xdb, 0x26, 0x1d, 0x74, 0x07,
+	0xa7, 0x6a, 0x86, 0xe7, 0xc9, 0x8a, 0x2e, 0xb3, 0xdb, 0x79, 0xaf, 0x66, 0x1b, 0xff, 0xa0, 0x60,
+	0x39, 0x58, 0x3e, 0x3b, 0xf0, 0x58, 0xaa, 0x1b, 0xc3, 0x11, 0x6d, 0xa5, 0xfd, 0xa1, 0xc1, 0x6e,
+	0x1a, 0xbf, 0x18, 0xf6, 0x46, 0x7d, 0x8c, 0xaf, 0x7b, 0x24, 0xda, 0xba, 0x9a, 0x41, 0x7b, 0xd2,
+	0xb8, 0x0c, 0x25, 0x35, 0x8b, 0x77, 0x48, 0x93, 0xb8, 0x67, 0xd4, 0x5c, 0xe3, 0xef, 0x15, 0xd8,
+	0xe6, 0x83, 0xb3, 0x98, 0x34, 0xb8, 0x45, 0xfb, 0xb0, 0xd7, 0xec, 0xe9, 0xd4, 0xb4, 0x9a, 0x2d,
+	0xb3, 0x3b, 0x1c, 0xa4, 0xac, 0x7a, 0x05, 0xda, 0x3a, 0x4d, 0xf8, 0x54, 0x55, 0xee, 0xa7, 0xb6,
+	0xa8, 0xde, 0x34, 0xd1, 0xbe, 0x7b, 0xa9, 0xa3, 0xf3, 0x36, 0x52, 0xb3, 0x8d, 0x5f, 0xc5, 0x43,
+	0x45, 0x62, 0xe6, 0x43, 0x11, 0x71, 0xed, 0x58, 0xe6, 0xbc, 0x49, 0x9b, 0xfd, 0xd8, 0x98, 0x97,
+	0xf0, 0xfc, 0x3e, 0xea, 0xb0, 0xd3, 0x51, 0x15, 0xbc, 0xc5, 0xbd, 0xc4, 0x81, 0x9a, 0x69, 0x1c,
+	0x43, 0x51, 0xfe, 0x43, 0x80, 0x94, 0x20, 0x27, 0xb5, 0x15, 0x21, 0xdb, 0x1b, 0xbe, 0x55, 0x15,
+	0x02, 0x50, 0xe8, 0xeb, 0xed, 0xee, 0xa8, 0xaf, 0x66, 0x90, 0x7c, 0xd6, 0x3d, 0x3d, 0x53, 0xb3,
+	0x8d, 0xbf, 0x83, 0xf2, 0xe2, 0x3f, 0x02, 0xe8, 0xea, 0xee, 0xd0, 0x3a, 0xa7, 0x43, 0x4c, 0x79,
+	0xcb, 0xd0, 0x7f, 0x31, 0xd2, 0x07, 0x66, 0xb7, 0xd9, 0x53, 0x3f, 0xc1, 0x9c, 0x4d, 0x90, 0x68,
+	0x73, 0xd0, 0x1e, 0x62, 0xb0, 0xec, 0x40, 0x35, 0x01, 0xb7, 0x4f, 0x44, 0x90, 0xa4, 0x20, 0x8b,
+	0xea, 0xfd, 0x21, 0xfa, 0x02, 0x8b, 0x71, 0x82, 0xd2, 0xea, 0x1b, 0x6a, 0xae, 0xf1, 0xdb, 0x0c,
+	0x54, 0x12, 0x93, 0x21, 0x9e, 0x23, 0xef, 0x87, 0x75, 0x2b, 0x19, 0x36, 0x29, 0xf8, 0x5c, 0x1f,
+	0xb4, 0x31, 0x26, 0x93, 0x0e, 0x11, 0x94, 0xe6, 0x45, 0xb3, 0xdb, 0x6b, 0x9e, 0xf4, 0x64, 0xe8,
+	0xa4, 0x69, 0xa6, 0xd9, 0x6c, 0x9d, 0x61, 0x9a, 0xac, 0x91, 0xda, 0xba, 0x24, 0xe5, 0x12, 0xfe,
+	0x5f, 0x92, 0xcc, 0xd6, 0x19, 0x1e, 0x97, 0xc7, 0x28, 0x4d, 0x11, 0x45, 0x0b, 0x29, 0xac, 0x19,
+	0x18, 0x27, 0x64, 0x91, 0xbc, 0x86, 0xfd, 0x14, 0xc5, 0xa4, 0xef, 0xe5, 0x69, 0xa8, 0xb1, 0xb4,
+	0x26, 0x49, 0x75, 0x2c, 0xe6, 0xba, 0x5a, 0x6e, 0xfc, 0x93, 0x02, 0x5b, 0xc9, 0xdf, 0x0f, 0x57,
+	0x0e, 0x5f, 0x76, 0xc1, 0xcf, 0xe0, 0xc5, 0x2a, 0x6e, 0x5a, 0xe7, 0x54, 0x37, 0xf4, 0x01, 0xf6,
+	0xc4, 0x5d, 0x50, 0xd3, 0xe4, 0xd1, 0xb9, 0x28, 0xdc, 0x69, 0x94, 0x37, 0xaa, 0xec, 0x8a, 0x43,
+	0x47, 0xc6, 0xb2, 0x4f, 0xe5, 0x1a, 0x7f, 0x0b, 0xd5, 0xd4, 0x7f, 0x4b, 0x45, 0x57, 0x13, 0xad,
+	0x47, 0x04, 0x97, 0xd5, 0x6f, 0x9e, 0x0e, 0x74, 0xb3, 0xdb, 0x52, 0x3f, 0x11, 0x3d, 0x32, 0x45,
+	0x34, 0x0c, 0x2c, 0x76, 0xbc, 0xdb, 0xa5, 0xf0, 0xc1, 0x45, 0x5f, 0x57, 0x33, 0x8d, 0x43, 0xa8,
+	0xca, 0x39, 0x69, 0xe0, 0x45, 0xee, 0xe5, 0x1d, 0x72, 0xca, 0x6c, 0x97, 0xa5, 0x46, 0x18, 0xf9,
+	0x49, 0x83, 0x41, 0x25, 0xf1, 0x2b, 0x26, 0x7e, 0x4d, 0xf1, 0x6d, 0xe3, 0xaf, 0xf2, 0xce, 0xd4,
+	0xe9, 0x80, 0x07, 0xee, 0x2a, 0xa9, 0x3b, 0x90, 0x24, 0x05, 0xdb, 0xe7, 0xbd, 0x24, 0xcb, 0x78,
+	0xdb, 0x35, 0x5b, 0x67, 0x6a, 0xa6, 0x61, 0x42, 0x6d, 0xe8, 0xb3, 0x80, 0xff, 0x37, 0xa8, 0x33,
+	0xb1, 0xaf, 0xf0, 0x07, 0x38, 0x75, 0x78, 0x6e, 0x75, 0x7a, 0xcd, 0x53, 0xc3, 0x1a, 0x0d, 0xbe,
+	0x1b, 0x70, 0x73, 0x30, 0x0d, 0x16, 0x28, 0xff, 0x26, 0xbc, 0x8c, 0x2e, 0x20, 0xf1, 0xb9, 0xad,
+	0xce, 0x90, 0xb6, 0x74, 0x35, 0x73, 0xf2, 0x0a, 0x9e, 0x39, 0xde, 0x74, 0x75, 0x08, 0x3a, 0x57,
+	0x7e, 0x99, 0xb5, 0x7d, 0xf7, 0x43, 0x81, 0xff, 0x70, 0xf0, 0xc7, 0xff, 0x37, 0x00, 0x6b, 0x99,
+	0x86, 0xe9, 0xba, 0x20, 0x00, 0x00,
 }
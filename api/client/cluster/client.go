@@ -258,6 +258,56 @@ func (c *clusterClient) EnumerateAlerts(ts, te time.Time, resource api.ResourceT
 	return &a, nil
 }
 
+// EnumerateAlertsWithFilter returns a page of the alerts that match filter.
+func (c *clusterClient) EnumerateAlertsWithFilter(filter *api.AlertFilter) (*api.AlertList, error) {
+	list := &api.AlertList{}
+	req := c.c.Get().Resource(clusterPath + "/alerts")
+	if filter.Resource != api.ResourceType_RESOURCE_TYPE_NONE {
+		req.QueryOption(api.OptResource, filter.Resource.SimpleString())
+	}
+	if filter.ResourceId != "" {
+		req.QueryOption(api.OptResourceID, filter.ResourceId)
+	}
+	if filter.Severity != api.SeverityType_SEVERITY_TYPE_NONE {
+		req.QueryOption(api.OptSeverity, filter.Severity.SimpleString())
+	}
+	if filter.Cleared != nil {
+		req.QueryOption(api.OptCleared, strconv.FormatBool(*filter.Cleared))
+	}
+	if filter.AlertType != 0 {
+		req.QueryOption(api.OptAlertType, strconv.FormatInt(filter.AlertType, 10))
+	}
+	if !filter.TimeStart.IsZero() {
+		req.QueryOption(api.OptTimeStart, filter.TimeStart.Format(time.RFC3339Nano))
+	}
+	if !filter.TimeEnd.IsZero() {
+		req.QueryOption(api.OptTimeEnd, filter.TimeEnd.Format(time.RFC3339Nano))
+	}
+	if filter.Text != "" {
+		req.QueryOption(api.OptText, filter.Text)
+	}
+	if filter.SortBy != "" {
+		req.QueryOption(api.OptSortBy, string(filter.SortBy))
+	}
+	if filter.Descending {
+		req.QueryOption(api.OptDescending, "true")
+	}
+	if filter.Limit > 0 {
+		req.QueryOption(api.OptLimit, strconv.Itoa(filter.Limit))
+	}
+	if filter.Token != "" {
+		req.QueryOption(api.OptToken, filter.Token)
+	}
+	resp := req.Do()
+	if resp.Error() != nil {
+		return nil, resp.FormatError()
+	}
+	if err := resp.Unmarshal(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
 func (c *clusterClient) ClearAlert(resource api.ResourceType, alertID int64) error {
 	path := clusterPath + "/alerts/" + strconv.FormatInt(int64(resource), 10) + "/" + strconv.FormatInt(alertID, 10)
 	request := c.c.Put().Resource(path)
@@ -277,3 +327,61 @@ func (c *clusterClient) EraseAlert(resource api.ResourceType, alertID int64) err
 	}
 	return nil
 }
+
+func (c *clusterClient) AcknowledgeAlert(resource api.ResourceType, alertID int64, user, comment string) error {
+	path := clusterPath + "/alerts/" + strconv.FormatInt(int64(resource), 10) + "/" + strconv.FormatInt(alertID, 10) + "/ack"
+	request := c.c.Put().Resource(path).Body(&api.AlertAcknowledgement{User: user, Comment: comment})
+	resp := request.Do()
+	if resp.Error() != nil {
+		return resp.FormatError()
+	}
+	return nil
+}
+
+func (c *clusterClient) EnumerateAlertSilences() ([]*api.AlertSilence, error) {
+	var silences []*api.AlertSilence
+	if err := c.c.Get().Resource(clusterPath + "/alerts/silences").Do().Unmarshal(&silences); err != nil {
+		return nil, err
+	}
+	return silences, nil
+}
+
+func (c *clusterClient) PutAlertSilence(silence *api.AlertSilence) error {
+	resp := c.c.Post().Resource(clusterPath + "/alerts/silences").Body(silence).Do()
+	if resp.Error() != nil {
+		return resp.FormatError()
+	}
+	return nil
+}
+
+func (c *clusterClient) DeleteAlertSilence(name string) error {
+	resp := c.c.Delete().Resource(clusterPath + "/alerts/silences/" + name).Do()
+	if resp.Error() != nil {
+		return resp.FormatError()
+	}
+	return nil
+}
+
+func (c *clusterClient) EnumerateAlertSinks() ([]*api.AlertSink, error) {
+	var sinks []*api.AlertSink
+	if err := c.c.Get().Resource(clusterPath + "/alerts/sinks").Do().Unmarshal(&sinks); err != nil {
+		return nil, err
+	}
+	return sinks, nil
+}
+
+func (c *clusterClient) PutAlertSink(sink *api.AlertSink) error {
+	resp := c.c.Post().Resource(clusterPath + "/alerts/sinks").Body(sink).Do()
+	if resp.Error() != nil {
+		return resp.FormatError()
+	}
+	return nil
+}
+
+func (c *clusterClient) DeleteAlertSink(name string) error {
+	resp := c.c.Delete().Resource(clusterPath + "/alerts/sinks/" + name).Do()
+	if resp.Error() != nil {
+		return resp.FormatError()
+	}
+	return nil
+}
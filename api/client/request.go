@@ -262,12 +262,12 @@ func (r *Request) Do() *Response {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Date", time.Now().String())
 
-	if len(r.authstring) > 0 {
-		req.Header.Set("Authorization", "Basic "+r.authstring)
-	}
-
+	// Servers that predate bearer tokens read the Access-Token header
 	if len(r.accesstoken) > 0 {
+		req.Header.Set("Authorization", "Bearer "+r.accesstoken)
 		req.Header.Set("Access-Token", r.accesstoken)
+	} else if len(r.authstring) > 0 {
+		req.Header.Set("Authorization", "Basic "+r.authstring)
 	}
 
 	resp, err = r.client.Do(req)
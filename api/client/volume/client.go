@@ -10,6 +10,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strconv"
+	"time"
 )
 
 const (
@@ -210,6 +211,34 @@ func (v *volumeClient) Restore(volumeID string, snapID string) error {
 	return nil
 }
 
+// SnapDelete deletes specified snapshot or clone, along with its own
+// snapshots and clones if cascade is set.
+func (v *volumeClient) SnapDelete(snapID string, cascade bool) error {
+	response := &api.VolumeResponse{}
+	req := v.c.Delete().Resource(snapPath).Instance(snapID)
+	req.QueryOption(api.OptCascade, strconv.FormatBool(cascade))
+
+	if err := req.Do().Unmarshal(response); err != nil {
+		return err
+	}
+	if response.Error != "" {
+		return errors.New(response.Error)
+	}
+	return nil
+}
+
+// Promote makes specified clone independent of its parent
+func (v *volumeClient) Promote(cloneID string) error {
+	response := &api.VolumeResponse{}
+	if err := v.c.Post().Resource(snapPath + "/promote").Instance(cloneID).Do().Unmarshal(response); err != nil {
+		return err
+	}
+	if response.Error != "" {
+		return errors.New(response.Error)
+	}
+	return nil
+}
+
 // Stats for specified volume.
 // Errors ErrEnoEnt may be returned
 func (v *volumeClient) Stats(
@@ -282,6 +311,49 @@ func (v *volumeClient) Enumerate(locator *api.VolumeLocator,
 	return volumes, nil
 }
 
+// EnumerateWithFilter returns a page of the volumes that match filter.
+func (v *volumeClient) EnumerateWithFilter(
+	filter *api.VolumeFilter,
+) (*api.VolumeList, error) {
+	list := &api.VolumeList{}
+	req := v.c.Get().Resource(volumePath + "/list")
+	if filter.Name != "" {
+		req.QueryOption(api.OptName, filter.Name)
+	}
+	if len(filter.Labels) != 0 {
+		req.QueryOption(api.OptSelector, filter.Labels.String())
+	}
+	for _, state := range filter.States {
+		req.QueryOption(api.OptState, state.SimpleString())
+	}
+	for _, status := range filter.Statuses {
+		req.QueryOption(api.OptStatus, status.SimpleString())
+	}
+	if filter.AttachedOn != "" {
+		req.QueryOption(api.OptAttachedOn, filter.AttachedOn)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		req.QueryOption(api.OptCreatedAfter, filter.CreatedAfter.Format(time.RFC3339Nano))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		req.QueryOption(api.OptCreatedBefore, filter.CreatedBefore.Format(time.RFC3339Nano))
+	}
+	if filter.Limit > 0 {
+		req.QueryOption(api.OptLimit, strconv.Itoa(filter.Limit))
+	}
+	if filter.Token != "" {
+		req.QueryOption(api.OptToken, filter.Token)
+	}
+	resp := req.Do()
+	if resp.Error() != nil {
+		return nil, resp.FormatError()
+	}
+	if err := resp.Unmarshal(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
 // Enumerate snaps for specified volume
 // Count indicates the number of snaps populated.
 func (v *volumeClient) SnapEnumerate(ids []string,
@@ -300,6 +372,24 @@ func (v *volumeClient) SnapEnumerate(ids []string,
 	return volumes, nil
 }
 
+// SnapTree returns the snapshots and clones descending from specified volume
+func (v *volumeClient) SnapTree(volumeID string) (*api.SnapshotTree, error) {
+	tree := &api.SnapshotTree{}
+	if err := v.c.Get().Resource(snapPath + "/tree").Instance(volumeID).Do().Unmarshal(tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// SnapChain returns the ancestors of specified volume, closest first
+func (v *volumeClient) SnapChain(volumeID string) ([]*api.Volume, error) {
+	var volumes []*api.Volume
+	if err := v.c.Get().Resource(snapPath + "/chain").Instance(volumeID).Do().Unmarshal(&volumes); err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
+
 // Attach map device to the host.
 // On success the devicePath specifies location where the device is exported
 // Errors ErrEnoEnt, ErrVolAttached may be returned.
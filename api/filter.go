@@ -0,0 +1,277 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LabelOperator is the relation a LabelRequirement checks.
+type LabelOperator string
+
+const (
+	// LabelOpEquals requires the label to have the value.
+	LabelOpEquals LabelOperator = "="
+	// LabelOpNotEquals requires the label to be missing or have another value.
+	LabelOpNotEquals LabelOperator = "!="
+	// LabelOpIn requires the label to have one of the values.
+	LabelOpIn LabelOperator = "in"
+	// LabelOpNotIn requires the label to be missing or have none of the values.
+	LabelOpNotIn LabelOperator = "notin"
+	// LabelOpExists requires the label to be present.
+	LabelOpExists LabelOperator = "exists"
+	// LabelOpDoesNotExist requires the label to be missing.
+	LabelOpDoesNotExist LabelOperator = "!"
+)
+
+// LabelRequirement is a condition on the value of one label.
+type LabelRequirement struct {
+	Key      string
+	Operator LabelOperator
+	Values   []string
+}
+
+// LabelSelector selects label sets that meet all of its requirements.
+// An empty selector selects every label set.
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector parses a comma separated list of requirements such as
+// "env=prod,tier!=web,zone in (a,b),region notin (x),backup,!legacy".
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	var selector LabelSelector
+	for _, part := range splitSelector(s) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		r, err := parseLabelRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+// Matches returns true if labels meet every requirement of s.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns s in the format accepted by ParseLabelSelector.
+func (s LabelSelector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Matches returns true if labels meet r.
+func (r LabelRequirement) Matches(labels map[string]string) bool {
+	v, ok := labels[r.Key]
+	switch r.Operator {
+	case LabelOpEquals:
+		return ok && len(r.Values) > 0 && v == r.Values[0]
+	case LabelOpNotEquals:
+		return !ok || len(r.Values) == 0 || v != r.Values[0]
+	case LabelOpIn:
+		return ok && containsString(r.Values, v)
+	case LabelOpNotIn:
+		return !ok || !containsString(r.Values, v)
+	case LabelOpExists:
+		return ok
+	case LabelOpDoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+// String returns r in the format accepted by ParseLabelSelector.
+func (r LabelRequirement) String() string {
+	switch r.Operator {
+	case LabelOpEquals, LabelOpNotEquals:
+		return r.Key + string(r.Operator) + strings.Join(r.Values, "")
+	case LabelOpIn, LabelOpNotIn:
+		return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator,
+			strings.Join(r.Values, ","))
+	case LabelOpDoesNotExist:
+		return "!" + r.Key
+	}
+	return r.Key
+}
+
+// splitSelector splits s at the commas that are not within parentheses.
+func splitSelector(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseLabelRequirement(s string) (LabelRequirement, error) {
+	if open := strings.Index(s, "("); open >= 0 {
+		if !strings.HasSuffix(s, ")") {
+			return LabelRequirement{}, fmt.Errorf("Missing ) in %q", s)
+		}
+		head := strings.Fields(s[:open])
+		if len(head) != 2 {
+			return LabelRequirement{}, fmt.Errorf("Invalid requirement %q", s)
+		}
+		op := LabelOperator(head[1])
+		if op != LabelOpIn && op != LabelOpNotIn {
+			return LabelRequirement{}, fmt.Errorf("Unknown operator %q in %q",
+				head[1], s)
+		}
+		var values []string
+		for _, v := range strings.Split(s[open+1:len(s)-1], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return LabelRequirement{}, fmt.Errorf("No values in %q", s)
+		}
+		return newLabelRequirement(head[0], op, values...)
+	}
+	if strings.HasPrefix(s, "!") && !strings.Contains(s, "=") {
+		return newLabelRequirement(strings.TrimSpace(s[1:]), LabelOpDoesNotExist)
+	}
+	for _, op := range []string{"!=", "==", "="} {
+		if i := strings.Index(s, op); i >= 0 {
+			operator := LabelOpEquals
+			if op == "!=" {
+				operator = LabelOpNotEquals
+			}
+			return newLabelRequirement(strings.TrimSpace(s[:i]), operator,
+				strings.TrimSpace(s[i+len(op):]))
+		}
+	}
+	return newLabelRequirement(s, LabelOpExists)
+}
+
+func newLabelRequirement(
+	key string,
+	op LabelOperator,
+	values ...string,
+) (LabelRequirement, error) {
+	if key == "" || strings.ContainsAny(key, " \t!=(),") {
+		return LabelRequirement{}, fmt.Errorf("Invalid label key %q", key)
+	}
+	for _, v := range values {
+		if strings.ContainsAny(v, "!=(),") {
+			return LabelRequirement{}, fmt.Errorf("Invalid label value %q", v)
+		}
+	}
+	return LabelRequirement{Key: key, Operator: op, Values: values}, nil
+}
+
+func containsString(set []string, s string) bool {
+	for _, v := range set {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// VolumeFilter selects the volumes returned by EnumerateWithFilter. Fields
+// that are left empty match every volume.
+type VolumeFilter struct {
+	// Name must equal the name of the volume.
+	Name string
+	// Labels selects on the locator labels of the volume.
+	Labels LabelSelector
+	// States must contain the state of the volume.
+	States []VolumeState
+	// Statuses must contain the status of the volume.
+	Statuses []VolumeStatus
+	// AttachedOn must equal the node the volume is attached on.
+	AttachedOn string
+	// CreatedAfter and CreatedBefore bound the creation time of the volume.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Limit is the largest number of volumes to return, 0 for no limit.
+	Limit int
+	// Token continues an enumeration from the NextToken of a VolumeList.
+	Token string
+}
+
+// Matches returns true if v meets all conditions of f. The paging fields
+// are ignored.
+func (f *VolumeFilter) Matches(v *Volume) bool {
+	if f.Name != "" && v.GetLocator().GetName() != f.Name {
+		return false
+	}
+	if !f.Labels.Matches(v.GetLocator().GetVolumeLabels()) {
+		return false
+	}
+	if len(f.States) > 0 && !containsState(f.States, v.GetState()) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !containsStatus(f.Statuses, v.GetStatus()) {
+		return false
+	}
+	if f.AttachedOn != "" && v.GetAttachedOn() != f.AttachedOn {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() || !f.CreatedBefore.IsZero() {
+		if v.GetCtime() == nil {
+			return false
+		}
+		ctime := time.Unix(v.GetCtime().GetSeconds(), int64(v.GetCtime().GetNanos()))
+		if !f.CreatedAfter.IsZero() && !ctime.After(f.CreatedAfter) {
+			return false
+		}
+		if !f.CreatedBefore.IsZero() && !ctime.Before(f.CreatedBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsState(set []VolumeState, s VolumeState) bool {
+	for _, v := range set {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStatus(set []VolumeStatus, s VolumeStatus) bool {
+	for _, v := range set {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// VolumeList is a page of volumes returned by EnumerateWithFilter.
+//
+// swagger:model
+type VolumeList struct {
+	// Volumes on this page.
+	Volumes []*Volume
+	// NextToken continues the enumeration after this page. It is empty on
+	// the last page.
+	NextToken string
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/context"
+	"go.pedge.io/dlog"
+
+	"github.com/libopenstorage/openstorage/pkg/auth"
+)
+
+const bearerPrefix = "Bearer "
+
+type contextKey int
+
+// claimsKey is the key of the claims of the request token in the
+// gorilla context of authorized requests, which is where mux keeps the
+// variables of their route too.
+const claimsKey contextKey = iota
+
+var (
+	authLock      sync.RWMutex
+	authenticator auth.Authenticator
+)
+
+// SetAuthenticator makes all REST servers require a bearer token verified
+// by a on every route that needs a role. A nil a turns authentication off,
+// which is the default.
+func SetAuthenticator(a auth.Authenticator) {
+	authLock.Lock()
+	defer authLock.Unlock()
+	authenticator = a
+}
+
+func getAuthenticator() auth.Authenticator {
+	authLock.RLock()
+	defer authLock.RUnlock()
+	return authenticator
+}
+
+// authorize wraps the handler of route so that it only runs for requests
+// whose token grants at least the role of the route. Requests without a
+// valid token get 401, requests with too little access get 403. Only open
+// routes served on the unix socket, local, are served without a token.
+func authorize(route *Route, local bool) http.HandlerFunc {
+	if route.open && local {
+		return route.fn
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		a := getAuthenticator()
+		if a == nil {
+			route.fn(w, r)
+			return
+		}
+		claims, err := a.AuthenticateToken(bearerToken(r))
+		if err != nil {
+			dlog.Warnf("Unauthenticated %v %v: %v", r.Method, r.URL.Path, err)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="openstorage"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if role := claims.Role(); role < route.role {
+			dlog.Warnf("Forbidden %v %v for %q with role %v",
+				r.Method, r.URL.Path, claims.Subject, role)
+			http.Error(w, fmt.Sprintf("Role %v required", route.role),
+				http.StatusForbidden)
+			return
+		}
+		context.Set(r, claimsKey, claims)
+		route.fn(w, r)
+	}
+}
+
+// allowed returns true if the token of r grants at least role, or if
+// authentication is off.
+func allowed(r *http.Request, role auth.Role) bool {
+	if getAuthenticator() == nil {
+		return true
+	}
+	claims, ok := context.Get(r, claimsKey).(*auth.Claims)
+	return ok && claims != nil && claims.Role() >= role
+}
+
+// requestUser returns the subject of the token of r, or user if r was not
+// authenticated.
+func requestUser(r *http.Request, user string) string {
+	if claims, ok := context.Get(r, claimsKey).(*auth.Claims); ok && claims != nil {
+		return claims.Subject
+	}
+	return user
+}
+
+// bearerToken returns the bearer token in the Authorization header of r.
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	if len(h) < len(bearerPrefix) ||
+		!strings.EqualFold(h[:len(bearerPrefix)], bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(h[len(bearerPrefix):])
+}
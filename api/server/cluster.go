@@ -4,13 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/libopenstorage/openstorage/alert"
 	"github.com/libopenstorage/openstorage/api"
 	"github.com/libopenstorage/openstorage/cluster"
+	"github.com/libopenstorage/openstorage/pkg/auth"
 )
 
 const (
@@ -24,23 +27,31 @@ type clusterApi struct {
 
 func (c *clusterApi) Routes() []*Route {
 	return []*Route{
-		{verb: "GET", path: "/cluster/versions", fn: c.versions},
-		{verb: "GET", path: clusterPath("/enumerate", cluster.APIVersion), fn: c.enumerate},
-		{verb: "GET", path: clusterPath("/gossipstate", cluster.APIVersion), fn: c.gossipState},
-		{verb: "GET", path: clusterPath("/nodestatus", cluster.APIVersion), fn: c.nodeStatus},
-		{verb: "GET", path: clusterPath("/nodehealth", cluster.APIVersion), fn: c.nodeHealth},
-		{verb: "GET", path: clusterPath("/status", cluster.APIVersion), fn: c.status},
-		{verb: "GET", path: clusterPath("/peerstatus", cluster.APIVersion), fn: c.peerStatus},
-		{verb: "GET", path: clusterPath("/inspect/{id}", cluster.APIVersion), fn: c.inspect},
-		{verb: "DELETE", path: clusterPath("", cluster.APIVersion), fn: c.delete},
-		{verb: "DELETE", path: clusterPath("/{id}", cluster.APIVersion), fn: c.delete},
-		{verb: "PUT", path: clusterPath("/enablegossip", cluster.APIVersion), fn: c.enableGossip},
-		{verb: "PUT", path: clusterPath("/disablegossip", cluster.APIVersion), fn: c.disableGossip},
-		{verb: "PUT", path: clusterPath("/shutdown", cluster.APIVersion), fn: c.shutdown},
-		{verb: "PUT", path: clusterPath("/shutdown/{id}", cluster.APIVersion), fn: c.shutdown},
-		{verb: "GET", path: clusterPath("/alerts/{resource}", cluster.APIVersion), fn: c.enumerateAlerts},
-		{verb: "PUT", path: clusterPath("/alerts/{resource}/{id}", cluster.APIVersion), fn: c.clearAlert},
-		{verb: "DELETE", path: clusterPath("/alerts/{resource}/{id}", cluster.APIVersion), fn: c.eraseAlert},
+		{verb: "GET", path: "/cluster/versions", fn: c.versions, role: auth.RoleViewer},
+		{verb: "GET", path: clusterPath("/enumerate", cluster.APIVersion), fn: c.enumerate, role: auth.RoleViewer},
+		{verb: "GET", path: clusterPath("/gossipstate", cluster.APIVersion), fn: c.gossipState, role: auth.RoleViewer},
+		{verb: "GET", path: clusterPath("/nodestatus", cluster.APIVersion), fn: c.nodeStatus, role: auth.RoleViewer},
+		{verb: "GET", path: clusterPath("/nodehealth", cluster.APIVersion), fn: c.nodeHealth, role: auth.RoleViewer},
+		{verb: "GET", path: clusterPath("/status", cluster.APIVersion), fn: c.status, role: auth.RoleViewer},
+		{verb: "GET", path: clusterPath("/peerstatus", cluster.APIVersion), fn: c.peerStatus, role: auth.RoleViewer},
+		{verb: "GET", path: clusterPath("/inspect/{id}", cluster.APIVersion), fn: c.inspect, role: auth.RoleViewer},
+		{verb: "DELETE", path: clusterPath("", cluster.APIVersion), fn: c.delete, role: auth.RoleAdmin},
+		{verb: "DELETE", path: clusterPath("/{id}", cluster.APIVersion), fn: c.delete, role: auth.RoleAdmin},
+		{verb: "PUT", path: clusterPath("/enablegossip", cluster.APIVersion), fn: c.enableGossip, role: auth.RoleAdmin},
+		{verb: "PUT", path: clusterPath("/disablegossip", cluster.APIVersion), fn: c.disableGossip, role: auth.RoleAdmin},
+		{verb: "PUT", path: clusterPath("/shutdown", cluster.APIVersion), fn: c.shutdown, role: auth.RoleAdmin},
+		{verb: "PUT", path: clusterPath("/shutdown/{id}", cluster.APIVersion), fn: c.shutdown, role: auth.RoleAdmin},
+		{verb: "GET", path: clusterPath("/alerts", cluster.APIVersion), fn: c.enumerateAlertsWithFilter, role: auth.RoleViewer},
+		{verb: "GET", path: clusterPath("/alerts/sinks", cluster.APIVersion), fn: c.enumerateAlertSinks, role: auth.RoleViewer},
+		{verb: "POST", path: clusterPath("/alerts/sinks", cluster.APIVersion), fn: c.putAlertSink, role: auth.RoleAdmin},
+		{verb: "DELETE", path: clusterPath("/alerts/sinks/{name}", cluster.APIVersion), fn: c.deleteAlertSink, role: auth.RoleAdmin},
+		{verb: "GET", path: clusterPath("/alerts/silences", cluster.APIVersion), fn: c.enumerateAlertSilences, role: auth.RoleViewer},
+		{verb: "POST", path: clusterPath("/alerts/silences", cluster.APIVersion), fn: c.putAlertSilence, role: auth.RoleOperator},
+		{verb: "DELETE", path: clusterPath("/alerts/silences/{name}", cluster.APIVersion), fn: c.deleteAlertSilence, role: auth.RoleOperator},
+		{verb: "GET", path: clusterPath("/alerts/{resource}", cluster.APIVersion), fn: c.enumerateAlerts, role: auth.RoleViewer},
+		{verb: "PUT", path: clusterPath("/alerts/{resource}/{id}", cluster.APIVersion), fn: c.clearAlert, role: auth.RoleOperator},
+		{verb: "DELETE", path: clusterPath("/alerts/{resource}/{id}", cluster.APIVersion), fn: c.eraseAlert, role: auth.RoleOperator},
+		{verb: "PUT", path: clusterPath("/alerts/{resource}/{id}/ack", cluster.APIVersion), fn: c.acknowledgeAlert, role: auth.RoleOperator},
 	}
 }
 
@@ -537,7 +548,7 @@ func (c *clusterApi) enumerateAlerts(w http.ResponseWriter, r *http.Request) {
 
 	timeEnd := params["timeend"]
 	if timeEnd != nil {
-		tS, err = time.Parse(api.TimeLayout, timeEnd[0])
+		tE, err = time.Parse(api.TimeLayout, timeEnd[0])
 		if err != nil {
 			c.sendError(c.name, method, w, "Invalid timeend param", http.StatusBadRequest)
 			return
@@ -558,6 +569,163 @@ func (c *clusterApi) enumerateAlerts(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(alerts)
 }
 
+// swagger:operation GET /cluster/alerts cluster alerts enumerate enumerateAlertsWithFilter
+//
+// This will return a page of the alerts that match the query params
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// - name: Resource
+//   in: query
+//   description: Resource type of the alerts such as volume
+//   required: false
+//   type: string
+// - name: ResourceID
+//   in: query
+//   description: Resource of the alerts
+//   required: false
+//   type: string
+// - name: Severity
+//   in: query
+//   description: Least severity of the alerts such as warning
+//   required: false
+//   type: string
+// - name: Cleared
+//   in: query
+//   description: true for cleared alerts, false for active ones
+//   required: false
+//   type: boolean
+// - name: AlertType
+//   in: query
+//   description: Type of the alerts
+//   required: false
+//   type: integer
+// - name: TimeStart
+//   in: query
+//   description: RFC 3339 time the alerts were last seen after
+//   required: false
+//   type: string
+//   format: date-time
+// - name: TimeEnd
+//   in: query
+//   description: RFC 3339 time the alerts were last seen before
+//   required: false
+//   type: string
+//   format: date-time
+// - name: Text
+//   in: query
+//   description: Text in the message of the alerts, ignoring case
+//   required: false
+//   type: string
+// - name: SortBy
+//   in: query
+//   description: id, last_seen, first_seen, severity or count
+//   required: false
+//   type: string
+// - name: Descending
+//   in: query
+//   description: Reverse the order of the alerts
+//   required: false
+//   type: boolean
+// - name: Limit
+//   in: query
+//   description: Maximum number of alerts to return
+//   required: false
+//   type: integer
+// - name: Token
+//   in: query
+//   description: NextToken of the previous page
+//   required: false
+//   type: string
+// responses:
+//   '200':
+//      description: a page of alerts
+//      schema:
+//         $ref: '#/definitions/AlertList'
+func (c *clusterApi) enumerateAlertsWithFilter(w http.ResponseWriter, r *http.Request) {
+	method := "enumerateAlertsWithFilter"
+
+	filter, err := parseAlertFilter(r.URL.Query())
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	inst, err := cluster.Inst()
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	list, err := inst.EnumerateAlertsWithFilter(filter)
+	if err == alert.ErrInvalidToken {
+		c.sendError(c.name, method, w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(list)
+}
+
+// parseAlertFilter returns the alert filter in the query params.
+func parseAlertFilter(params url.Values) (*api.AlertFilter, error) {
+	filter := &api.AlertFilter{
+		ResourceId: params.Get(api.OptResourceID),
+		Text:       params.Get(api.OptText),
+		Token:      params.Get(api.OptToken),
+	}
+	var err error
+	if v := params.Get(api.OptResource); v != "" {
+		if filter.Resource, err = handleResourceType(v); err != nil {
+			return nil, err
+		}
+	}
+	if v := params.Get(api.OptSeverity); v != "" {
+		if filter.Severity, err = api.SeverityTypeSimpleValueOf(v); err != nil {
+			return nil, err
+		}
+	}
+	if v := params.Get(api.OptCleared); v != "" {
+		cleared, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s %q", api.OptCleared, v)
+		}
+		filter.Cleared = &cleared
+	}
+	if v := params.Get(api.OptAlertType); v != "" {
+		if filter.AlertType, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, fmt.Errorf("Invalid %s %q", api.OptAlertType, v)
+		}
+	}
+	if v := params.Get(api.OptTimeStart); v != "" {
+		if filter.TimeStart, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %v", api.OptTimeStart, err)
+		}
+	}
+	if v := params.Get(api.OptTimeEnd); v != "" {
+		if filter.TimeEnd, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %v", api.OptTimeEnd, err)
+		}
+	}
+	if filter.SortBy, err = api.AlertSortValueOf(params.Get(api.OptSortBy)); err != nil {
+		return nil, err
+	}
+	if v := params.Get(api.OptDescending); v != "" {
+		if filter.Descending, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("Invalid %s %q", api.OptDescending, v)
+		}
+	}
+	if v := params.Get(api.OptLimit); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 0 {
+			return nil, fmt.Errorf("Invalid %s %q", api.OptLimit, v)
+		}
+	}
+	return filter, nil
+}
+
 // swagger:operation PUT /cluster/alerts/{resource}/{id} cluster alerts clear clearAlert
 //
 // This will clear alert {id} with resourcetype {resource}
@@ -648,6 +816,311 @@ func (c *clusterApi) eraseAlert(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode("Successfully erased Alert")
 }
 
+// swagger:operation PUT /cluster/alerts/{resource}/{id}/ack cluster alerts acknowledge acknowledgeAlert
+//
+// This will acknowledge alert {id} with resourcetype {resource}
+//
+// ---
+// consumes:
+// - application/json
+// produces:
+// - application/json
+// parameters:
+// - name: resource
+//   in: path
+//   description: resourcetype to get alerts with
+//   required: true
+//   schema:
+//    "$ref": "#/definitions/ResourceType"
+// - name: id
+//   in: path
+//   description: id to get alerts with
+//   required: true
+// - name: acknowledgement
+//   in: body
+//   description: who acknowledges the alert and why
+//   schema:
+//    "$ref": "#/definitions/AlertAcknowledgement"
+// responses:
+//   '200':
+//      description: success message
+//      schema:
+//       type: string
+func (c *clusterApi) acknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	method := "acknowledgeAlert"
+
+	resourceType, alertId, err := c.getAlertParams(w, r, method)
+	if err != nil {
+		return
+	}
+
+	var ack api.AlertAcknowledgement
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&ack); err != nil {
+			c.sendError(c.name, method, w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	inst, err := cluster.Inst()
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = inst.AcknowledgeAlert(resourceType, alertId, requestUser(r, ack.User), ack.Comment)
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode("Successfully acknowledged Alert")
+}
+
+// swagger:operation GET /cluster/alerts/silences cluster alerts silences enumerateAlertSilences
+//
+// This will return the alert silences that have not expired
+//
+// ---
+// produces:
+// - application/json
+// responses:
+//   '200':
+//      description: a list of alert silences
+//      schema:
+//       type: array
+//       items:
+//          $ref: '#/definitions/AlertSilence'
+func (c *clusterApi) enumerateAlertSilences(w http.ResponseWriter, r *http.Request) {
+	method := "enumerateAlertSilences"
+
+	inst, err := cluster.Inst()
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	silences, err := inst.EnumerateAlertSilences()
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if silences == nil {
+		silences = []*api.AlertSilence{}
+	}
+	json.NewEncoder(w).Encode(silences)
+}
+
+// swagger:operation POST /cluster/alerts/silences cluster alerts silences putAlertSilence
+//
+// This will create or replace the alert silence with the name in the request
+//
+// ---
+// consumes:
+// - application/json
+// produces:
+// - application/json
+// parameters:
+// - name: silence
+//   in: body
+//   description: alert silence to create or replace
+//   required: true
+//   schema:
+//    "$ref": "#/definitions/AlertSilence"
+// responses:
+//   '200':
+//      description: success message
+//      schema:
+//       type: string
+func (c *clusterApi) putAlertSilence(w http.ResponseWriter, r *http.Request) {
+	method := "putAlertSilence"
+
+	var silence api.AlertSilence
+	if err := json.NewDecoder(r.Body).Decode(&silence); err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	silence.User = requestUser(r, silence.User)
+	if err := silence.Validate(); err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	inst, err := cluster.Inst()
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := inst.PutAlertSilence(&silence); err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode("Successfully updated alert silence")
+}
+
+// swagger:operation DELETE /cluster/alerts/silences/{name} cluster alerts silences deleteAlertSilence
+//
+// This will delete alert silence {name}
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// - name: name
+//   in: path
+//   description: name of the alert silence
+//   required: true
+//   type: string
+// responses:
+//   '200':
+//      description: success message
+//      schema:
+//       type: string
+func (c *clusterApi) deleteAlertSilence(w http.ResponseWriter, r *http.Request) {
+	method := "deleteAlertSilence"
+
+	name := mux.Vars(r)["name"]
+
+	inst, err := cluster.Inst()
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := inst.DeleteAlertSilence(name); err != nil {
+		status := http.StatusInternalServerError
+		if err == alert.ErrSilenceNotFound {
+			status = http.StatusNotFound
+		}
+		c.sendError(c.name, method, w, err.Error(), status)
+		return
+	}
+	json.NewEncoder(w).Encode("Successfully deleted alert silence")
+}
+
+// swagger:operation GET /cluster/alerts/sinks cluster alerts sinks enumerateAlertSinks
+//
+// This will return the sinks alert events are sent to, without their
+// webhook secrets and mail passwords
+//
+// ---
+// produces:
+// - application/json
+// responses:
+//   '200':
+//      description: a list of alert sinks
+//      schema:
+//       type: array
+//       items:
+//          $ref: '#/definitions/AlertSink'
+func (c *clusterApi) enumerateAlertSinks(w http.ResponseWriter, r *http.Request) {
+	method := "enumerateAlertSinks"
+
+	inst, err := cluster.Inst()
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sinks, err := inst.EnumerateAlertSinks()
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	redacted := make([]*api.AlertSink, 0, len(sinks))
+	for _, sink := range sinks {
+		redacted = append(redacted, sink.Redacted())
+	}
+	json.NewEncoder(w).Encode(redacted)
+}
+
+// swagger:operation POST /cluster/alerts/sinks cluster alerts sinks putAlertSink
+//
+// This will create or replace the alert sink with the name in the request
+//
+// ---
+// consumes:
+// - application/json
+// produces:
+// - application/json
+// parameters:
+// - name: sink
+//   in: body
+//   description: alert sink to create or replace
+//   required: true
+//   schema:
+//    "$ref": "#/definitions/AlertSink"
+// responses:
+//   '200':
+//      description: success message
+//      schema:
+//       type: string
+func (c *clusterApi) putAlertSink(w http.ResponseWriter, r *http.Request) {
+	method := "putAlertSink"
+
+	var sink api.AlertSink
+	if err := json.NewDecoder(r.Body).Decode(&sink); err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := sink.Validate(); err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	inst, err := cluster.Inst()
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := inst.PutAlertSink(&sink); err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode("Successfully updated alert sink")
+}
+
+// swagger:operation DELETE /cluster/alerts/sinks/{name} cluster alerts sinks deleteAlertSink
+//
+// This will delete alert sink {name}
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// - name: name
+//   in: path
+//   description: name of the alert sink
+//   required: true
+//   type: string
+// responses:
+//   '200':
+//      description: success message
+//      schema:
+//       type: string
+func (c *clusterApi) deleteAlertSink(w http.ResponseWriter, r *http.Request) {
+	method := "deleteAlertSink"
+
+	name := mux.Vars(r)["name"]
+
+	inst, err := cluster.Inst()
+	if err != nil {
+		c.sendError(c.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := inst.DeleteAlertSink(name); err != nil {
+		status := http.StatusInternalServerError
+		if err == alert.ErrSinkNotFound {
+			status = http.StatusNotFound
+		}
+		c.sendError(c.name, method, w, err.Error(), status)
+		return
+	}
+	json.NewEncoder(w).Encode("Successfully deleted alert sink")
+}
+
 func (c *clusterApi) getAlertParams(w http.ResponseWriter, r *http.Request, method string) (api.ResourceType, int64, error) {
 	var (
 		resourceType api.ResourceType
@@ -4,14 +4,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/libopenstorage/openstorage/alert"
 	"github.com/libopenstorage/openstorage/api"
 	client "github.com/libopenstorage/openstorage/api/client/cluster"
 	"github.com/libopenstorage/openstorage/cluster"
 	mockcluster "github.com/libopenstorage/openstorage/cluster/mock"
+	"github.com/libopenstorage/openstorage/pkg/auth"
 	"github.com/stretchr/testify/assert"
 
+	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 	"github.com/kubernetes-csi/csi-test/utils"
 )
 
@@ -78,3 +83,202 @@ func TestServerNodeStatus(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, api.Status_STATUS_OK, status)
 }
+
+func TestServerAlertSinks(t *testing.T) {
+	c := newTestClutser(t)
+	defer c.Finish()
+
+	capi := &clusterApi{}
+	router := mux.NewRouter()
+	for _, route := range capi.Routes() {
+		router.Methods(route.verb).
+			Path(route.path).
+			Handler(http.HandlerFunc(route.fn))
+	}
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	restClient, err := client.NewClusterClient(ts.URL, cluster.APIVersion)
+	assert.NoError(t, err)
+	manager := client.ClusterManager(restClient)
+
+	sink := &api.AlertSink{
+		Name:    "hook",
+		Type:    api.AlertSinkWebhook,
+		Webhook: &api.WebhookSinkConfig{URL: "http://localhost/hook", Secret: "secret"},
+	}
+	c.MockCluster().
+		EXPECT().
+		PutAlertSink(sink).
+		Return(nil)
+	c.MockCluster().
+		EXPECT().
+		EnumerateAlertSinks().
+		Return([]*api.AlertSink{sink}, nil)
+	c.MockCluster().
+		EXPECT().
+		DeleteAlertSink("hook").
+		Return(nil)
+	c.MockCluster().
+		EXPECT().
+		DeleteAlertSink("missing").
+		Return(alert.ErrSinkNotFound)
+
+	assert.NoError(t, manager.PutAlertSink(sink))
+	assert.Error(t, manager.PutAlertSink(&api.AlertSink{Name: "bad", Type: api.AlertSinkSMTP}))
+
+	// Secrets are not returned.
+	sinks, err := manager.EnumerateAlertSinks()
+	assert.NoError(t, err)
+	assert.Len(t, sinks, 1)
+	assert.Equal(t, sink.Webhook.URL, sinks[0].Webhook.URL)
+	assert.Empty(t, sinks[0].Webhook.Secret)
+
+	assert.NoError(t, manager.DeleteAlertSink("hook"))
+	err = manager.DeleteAlertSink("missing")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), alert.ErrSinkNotFound.Error())
+}
+
+func TestServerAlertSilences(t *testing.T) {
+	c := newTestClutser(t)
+	defer c.Finish()
+
+	capi := &clusterApi{}
+	router := mux.NewRouter()
+	for _, route := range capi.Routes() {
+		router.Methods(route.verb).
+			Path(route.path).
+			Handler(authorize(route, false))
+	}
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	restClient, err := client.NewClusterClient(ts.URL, cluster.APIVersion)
+	assert.NoError(t, err)
+	manager := client.ClusterManager(restClient)
+
+	silence := &api.AlertSilence{
+		Name:       "maintenance",
+		Resource:   api.ResourceType_RESOURCE_TYPE_NODE,
+		ResourceId: "node1",
+		Expiry:     time.Now().Add(time.Hour).UTC(),
+		User:       "bob",
+	}
+	c.MockCluster().
+		EXPECT().
+		AcknowledgeAlert(api.ResourceType_RESOURCE_TYPE_VOLUME, int64(5), "bob", "looking").
+		Return(nil)
+	c.MockCluster().
+		EXPECT().
+		PutAlertSilence(gomock.Any()).
+		Do(func(s *api.AlertSilence) {
+			assert.Equal(t, silence.Name, s.Name)
+			assert.Equal(t, "bob", s.User)
+		}).
+		Return(nil)
+	c.MockCluster().
+		EXPECT().
+		EnumerateAlertSilences().
+		Return([]*api.AlertSilence{silence}, nil)
+	c.MockCluster().
+		EXPECT().
+		DeleteAlertSilence("missing").
+		Return(alert.ErrSilenceNotFound)
+
+	assert.NoError(t, manager.AcknowledgeAlert(api.ResourceType_RESOURCE_TYPE_VOLUME, 5, "bob", "looking"))
+	assert.NoError(t, manager.PutAlertSilence(silence))
+	assert.Error(t, manager.PutAlertSilence(&api.AlertSilence{Name: "expired"}))
+	silences, err := manager.EnumerateAlertSilences()
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.AlertSilence{silence}, silences)
+	err = manager.DeleteAlertSilence("missing")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), alert.ErrSilenceNotFound.Error())
+
+	// With authentication the user is the subject of the token.
+	a, err := auth.NewJwtAuthenticator(&auth.JwtConfig{SharedSecret: "secret"})
+	assert.NoError(t, err)
+	SetAuthenticator(a)
+	defer SetAuthenticator(nil)
+	token, err := jwt.NewWithClaims(
+		jwt.SigningMethodHS256,
+		&auth.Claims{
+			StandardClaims: jwt.StandardClaims{Subject: "alice"},
+			Roles:          []string{"operator"},
+		},
+	).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+	restClient, err = client.NewAuthClusterClient(ts.URL, cluster.APIVersion, "", token)
+	assert.NoError(t, err)
+
+	c.MockCluster().
+		EXPECT().
+		AcknowledgeAlert(api.ResourceType_RESOURCE_TYPE_VOLUME, int64(5), "alice", "looking").
+		Return(nil)
+	assert.NoError(t, client.ClusterManager(restClient).
+		AcknowledgeAlert(api.ResourceType_RESOURCE_TYPE_VOLUME, 5, "bob", "looking"))
+}
+
+func TestServerAlertsWithFilter(t *testing.T) {
+	c := newTestClutser(t)
+	defer c.Finish()
+
+	capi := &clusterApi{}
+	router := mux.NewRouter()
+	for _, route := range capi.Routes() {
+		router.Methods(route.verb).
+			Path(route.path).
+			Handler(http.HandlerFunc(route.fn))
+	}
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	restClient, err := client.NewClusterClient(ts.URL, cluster.APIVersion)
+	assert.NoError(t, err)
+	manager := client.ClusterManager(restClient)
+
+	cleared := false
+	filter := &api.AlertFilter{
+		Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+		ResourceId: "vol1",
+		Severity:   api.SeverityType_SEVERITY_TYPE_WARNING,
+		Cleared:    &cleared,
+		AlertType:  7,
+		TimeStart:  time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC),
+		TimeEnd:    time.Date(2018, 3, 2, 10, 0, 0, 0, time.UTC),
+		Text:       "down",
+		SortBy:     api.AlertSortLastSeen,
+		Descending: true,
+		Limit:      10,
+		Token:      "dG9rZW4",
+	}
+	c.MockCluster().
+		EXPECT().
+		EnumerateAlertsWithFilter(filter).
+		Return(&api.AlertList{
+			Alerts:    []*api.Alert{{Id: 3, ResourceId: "vol1"}},
+			NextToken: "bmV4dA",
+		}, nil)
+	list, err := manager.EnumerateAlertsWithFilter(filter)
+	assert.NoError(t, err)
+	assert.Len(t, list.Alerts, 1)
+	assert.Equal(t, int64(3), list.Alerts[0].Id)
+	assert.Equal(t, "bmV4dA", list.NextToken)
+
+	c.MockCluster().
+		EXPECT().
+		EnumerateAlertsWithFilter(&api.AlertFilter{SortBy: api.AlertSortID, Token: "bad"}).
+		Return(nil, alert.ErrInvalidToken)
+	_, err = manager.EnumerateAlertsWithFilter(&api.AlertFilter{Token: "bad"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), alert.ErrInvalidToken.Error())
+
+	_, err = manager.EnumerateAlertsWithFilter(&api.AlertFilter{SortBy: "color"})
+	assert.Error(t, err)
+
+	// Both ends of the time range of the alerts of a resource are passed on.
+	c.MockCluster().
+		EXPECT().
+		EnumerateAlerts(filter.TimeStart, filter.TimeEnd, api.ResourceType_RESOURCE_TYPE_VOLUME).
+		Return(&api.Alerts{}, nil)
+	_, err = manager.EnumerateAlerts(filter.TimeStart, filter.TimeEnd, api.ResourceType_RESOURCE_TYPE_VOLUME)
+	assert.NoError(t, err)
+}
@@ -92,18 +92,21 @@ func (d *driver) volNotMounted(request string, id string) error {
 	return err
 }
 
+// Routes of the docker volume plugin API. Docker cannot present a token, so
+// these routes are open on the unix socket. On the plugin port they need a
+// token when authentication is enabled.
 func (d *driver) Routes() []*Route {
 	return []*Route{
-		{verb: "POST", path: volDriverPath("Create"), fn: d.create},
-		{verb: "POST", path: volDriverPath("Remove"), fn: d.remove},
-		{verb: "POST", path: volDriverPath("Mount"), fn: d.mount},
-		{verb: "POST", path: volDriverPath("Path"), fn: d.path},
-		{verb: "POST", path: volDriverPath("List"), fn: d.list},
-		{verb: "POST", path: volDriverPath("Get"), fn: d.get},
-		{verb: "POST", path: volDriverPath("Unmount"), fn: d.unmount},
-		{verb: "POST", path: volDriverPath("Capabilities"), fn: d.capabilities},
-		{verb: "POST", path: "/Plugin.Activate", fn: d.handshake},
-		{verb: "GET", path: "/status", fn: d.status},
+		{verb: "POST", path: volDriverPath("Create"), fn: d.create, open: true},
+		{verb: "POST", path: volDriverPath("Remove"), fn: d.remove, open: true},
+		{verb: "POST", path: volDriverPath("Mount"), fn: d.mount, open: true},
+		{verb: "POST", path: volDriverPath("Path"), fn: d.path, open: true},
+		{verb: "POST", path: volDriverPath("List"), fn: d.list, open: true},
+		{verb: "POST", path: volDriverPath("Get"), fn: d.get, open: true},
+		{verb: "POST", path: volDriverPath("Unmount"), fn: d.unmount, open: true},
+		{verb: "POST", path: volDriverPath("Capabilities"), fn: d.capabilities, open: true},
+		{verb: "POST", path: "/Plugin.Activate", fn: d.handshake, open: true},
+		{verb: "GET", path: "/status", fn: d.status, open: true},
 	}
 }
 
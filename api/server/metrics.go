@@ -0,0 +1,279 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"go.pedge.io/dlog"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/cluster"
+	"github.com/libopenstorage/openstorage/volume"
+)
+
+const metricsNamespace = "osd"
+
+var (
+	restRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "rest",
+			Name:      "requests_total",
+			Help:      "Number of REST requests by route and status code.",
+		},
+		[]string{"method", "route", "code"},
+	)
+	restLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "rest",
+			Name:      "request_duration_seconds",
+			Help:      "Time taken to serve REST requests by route.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method", "route"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(restRequests, restLatency)
+}
+
+// statusWriter remembers the status code written to a ResponseWriter.
+type statusWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.code = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// instrument wraps fn so that the requests it serves for route are counted
+// and timed. Requests are labelled by the path template of the route, not
+// by the requested path, to keep the number of series bounded.
+func instrument(route *Route, fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+		fn(sw, r)
+		restRequests.WithLabelValues(route.verb, route.path,
+			strconv.Itoa(sw.code)).Inc()
+		restLatency.WithLabelValues(route.verb, route.path).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// writeMetrics writes the metrics of g in the format negotiated with the
+// client. Metrics that could be gathered are written even if others failed.
+func writeMetrics(w http.ResponseWriter, r *http.Request, g prometheus.Gatherer) {
+	mfs, err := g.Gather()
+	if err != nil {
+		dlog.Warnf("Failed to gather metrics: %v", err)
+		if len(mfs) == 0 {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	contentType := expfmt.Negotiate(r.Header)
+	w.Header().Set("Content-Type", string(contentType))
+	enc := expfmt.NewEncoder(w, contentType)
+	for _, mf := range mfs {
+		if err := enc.Encode(mf); err != nil {
+			dlog.Warnf("Failed to encode metrics: %v", err)
+			return
+		}
+	}
+}
+
+var (
+	volumeLabels = []string{"volume", "name"}
+
+	volumeReadsDesc = prometheus.NewDesc(
+		"osd_volume_reads_total",
+		"Reads completed successfully.",
+		volumeLabels, nil,
+	)
+	volumeReadBytesDesc = prometheus.NewDesc(
+		"osd_volume_read_bytes_total",
+		"Bytes read.",
+		volumeLabels, nil,
+	)
+	volumeReadSecondsDesc = prometheus.NewDesc(
+		"osd_volume_read_seconds_total",
+		"Time spent in reads.",
+		volumeLabels, nil,
+	)
+	volumeWritesDesc = prometheus.NewDesc(
+		"osd_volume_writes_total",
+		"Writes completed successfully.",
+		volumeLabels, nil,
+	)
+	volumeWriteBytesDesc = prometheus.NewDesc(
+		"osd_volume_write_bytes_total",
+		"Bytes written.",
+		volumeLabels, nil,
+	)
+	volumeWriteSecondsDesc = prometheus.NewDesc(
+		"osd_volume_write_seconds_total",
+		"Time spent in writes.",
+		volumeLabels, nil,
+	)
+	volumeIOSecondsDesc = prometheus.NewDesc(
+		"osd_volume_io_seconds_total",
+		"Time spent doing IOs.",
+		volumeLabels, nil,
+	)
+	volumeIOProgressDesc = prometheus.NewDesc(
+		"osd_volume_io_in_progress",
+		"IOs currently in progress.",
+		volumeLabels, nil,
+	)
+	volumeUsedBytesDesc = prometheus.NewDesc(
+		"osd_volume_used_bytes",
+		"Bytes used by the volume.",
+		volumeLabels, nil,
+	)
+	volumeSizeBytesDesc = prometheus.NewDesc(
+		"osd_volume_size_bytes",
+		"Provisioned size of the volume.",
+		volumeLabels, nil,
+	)
+	nodeStatusDesc = prometheus.NewDesc(
+		"osd_node_status",
+		"Status of this node, 1 for the current status.",
+		[]string{"status"}, nil,
+	)
+	alertsDesc = prometheus.NewDesc(
+		"osd_alerts",
+		"Alerts that are not cleared by resource and severity.",
+		[]string{"resource", "severity"}, nil,
+	)
+
+	alertResources = []api.ResourceType{
+		api.ResourceType_RESOURCE_TYPE_VOLUME,
+		api.ResourceType_RESOURCE_TYPE_NODE,
+		api.ResourceType_RESOURCE_TYPE_CLUSTER,
+		api.ResourceType_RESOURCE_TYPE_DRIVE,
+	}
+	alertSeverities = []api.SeverityType{
+		api.SeverityType_SEVERITY_TYPE_ALARM,
+		api.SeverityType_SEVERITY_TYPE_WARNING,
+		api.SeverityType_SEVERITY_TYPE_NOTIFY,
+	}
+)
+
+// stateCollector collects the state of the volumes of a driver, of this
+// node and of the cluster alerts every time it is scraped.
+type stateCollector struct {
+	driver volume.VolumeDriver
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		volumeReadsDesc,
+		volumeReadBytesDesc,
+		volumeReadSecondsDesc,
+		volumeWritesDesc,
+		volumeWriteBytesDesc,
+		volumeWriteSecondsDesc,
+		volumeIOSecondsDesc,
+		volumeIOProgressDesc,
+		volumeUsedBytesDesc,
+		volumeSizeBytesDesc,
+		nodeStatusDesc,
+		alertsDesc,
+	} {
+		ch <- d
+	}
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectVolumes(ch)
+
+	inst, err := cluster.Inst()
+	if err != nil {
+		// Not in cluster mode.
+		return
+	}
+	c.collectNode(ch, inst)
+	c.collectAlerts(ch, inst)
+}
+
+func (c *stateCollector) collectVolumes(ch chan<- prometheus.Metric) {
+	vols, err := c.driver.Enumerate(&api.VolumeLocator{}, nil)
+	if err != nil {
+		dlog.Warnf("Failed to enumerate volumes for metrics: %v", err)
+		return
+	}
+	for _, v := range vols {
+		labels := []string{v.GetId(), v.GetLocator().GetName()}
+		gauge := func(desc *prometheus.Desc, value uint64) {
+			ch <- prometheus.MustNewConstMetric(desc,
+				prometheus.GaugeValue, float64(value), labels...)
+		}
+		counter := func(desc *prometheus.Desc, value float64) {
+			ch <- prometheus.MustNewConstMetric(desc,
+				prometheus.CounterValue, value, labels...)
+		}
+
+		gauge(volumeSizeBytesDesc, v.GetSpec().GetSize())
+		if used, err := c.driver.UsedSize(v.GetId()); err == nil {
+			gauge(volumeUsedBytesDesc, used)
+		} else if err != volume.ErrNotSupported {
+			dlog.Warnf("Failed to get used size of volume %v: %v", v.GetId(), err)
+		}
+
+		stats, err := c.driver.Stats(v.GetId(), true)
+		if err != nil {
+			if err != volume.ErrNotSupported {
+				dlog.Warnf("Failed to get stats of volume %v: %v", v.GetId(), err)
+			}
+			continue
+		}
+		counter(volumeReadsDesc, float64(stats.Reads))
+		counter(volumeReadBytesDesc, float64(stats.ReadBytes))
+		counter(volumeReadSecondsDesc, float64(stats.ReadMs)/1000)
+		counter(volumeWritesDesc, float64(stats.Writes))
+		counter(volumeWriteBytesDesc, float64(stats.WriteBytes))
+		counter(volumeWriteSecondsDesc, float64(stats.WriteMs)/1000)
+		counter(volumeIOSecondsDesc, float64(stats.IoMs)/1000)
+		gauge(volumeIOProgressDesc, stats.IoProgress)
+	}
+}
+
+func (c *stateCollector) collectNode(ch chan<- prometheus.Metric, inst cluster.Cluster) {
+	status, err := inst.NodeStatus()
+	if err != nil {
+		dlog.Warnf("Failed to get node status for metrics: %v", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(nodeStatusDesc,
+		prometheus.GaugeValue, 1, status.SimpleString())
+}
+
+func (c *stateCollector) collectAlerts(ch chan<- prometheus.Metric, inst cluster.Cluster) {
+	for _, resource := range alertResources {
+		alerts, err := inst.EnumerateAlerts(time.Time{}, time.Now(), resource)
+		if err != nil {
+			dlog.Warnf("Failed to enumerate %v alerts for metrics: %v",
+				resource.SimpleString(), err)
+			continue
+		}
+		counts := make(map[api.SeverityType]int)
+		for _, a := range alerts.GetAlert() {
+			if !a.GetCleared() {
+				counts[a.GetSeverity()]++
+			}
+		}
+		for _, severity := range alertSeverities {
+			ch <- prometheus.MustNewConstMetric(alertsDesc,
+				prometheus.GaugeValue, float64(counts[severity]),
+				resource.SimpleString(), severity.SimpleString())
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"go.pedge.io/dlog"
 
 	"github.com/gorilla/mux"
+	"github.com/libopenstorage/openstorage/pkg/auth"
 )
 
 // Route is a specification and  handler for a REST endpoint.
@@ -17,6 +18,13 @@ type Route struct {
 	verb string
 	path string
 	fn   func(http.ResponseWriter, *http.Request)
+	// role is the least role a token must grant to use the route when
+	// authentication is enabled.
+	role auth.Role
+	// open routes are served without a token on the unix socket, since
+	// the container engine cannot present one. They need a token on the
+	// TCP port like the other routes.
+	open bool
 }
 
 func (r *Route) GetVerb() string {
@@ -31,6 +39,10 @@ func (r *Route) GetFn() func(http.ResponseWriter, *http.Request) {
 	return r.fn
 }
 
+func (r *Route) GetRole() auth.Role {
+	return r.role
+}
+
 // StartGraphAPI starts a REST server to receive GraphDriver commands from
 // the Linux container engine.
 func StartGraphAPI(name string, restBase string) error {
@@ -132,11 +144,6 @@ func startServer(name string, sockBase string, port uint16, routes []*Route) err
 		listener net.Listener
 		err      error
 	)
-	router := mux.NewRouter()
-	router.NotFoundHandler = http.HandlerFunc(notFound)
-	for _, v := range routes {
-		router.Methods(v.verb).Path(v.path).HandlerFunc(v.fn)
-	}
 	socket := path.Join(sockBase, name+".sock")
 	os.Remove(socket)
 	os.MkdirAll(path.Dir(socket), 0755)
@@ -147,14 +154,36 @@ func startServer(name string, sockBase string, port uint16, routes []*Route) err
 		dlog.Warnln("Cannot listen on UNIX socket: ", err)
 		return err
 	}
-	go http.Serve(listener, router)
+	go http.Serve(listener, newRouter(routes, true))
 	if port != 0 {
-		dlog.Printf("Starting REST service on port : %v", port)
-		go http.ListenAndServe(fmt.Sprintf(":%d", port), router)
+		s := &http.Server{
+			Addr:      fmt.Sprintf(":%d", port),
+			Handler:   newRouter(routes, false),
+			TLSConfig: getTLSConfig(),
+		}
+		if s.TLSConfig != nil {
+			dlog.Printf("Starting REST service on TLS port : %v", port)
+			// The certificates come from TLSConfig.
+			go s.ListenAndServeTLS("", "")
+		} else {
+			dlog.Printf("Starting REST service on port : %v", port)
+			go s.ListenAndServe()
+		}
 	}
 	return nil
 }
 
+// newRouter returns a router of routes, which are served on the unix socket
+// if local is true.
+func newRouter(routes []*Route, local bool) *mux.Router {
+	router := mux.NewRouter()
+	router.NotFoundHandler = http.HandlerFunc(notFound)
+	for _, v := range routes {
+		router.Methods(v.verb).Path(v.path).HandlerFunc(instrument(v, authorize(v, local)))
+	}
+	return router
+}
+
 type restServer interface {
 	Routes() []*Route
 	String() string
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"crypto/tls"
+	"sync"
+)
+
+var (
+	tlsLock   sync.RWMutex
+	tlsConfig *tls.Config
+)
+
+// SetTLSConfig makes REST servers started afterwards serve their TCP ports
+// over TLS with c. UNIX sockets are always served in plain text. A nil c,
+// the default, serves TCP ports in plain text as well.
+func SetTLSConfig(c *tls.Config) {
+	tlsLock.Lock()
+	defer tlsLock.Unlock()
+	tlsConfig = c
+}
+
+func getTLSConfig() *tls.Config {
+	tlsLock.RLock()
+	defer tlsLock.RUnlock()
+	return tlsConfig
+}
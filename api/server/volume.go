@@ -4,17 +4,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/libopenstorage/openstorage/api"
 	"github.com/libopenstorage/openstorage/api/errors"
+	"github.com/libopenstorage/openstorage/pkg/auth"
+	"github.com/libopenstorage/openstorage/pkg/seed"
+	"github.com/libopenstorage/openstorage/pkg/util"
 	"github.com/libopenstorage/openstorage/volume"
 	"github.com/libopenstorage/openstorage/volume/drivers"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-const schedDriverPostFix = "-sched"
+const (
+	schedDriverPostFix = "-sched"
+
+	// inspectRole is the role required to inspect a volume.
+	inspectRole = auth.RoleViewer
+)
 
 type volAPI struct {
 	restBase
@@ -68,7 +79,8 @@ func (vd *volAPI) parseID(r *http.Request) (string, error) {
 
 // swagger:operation POST /osd-volumes volume create createVolume
 //
-// Creates a single volume with given spec.
+// Creates a single volume with given spec. A volume with a parent in its
+// source is created as a clone of the parent.
 //
 // ---
 // produces:
@@ -105,6 +117,40 @@ func (vd *volAPI) create(w http.ResponseWriter, r *http.Request) {
 		notFound(w, r)
 		return
 	}
+
+	// A clone is created from the parent given by name or ID. It has the
+	// spec of the parent unless another one is given.
+	if parentName := dcReq.GetSource().GetParent(); parentName != "" {
+		parent, err := util.VolumeFromName(d, parentName)
+		if err != nil {
+			dcRes.VolumeResponse = &api.VolumeResponse{Error: responseStatus(err)}
+			json.NewEncoder(w).Encode(&dcRes)
+			return
+		}
+		dcReq.Source.Parent = parent.GetId()
+		if dcReq.Spec == nil {
+			dcReq.Spec = parent.GetSpec()
+		}
+	}
+
+	// Seeding from a volume copies its data, so it requires the same
+	// access as an inspect of the volume.
+	if sourceName := seed.VolumeSourceID(dcReq.GetSource().GetSeed()); sourceName != "" {
+		if !allowed(r, inspectRole) {
+			vd.sendError(vd.name, method, w,
+				fmt.Sprintf("Role %v required to seed from a volume", inspectRole),
+				http.StatusForbidden)
+			return
+		}
+		source, err := util.VolumeFromName(d, sourceName)
+		if err != nil {
+			dcRes.VolumeResponse = &api.VolumeResponse{Error: responseStatus(err)}
+			json.NewEncoder(w).Encode(&dcRes)
+			return
+		}
+		dcReq.Source.Seed = "volume://" + source.GetId()
+	}
+
 	id, err := d.Create(dcReq.Locator, dcReq.Source, dcReq.Spec)
 	dcRes.VolumeResponse = &api.VolumeResponse{Error: responseStatus(err)}
 	dcRes.Id = id
@@ -139,7 +185,8 @@ func processErrorForVolSetResponse(action *api.VolumeStateAction, err error, res
 
 // swagger:operation PUT /osd-volumes/{id} volume update setVolume
 //
-// Updates a single volume with given spec.
+// Updates a single volume with given spec. A larger spec size expands the
+// volume and its filesystem.
 //
 // ---
 // produces:
@@ -433,6 +480,135 @@ func (vd *volAPI) enumerate(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(vols)
 }
 
+// swagger:operation GET /osd-volumes/list volume list listVolumes
+//
+// Enumerate volumes one page at a time, ordered by volume id.
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// - name: Name
+//   in: query
+//   description: User specified volume name (Case Sensitive)
+//   required: false
+//   type: string
+// - name: Selector
+//   in: query
+//   description: Label selector such as env=prod,tier!=web,zone in (a,b)
+//   required: false
+//   type: string
+// - name: State
+//   in: query
+//   description: Volume state such as attached, may be repeated
+//   required: false
+//   type: string
+// - name: Status
+//   in: query
+//   description: Volume status such as up, may be repeated
+//   required: false
+//   type: string
+// - name: AttachedOn
+//   in: query
+//   description: Node the volume is attached on
+//   required: false
+//   type: string
+// - name: CreatedAfter
+//   in: query
+//   description: RFC 3339 time the volume was created after
+//   required: false
+//   type: string
+//   format: date-time
+// - name: CreatedBefore
+//   in: query
+//   description: RFC 3339 time the volume was created before
+//   required: false
+//   type: string
+//   format: date-time
+// - name: Limit
+//   in: query
+//   description: Maximum number of volumes to return
+//   required: false
+//   type: integer
+// - name: Token
+//   in: query
+//   description: NextToken of the previous page
+//   required: false
+//   type: string
+// responses:
+//   '200':
+//      description: a page of volumes
+//      schema:
+//         $ref: '#/definitions/VolumeList'
+func (vd *volAPI) enumerateWithFilter(w http.ResponseWriter, r *http.Request) {
+	method := "enumerateWithFilter"
+
+	d, err := vd.getVolDriver(r)
+	if err != nil {
+		notFound(w, r)
+		return
+	}
+	filter, err := parseVolumeFilter(r.URL.Query())
+	if err != nil {
+		vd.sendError(vd.name, method, w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	list, err := d.EnumerateWithFilter(filter)
+	if err == volume.ErrInvalidToken {
+		vd.sendError(vd.name, method, w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		vd.sendError(vd.name, method, w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(list)
+}
+
+// parseVolumeFilter returns the volume filter in the query params.
+func parseVolumeFilter(params url.Values) (*api.VolumeFilter, error) {
+	filter := &api.VolumeFilter{
+		Name:       params.Get(api.OptName),
+		AttachedOn: params.Get(api.OptAttachedOn),
+		Token:      params.Get(api.OptToken),
+	}
+	var err error
+	if v := params.Get(api.OptSelector); v != "" {
+		if filter.Labels, err = api.ParseLabelSelector(v); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %v", api.OptSelector, err)
+		}
+	}
+	for _, v := range params[api.OptState] {
+		state, err := api.VolumeStateSimpleValueOf(v)
+		if err != nil {
+			return nil, err
+		}
+		filter.States = append(filter.States, state)
+	}
+	for _, v := range params[api.OptStatus] {
+		status, err := api.VolumeStatusSimpleValueOf(v)
+		if err != nil {
+			return nil, err
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	if v := params.Get(api.OptCreatedAfter); v != "" {
+		if filter.CreatedAfter, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %v", api.OptCreatedAfter, err)
+		}
+	}
+	if v := params.Get(api.OptCreatedBefore); v != "" {
+		if filter.CreatedBefore, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %v", api.OptCreatedBefore, err)
+		}
+	}
+	if v := params.Get(api.OptLimit); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 0 {
+			return nil, fmt.Errorf("Invalid %s %q", api.OptLimit, v)
+		}
+	}
+	return filter, nil
+}
+
 // swagger:operation POST /osd-snapshots snapshot create createSnap
 //
 // Take a snapshot of volume in SnapCreateRequest
@@ -620,6 +796,201 @@ func (vd *volAPI) snapEnumerate(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(snaps)
 }
 
+// swagger:operation DELETE /osd-snapshots/{id} snapshot delete deleteSnap
+//
+// Delete snapshot or clone with specified id.
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// - name: id
+//   in: path
+//   description: id of snapshot to delete
+//   required: true
+// - name: Cascade
+//   in: query
+//   description: delete snapshots and clones of this snapshot as well
+//   required: false
+//   type: boolean
+// responses:
+//  '200':
+//    description: volume response
+//    schema:
+//     "$ref": '#/definitions/VolumeResponse'
+//  default:
+//   description: unexpected error
+//   schema:
+//    "$ref": "#/definitions/VolumeResponse"
+func (vd *volAPI) snapDelete(w http.ResponseWriter, r *http.Request) {
+	var snapID string
+	var err error
+	method := "snapDelete"
+
+	if snapID, err = vd.parseID(r); err != nil {
+		e := fmt.Errorf("Failed to parse parse snapID: %s", err.Error())
+		vd.sendError(vd.name, method, w, e.Error(), http.StatusBadRequest)
+		return
+	}
+
+	d, err := vd.getVolDriver(r)
+	if err != nil {
+		notFound(w, r)
+		return
+	}
+
+	cascade := false
+	params := r.URL.Query()
+	if v := params.Get(api.OptCascade); v != "" {
+		if cascade, err = strconv.ParseBool(v); err != nil {
+			vd.sendError(vd.name, method, w, api.OptCascade+" must be bool",
+				http.StatusBadRequest)
+			return
+		}
+	}
+
+	vd.logRequest(method, snapID).Infof("cascade=%v", cascade)
+
+	volumeResponse := &api.VolumeResponse{}
+	if err := d.SnapDelete(snapID, cascade); err != nil {
+		volumeResponse.Error = responseStatus(err)
+	}
+	json.NewEncoder(w).Encode(volumeResponse)
+}
+
+// swagger:operation POST /osd-snapshots/promote/{id} snapshot promote promoteSnap
+//
+// Promote clone with specified id so it no longer depends on its parent.
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// - name: id
+//   in: path
+//   description: id of clone to promote
+//   required: true
+// responses:
+//  '200':
+//    description: volume response
+//    schema:
+//     "$ref": '#/definitions/VolumeResponse'
+//  default:
+//   description: unexpected error
+//   schema:
+//    "$ref": "#/definitions/VolumeResponse"
+func (vd *volAPI) promote(w http.ResponseWriter, r *http.Request) {
+	var cloneID string
+	var err error
+	method := "promote"
+
+	if cloneID, err = vd.parseID(r); err != nil {
+		e := fmt.Errorf("Failed to parse parse cloneID: %s", err.Error())
+		vd.sendError(vd.name, method, w, e.Error(), http.StatusBadRequest)
+		return
+	}
+
+	d, err := vd.getVolDriver(r)
+	if err != nil {
+		notFound(w, r)
+		return
+	}
+
+	vd.logRequest(method, cloneID).Infoln("")
+
+	volumeResponse := &api.VolumeResponse{}
+	if err := d.Promote(cloneID); err != nil {
+		volumeResponse.Error = responseStatus(err)
+	}
+	json.NewEncoder(w).Encode(volumeResponse)
+}
+
+// swagger:operation GET /osd-snapshots/tree/{id} snapshot tree snapTree
+//
+// Get the snapshots and clones descending from volume with specified id.
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// - name: id
+//   in: path
+//   description: id of the volume at the root of the tree
+//   required: true
+// responses:
+//  '200':
+//   description: snapshot tree
+//   schema:
+//    "$ref": "#/definitions/SnapshotTree"
+func (vd *volAPI) snapTree(w http.ResponseWriter, r *http.Request) {
+	var volumeID string
+	var err error
+	method := "snapTree"
+
+	if volumeID, err = vd.parseID(r); err != nil {
+		e := fmt.Errorf("Failed to parse parse volumeID: %s", err.Error())
+		vd.sendError(vd.name, method, w, e.Error(), http.StatusBadRequest)
+		return
+	}
+
+	d, err := vd.getVolDriver(r)
+	if err != nil {
+		notFound(w, r)
+		return
+	}
+
+	tree, err := d.SnapTree(volumeID)
+	if err != nil {
+		vd.sendError(vd.name, method, w, err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(tree)
+}
+
+// swagger:operation GET /osd-snapshots/chain/{id} snapshot chain snapChain
+//
+// Get the ancestors of volume with specified id, closest first.
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// - name: id
+//   in: path
+//   description: id of the volume to get ancestors for
+//   required: true
+// responses:
+//  '200':
+//   description: an array of volumes
+//   schema:
+//    type: array
+//    items:
+//     $ref: '#/definitions/Volume'
+func (vd *volAPI) snapChain(w http.ResponseWriter, r *http.Request) {
+	var volumeID string
+	var err error
+	method := "snapChain"
+
+	if volumeID, err = vd.parseID(r); err != nil {
+		e := fmt.Errorf("Failed to parse parse volumeID: %s", err.Error())
+		vd.sendError(vd.name, method, w, e.Error(), http.StatusBadRequest)
+		return
+	}
+
+	d, err := vd.getVolDriver(r)
+	if err != nil {
+		notFound(w, r)
+		return
+	}
+
+	chain, err := d.SnapChain(volumeID)
+	if err != nil {
+		vd.sendError(vd.name, method, w, err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(chain)
+}
+
 // swagger:operation GET /osd-volumes/stats/{id} volume stats statsVolume
 //
 // Get stats for volume with specified id.
@@ -891,6 +1262,33 @@ func (vd *volAPI) versions(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(versions)
 }
 
+// swagger:operation GET /metrics volume metrics metrics
+//
+// Get metrics of the volumes, this node, alerts and REST API calls in the
+// Prometheus exposition format.
+//
+// ---
+// produces:
+// - text/plain
+// responses:
+//   '200':
+//     description: Prometheus metrics
+//     type: string
+func (vd *volAPI) metrics(w http.ResponseWriter, r *http.Request) {
+	d, err := vd.getVolDriver(r)
+	if err != nil {
+		notFound(w, r)
+		return
+	}
+
+	state := prometheus.NewRegistry()
+	if err := state.Register(&stateCollector{driver: d}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeMetrics(w, r, prometheus.Gatherers{prometheus.DefaultGatherer, state})
+}
+
 func volVersion(route, version string) string {
 	if version == "" {
 		return "/" + route
@@ -913,26 +1311,32 @@ func credsPath(route, version string) string {
 
 func (vd *volAPI) Routes() []*Route {
 	return []*Route{
-		{verb: "GET", path: "/" + api.OsdVolumePath + "/versions", fn: vd.versions},
-		{verb: "POST", path: volPath("", volume.APIVersion), fn: vd.create},
-		{verb: "PUT", path: volPath("/{id}", volume.APIVersion), fn: vd.volumeSet},
-		{verb: "GET", path: volPath("", volume.APIVersion), fn: vd.enumerate},
-		{verb: "GET", path: volPath("/{id}", volume.APIVersion), fn: vd.inspect},
-		{verb: "DELETE", path: volPath("/{id}", volume.APIVersion), fn: vd.delete},
-		{verb: "GET", path: volPath("/stats", volume.APIVersion), fn: vd.stats},
-		{verb: "GET", path: volPath("/stats/{id}", volume.APIVersion), fn: vd.stats},
-		{verb: "GET", path: volPath("/usedsize", volume.APIVersion), fn: vd.usedsize},
-		{verb: "GET", path: volPath("/usedsize/{id}", volume.APIVersion), fn: vd.usedsize},
-		{verb: "GET", path: volPath("/requests", volume.APIVersion), fn: vd.requests},
-		{verb: "GET", path: volPath("/requests/{id}", volume.APIVersion), fn: vd.requests},
-		{verb: "POST", path: volPath("/quiesce/{id}", volume.APIVersion), fn: vd.quiesce},
-		{verb: "POST", path: volPath("/unquiesce/{id}", volume.APIVersion), fn: vd.unquiesce},
-		{verb: "POST", path: snapPath("", volume.APIVersion), fn: vd.snap},
-		{verb: "GET", path: snapPath("", volume.APIVersion), fn: vd.snapEnumerate},
-		{verb: "POST", path: snapPath("/restore/{id}", volume.APIVersion), fn: vd.restore},
-		{verb: "GET", path: credsPath("", volume.APIVersion), fn: vd.credsEnumerate},
-		{verb: "POST", path: credsPath("", volume.APIVersion), fn: vd.credsCreate},
-		{verb: "DELETE", path: credsPath("/{uuid}", volume.APIVersion), fn: vd.credsDelete},
-		{verb: "POST", path: credsPath("/validate/{uuid}", volume.APIVersion), fn: vd.credsValidate},
+		{verb: "GET", path: "/" + api.OsdVolumePath + "/versions", fn: vd.versions, role: auth.RoleViewer},
+		{verb: "GET", path: "/metrics", fn: vd.metrics, role: auth.RoleViewer},
+		{verb: "POST", path: volPath("", volume.APIVersion), fn: vd.create, role: auth.RoleOperator},
+		{verb: "PUT", path: volPath("/{id}", volume.APIVersion), fn: vd.volumeSet, role: auth.RoleOperator},
+		{verb: "GET", path: volPath("", volume.APIVersion), fn: vd.enumerate, role: auth.RoleViewer},
+		{verb: "GET", path: volPath("/list", volume.APIVersion), fn: vd.enumerateWithFilter, role: auth.RoleViewer},
+		{verb: "GET", path: volPath("/{id}", volume.APIVersion), fn: vd.inspect, role: inspectRole},
+		{verb: "DELETE", path: volPath("/{id}", volume.APIVersion), fn: vd.delete, role: auth.RoleOperator},
+		{verb: "GET", path: volPath("/stats", volume.APIVersion), fn: vd.stats, role: auth.RoleViewer},
+		{verb: "GET", path: volPath("/stats/{id}", volume.APIVersion), fn: vd.stats, role: auth.RoleViewer},
+		{verb: "GET", path: volPath("/usedsize", volume.APIVersion), fn: vd.usedsize, role: auth.RoleViewer},
+		{verb: "GET", path: volPath("/usedsize/{id}", volume.APIVersion), fn: vd.usedsize, role: auth.RoleViewer},
+		{verb: "GET", path: volPath("/requests", volume.APIVersion), fn: vd.requests, role: auth.RoleViewer},
+		{verb: "GET", path: volPath("/requests/{id}", volume.APIVersion), fn: vd.requests, role: auth.RoleViewer},
+		{verb: "POST", path: volPath("/quiesce/{id}", volume.APIVersion), fn: vd.quiesce, role: auth.RoleOperator},
+		{verb: "POST", path: volPath("/unquiesce/{id}", volume.APIVersion), fn: vd.unquiesce, role: auth.RoleOperator},
+		{verb: "POST", path: snapPath("", volume.APIVersion), fn: vd.snap, role: auth.RoleOperator},
+		{verb: "GET", path: snapPath("", volume.APIVersion), fn: vd.snapEnumerate, role: auth.RoleViewer},
+		{verb: "POST", path: snapPath("/restore/{id}", volume.APIVersion), fn: vd.restore, role: auth.RoleOperator},
+		{verb: "DELETE", path: snapPath("/{id}", volume.APIVersion), fn: vd.snapDelete, role: auth.RoleOperator},
+		{verb: "POST", path: snapPath("/promote/{id}", volume.APIVersion), fn: vd.promote, role: auth.RoleOperator},
+		{verb: "GET", path: snapPath("/tree/{id}", volume.APIVersion), fn: vd.snapTree, role: auth.RoleViewer},
+		{verb: "GET", path: snapPath("/chain/{id}", volume.APIVersion), fn: vd.snapChain, role: auth.RoleViewer},
+		{verb: "GET", path: credsPath("", volume.APIVersion), fn: vd.credsEnumerate, role: auth.RoleAdmin},
+		{verb: "POST", path: credsPath("", volume.APIVersion), fn: vd.credsCreate, role: auth.RoleAdmin},
+		{verb: "DELETE", path: credsPath("/{uuid}", volume.APIVersion), fn: vd.credsDelete, role: auth.RoleAdmin},
+		{verb: "POST", path: credsPath("/validate/{uuid}", volume.APIVersion), fn: vd.credsValidate, role: auth.RoleAdmin},
 	}
 }
@@ -280,7 +280,7 @@ func (d *specHandler) UpdateSpecFromOpts(opts map[string]string, spec *api.Volum
 			} else {
 				spec.GroupEnforced = groupEnforced
 			}
-		case api.SpecZones, api.SpecRacks:
+		case api.SpecZones, api.SpecRacks, api.SpecRegions:
 			locator.VolumeLabels[k] = v
 		case api.SpecRack:
 			locator.VolumeLabels[api.SpecRacks] = v
@@ -0,0 +1,150 @@
+package testing
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/libopenstorage/openstorage/api"
+	volumeclient "github.com/libopenstorage/openstorage/api/client/volume"
+	"github.com/libopenstorage/openstorage/api/server"
+	"github.com/libopenstorage/openstorage/pkg/auth"
+)
+
+const testSecret = "osd-test-secret"
+
+func enableAuth(t *testing.T) {
+	a, err := auth.NewJwtAuthenticator(&auth.JwtConfig{SharedSecret: testSecret})
+	assert.NoError(t, err)
+	server.SetAuthenticator(a)
+}
+
+func testToken(t *testing.T, roles ...string) string {
+	token, err := jwt.NewWithClaims(
+		jwt.SigningMethodHS256,
+		&auth.Claims{
+			StandardClaims: jwt.StandardClaims{Subject: "tester"},
+			Roles:          roles,
+		},
+	).SignedString([]byte(testSecret))
+	assert.NoError(t, err)
+	return token
+}
+
+func TestAuthVolumeCreate(t *testing.T) {
+	enableAuth(t)
+	defer server.SetAuthenticator(nil)
+
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	req := &api.VolumeCreateRequest{
+		Locator: &api.VolumeLocator{Name: "myvol"},
+		Source:  &api.Source{},
+		Spec:    &api.VolumeSpec{Size: 1234},
+	}
+
+	// No token
+	c, err := volumeclient.NewDriverClient(getBaseURL(), driver, version, "")
+	assert.NoError(t, err)
+	_, err = volumeclient.VolumeDriver(c).Create(req.GetLocator(), req.GetSource(), req.GetSpec())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+
+	// Token with too little access
+	c, err = volumeclient.NewAuthDriverClient(getBaseURL(), driver, version,
+		"", testToken(t, "viewer"), "")
+	assert.NoError(t, err)
+	_, err = volumeclient.VolumeDriver(c).Create(req.GetLocator(), req.GetSource(), req.GetSpec())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+
+	// Token with wrong signature
+	c.SetAccessToken(testToken(t, "operator") + "x")
+	_, err = volumeclient.VolumeDriver(c).Create(req.GetLocator(), req.GetSource(), req.GetSpec())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+
+	// Operator token
+	ts.MockDriver().
+		EXPECT().
+		Create(req.GetLocator(), req.GetSource(), req.GetSpec()).
+		Return("myid", nil)
+
+	c.SetAccessToken(testToken(t, "operator"))
+	id, err := volumeclient.VolumeDriver(c).Create(req.GetLocator(), req.GetSource(), req.GetSpec())
+	assert.NoError(t, err)
+	assert.Equal(t, "myid", id)
+}
+
+func TestAuthCredsRequireAdmin(t *testing.T) {
+	enableAuth(t)
+	defer server.SetAuthenticator(nil)
+
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	c, err := volumeclient.NewAuthDriverClient(getBaseURL(), driver, version,
+		"", testToken(t, "operator"), "")
+	assert.NoError(t, err)
+	_, err = volumeclient.VolumeDriver(c).CredsEnumerate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+
+	ts.MockDriver().
+		EXPECT().
+		CredsEnumerate().
+		Return(map[string]interface{}{}, nil)
+
+	c.SetAccessToken(testToken(t, "admin"))
+	_, err = volumeclient.VolumeDriver(c).CredsEnumerate()
+	assert.NoError(t, err)
+}
+
+func TestAuthPluginRoutes(t *testing.T) {
+	enableAuth(t)
+	defer server.SetAuthenticator(nil)
+
+	sockBase, err := ioutil.TempDir("", "osd-plugin")
+	assert.NoError(t, err)
+	defer os.RemoveAll(sockBase)
+	err = server.StartVolumePluginAPI(driver, sockBase, pluginPort)
+	assert.NoError(t, err)
+	time.Sleep(time.Second)
+
+	// Docker reaches the plugin on the unix socket without a token
+	local := &http.Client{
+		Transport: &http.Transport{
+			Dial: func(string, string) (net.Conn, error) {
+				return net.Dial("unix", filepath.Join(sockBase, driver+".sock"))
+			},
+		},
+	}
+	resp, err := local.Post("http://unix/VolumeDriver.Capabilities", "application/json", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The plugin port requires a token
+	url := host + ":" + strconv.Itoa(pluginPort) + "/VolumeDriver.Capabilities"
+	resp, err = http.Post(url, "application/json", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, err := http.NewRequest("POST", url, nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testToken(t, "viewer"))
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,104 @@
+package testing
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/libopenstorage/openstorage/api"
+	volumeclient "github.com/libopenstorage/openstorage/api/client/volume"
+	"github.com/libopenstorage/openstorage/cluster"
+)
+
+func TestMetrics(t *testing.T) {
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	oldInst := cluster.Inst
+	cluster.Inst = func() (cluster.Cluster, error) {
+		return ts.MockCluster(), nil
+	}
+	defer func() { cluster.Inst = oldInst }()
+
+	id := "myid"
+	vol := &api.Volume{
+		Id:      id,
+		Locator: &api.VolumeLocator{Name: "myvol"},
+		Spec:    &api.VolumeSpec{Size: 1024},
+	}
+
+	// A REST call to show up in the route metrics
+	ts.MockDriver().
+		EXPECT().
+		Inspect([]string{id}).
+		Return([]*api.Volume{vol}, nil)
+	c, err := volumeclient.NewDriverClient(getBaseURL(), driver, version, "")
+	assert.NoError(t, err)
+	_, err = volumeclient.VolumeDriver(c).Inspect([]string{id})
+	assert.NoError(t, err)
+
+	ts.MockDriver().
+		EXPECT().
+		Enumerate(&api.VolumeLocator{}, nil).
+		Return([]*api.Volume{vol}, nil)
+	ts.MockDriver().
+		EXPECT().
+		UsedSize(id).
+		Return(uint64(512), nil)
+	ts.MockDriver().
+		EXPECT().
+		Stats(id, true).
+		Return(&api.Stats{Reads: 7, ReadMs: 1500, Writes: 3, IoProgress: 2}, nil)
+	ts.MockCluster().
+		EXPECT().
+		NodeStatus().
+		Return(api.Status_STATUS_OK, nil)
+	for _, resource := range []api.ResourceType{
+		api.ResourceType_RESOURCE_TYPE_NODE,
+		api.ResourceType_RESOURCE_TYPE_CLUSTER,
+		api.ResourceType_RESOURCE_TYPE_DRIVE,
+	} {
+		ts.MockCluster().
+			EXPECT().
+			EnumerateAlerts(time.Time{}, gomock.Any(), resource).
+			Return(&api.Alerts{}, nil)
+	}
+	ts.MockCluster().
+		EXPECT().
+		EnumerateAlerts(time.Time{}, gomock.Any(), api.ResourceType_RESOURCE_TYPE_VOLUME).
+		Return(&api.Alerts{Alert: []*api.Alert{
+			{Severity: api.SeverityType_SEVERITY_TYPE_ALARM},
+			{Severity: api.SeverityType_SEVERITY_TYPE_ALARM},
+			{Severity: api.SeverityType_SEVERITY_TYPE_ALARM, Cleared: true},
+			{Severity: api.SeverityType_SEVERITY_TYPE_WARNING},
+		}}, nil)
+
+	resp, err := http.Get(getBaseURL() + "/metrics")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	b, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	body := string(b)
+
+	for _, line := range []string{
+		`osd_volume_reads_total{name="myvol",volume="myid"} 7`,
+		`osd_volume_read_seconds_total{name="myvol",volume="myid"} 1.5`,
+		`osd_volume_writes_total{name="myvol",volume="myid"} 3`,
+		`osd_volume_io_in_progress{name="myvol",volume="myid"} 2`,
+		`osd_volume_used_bytes{name="myvol",volume="myid"} 512`,
+		`osd_volume_size_bytes{name="myvol",volume="myid"} 1024`,
+		`osd_node_status{status="ok"} 1`,
+		`osd_alerts{resource="volume",severity="alarm"} 2`,
+		`osd_alerts{resource="volume",severity="warning"} 1`,
+		`osd_alerts{resource="node",severity="alarm"} 0`,
+		`osd_rest_requests_total{code="200",method="GET",route="/v1/osd-volumes"}`,
+		`osd_rest_request_duration_seconds_count{method="GET",route="/v1/osd-volumes"}`,
+	} {
+		assert.Contains(t, body, line)
+	}
+}
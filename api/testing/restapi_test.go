@@ -119,6 +119,143 @@ func TestVolumeCreateFailed(t *testing.T) {
 	assert.Contains(t, err.Error(), "error in create")
 }
 
+func TestVolumeCreateCloneSuccess(t *testing.T) {
+	var err error
+
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	baseURL := getBaseURL()
+	ts.client, err = volumeclient.NewDriverClient(baseURL, driver, version, "")
+
+	assert.Nil(t, err)
+	assert.NotNil(t, ts.client)
+
+	// Setup request
+	parent := &api.Volume{
+		Id:      "parentid",
+		Locator: &api.VolumeLocator{Name: "parent"},
+		Spec:    &api.VolumeSpec{Size: 1234},
+	}
+	locator := &api.VolumeLocator{Name: "myclone"}
+
+	// Setup mock functions. The parent is given by name and the clone gets
+	// its ID and spec.
+	id := "myid"
+	gomock.InOrder(
+		ts.MockDriver().
+			EXPECT().
+			Inspect([]string{"parent"}).
+			Return(nil, fmt.Errorf("not found")),
+		ts.MockDriver().
+			EXPECT().
+			Enumerate(&api.VolumeLocator{Name: "parent"}, nil).
+			Return([]*api.Volume{parent}, nil),
+		ts.MockDriver().
+			EXPECT().
+			Create(locator, &api.Source{Parent: parent.GetId()}, parent.GetSpec()).
+			Return(id, nil),
+	)
+
+	// create a volume client
+	driverclient := volumeclient.VolumeDriver(ts.client)
+
+	res, err := driverclient.Create(locator, &api.Source{Parent: "parent"}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, id, res)
+}
+
+func TestVolumeCreateCloneFailed(t *testing.T) {
+	var err error
+
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	baseURL := getBaseURL()
+	ts.client, err = volumeclient.NewDriverClient(baseURL, driver, version, "")
+
+	assert.Nil(t, err)
+	assert.NotNil(t, ts.client)
+
+	// Setup mock functions. Create is not called for a missing parent.
+	gomock.InOrder(
+		ts.MockDriver().
+			EXPECT().
+			Inspect([]string{"parent"}).
+			Return(nil, fmt.Errorf("not found")),
+		ts.MockDriver().
+			EXPECT().
+			Enumerate(&api.VolumeLocator{Name: "parent"}, nil).
+			Return(nil, nil),
+	)
+
+	// create a volume client
+	driverclient := volumeclient.VolumeDriver(ts.client)
+
+	res, err := driverclient.Create(&api.VolumeLocator{Name: "myclone"},
+		&api.Source{Parent: "parent"}, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "", res)
+	assert.Contains(t, err.Error(), "parent")
+}
+
+func TestVolumeCreateSeedVolume(t *testing.T) {
+	var err error
+
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	baseURL := getBaseURL()
+	ts.client, err = volumeclient.NewDriverClient(baseURL, driver, version, "")
+
+	assert.Nil(t, err)
+	assert.NotNil(t, ts.client)
+
+	// Setup mock functions. The seed volume is looked up like an inspect,
+	// and the volume is seeded from its ID.
+	source := &api.Volume{
+		Id:      "sourceid",
+		Locator: &api.VolumeLocator{Name: "source"},
+	}
+	locator := &api.VolumeLocator{Name: "myvol"}
+	id := "myid"
+	gomock.InOrder(
+		ts.MockDriver().
+			EXPECT().
+			Inspect([]string{"source"}).
+			Return(nil, fmt.Errorf("not found")),
+		ts.MockDriver().
+			EXPECT().
+			Enumerate(&api.VolumeLocator{Name: "source"}, nil).
+			Return([]*api.Volume{source}, nil),
+		ts.MockDriver().
+			EXPECT().
+			Create(locator, &api.Source{Seed: "volume://sourceid"}, nil).
+			Return(id, nil),
+		ts.MockDriver().
+			EXPECT().
+			Inspect([]string{"missing"}).
+			Return(nil, fmt.Errorf("not found")),
+		ts.MockDriver().
+			EXPECT().
+			Enumerate(&api.VolumeLocator{Name: "missing"}, nil).
+			Return(nil, nil),
+	)
+
+	// create a volume client
+	driverclient := volumeclient.VolumeDriver(ts.client)
+
+	res, err := driverclient.Create(locator, &api.Source{Seed: "volume://source"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, id, res)
+
+	// Create is not called for a missing seed volume.
+	_, err = driverclient.Create(locator, &api.Source{Seed: "volume://missing"}, nil)
+	assert.NotNil(t, err)
+}
+
 func TestVolumeDeleteSuccess(t *testing.T) {
 	ts := newTestServer(driver)
 
@@ -420,6 +557,35 @@ func TestVolumeSetFailed(t *testing.T) {
 	assert.Contains(t, res.Error(), "error in set")
 }
 
+func TestVolumeResizeShrinkFailed(t *testing.T) {
+
+	ts := newTestServer(driver)
+
+	defer ts.Stop()
+
+	var err error
+	baseURL := getBaseURL()
+	ts.client, err = volumeclient.NewDriverClient(baseURL, driver, version, "")
+
+	assert.Nil(t, err)
+
+	id := "myid"
+	spec := &api.VolumeSpec{Size: uint64(10)}
+
+	ts.MockDriver().
+		EXPECT().
+		Set(id, nil, spec).
+		Return(volume.ErrVolShrink)
+
+	// create driver client
+	driverclient := volumeclient.VolumeDriver(ts.client)
+
+	res := driverclient.Set(id, nil, spec)
+
+	assert.NotNil(t, res)
+	assert.Contains(t, res.Error(), volume.ErrVolShrink.Error())
+}
+
 func TestVolumeAttachSuccess(t *testing.T) {
 
 	ts := newTestServer(driver)
@@ -1023,6 +1189,137 @@ func TestVolumeRestoreFailed(t *testing.T) {
 	assert.Contains(t, res.Error(), "error in restore")
 }
 
+func TestVolumeSnapDeleteSuccess(t *testing.T) {
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	var err error
+	baseURL := getBaseURL()
+	ts.client, err = volumeclient.NewDriverClient(baseURL, driver, version, "")
+	assert.Nil(t, err)
+
+	snapID := "snapid"
+
+	ts.MockDriver().
+		EXPECT().
+		SnapDelete(snapID, true).
+		Return(nil)
+
+	// create client
+	driverclient := volumeclient.VolumeDriver(ts.client)
+	res := driverclient.SnapDelete(snapID, true)
+
+	assert.Nil(t, res)
+}
+
+func TestVolumeSnapDeleteFailed(t *testing.T) {
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	var err error
+	baseURL := getBaseURL()
+	ts.client, err = volumeclient.NewDriverClient(baseURL, driver, version, "")
+	assert.Nil(t, err)
+
+	snapID := "snapid"
+
+	ts.MockDriver().
+		EXPECT().
+		SnapDelete(snapID, false).
+		Return(volume.ErrVolHasSnaps)
+
+	// create client
+	driverclient := volumeclient.VolumeDriver(ts.client)
+	res := driverclient.SnapDelete(snapID, false)
+
+	assert.NotNil(t, res)
+	assert.Contains(t, res.Error(), volume.ErrVolHasSnaps.Error())
+}
+
+func TestVolumePromoteSuccess(t *testing.T) {
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	var err error
+	baseURL := getBaseURL()
+	ts.client, err = volumeclient.NewDriverClient(baseURL, driver, version, "")
+	assert.Nil(t, err)
+
+	cloneID := "cloneid"
+
+	ts.MockDriver().
+		EXPECT().
+		Promote(cloneID).
+		Return(nil)
+
+	// create client
+	driverclient := volumeclient.VolumeDriver(ts.client)
+	res := driverclient.Promote(cloneID)
+
+	assert.Nil(t, res)
+}
+
+func TestVolumeSnapTreeSuccess(t *testing.T) {
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	var err error
+	baseURL := getBaseURL()
+	ts.client, err = volumeclient.NewDriverClient(baseURL, driver, version, "")
+	assert.Nil(t, err)
+
+	volID := "volid"
+
+	ts.MockDriver().
+		EXPECT().
+		SnapTree(volID).
+		Return(&api.SnapshotTree{
+			Volume: &api.Volume{Id: volID},
+			Children: []*api.SnapshotTree{
+				&api.SnapshotTree{
+					Volume: &api.Volume{
+						Id:     "snapid",
+						Source: &api.Source{Parent: volID},
+					},
+				},
+			},
+		}, nil)
+
+	// create client
+	driverclient := volumeclient.VolumeDriver(ts.client)
+	res, err := driverclient.SnapTree(volID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, volID, res.Volume.Id)
+	assert.Len(t, res.Children, 1)
+	assert.Equal(t, "snapid", res.Children[0].Volume.Id)
+}
+
+func TestVolumeSnapChainSuccess(t *testing.T) {
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	var err error
+	baseURL := getBaseURL()
+	ts.client, err = volumeclient.NewDriverClient(baseURL, driver, version, "")
+	assert.Nil(t, err)
+
+	snapID := "snapid"
+
+	ts.MockDriver().
+		EXPECT().
+		SnapChain(snapID).
+		Return([]*api.Volume{&api.Volume{Id: "volid"}}, nil)
+
+	// create client
+	driverclient := volumeclient.VolumeDriver(ts.client)
+	res, err := driverclient.SnapChain(snapID)
+
+	assert.Nil(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "volid", res[0].Id)
+}
+
 func TestVolumeUsedSizeSuccess(t *testing.T) {
 	ts := newTestServer(driver)
 	defer ts.Stop()
@@ -1169,6 +1466,74 @@ func TestVolumeEnumerateFailed(t *testing.T) {
 
 }
 
+func TestVolumeEnumerateWithFilterSuccess(t *testing.T) {
+
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	var err error
+	ts.client, err = volumeclient.NewDriverClient(getBaseURL(), driver, version, "")
+	assert.Nil(t, err)
+
+	selector, err := api.ParseLabelSelector("class=f9,tier!=db,zone in (a,b)")
+	assert.Nil(t, err)
+	filter := &api.VolumeFilter{
+		Labels:        selector,
+		States:        []api.VolumeState{api.VolumeState_VOLUME_STATE_ATTACHED},
+		AttachedOn:    "node1",
+		CreatedAfter:  time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+		CreatedBefore: time.Date(2017, 2, 1, 0, 0, 0, 500, time.UTC),
+		Limit:         2,
+		Token:         "dm9sMQ",
+	}
+
+	ts.MockDriver().
+		EXPECT().
+		EnumerateWithFilter(filter).
+		Return(&api.VolumeList{
+			Volumes: []*api.Volume{
+				&api.Volume{Id: "vol2"},
+				&api.Volume{Id: "vol3"},
+			},
+			NextToken: "dm9sMw",
+		}, nil)
+
+	list, err := volumeclient.VolumeDriver(ts.client).EnumerateWithFilter(filter)
+
+	assert.Nil(t, err)
+	assert.Len(t, list.Volumes, 2)
+	assert.Equal(t, "vol2", list.Volumes[0].GetId())
+	assert.Equal(t, "dm9sMw", list.NextToken)
+}
+
+func TestVolumeEnumerateWithFilterFailed(t *testing.T) {
+
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	var err error
+	ts.client, err = volumeclient.NewDriverClient(getBaseURL(), driver, version, "")
+	assert.Nil(t, err)
+
+	driverclient := volumeclient.VolumeDriver(ts.client)
+
+	// Invalid selectors are rejected before reaching the driver.
+	_, err = driverclient.EnumerateWithFilter(&api.VolumeFilter{
+		Labels: api.LabelSelector{{Key: "bad key", Operator: api.LabelOpExists}},
+	})
+	assert.NotNil(t, err)
+
+	filter := &api.VolumeFilter{Token: "bogus"}
+	ts.MockDriver().
+		EXPECT().
+		EnumerateWithFilter(filter).
+		Return(nil, volume.ErrInvalidToken)
+
+	_, err = driverclient.EnumerateWithFilter(filter)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), volume.ErrInvalidToken.Error())
+}
+
 func TestVolumeSnapshotEnumerateSuccess(t *testing.T) {
 	ts := newTestServer(driver)
 	defer ts.Stop()
@@ -0,0 +1,107 @@
+package testing
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/libopenstorage/openstorage/api"
+	volumeclient "github.com/libopenstorage/openstorage/api/client/volume"
+	"github.com/libopenstorage/openstorage/api/server"
+)
+
+const tlsPort = 2378
+
+// selfSigned returns a self-signed certificate for 127.0.0.1 that can be
+// used by both servers and clients, and a pool that trusts it.
+func selfSigned(t *testing.T, cn string) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth,
+		},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+func TestVolumeMgmtAPIMutualTLS(t *testing.T) {
+	serverCert, serverPool := selfSigned(t, "server")
+	clientCert, clientPool := selfSigned(t, "client")
+
+	server.SetTLSConfig(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientPool,
+	})
+	sockBase, err := ioutil.TempDir("", "osd-tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(sockBase)
+	err = server.StartVolumeMgmtAPI(driver, sockBase, tlsPort)
+	server.SetTLSConfig(nil)
+	assert.NoError(t, err)
+	time.Sleep(500 * time.Millisecond)
+
+	ts := newTestServer(driver)
+	defer ts.Stop()
+
+	baseURL := "https://127.0.0.1:" + strconv.Itoa(tlsPort)
+	id := "myid"
+
+	// Plain HTTP is refused
+	c, err := volumeclient.NewDriverClient(
+		"http://127.0.0.1:"+strconv.Itoa(tlsPort), driver, version, "")
+	assert.NoError(t, err)
+	_, err = volumeclient.VolumeDriver(c).Inspect([]string{id})
+	assert.Error(t, err)
+
+	// TLS without a client certificate is refused
+	c, err = volumeclient.NewDriverClient(baseURL, driver, version, "")
+	assert.NoError(t, err)
+	c.SetTLS(&tls.Config{RootCAs: serverPool})
+	_, err = volumeclient.VolumeDriver(c).Inspect([]string{id})
+	assert.Error(t, err)
+
+	// Mutual TLS
+	ts.MockDriver().
+		EXPECT().
+		Inspect([]string{id}).
+		Return([]*api.Volume{&api.Volume{Id: id}}, nil)
+
+	c.SetTLS(&tls.Config{
+		RootCAs:      serverPool,
+		Certificates: []tls.Certificate{clientCert},
+	})
+	vols, err := volumeclient.VolumeDriver(c).Inspect([]string{id})
+	assert.NoError(t, err)
+	assert.Len(t, vols, 1)
+	assert.Equal(t, id, vols[0].GetId())
+}
@@ -11,6 +11,11 @@ const (
 	DaemonFlag = "daemon"
 	// DriverFlag key for for the driver parameter.
 	DriverFlag = "driver"
+	// TokenFlag key for the token parameter.
+	TokenFlag = "token"
+	// TokenEnvVar environment variable the token is read from if no
+	// -<TokenFlag> parameter is given.
+	TokenEnvVar = "OSD_TOKEN"
 )
 
 // DaemonMode returns true if we are running as daemon
@@ -22,3 +27,8 @@ func DaemonMode(c *cli.Context) bool {
 func DriverName(c *cli.Context) string {
 	return c.GlobalString(DriverFlag)
 }
+
+// Token as specified in the -<TokenFlag> parameter.
+func Token(c *cli.Context) string {
+	return c.GlobalString(TokenFlag)
+}
@@ -3,7 +3,9 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"text/tabwriter"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 
@@ -21,7 +23,8 @@ type clusterClient struct {
 
 func (c *clusterClient) clusterOptions(context *cli.Context) {
 	// Currently we choose the default version
-	clnt, err := clusterclient.NewClusterClient("", cluster.APIVersion)
+	clnt, err := clusterclient.NewAuthClusterClient("", cluster.APIVersion,
+		"", Token(context))
 	if err != nil {
 		fmt.Printf("Failed to initialize client library: %v\n", err)
 		os.Exit(1)
@@ -143,6 +146,118 @@ func (c *clusterClient) gossipStatus(context *cli.Context) {
 	}
 }
 
+func (c *clusterClient) alertAck(context *cli.Context) {
+	fn := "alert-ack"
+	if len(context.Args()) != 2 {
+		missingParameter(context, fn, "resource alertID", "Invalid number of arguments")
+		return
+	}
+	resource, err := api.ResourceTypeSimpleValueOf(context.Args()[0])
+	if err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+	alertID, err := strconv.ParseInt(context.Args()[1], 10, 64)
+	if err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+	c.clusterOptions(context)
+	err = c.manager.AcknowledgeAlert(resource, alertID,
+		context.String("user"), context.String("comment"))
+	if err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+	fmtOutput(context, &Format{UUID: []string{context.Args()[1]}})
+}
+
+func (c *clusterClient) alertSilence(context *cli.Context) {
+	fn := "alert-silence"
+	if len(context.Args()) != 1 {
+		missingParameter(context, fn, "name", "Invalid number of arguments")
+		return
+	}
+	silence := &api.AlertSilence{
+		Name:       context.Args()[0],
+		ResourceId: context.String("resource-id"),
+		AlertType:  int64(context.Int("alert-type")),
+		User:       context.String("user"),
+		Comment:    context.String("comment"),
+		Start:      time.Now(),
+	}
+	var err error
+	if s := context.String("resource"); s != "" {
+		if silence.Resource, err = api.ResourceTypeSimpleValueOf(s); err != nil {
+			cmdError(context, fn, err)
+			return
+		}
+	}
+	if s := context.String("severity"); s != "" {
+		if silence.Severity, err = api.SeverityTypeSimpleValueOf(s); err != nil {
+			cmdError(context, fn, err)
+			return
+		}
+	}
+	if s := context.String("start"); s != "" {
+		if silence.Start, err = time.Parse(time.RFC3339, s); err != nil {
+			cmdError(context, fn, err)
+			return
+		}
+	}
+	silence.Expiry = silence.Start.Add(context.Duration("duration"))
+	if s := context.String("expiry"); s != "" {
+		if silence.Expiry, err = time.Parse(time.RFC3339, s); err != nil {
+			cmdError(context, fn, err)
+			return
+		}
+	}
+	c.clusterOptions(context)
+	if err := c.manager.PutAlertSilence(silence); err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+	fmtOutput(context, &Format{UUID: []string{silence.Name}})
+}
+
+func (c *clusterClient) alertSilences(context *cli.Context) {
+	fn := "alert-silences"
+	c.clusterOptions(context)
+	silences, err := c.manager.EnumerateAlertSilences()
+	if err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+	if context.GlobalBool("json") {
+		fmtOutput(context, &Format{Result: silences})
+		return
+	}
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 12, 12, 1, ' ', 0)
+	fmt.Fprintln(w, "NAME	 RESOURCE	 RESOURCE ID	 ALERT TYPE	 SEVERITY	 START	 EXPIRY	 USER	 COMMENT")
+	for _, s := range silences {
+		fmt.Fprintln(w, s.Name, "\t", s.Resource.SimpleString(), "\t",
+			s.ResourceId, "\t", s.AlertType, "\t", s.Severity.SimpleString(), "\t",
+			s.Start.Format(time.RFC3339), "\t", s.Expiry.Format(time.RFC3339), "\t",
+			s.User, "\t", s.Comment)
+	}
+	w.Flush()
+}
+
+func (c *clusterClient) alertUnsilence(context *cli.Context) {
+	fn := "alert-unsilence"
+	if len(context.Args()) != 1 {
+		missingParameter(context, fn, "name", "Invalid number of arguments")
+		return
+	}
+	c.clusterOptions(context)
+	if err := c.manager.DeleteAlertSilence(context.Args()[0]); err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+	fmtOutput(context, &Format{UUID: []string{context.Args()[0]}})
+}
+
 // ClusterCommands exports CLI comamnds for File VolumeDriver
 func ClusterCommands() []cli.Command {
 	c := &clusterClient{}
@@ -217,6 +332,78 @@ func ClusterCommands() []cli.Command {
 				},
 			},
 		},
+		{
+			Name:      "alert-ack",
+			Usage:     "Acknowledge an alert",
+			ArgsUsage: "resource alertID",
+			Action:    c.alertAck,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "user",
+					Usage: "User acknowledging the alert, the token subject is used instead with authentication",
+				},
+				cli.StringFlag{
+					Name:  "comment",
+					Usage: "Note on the alert",
+				},
+			},
+		},
+		{
+			Name:      "alert-silence",
+			Usage:     "Silence matching alerts for a while, e.g. during maintenance",
+			ArgsUsage: "name",
+			Action:    c.alertSilence,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "resource",
+					Usage: "Resource type of the alerts, e.g. volume or node",
+				},
+				cli.StringFlag{
+					Name:  "resource-id",
+					Usage: "Resource ID of the alerts",
+				},
+				cli.IntFlag{
+					Name:  "alert-type",
+					Usage: "Alert type",
+				},
+				cli.StringFlag{
+					Name:  "severity",
+					Usage: "Silence alerts of this severity and less severe ones: notify, warning or alarm",
+				},
+				cli.StringFlag{
+					Name:  "start",
+					Usage: "Start of the silence in RFC3339 format, now if not set",
+				},
+				cli.DurationFlag{
+					Name:  "duration",
+					Usage: "Duration of the silence",
+					Value: time.Hour,
+				},
+				cli.StringFlag{
+					Name:  "expiry",
+					Usage: "End of the silence in RFC3339 format, overrides duration",
+				},
+				cli.StringFlag{
+					Name:  "user",
+					Usage: "User silencing the alerts, the token subject is used instead with authentication",
+				},
+				cli.StringFlag{
+					Name:  "comment",
+					Usage: "Reason for the silence",
+				},
+			},
+		},
+		{
+			Name:   "alert-silences",
+			Usage:  "List alert silences",
+			Action: c.alertSilences,
+		},
+		{
+			Name:      "alert-unsilence",
+			Usage:     "Remove an alert silence",
+			ArgsUsage: "name",
+			Action:    c.alertUnsilence,
+		},
 	}
 	return commands
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/codegangsta/cli"
@@ -12,6 +13,7 @@ import (
 	volumeclient "github.com/libopenstorage/openstorage/api/client/volume"
 	"github.com/libopenstorage/openstorage/cluster"
 	"github.com/libopenstorage/openstorage/pkg/options"
+	"github.com/libopenstorage/openstorage/pkg/proto/time"
 	"github.com/libopenstorage/openstorage/volume"
 )
 
@@ -55,7 +57,8 @@ func processLabels(s string) (map[string]string, error) {
 
 func (v *volDriver) volumeOptions(context *cli.Context) {
 	// Currently we choose the default version
-	clnt, err := volumeclient.NewDriverClient("", v.name, volume.APIVersion, "")
+	clnt, err := volumeclient.NewAuthDriverClient("", v.name, volume.APIVersion,
+		"", Token(context), "")
 	if err != nil {
 		fmt.Printf("Failed to initialize client library: %v\n", err)
 		os.Exit(1)
@@ -115,6 +118,44 @@ func (v *volDriver) volumeCreate(context *cli.Context) {
 	fmtOutput(context, &Format{UUID: []string{string(id)}})
 }
 
+func (v *volDriver) volumeClone(context *cli.Context) {
+	var err error
+	var labels map[string]string
+	fn := "clone"
+
+	if len(context.Args()) != 1 {
+		missingParameter(context, fn, "volumeID", "Invalid number of arguments")
+		return
+	}
+	if context.String("name") == "" {
+		missingParameter(context, fn, "name", "Missing name of the clone")
+		return
+	}
+
+	v.volumeOptions(context)
+	if l := context.String("label"); l != "" {
+		if labels, err = processLabels(l); err != nil {
+			cmdError(context, fn, err)
+			return
+		}
+	}
+	locator := &api.VolumeLocator{
+		Name:         context.String("name"),
+		VolumeLabels: labels,
+	}
+	source := &api.Source{
+		Parent: context.Args()[0],
+	}
+	// The clone gets the spec of its parent.
+	id, err := v.volDriver.Create(locator, source, nil)
+	if err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+
+	fmtOutput(context, &Format{UUID: []string{string(id)}})
+}
+
 func (v *volDriver) volumeMount(context *cli.Context) {
 	v.volumeOptions(context)
 	fn := "mount"
@@ -238,6 +279,30 @@ func (v *volDriver) volumeStats(context *cli.Context) {
 	cmdOutputProto(stats, context.GlobalBool("raw"))
 }
 
+func (v *volDriver) volumeResize(context *cli.Context) {
+	v.volumeOptions(context)
+	fn := "resize"
+	if len(context.Args()) != 1 {
+		missingParameter(context, fn, "volumeID", "Invalid number of arguments")
+		return
+	}
+	size := context.Int("size")
+	if size <= 0 {
+		missingParameter(context, fn, "size", "New volume size")
+		return
+	}
+	volumeID := context.Args()[0]
+	spec := &api.VolumeSpec{
+		Size: uint64(VolumeSzUnits(size) * MiB),
+	}
+	if err := v.volDriver.Set(volumeID, nil, spec); err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+
+	fmtOutput(context, &Format{UUID: []string{volumeID}})
+}
+
 func (v *volDriver) volumeEnumerate(context *cli.Context) {
 	locator := &api.VolumeLocator{}
 	var err error
@@ -253,12 +318,102 @@ func (v *volDriver) volumeEnumerate(context *cli.Context) {
 	}
 
 	v.volumeOptions(context)
-	volumes, err := v.volDriver.Enumerate(locator, nil)
+	filter, err := volumeFilter(context, locator)
 	if err != nil {
 		cmdError(context, fn, err)
 		return
 	}
-	cmdOutputVolumes(volumes, context.GlobalBool("raw"))
+	if filter == nil {
+		volumes, err := v.volDriver.Enumerate(locator, nil)
+		if err != nil {
+			cmdError(context, fn, err)
+			return
+		}
+		cmdOutputVolumes(volumes, context.GlobalBool("raw"))
+		return
+	}
+	list, err := v.volDriver.EnumerateWithFilter(filter)
+	if err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+	cmdOutputVolumes(list.Volumes, context.GlobalBool("raw"))
+	if list.NextToken != "" {
+		fmt.Fprintf(os.Stderr, "Next page: --token %s\n", list.NextToken)
+	}
+}
+
+// volumeFilter returns the filter given by the enumerate flags, or nil if
+// only the flags of a plain enumerate are used.
+func volumeFilter(context *cli.Context, locator *api.VolumeLocator) (*api.VolumeFilter, error) {
+	paged := false
+	for _, flag := range []string{"selector", "state", "status", "attached-on",
+		"created-after", "created-before", "limit", "token"} {
+		if context.IsSet(flag) {
+			paged = true
+		}
+	}
+	if !paged {
+		return nil, nil
+	}
+
+	filter := &api.VolumeFilter{
+		Name:       locator.Name,
+		AttachedOn: context.String("attached-on"),
+		Limit:      context.Int("limit"),
+		Token:      context.String("token"),
+	}
+	for k, v := range locator.VolumeLabels {
+		filter.Labels = append(filter.Labels, api.LabelRequirement{
+			Key:      k,
+			Operator: api.LabelOpEquals,
+			Values:   []string{v},
+		})
+	}
+	if s := context.String("selector"); s != "" {
+		selector, err := api.ParseLabelSelector(s)
+		if err != nil {
+			return nil, err
+		}
+		filter.Labels = append(filter.Labels, selector...)
+	}
+	for _, s := range splitList(context.String("state")) {
+		state, err := api.VolumeStateSimpleValueOf(s)
+		if err != nil {
+			return nil, err
+		}
+		filter.States = append(filter.States, state)
+	}
+	for _, s := range splitList(context.String("status")) {
+		status, err := api.VolumeStatusSimpleValueOf(s)
+		if err != nil {
+			return nil, err
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	var err error
+	if s := context.String("created-after"); s != "" {
+		if filter.CreatedAfter, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, err
+		}
+	}
+	if s := context.String("created-before"); s != "" {
+		if filter.CreatedBefore, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+// splitList splits a comma separated list, dropping empty elements.
+func splitList(s string) []string {
+	var list []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
 }
 
 func (v *volDriver) volumeDelete(context *cli.Context) {
@@ -315,6 +470,10 @@ func (v *volDriver) snapEnumerate(context *cli.Context) {
 	var err error
 
 	fn := "snap enumerate"
+	if context.Bool("tree") {
+		v.snapTree(context)
+		return
+	}
 	locator.Name = context.String("name")
 	if l := context.String("label"); l != "" {
 		locator.VolumeLabels, err = processLabels(l)
@@ -337,22 +496,136 @@ func (v *volDriver) snapEnumerate(context *cli.Context) {
 	cmdOutputVolumes(snaps, context.GlobalBool("raw"))
 }
 
+func (v *volDriver) snapTree(context *cli.Context) {
+	fn := "snap enumerate"
+	if len(context.Args()) < 1 {
+		missingParameter(context, fn, "volumeID", "Invalid number of arguments")
+		return
+	}
+
+	v.volumeOptions(context)
+	trees := make([]*api.SnapshotTree, 0, len(context.Args()))
+	for _, volumeID := range context.Args() {
+		tree, err := v.volDriver.SnapTree(volumeID)
+		if err != nil {
+			cmdError(context, fn, err)
+			return
+		}
+		trees = append(trees, tree)
+	}
+	if context.GlobalBool("json") {
+		fmtOutput(context, &Format{Result: trees})
+		return
+	}
+	for _, tree := range trees {
+		fmt.Println(tree.Volume.DisplayId())
+		cmdOutputSnapTree(tree.Children, "")
+	}
+}
+
+func (v *volDriver) snapDelete(context *cli.Context) {
+	fn := "snapDelete"
+	if len(context.Args()) < 1 {
+		missingParameter(context, fn, "snapID", "Invalid number of arguments")
+		return
+	}
+	snapID := context.Args()[0]
+	v.volumeOptions(context)
+	if err := v.volDriver.SnapDelete(snapID, context.Bool("cascade")); err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+
+	fmtOutput(context, &Format{UUID: []string{snapID}})
+}
+
+func (v *volDriver) promote(context *cli.Context) {
+	fn := "promote"
+	if len(context.Args()) < 1 {
+		missingParameter(context, fn, "cloneID", "Invalid number of arguments")
+		return
+	}
+	cloneID := context.Args()[0]
+	v.volumeOptions(context)
+	if err := v.volDriver.Promote(cloneID); err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+
+	fmtOutput(context, &Format{UUID: []string{cloneID}})
+}
+
 func (v *volDriver) volumeAlerts(context *cli.Context) {
 	v.volumeOptions(context)
+	fn := "alerts"
 
-	clnt, err := clusterclient.NewClusterClient("", cluster.APIVersion)
+	filter, err := alertFilter(context)
+	if err != nil {
+		cmdError(context, fn, err)
+		return
+	}
+	clnt, err := clusterclient.NewAuthClusterClient("", cluster.APIVersion,
+		"", Token(context))
 	if err != nil {
 		fmt.Printf("Failed to initialize client library: %v\n", err)
 		return
 	}
 	manager := clusterclient.ClusterManager(clnt)
-	alerts, err := manager.EnumerateAlerts(time.Time{}, time.Time{}, api.ResourceType_RESOURCE_TYPE_VOLUME)
+	list, err := manager.EnumerateAlertsWithFilter(filter)
 	if err != nil {
 		fmt.Printf("Unable to enumerate alerts: %v\n", err)
 		return
 	}
 
-	cmdOutputProto(alerts, context.GlobalBool("raw"))
+	if context.Bool("summary") {
+		cmdOutputAlerts(list.Alerts)
+	} else {
+		cmdOutputProto(&api.Alerts{Alert: list.Alerts}, context.GlobalBool("raw"))
+	}
+	if list.NextToken != "" {
+		fmt.Fprintf(os.Stderr, "Next page: --token %s\n", list.NextToken)
+	}
+}
+
+// alertFilter returns the filter of volume alerts given by the alerts
+// flags and the optional volume ID argument.
+func alertFilter(context *cli.Context) (*api.AlertFilter, error) {
+	filter := &api.AlertFilter{
+		Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+		ResourceId: context.Args().First(),
+		AlertType:  int64(context.Int("alert-type")),
+		Text:       context.String("text"),
+		Descending: context.Bool("descending"),
+		Limit:      context.Int("limit"),
+		Token:      context.String("token"),
+	}
+	var err error
+	if s := context.String("severity"); s != "" {
+		if filter.Severity, err = api.SeverityTypeSimpleValueOf(s); err != nil {
+			return nil, err
+		}
+	}
+	if context.Bool("active") && context.Bool("cleared") {
+		return nil, fmt.Errorf("--active and --cleared cannot be used together")
+	}
+	if context.Bool("active") || context.Bool("cleared") {
+		cleared := context.Bool("cleared")
+		filter.Cleared = &cleared
+	}
+	if s := context.String("seen-after"); s != "" {
+		if filter.TimeStart, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, err
+		}
+	}
+	if s := context.String("seen-before"); s != "" {
+		if filter.TimeEnd, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, err
+		}
+	}
+	if filter.SortBy, err = api.AlertSortValueOf(context.String("sort")); err != nil {
+		return nil, err
+	}
+	return filter, nil
 }
 
 // baseVolumeCommand exports commands common to block and file volume drivers.
@@ -382,7 +655,7 @@ func baseVolumeCommand(v *volDriver) []cli.Command {
 				},
 				cli.StringFlag{
 					Name:  "seed",
-					Usage: "optional data that the volume should be seeded with",
+					Usage: "optional data that the volume should be seeded with: github://, http(s)://, file:// or volume:// URI",
 				},
 				cli.IntFlag{
 					Name:  "block_size,b",
@@ -406,6 +679,21 @@ func baseVolumeCommand(v *volDriver) []cli.Command {
 				},
 			},
 		},
+		{
+			Name:   "clone",
+			Usage:  "create a volume from a copy of another volume",
+			Action: v.volumeClone,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "name",
+					Usage: "name of the clone",
+				},
+				cli.StringFlag{
+					Name:  "label,l",
+					Usage: "Comma separated name=value pairs, e.g name=sqlvolume,type=production",
+				},
+			},
+		},
 		{
 			Name:    "mount",
 			Aliases: []string{"m"},
@@ -450,6 +738,38 @@ func baseVolumeCommand(v *volDriver) []cli.Command {
 					Name:  "label,l",
 					Usage: "Comma separated name=value pairs, e.g name=sqlvolume,type=production",
 				},
+				cli.StringFlag{
+					Name:  "selector",
+					Usage: "label selector, e.g. 'type=production,tier!=web,zone in (a,b)'",
+				},
+				cli.StringFlag{
+					Name:  "state",
+					Usage: "comma separated volume states, e.g. attached,detached",
+				},
+				cli.StringFlag{
+					Name:  "status",
+					Usage: "comma separated volume statuses, e.g. up,degraded",
+				},
+				cli.StringFlag{
+					Name:  "attached-on",
+					Usage: "node the volumes are attached on",
+				},
+				cli.StringFlag{
+					Name:  "created-after",
+					Usage: "RFC 3339 time the volumes were created after",
+				},
+				cli.StringFlag{
+					Name:  "created-before",
+					Usage: "RFC 3339 time the volumes were created before",
+				},
+				cli.IntFlag{
+					Name:  "limit",
+					Usage: "maximum number of volumes to list",
+				},
+				cli.StringFlag{
+					Name:  "token",
+					Usage: "continue listing from the token printed for the previous page",
+				},
 			},
 		},
 		{
@@ -459,15 +779,77 @@ func baseVolumeCommand(v *volDriver) []cli.Command {
 			Action:  v.volumeInspect,
 		},
 		{
-			Name:   "alerts",
-			Usage:  "Enumerate volume alerts",
-			Action: v.volumeAlerts,
+			Name:      "alerts",
+			Usage:     "Enumerate volume alerts",
+			ArgsUsage: "[volumeID]",
+			Action:    v.volumeAlerts,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "summary",
+					Usage: "show one line per alert with how often and when it occurred",
+				},
+				cli.StringFlag{
+					Name:  "severity",
+					Usage: "least severity of the alerts: notify, warning or alarm",
+				},
+				cli.BoolFlag{
+					Name:  "active",
+					Usage: "list only alerts that are not cleared",
+				},
+				cli.BoolFlag{
+					Name:  "cleared",
+					Usage: "list only cleared alerts",
+				},
+				cli.IntFlag{
+					Name:  "alert-type",
+					Usage: "type of the alerts",
+				},
+				cli.StringFlag{
+					Name:  "seen-after",
+					Usage: "RFC 3339 time the alerts were last seen after",
+				},
+				cli.StringFlag{
+					Name:  "seen-before",
+					Usage: "RFC 3339 time the alerts were last seen before",
+				},
+				cli.StringFlag{
+					Name:  "text",
+					Usage: "text in the message of the alerts, ignoring case",
+				},
+				cli.StringFlag{
+					Name:  "sort",
+					Usage: "order of the alerts: id, last_seen, first_seen, severity or count",
+				},
+				cli.BoolFlag{
+					Name:  "descending",
+					Usage: "list the alerts in reverse order",
+				},
+				cli.IntFlag{
+					Name:  "limit",
+					Usage: "maximum number of alerts to list",
+				},
+				cli.StringFlag{
+					Name:  "token",
+					Usage: "continue listing from the token printed for the previous page",
+				},
+			},
 		},
 		{
 			Name:   "stats",
 			Usage:  "volume stats",
 			Action: v.volumeStats,
 		},
+		{
+			Name:   "resize",
+			Usage:  "Expand volume and its filesystem",
+			Action: v.volumeResize,
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "size,s",
+					Usage: "new volume size in MB",
+				},
+			},
+		},
 		{
 			Name:    "snap",
 			Aliases: []string{"sc"},
@@ -502,8 +884,29 @@ func baseVolumeCommand(v *volDriver) []cli.Command {
 					Name:  "label,l",
 					Usage: "Comma separated name=value pairs, e.g name=sqlvolume,type=production",
 				},
+				cli.BoolFlag{
+					Name:  "tree",
+					Usage: "show the snapshots and clones descending from the specified volumes",
+				},
 			},
 		},
+		{
+			Name:    "snapDelete",
+			Aliases: []string{"sd"},
+			Usage:   "Delete snap",
+			Action:  v.snapDelete,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "cascade",
+					Usage: "also delete snaps and clones of this snap, or of this volume along with it",
+				},
+			},
+		},
+		{
+			Name:   "promote",
+			Usage:  "Make clone independent of its parent",
+			Action: v.promote,
+		},
 	}
 	return commands
 }
@@ -555,3 +958,34 @@ func cmdOutputVolumes(volumes []*api.Volume, j bool) {
 	}
 	fmt.Println("]")
 }
+
+func cmdOutputAlerts(alerts []*api.Alert) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 12, 12, 1, ' ', 0)
+	fmt.Fprintln(w, "ID\t RESOURCE ID\t SEVERITY\t COUNT\t FIRST SEEN\t LAST SEEN\t CLEARED\t MESSAGE")
+	for _, a := range alerts {
+		count, firstSeen, lastSeen := a.Count, a.FirstSeen, a.LastSeen
+		if count == 0 {
+			// Raised before occurrences were counted.
+			count, firstSeen, lastSeen = 1, a.Timestamp, a.Timestamp
+		}
+		fmt.Fprintln(w, a.Id, "\t", a.ResourceId, "\t",
+			strings.TrimPrefix(a.Severity.String(), "SEVERITY_TYPE_"), "\t",
+			count, "\t",
+			prototime.TimestampToTime(firstSeen).Format(time.RFC3339), "\t",
+			prototime.TimestampToTime(lastSeen).Format(time.RFC3339), "\t",
+			a.Cleared, "\t", a.Message)
+	}
+	w.Flush()
+}
+
+func cmdOutputSnapTree(trees []*api.SnapshotTree, indent string) {
+	for i, tree := range trees {
+		branch, next := "|-- ", "|   "
+		if i == len(trees)-1 {
+			branch, next = "`-- ", "    "
+		}
+		fmt.Println(indent + branch + tree.Volume.DisplayId())
+		cmdOutputSnapTree(tree.Children, indent+next)
+	}
+}
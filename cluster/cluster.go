@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/libopenstorage/gossip/types"
+	"github.com/libopenstorage/openstorage/alert"
 	"github.com/libopenstorage/openstorage/api"
 	"github.com/libopenstorage/openstorage/config"
 	"github.com/portworx/kvdb"
@@ -238,6 +239,36 @@ type ClusterAlerts interface {
 	EraseAlert(resource api.ResourceType, alertID int64) error
 }
 
+// ClusterAlertFilter queries the alerts of the cluster.
+type ClusterAlertFilter interface {
+	// EnumerateAlertsWithFilter returns a page of the alerts on this cluster
+	// that match filter.
+	EnumerateAlertsWithFilter(filter *api.AlertFilter) (*api.AlertList, error)
+}
+
+// ClusterAlertSinks manages the sinks alert events of the cluster are sent to.
+type ClusterAlertSinks interface {
+	// EnumerateAlertSinks lists the alert sinks of this cluster.
+	EnumerateAlertSinks() ([]*api.AlertSink, error)
+	// PutAlertSink creates or replaces the alert sink with the name of sink.
+	PutAlertSink(sink *api.AlertSink) error
+	// DeleteAlertSink deletes an alert sink by name.
+	DeleteAlertSink(name string) error
+}
+
+// ClusterAlertSilences acknowledges and silences the alerts of the cluster.
+type ClusterAlertSilences interface {
+	// AcknowledgeAlert records that user has taken note of an alert.
+	AcknowledgeAlert(resource api.ResourceType, alertID int64, user, comment string) error
+	// EnumerateAlertSilences lists the alert silences that have not expired.
+	EnumerateAlertSilences() ([]*api.AlertSilence, error)
+	// PutAlertSilence creates or replaces the alert silence with the name
+	// of silence.
+	PutAlertSilence(silence *api.AlertSilence) error
+	// DeleteAlertSilence deletes an alert silence by name.
+	DeleteAlertSilence(name string) error
+}
+
 // Cluster is the API that a cluster provider will implement.
 type Cluster interface {
 	// Inspect the node given a UUID.
@@ -265,6 +296,9 @@ type Cluster interface {
 	ClusterRemove
 	ClusterStatus
 	ClusterAlerts
+	ClusterAlertFilter
+	ClusterAlertSinks
+	ClusterAlertSilences
 }
 
 // ClusterNotify is the callback function listeners can use to notify cluster manager
@@ -282,17 +316,33 @@ func Init(cfg config.ClusterConfig) error {
 			"A valid KVDB instance required for the cluster to start.")
 	}
 
+	alerts, err := alert.New(alert.Name, cfg.ClusterId, kv)
+	if err != nil {
+		return err
+	}
+
 	inst = &ClusterManager{
 		listeners:    list.New(),
 		config:       cfg,
 		kv:           kv,
+		alert:        alerts,
+		healthAlerts: make(chan func(), healthAlertQueueSize),
 		nodeCache:    make(map[string]api.Node),
 		nodeStatuses: make(map[string]api.Status),
 	}
+	go inst.runHealthAlerts()
 
 	return nil
 }
 
+// Alerts returns the alerts of the cluster manager created by Init.
+func Alerts() (alert.Alert, error) {
+	if inst == nil {
+		return nil, errClusterNotInitialized
+	}
+	return inst.alert, nil
+}
+
 func clusterInst() (Cluster, error) {
 	if inst == nil {
 		return nil, errClusterNotInitialized
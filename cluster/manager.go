@@ -11,6 +11,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/libopenstorage/gossip"
 	"github.com/libopenstorage/gossip/types"
+	"github.com/libopenstorage/openstorage/alert"
 	"github.com/libopenstorage/openstorage/api"
 	"github.com/libopenstorage/openstorage/config"
 	"github.com/libopenstorage/systemutils"
@@ -31,6 +33,9 @@ const (
 	gossipVersionKey   = "Gossip Version"
 	decommissionErrMsg = "Node %s must be offline or in maintenance " +
 		"mode to be decommissioned."
+	// healthAlertQueueSize is the number of health alert updates that can
+	// wait to be written before further updates are dropped.
+	healthAlertQueueSize = 128
 )
 
 var (
@@ -50,6 +55,8 @@ type ClusterManager struct {
 	listeners     *list.List
 	config        config.ClusterConfig
 	kv            kvdb.Kvdb
+	alert         alert.Alert
+	healthAlerts  chan func() // Updates of health alerts, written in order.
 	status        api.Status
 	nodeCache     map[string]api.Node // Cached info on the nodes in the cluster.
 	nodeCacheLock sync.Mutex
@@ -661,6 +668,8 @@ func (c *ClusterManager) updateClusterStatus() {
 					dlog.Warnf("Can't reach quorum no. of nodes. Suspecting out of quorum...")
 					c.selfNode.Status = api.Status_STATUS_NOT_IN_QUORUM
 					c.status = api.Status_STATUS_NOT_IN_QUORUM
+					c.raiseHealthAlert(alert.TypeNodeNotInQuorum, node.Id,
+						fmt.Sprintf("Node %v is not in quorum", node.Id))
 				} else if (c.selfNode.Status == api.Status_STATUS_NOT_IN_QUORUM ||
 					c.selfNode.Status == api.Status_STATUS_OK) &&
 					(gossipNodeInfo.Status == types.NODE_STATUS_NOT_IN_QUORUM ||
@@ -686,6 +695,7 @@ func (c *ClusterManager) updateClusterStatus() {
 					// Cluster Manager : UP
 					c.selfNode.Status = api.Status_STATUS_OK
 					c.status = api.Status_STATUS_OK
+					c.clearHealthAlert(alert.TypeNodeNotInQuorum, node.Id)
 				} else {
 					// Ignore the update
 				}
@@ -718,6 +728,8 @@ func (c *ClusterManager) updateClusterStatus() {
 				}
 
 				c.nodeStatuses[string(id)] = peerNodeInCache.Status
+				c.raiseHealthAlert(alert.TypeNodeOffline, string(id),
+					fmt.Sprintf("Node %v is offline", id))
 
 				for e := c.listeners.Front(); e != nil && c.gEnabled; e = e.Next() {
 					err := e.Value.(ClusterListener).Update(&peerNodeInCache)
@@ -738,6 +750,7 @@ func (c *ClusterManager) updateClusterStatus() {
 				// A node discovered in the cluster.
 				dlog.Infoln("Detected node", peerNodeInCache.Id,
 					" to be in the cluster.")
+				c.clearHealthAlert(alert.TypeNodeOffline, string(id))
 
 				for e := c.listeners.Front(); e != nil && c.gEnabled; e = e.Next() {
 					err := e.Value.(ClusterListener).Add(&peerNodeInCache)
@@ -1260,10 +1273,17 @@ func (c *ClusterManager) Start(
 	if err != nil {
 		return err
 	}
+	// Alerts raised before this node went down or restarted.
+	c.clearHealthAlert(alert.TypeNodeNotInQuorum, c.selfNode.Id)
+	c.clearHealthAlert(alert.TypeNodeOffline, c.selfNode.Id)
 
 	go c.updateClusterStatus()
 	go c.replayNodeDecommission()
 
+	if err := alert.NewNotifier(c.alert, c.config.ClusterId).Start(); err != nil {
+		dlog.Warnf("Failed to start alert notifications: %v", err)
+	}
+
 	return nil
 }
 
@@ -1743,24 +1763,79 @@ func (c *ClusterManager) HandleNotifications(culpritNodeId string, notification
 }
 
 func (c *ClusterManager) EnumerateAlerts(ts, te time.Time, resource api.ResourceType) (*api.Alerts, error) {
-	a := api.Alerts{}
+	list, err := c.EnumerateAlertsWithFilter(&api.AlertFilter{
+		Resource:  resource,
+		TimeStart: ts,
+		TimeEnd:   te,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &api.Alerts{Alert: list.Alerts}, nil
+}
 
+// EnumerateAlertsWithFilter returns a page of the alerts raised by this
+// cluster and by its listeners that match filter. The page of the alerts of
+// this cluster is read from its store, and merged with the alerts of the
+// listeners, which are not paged.
+func (c *ClusterManager) EnumerateAlertsWithFilter(filter *api.AlertFilter) (*api.AlertList, error) {
+	if filter == nil {
+		filter = &api.AlertFilter{}
+	}
+	var listenerAlerts []*api.Alert
 	for e := c.listeners.Front(); e != nil; e = e.Next() {
-		listenerAlerts, err := e.Value.(ClusterListener).EnumerateAlerts(ts, te, resource)
+		alerts, err := e.Value.(ClusterListener).EnumerateAlerts(
+			filter.TimeStart, filter.TimeEnd, filter.Resource)
 		if err != nil {
 			dlog.Warnf("Failed to enumerate alerts from (%v): %v",
 				e.Value.(ClusterListener).String(), err)
 			continue
 		}
-		if listenerAlerts != nil {
-			a.Alert = append(a.Alert, listenerAlerts.Alert...)
+		if alerts == nil {
+			continue
+		}
+		for _, a := range alerts.Alert {
+			if filter.Matches(a) {
+				listenerAlerts = append(listenerAlerts, a)
+			}
 		}
 	}
-	return &a, nil
+	if c.alert == nil {
+		return alert.List(listenerAlerts, filter)
+	}
+	if len(listenerAlerts) == 0 {
+		return c.alert.EnumerateWithFilter(filter)
+	}
+
+	// The alerts of the page are among the first Limit alerts of the store
+	// after the token and the alerts of the listeners. One more is read to
+	// know whether there is a next page.
+	page := *filter
+	if page.Limit > 0 {
+		page.Limit++
+	}
+	list, err := c.alert.EnumerateWithFilter(&page)
+	if err != nil {
+		return nil, err
+	}
+	alerts := list.Alerts
+	seen := make(map[string]bool)
+	for _, a := range alerts {
+		seen[a.Resource.String()+"/"+strconv.FormatInt(a.Id, 10)] = true
+	}
+	for _, a := range listenerAlerts {
+		// Listeners may keep their alerts in the same store.
+		key := a.Resource.String() + "/" + strconv.FormatInt(a.Id, 10)
+		if !seen[key] {
+			seen[key] = true
+			alerts = append(alerts, a)
+		}
+	}
+	return alert.List(alerts, filter)
 }
 
 func (c *ClusterManager) ClearAlert(resource api.ResourceType, alertID int64) error {
-	cleared := false
+	cleared := c.alert != nil && c.alert.Clear(resource, alertID, 0) == nil
 	for e := c.listeners.Front(); e != nil; e = e.Next() {
 		if err := e.Value.(ClusterListener).ClearAlert(resource, alertID); err != nil {
 			continue
@@ -1774,7 +1849,7 @@ func (c *ClusterManager) ClearAlert(resource api.ResourceType, alertID int64) er
 }
 
 func (c *ClusterManager) EraseAlert(resource api.ResourceType, alertID int64) error {
-	erased := false
+	erased := c.alert != nil && c.alert.Erase(resource, alertID) == nil
 	for e := c.listeners.Front(); e != nil; e = e.Next() {
 		if err := e.Value.(ClusterListener).EraseAlert(resource, alertID); err != nil {
 			continue
@@ -1787,6 +1862,86 @@ func (c *ClusterManager) EraseAlert(resource api.ResourceType, alertID int64) er
 	return nil
 }
 
+// raiseHealthAlert raises the alert of alertType on resourceID in the
+// background, so that the cluster state machine is not held up by the
+// store. Failures are only logged.
+func (c *ClusterManager) raiseHealthAlert(alertType int64, resourceID, message string) {
+	c.queueHealthAlert(alertType, resourceID, func() error {
+		return alert.RaiseType(c.alert, alertType, resourceID, message)
+	})
+}
+
+// clearHealthAlert clears the alert of alertType on resourceID in the
+// background, after the updates queued before it.
+func (c *ClusterManager) clearHealthAlert(alertType int64, resourceID string) {
+	c.queueHealthAlert(alertType, resourceID, func() error {
+		return alert.ClearType(c.alert, alertType, resourceID)
+	})
+}
+
+// queueHealthAlert queues update of the alert of alertType on resourceID.
+// The update is dropped if the queue is full.
+func (c *ClusterManager) queueHealthAlert(
+	alertType int64,
+	resourceID string,
+	update func() error,
+) {
+	if c.alert == nil {
+		return
+	}
+	select {
+	case c.healthAlerts <- func() {
+		if err := update(); err != nil {
+			dlog.Warnf("Failed to update alert %v on %v: %v",
+				alertType, resourceID, err)
+		}
+	}:
+	default:
+		dlog.Warnf("Dropped update of alert %v on %v: too many pending",
+			alertType, resourceID)
+	}
+}
+
+// runHealthAlerts writes the queued updates of the health alerts.
+func (c *ClusterManager) runHealthAlerts() {
+	for update := range c.healthAlerts {
+		update()
+	}
+}
+
+func (c *ClusterManager) AcknowledgeAlert(
+	resource api.ResourceType,
+	alertID int64,
+	user string,
+	comment string,
+) error {
+	return c.alert.Acknowledge(resource, alertID, user, comment)
+}
+
+func (c *ClusterManager) EnumerateAlertSilences() ([]*api.AlertSilence, error) {
+	return c.alert.EnumerateSilences()
+}
+
+func (c *ClusterManager) PutAlertSilence(silence *api.AlertSilence) error {
+	return c.alert.PutSilence(silence)
+}
+
+func (c *ClusterManager) DeleteAlertSilence(name string) error {
+	return c.alert.DeleteSilence(name)
+}
+
+func (c *ClusterManager) EnumerateAlertSinks() ([]*api.AlertSink, error) {
+	return c.alert.EnumerateSinks()
+}
+
+func (c *ClusterManager) PutAlertSink(sink *api.AlertSink) error {
+	return c.alert.PutSink(sink)
+}
+
+func (c *ClusterManager) DeleteAlertSink(name string) error {
+	return c.alert.DeleteSink(name)
+}
+
 func (c *ClusterManager) getNodeCacheEntry(nodeId string) (api.Node, bool) {
 	c.nodeCacheLock.Lock()
 	defer c.nodeCacheLock.Unlock()
@@ -35,6 +35,18 @@ func (m *MockCluster) EXPECT() *MockClusterMockRecorder {
 	return m.recorder
 }
 
+// AcknowledgeAlert mocks base method
+func (m *MockCluster) AcknowledgeAlert(arg0 api.ResourceType, arg1 int64, arg2, arg3 string) error {
+	ret := m.ctrl.Call(m, "AcknowledgeAlert", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcknowledgeAlert indicates an expected call of AcknowledgeAlert
+func (mr *MockClusterMockRecorder) AcknowledgeAlert(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcknowledgeAlert", reflect.TypeOf((*MockCluster)(nil).AcknowledgeAlert), arg0, arg1, arg2, arg3)
+}
+
 // AddEventListener mocks base method
 func (m *MockCluster) AddEventListener(arg0 cluster.ClusterListener) error {
 	ret := m.ctrl.Call(m, "AddEventListener", arg0)
@@ -59,6 +71,30 @@ func (mr *MockClusterMockRecorder) ClearAlert(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearAlert", reflect.TypeOf((*MockCluster)(nil).ClearAlert), arg0, arg1)
 }
 
+// DeleteAlertSilence mocks base method
+func (m *MockCluster) DeleteAlertSilence(arg0 string) error {
+	ret := m.ctrl.Call(m, "DeleteAlertSilence", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlertSilence indicates an expected call of DeleteAlertSilence
+func (mr *MockClusterMockRecorder) DeleteAlertSilence(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlertSilence", reflect.TypeOf((*MockCluster)(nil).DeleteAlertSilence), arg0)
+}
+
+// DeleteAlertSink mocks base method
+func (m *MockCluster) DeleteAlertSink(arg0 string) error {
+	ret := m.ctrl.Call(m, "DeleteAlertSink", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlertSink indicates an expected call of DeleteAlertSink
+func (mr *MockClusterMockRecorder) DeleteAlertSink(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlertSink", reflect.TypeOf((*MockCluster)(nil).DeleteAlertSink), arg0)
+}
+
 // DisableUpdates mocks base method
 func (m *MockCluster) DisableUpdates() error {
 	ret := m.ctrl.Call(m, "DisableUpdates")
@@ -109,6 +145,45 @@ func (mr *MockClusterMockRecorder) EnumerateAlerts(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnumerateAlerts", reflect.TypeOf((*MockCluster)(nil).EnumerateAlerts), arg0, arg1, arg2)
 }
 
+// EnumerateAlertsWithFilter mocks base method
+func (m *MockCluster) EnumerateAlertsWithFilter(arg0 *api.AlertFilter) (*api.AlertList, error) {
+	ret := m.ctrl.Call(m, "EnumerateAlertsWithFilter", arg0)
+	ret0, _ := ret[0].(*api.AlertList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnumerateAlertsWithFilter indicates an expected call of EnumerateAlertsWithFilter
+func (mr *MockClusterMockRecorder) EnumerateAlertsWithFilter(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnumerateAlertsWithFilter", reflect.TypeOf((*MockCluster)(nil).EnumerateAlertsWithFilter), arg0)
+}
+
+// EnumerateAlertSilences mocks base method
+func (m *MockCluster) EnumerateAlertSilences() ([]*api.AlertSilence, error) {
+	ret := m.ctrl.Call(m, "EnumerateAlertSilences")
+	ret0, _ := ret[0].([]*api.AlertSilence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnumerateAlertSilences indicates an expected call of EnumerateAlertSilences
+func (mr *MockClusterMockRecorder) EnumerateAlertSilences() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnumerateAlertSilences", reflect.TypeOf((*MockCluster)(nil).EnumerateAlertSilences))
+}
+
+// EnumerateAlertSinks mocks base method
+func (m *MockCluster) EnumerateAlertSinks() ([]*api.AlertSink, error) {
+	ret := m.ctrl.Call(m, "EnumerateAlertSinks")
+	ret0, _ := ret[0].([]*api.AlertSink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnumerateAlertSinks indicates an expected call of EnumerateAlertSinks
+func (mr *MockClusterMockRecorder) EnumerateAlertSinks() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnumerateAlertSinks", reflect.TypeOf((*MockCluster)(nil).EnumerateAlertSinks))
+}
+
 // EraseAlert mocks base method
 func (m *MockCluster) EraseAlert(arg0 api.ResourceType, arg1 int64) error {
 	ret := m.ctrl.Call(m, "EraseAlert", arg0, arg1)
@@ -207,6 +282,30 @@ func (mr *MockClusterMockRecorder) PeerStatus(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerStatus", reflect.TypeOf((*MockCluster)(nil).PeerStatus), arg0)
 }
 
+// PutAlertSilence mocks base method
+func (m *MockCluster) PutAlertSilence(arg0 *api.AlertSilence) error {
+	ret := m.ctrl.Call(m, "PutAlertSilence", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutAlertSilence indicates an expected call of PutAlertSilence
+func (mr *MockClusterMockRecorder) PutAlertSilence(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAlertSilence", reflect.TypeOf((*MockCluster)(nil).PutAlertSilence), arg0)
+}
+
+// PutAlertSink mocks base method
+func (m *MockCluster) PutAlertSink(arg0 *api.AlertSink) error {
+	ret := m.ctrl.Call(m, "PutAlertSink", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutAlertSink indicates an expected call of PutAlertSink
+func (mr *MockClusterMockRecorder) PutAlertSink(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAlertSink", reflect.TypeOf((*MockCluster)(nil).PutAlertSink), arg0)
+}
+
 // Remove mocks base method
 func (m *MockCluster) Remove(arg0 []api.Node, arg1 bool) error {
 	ret := m.ctrl.Call(m, "Remove", arg0, arg1)
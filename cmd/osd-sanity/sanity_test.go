@@ -18,8 +18,12 @@ package sanity
 import (
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	csisanity "github.com/kubernetes-csi/csi-test/pkg/sanity"
+
 	"github.com/libopenstorage/openstorage/pkg/sanity"
 )
 
@@ -31,11 +35,23 @@ var (
 	VERSION  = "(dev)"
 	endpoint string
 	version  bool
+
+	csiEndpoint    string
+	csiTargetPath  string
+	csiStagingPath string
 )
 
 func init() {
 	flag.StringVar(&endpoint, prefix+"endpoint", "", "OSD endpoint")
 	flag.BoolVar(&version, prefix+"version", false, "Version of this program")
+	flag.StringVar(&csiEndpoint, prefix+"csi-endpoint", "",
+		"OSD CSI endpoint. The CSI sanity tests are run if set")
+	flag.StringVar(&csiTargetPath, prefix+"csi-target-path",
+		filepath.Join(os.TempDir(), "osd-csi-target"),
+		"Directory the CSI sanity tests publish volumes in")
+	flag.StringVar(&csiStagingPath, prefix+"csi-staging-path",
+		filepath.Join(os.TempDir(), "osd-csi-staging"),
+		"Directory the CSI sanity tests stage volumes in")
 	flag.Parse()
 }
 
@@ -47,5 +63,13 @@ func TestSanity(t *testing.T) {
 	if len(endpoint) == 0 {
 		t.Fatalf("--%s.endpoint must be provided with an OSD endpoint", prefix)
 	}
-	sanity.Test(t, endpoint)
+	if len(csiEndpoint) == 0 {
+		sanity.Test(t, endpoint)
+		return
+	}
+	sanity.TestWithCSI(t, endpoint, &csisanity.Config{
+		Address:     csiEndpoint,
+		TargetPath:  csiTargetPath,
+		StagingPath: csiStagingPath,
+	})
 }
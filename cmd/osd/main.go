@@ -27,25 +27,35 @@
 package main
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/url"
 	"os"
 	"runtime"
 	"strconv"
+	"time"
 
 	"go.pedge.io/dlog"
 
 	"github.com/codegangsta/cli"
 	"github.com/docker/docker/pkg/reexec"
+	"github.com/libopenstorage/openstorage/alert"
 	"github.com/libopenstorage/openstorage/api"
 	"github.com/libopenstorage/openstorage/api/flexvolume"
 	"github.com/libopenstorage/openstorage/api/server"
 	osdcli "github.com/libopenstorage/openstorage/cli"
 	"github.com/libopenstorage/openstorage/cluster"
 	"github.com/libopenstorage/openstorage/config"
+	"github.com/libopenstorage/openstorage/csi"
 	"github.com/libopenstorage/openstorage/graph/drivers"
+	"github.com/libopenstorage/openstorage/pkg/auth"
+	"github.com/libopenstorage/openstorage/pkg/sched"
+	"github.com/libopenstorage/openstorage/pkg/seed"
+	"github.com/libopenstorage/openstorage/pkg/tlsutil"
 	"github.com/libopenstorage/openstorage/volume"
 	"github.com/libopenstorage/openstorage/volume/drivers"
+	"github.com/libopenstorage/openstorage/volume/monitor"
+	"github.com/libopenstorage/openstorage/volume/snapscheduler"
 	"github.com/portworx/kvdb"
 	"github.com/portworx/kvdb/consul"
 	etcd "github.com/portworx/kvdb/etcd/v2"
@@ -88,6 +98,11 @@ func main() {
 			Usage: "file to read the OSD configuration from.",
 			Value: "",
 		},
+		cli.StringFlag{
+			Name:   osdcli.TokenFlag,
+			Usage:  "bearer token to authenticate to the OSD REST API",
+			EnvVar: osdcli.TokenEnvVar,
+		},
 	}
 	app.Action = wrapAction(start)
 	app.Commands = []cli.Command{
@@ -170,6 +185,23 @@ func start(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if cfg.Osd.Auth != nil {
+		authenticator, err := auth.NewJwtAuthenticator(cfg.Osd.Auth)
+		if err != nil {
+			return fmt.Errorf("Unable to set up authentication: %v", err)
+		}
+		server.SetAuthenticator(authenticator)
+		dlog.Infof("OSD REST API requires token authentication.")
+	}
+	if cfg.Osd.TLS != nil {
+		tlsConfig, err := newTLSConfig(cfg.Osd.TLS)
+		if err != nil {
+			return fmt.Errorf("Unable to set up TLS for the REST API: %v", err)
+		}
+		server.SetTLSConfig(tlsConfig)
+	}
+	seed.SetFileRoots(cfg.Osd.SeedFileRoots)
+
 	kvdbURL := c.String("kvdb")
 	u, err := url.Parse(kvdbURL)
 	scheme := u.Scheme
@@ -185,6 +217,7 @@ func start(c *cli.Context) error {
 
 	// Start the cluster state machine, if enabled.
 	clusterInit := false
+	var alerts alert.Alert
 	if cfg.Osd.ClusterConfig.NodeId != "" && cfg.Osd.ClusterConfig.ClusterId != "" {
 		dlog.Infof("OSD enabling cluster mode.")
 		if err := cluster.Init(cfg.Osd.ClusterConfig); err != nil {
@@ -194,15 +227,33 @@ func start(c *cli.Context) error {
 			return fmt.Errorf("Unable to start cluster API server: %v", err)
 		}
 		clusterInit = true
+		if alerts, err = cluster.Alerts(); err != nil {
+			return fmt.Errorf("Unable to init alerts: %v", err)
+		}
 	}
 
 	isDefaultSet := false
+	snapDrivers := make([]volume.VolumeDriver, 0, len(cfg.Osd.Drivers))
 	// Start the volume drivers.
 	for d, v := range cfg.Osd.Drivers {
 		dlog.Infof("Starting volume driver: %v", d)
 		if err := volumedrivers.Register(d, v); err != nil {
 			return fmt.Errorf("Unable to start volume driver: %v, %v", d, err)
 		}
+		if alerts != nil {
+			// Raise alerts on the attach, mount and unmount failures of
+			// the driver.
+			if err := volumedrivers.Wrap(d, func(vd volume.VolumeDriver) volume.VolumeDriver {
+				return monitor.NewDriver(vd, alerts)
+			}); err != nil {
+				return fmt.Errorf("Unable to wrap volume driver: %v, %v", d, err)
+			}
+		}
+		vd, err := volumedrivers.Get(d)
+		if err != nil {
+			return fmt.Errorf("Unable to find volume driver: %v, %v", d, err)
+		}
+		snapDrivers = append(snapDrivers, vd)
 
 		var mgmtPort, pluginPort uint64
 		if port, ok := v[config.MgmtPortKey]; ok {
@@ -263,10 +314,97 @@ func start(c *cli.Context) error {
 		}
 	}
 
+	// Start the CSI servers of the drivers that have a CSI endpoint.
+	var csiTLS *tls.Config
+	if cfg.Osd.CsiTLS != nil {
+		if csiTLS, err = newTLSConfig(cfg.Osd.CsiTLS); err != nil {
+			return fmt.Errorf("Unable to set up TLS for CSI: %v", err)
+		}
+	}
+	for d, v := range cfg.Osd.Drivers {
+		endpoint, ok := v[config.CsiEndpointKey]
+		if !ok {
+			continue
+		}
+		if err := startCsiServer(d, endpoint, clusterInit, csiTLS); err != nil {
+			return fmt.Errorf("Unable to start CSI server for driver %v: %v", d, err)
+		}
+	}
+
+	// Start the snapshot scheduler. Only the node holding the scheduler
+	// lock in kvdb takes snapshots.
+	nodeID := cfg.Osd.ClusterConfig.NodeId
+	if nodeID == "" {
+		if nodeID, err = os.Hostname(); err != nil {
+			return fmt.Errorf("Unable to get hostname: %v", err)
+		}
+	}
+	if sched.Instance() == nil {
+		sched.Init(time.Second)
+	}
+	snapscheduler.New(kv, nodeID, snapDrivers, sched.Instance()).Start()
+
+	// Raise alerts on the status and usage of the volumes.
+	if alerts != nil {
+		monitor.New(alerts, snapDrivers).Start()
+	}
+
 	// Daemon does not exit.
 	select {}
 }
 
+// newTLSConfig returns a server TLS configuration with the certificates in
+// c, which are reloaded every time the daemon receives SIGHUP.
+func newTLSConfig(c *tlsutil.Config) (*tls.Config, error) {
+	r, err := tlsutil.NewReloader(c)
+	if err != nil {
+		return nil, err
+	}
+	r.ReloadOnSignal()
+	return r.TLSConfig(), nil
+}
+
+// startCsiServer serves CSI for driver on endpoint, which is either a
+// unix:// or a tcp:// URL.
+func startCsiServer(
+	driver string,
+	endpoint string,
+	clusterInit bool,
+	tlsConfig *tls.Config,
+) error {
+	if !clusterInit {
+		return fmt.Errorf("CSI requires cluster mode")
+	}
+	cm, err := cluster.Inst()
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	address := u.Host
+	switch u.Scheme {
+	case "unix":
+		address = u.Path
+		os.Remove(address)
+	case "tcp":
+	default:
+		return fmt.Errorf("Invalid CSI endpoint %v", endpoint)
+	}
+	s, err := csi.NewOsdCsiServer(&csi.OsdCsiServerConfig{
+		Net:        u.Scheme,
+		Address:    address,
+		DriverName: driver,
+		Cluster:    cm,
+		TLS:        tlsConfig,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Start()
+}
+
 func showVersion(c *cli.Context) error {
 	fmt.Println("OSD Version:", config.Version)
 	fmt.Println("Go Version:", runtime.Version())
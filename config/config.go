@@ -7,6 +7,8 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/libopenstorage/openstorage/pkg/auth"
+	"github.com/libopenstorage/openstorage/pkg/tlsutil"
 	"github.com/libopenstorage/openstorage/volume"
 	"go.pedge.io/dlog/logrus"
 )
@@ -17,6 +19,7 @@ const (
 	UrlKey                    = "url"
 	MgmtPortKey               = "mgmtPort"
 	PluginPortKey             = "pluginPort"
+	CsiEndpointKey            = "csiEndpoint"
 	VersionKey                = "version"
 	DataDir                   = ".data"
 	FlexVolumePort     uint16 = 2345
@@ -45,6 +48,15 @@ type ClusterConfig struct {
 type Config struct {
 	Osd struct {
 		ClusterConfig ClusterConfig `yaml:"cluster"`
+		// Auth turns on token authentication of the REST API if set.
+		Auth *auth.JwtConfig `yaml:"auth"`
+		// TLS serves the REST API on its TCP ports over TLS if set.
+		TLS *tlsutil.Config `yaml:"tls"`
+		// CsiTLS serves the CSI endpoints over TLS if set.
+		CsiTLS *tlsutil.Config `yaml:"csi_tls"`
+		// SeedFileRoots are the directories volumes may be seeded from
+		// with file:// URIs.
+		SeedFileRoots []string `yaml:"seed_file_roots"`
 		// map[string]string is volume.VolumeParams equivalent
 		Drivers map[string]map[string]string
 		// map[string]string is volume.VolumeParams equivalent
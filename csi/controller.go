@@ -18,9 +18,13 @@ package csi
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/options"
 	"github.com/libopenstorage/openstorage/pkg/util"
+	"github.com/libopenstorage/openstorage/volume"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"go.pedge.io/dlog"
@@ -34,8 +38,31 @@ const (
 	volumeCapabilityMessageNotMultinodeVolume = "Volume is not a multinode volume"
 	volumeCapabilityMessageReadOnlyVolume     = "Volume is read only"
 	volumeCapabilityMessageNotReadOnlyVolume  = "Volume is not read only"
+	volumeCapabilityMessageBlockNotSupported  = "Block access requires a block driver"
+
+	// publishInfoDevicePath is the publish context key of the device a
+	// volume was attached at by ControllerPublishVolume
+	publishInfoDevicePath = "devicePath"
+
+	// defaultCSIVolumeSize is the size of the volumes created without a
+	// capacity range
+	defaultCSIVolumeSize = 1 << 30
+
+	// topologyKeyPrefix prefixes the topology segment keys of the nodes
+	topologyKeyPrefix = "topology.openstorage.org/"
+
+	// topologyKeyNode is the topology segment key of the node id
+	topologyKeyNode = topologyKeyPrefix + "node"
 )
 
+// topologyPlacement maps the node labels reported as topology segments,
+// under the topologyKeyPrefix, to the volume placement parameters.
+var topologyPlacement = map[string]string{
+	"zone":   api.SpecZones,
+	"rack":   api.SpecRacks,
+	"region": api.SpecRegions,
+}
+
 // ControllerGetCapabilities is a CSI API functions which returns to the caller
 // the capabilities of the OSD CSI driver.
 func (s *OsdCsiServer) ControllerGetCapabilities(
@@ -52,6 +79,15 @@ func (s *OsdCsiServer) ControllerGetCapabilities(
 		},
 	}
 
+	// Attaching and detaching volumes on other nodes supported
+	capPublishUnpublishVolume := &csi.ControllerServiceCapability{
+		Type: &csi.ControllerServiceCapability_Rpc{
+			Rpc: &csi.ControllerServiceCapability_RPC{
+				Type: csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+			},
+		},
+	}
+
 	// ListVolumes supported
 	capListVolumes := &csi.ControllerServiceCapability{
 		Type: &csi.ControllerServiceCapability_Rpc{
@@ -61,50 +97,227 @@ func (s *OsdCsiServer) ControllerGetCapabilities(
 		},
 	}
 
-	return &csi.ControllerGetCapabilitiesResponse{
-		Capabilities: []*csi.ControllerServiceCapability{
-			capCreateDeleteVolume,
-			capListVolumes,
+	// Creating and deleting snapshots supported
+	capCreateDeleteSnapshot := &csi.ControllerServiceCapability{
+		Type: &csi.ControllerServiceCapability_Rpc{
+			Rpc: &csi.ControllerServiceCapability_RPC{
+				Type: csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+			},
+		},
+	}
+
+	// ListSnapshots supported
+	capListSnapshots := &csi.ControllerServiceCapability{
+		Type: &csi.ControllerServiceCapability_Rpc{
+			Rpc: &csi.ControllerServiceCapability_RPC{
+				Type: csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+			},
+		},
+	}
+
+	// Creating volumes from other volumes supported
+	capCloneVolume := &csi.ControllerServiceCapability{
+		Type: &csi.ControllerServiceCapability_Rpc{
+			Rpc: &csi.ControllerServiceCapability_RPC{
+				Type: csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+			},
 		},
+	}
+
+	// Expanding volumes supported
+	capExpandVolume := &csi.ControllerServiceCapability{
+		Type: &csi.ControllerServiceCapability_Rpc{
+			Rpc: &csi.ControllerServiceCapability_RPC{
+				Type: csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+			},
+		},
+	}
+
+	capabilities := []*csi.ControllerServiceCapability{
+		capCreateDeleteVolume,
+		capListVolumes,
+		capCreateDeleteSnapshot,
+		capListSnapshots,
+		capCloneVolume,
+		capExpandVolume,
+	}
+	// Volumes of other drivers are attached by the node they are staged on
+	if s.attachesRemotely() {
+		capabilities = append(capabilities, capPublishUnpublishVolume)
+	}
+
+	return &csi.ControllerGetCapabilitiesResponse{
+		Capabilities: capabilities,
 	}, nil
 
 }
 
 // ControllerPublishVolume is a CSI API implements the attachment of a volume
-// on to a node.
+// on to a node. Volumes of block drivers which attach on other nodes are
+// attached on the node and the device path is returned to NodePublishVolume
+// in the publish context. Other volumes are attached by NodeStageVolume or
+// NodePublishVolume, if they need to be attached at all.
 func (s *OsdCsiServer) ControllerPublishVolume(
-	context.Context,
-	*csi.ControllerPublishVolumeRequest,
+	ctx context.Context,
+	req *csi.ControllerPublishVolumeRequest,
 ) (*csi.ControllerPublishVolumeResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "This request is not supported")
 
+	dlog.Debugf("ControllerPublishVolume req[%#v]", req)
+
+	// Check arguments
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
+	}
+	if len(req.GetNodeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Node id must be provided")
+	}
+	if req.GetVolumeCapability() == nil || req.GetVolumeCapability().GetAccessMode() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Volume access mode must be provided")
+	}
+
+	// Get volume information
+	v, err := util.VolumeFromName(s.driver, req.GetVolumeId())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Volume id %s not found: %s",
+			req.GetVolumeId(),
+			err.Error())
+	}
+
+	if _, err := s.cluster.Inspect(req.GetNodeId()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "Node id %s not found: %s",
+			req.GetNodeId(),
+			err.Error())
+	}
+
+	if !s.attachesRemotely() {
+		return &csi.ControllerPublishVolumeResponse{}, nil
+	}
+
+	// Publishing again on the same node returns the same device
+	if v.GetState() == api.VolumeState_VOLUME_STATE_ATTACHED && len(v.GetAttachedOn()) != 0 {
+		if v.GetAttachedOn() != req.GetNodeId() {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"Volume %s is attached on node %s",
+				req.GetVolumeId(),
+				v.GetAttachedOn())
+		}
+		if len(v.GetDevicePath()) != 0 {
+			return publishResponse(v.GetDevicePath()), nil
+		}
+	}
+
+	spec, _, _, err := s.specHandler.SpecFromOpts(req.GetVolumeContext())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"Invalid volume context: %#v",
+			req.GetVolumeContext())
+	}
+	opts := map[string]string{
+		options.OptionsAttachNode: req.GetNodeId(),
+	}
+	if len(spec.GetPassphrase()) != 0 {
+		opts[options.OptionsSecret] = spec.GetPassphrase()
+	}
+
+	devicePath, err := s.driver.Attach(v.GetId(), opts)
+	if err == volume.ErrVolAttachedOnRemoteNode || err == volume.ErrVolAttachedScale {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"Unable to attach volume %s on node %s: %s",
+			req.GetVolumeId(),
+			req.GetNodeId(),
+			err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(
+			codes.Internal,
+			"Unable to attach volume: %s",
+			err.Error())
+	}
+
+	dlog.Infof("Volume %s attached on node %s at %s",
+		req.GetVolumeId(),
+		req.GetNodeId(),
+		devicePath)
+
+	return publishResponse(devicePath), nil
 }
 
 // ControllerUnpublishVolume is a CSI API which implements the detaching of a volume
-// onto a node.
+// onto a node. Volumes that are not attached, or attached on another node
+// than the one requested, are left as they are.
 func (s *OsdCsiServer) ControllerUnpublishVolume(
-	context.Context,
-	*csi.ControllerUnpublishVolumeRequest,
+	ctx context.Context,
+	req *csi.ControllerUnpublishVolumeRequest,
 ) (*csi.ControllerUnpublishVolumeResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "This request is not supported")
+
+	dlog.Debugf("ControllerUnpublishVolume req[%#v]", req)
+
+	// Check arguments
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
+	}
+
+	// Get volume information
+	v, err := util.VolumeFromName(s.driver, req.GetVolumeId())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Volume id %s not found: %s",
+			req.GetVolumeId(),
+			err.Error())
+	}
+
+	if !s.attachesRemotely() ||
+		v.GetState() == api.VolumeState_VOLUME_STATE_DETACHED {
+		return &csi.ControllerUnpublishVolumeResponse{}, nil
+	}
+	if len(req.GetNodeId()) != 0 &&
+		len(v.GetAttachedOn()) != 0 &&
+		v.GetAttachedOn() != req.GetNodeId() {
+		return &csi.ControllerUnpublishVolumeResponse{}, nil
+	}
+
+	opts := make(map[string]string)
+	if len(req.GetNodeId()) != 0 {
+		opts[options.OptionsAttachNode] = req.GetNodeId()
+	}
+	if err := s.driver.Detach(v.GetId(), opts); err != nil && err != volume.ErrVolDetached {
+		return nil, status.Errorf(
+			codes.Internal,
+			"Unable to detach volume: %s",
+			err.Error())
+	}
+
+	dlog.Infof("Volume %s detached", req.GetVolumeId())
+
+	return &csi.ControllerUnpublishVolumeResponse{}, nil
+}
+
+// attachesRemotely returns true if the driver is a block driver which can
+// attach volumes on other nodes than this one.
+func (s *OsdCsiServer) attachesRemotely() bool {
+	r, ok := s.driver.(volume.RemoteAttacher)
+	return ok && r.AttachesRemotely() &&
+		s.driver.Type() == api.DriverType_DRIVER_TYPE_BLOCK
+}
+
+func publishResponse(devicePath string) *csi.ControllerPublishVolumeResponse {
+	return &csi.ControllerPublishVolumeResponse{
+		PublishContext: map[string]string{
+			publishInfoDevicePath: devicePath,
+		},
+	}
 }
 
 // ValidateVolumeCapabilities is a CSI API used by container orchestration systems
 // to make sure a volume specification is validiated by the CSI driver.
-// Note: The method used here to return errors is still not part of the spec.
-// See: https://github.com/container-storage-interface/spec/pull/115
-// Discussion:  https://groups.google.com/forum/#!topic/kubernetes-sig-storage-wg-csi/TpTrNFbRa1I
-//
+// The capabilities are confirmed only if the volume supports all of them,
+// otherwise the message says why they are not.
 func (s *OsdCsiServer) ValidateVolumeCapabilities(
 	ctx context.Context,
 	req *csi.ValidateVolumeCapabilitiesRequest,
 ) (*csi.ValidateVolumeCapabilitiesResponse, error) {
 
-	// Probably we may use version in the future, but for now, let's just log it
-	version := req.GetVersion()
-	if version == nil {
-		return nil, status.Error(codes.InvalidArgument, "Version must be specified")
-	}
 	capabilities := req.GetVolumeCapabilities()
 	if capabilities == nil || len(capabilities) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "volume_capabilities must be specified")
@@ -113,16 +326,14 @@ func (s *OsdCsiServer) ValidateVolumeCapabilities(
 	if len(id) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "volume_id must be specified")
 	}
-	attributes := req.GetVolumeAttributes()
+	attributes := req.GetVolumeContext()
 
 	// Log request
 	dlog.Debugf("ValidateVolumeCapabilities of id %s "+
 		"capabilities %#v "+
-		"version %#v "+
-		"attributes %#v ",
+		"context %#v ",
 		id,
 		capabilities,
-		version,
 		attributes)
 
 	// Check ID is valid with the specified volume capabilities
@@ -148,66 +359,60 @@ func (s *OsdCsiServer) ValidateVolumeCapabilities(
 	}
 
 	// Setup uninitialized response object
-	result := &csi.ValidateVolumeCapabilitiesResponse{
-		Supported: true,
-	}
+	result := &csi.ValidateVolumeCapabilitiesResponse{}
 
 	// Check capability
 	for _, capability := range capabilities {
-		// Currently the CSI spec defines all storage as "file systems."
-		// So we do not need to check this with the volume. All we will check
-		// here is the validity of the capability access type.
+		// Check the access type. Raw block access needs the device of a
+		// block driver, any driver can mount.
 		if capability.GetMount() == nil && capability.GetBlock() == nil {
 			return nil, status.Error(
 				codes.InvalidArgument,
 				"Cannot have both mount and block be undefined")
 		}
+		if capability.GetBlock() != nil &&
+			s.driver.Type() != api.DriverType_DRIVER_TYPE_BLOCK {
+			result.Message = volumeCapabilityMessageBlockNotSupported
+			return result, nil
+		}
 
 		// Check access mode is setup correctly
 		mode := capability.GetAccessMode()
 		switch {
-		case mode.Mode == csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER:
+		case mode.GetMode() == csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER:
 			if v.Spec.Shared {
-				result.Supported = false
 				result.Message = volumeCapabilityMessageMultinodeVolume
 				break
 			}
 			if v.Readonly {
-				result.Supported = false
 				result.Message = volumeCapabilityMessageReadOnlyVolume
 				break
 			}
-		case mode.Mode == csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY:
+		case mode.GetMode() == csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY:
 			if v.Spec.Shared {
-				result.Supported = false
 				result.Message = volumeCapabilityMessageMultinodeVolume
 				break
 			}
 			if !v.Readonly {
-				result.Supported = false
 				result.Message = volumeCapabilityMessageNotReadOnlyVolume
 				break
 			}
-		case mode.Mode == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY:
+		case mode.GetMode() == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY:
 			if !v.Spec.Shared {
-				result.Supported = false
 				result.Message = volumeCapabilityMessageNotMultinodeVolume
 				break
 			}
 			if !v.Readonly {
-				result.Supported = false
 				result.Message = volumeCapabilityMessageNotReadOnlyVolume
 				break
 			}
-		case mode.Mode == csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER ||
-			mode.Mode == csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER:
+		case mode.GetMode() == csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER ||
+			mode.GetMode() == csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER:
 			if !v.Spec.Shared {
-				result.Supported = false
 				result.Message = volumeCapabilityMessageNotMultinodeVolume
 				break
 			}
 			if v.Readonly {
-				result.Supported = false
 				result.Message = volumeCapabilityMessageReadOnlyVolume
 				break
 			}
@@ -215,15 +420,20 @@ func (s *OsdCsiServer) ValidateVolumeCapabilities(
 			return nil, status.Errorf(
 				codes.InvalidArgument,
 				"AccessMode %s is not allowed",
-				mode.Mode.String())
+				mode.GetMode().String())
 		}
 
-		if !result.Supported {
+		if len(result.Message) != 0 {
 			return result, nil
 		}
 	}
 
 	// If we passed all the checks, then it is valid
+	result.Confirmed = &csi.ValidateVolumeCapabilitiesResponse_Confirmed{
+		VolumeContext:      req.GetVolumeContext(),
+		VolumeCapabilities: capabilities,
+		Parameters:         req.GetParameters(),
+	}
 	result.Message = "Volume is supported"
 	return result, nil
 }
@@ -233,66 +443,58 @@ func (s *OsdCsiServer) ValidateVolumeCapabilities(
 // using other interfaces. This is important because the user could
 // be requesting to mount a OSD volume created using non-CSI interfaces.
 //
-// This call does not yet implement tokens to due to the following
-// issue: https://github.com/container-storage-interface/spec/issues/138
+// When max_entries is set the volumes are returned in pages, and
+// next_token is the starting_token of the following page.
 func (s *OsdCsiServer) ListVolumes(
 	ctx context.Context,
 	req *csi.ListVolumesRequest,
 ) (*csi.ListVolumesResponse, error) {
 
-	// Future: Once CSI is released, check version
-	// for now, just log it.
 	dlog.Debugf("ListVolumes req[%#v]", req)
 
-	// Check arguments
-	if req.GetVersion() == nil {
-		return nil, status.Error(codes.InvalidArgument, "Version must be provided")
-	}
-
-	// Until the issue #138 on the CSI spec is resolved we will not support
-	// tokenization
-	if req.GetMaxEntries() != 0 {
-		return nil, status.Error(
-			codes.Unimplemented,
-			"Driver does not support tokenization. Please see "+
-				"https://github.com/container-storage-interface/spec/issues/138")
-	}
-
-	volumes, err := s.driver.Enumerate(&api.VolumeLocator{}, nil)
-	if err != nil {
+	list, err := s.driver.EnumerateWithFilter(&api.VolumeFilter{
+		Limit: int(req.GetMaxEntries()),
+		Token: req.GetStartingToken(),
+	})
+	if err == volume.ErrInvalidToken {
+		return nil, status.Errorf(codes.Aborted,
+			"Invalid starting_token %q", req.GetStartingToken())
+	} else if err != nil {
 		errs := fmt.Sprintf("Unable to get list of volumes: %s", err.Error())
 		dlog.Errorln(errs)
 		return nil, status.Error(codes.Internal, errs)
 	}
+	volumes := list.Volumes
 	entries := make([]*csi.ListVolumesResponse_Entry, len(volumes))
 	for i, v := range volumes {
 		// Initialize entry
 		entries[i] = &csi.ListVolumesResponse_Entry{
-			VolumeInfo: &csi.VolumeInfo{},
+			Volume: &csi.Volume{},
 		}
 
 		// Required
-		entries[i].VolumeInfo.Id = v.Id
+		entries[i].Volume.VolumeId = v.Id
 
 		// This entry is optional in the API, but OSD has
 		// the information available to provide it
-		entries[i].VolumeInfo.CapacityBytes = v.Spec.Size
+		entries[i].Volume.CapacityBytes = int64(v.Spec.Size)
 
 		// Attributes. We can add or remove as needed since they
 		// are optional and opaque to the Container Orchestrator(CO)
 		// but could be used for debugging using a csi complient client.
-		entries[i].VolumeInfo.Attributes = osdVolumeAttributes(v)
+		entries[i].Volume.VolumeContext = osdVolumeAttributes(v)
 	}
 
 	return &csi.ListVolumesResponse{
-		Entries: entries,
+		Entries:   entries,
+		NextToken: list.NextToken,
 	}, nil
 }
 
 // osdVolumeAttributes returns the attributes of a volume as a map
-// to be returned to the CSI API caller
+// to be returned to the CSI API caller as the volume context
 func osdVolumeAttributes(v *api.Volume) map[string]string {
-	return map[string]string{
+	attributes := map[string]string{
 		api.SpecParent: v.GetSource().GetParent(),
 		api.SpecSecure: fmt.Sprintf("%v", v.GetSpec().GetEncrypted()),
 		api.SpecShared: fmt.Sprintf("%v", v.GetSpec().GetShared()),
@@ -301,11 +503,20 @@ func osdVolumeAttributes(v *api.Volume) map[string]string {
 		"state":        v.State.String(),
 		"error":        v.GetError(),
 	}
+
+	// The placement is also returned as attributes, which the 0.x specs
+	// used before accessible topology.
+	for _, k := range []string{api.SpecZones, api.SpecRacks, api.SpecRegions} {
+		if placement, ok := v.GetLocator().GetVolumeLabels()[k]; ok {
+			attributes[k] = placement
+		}
+	}
+	return attributes
 }
 
 // CreateVolume is a CSI API which creates a volume on OSD
-// This function supports snapshots if the parent volume id is supplied
-// in the parameters.
+// Volumes with a content source are created as a clone of the snapshot or
+// volume, as are volumes with a parent volume id in the parameters.
 func (s *OsdCsiServer) CreateVolume(
 	ctx context.Context,
 	req *csi.CreateVolumeRequest,
@@ -315,20 +526,21 @@ func (s *OsdCsiServer) CreateVolume(
 	dlog.Debugf("CreateVolume req[%#v]", *req)
 
 	// Check arguments
-	if req.GetVersion() == nil {
-		return nil, status.Error(codes.InvalidArgument, "Version must be provided")
-	}
 	if len(req.GetName()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Name must be provided")
 	}
 	if req.GetVolumeCapabilities() == nil || len(req.GetVolumeCapabilities()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume capabilities must be provided")
 	}
-	if req.GetCapacityRange() == nil {
-		return nil, status.Error(codes.InvalidArgument, "CapacityRange must be specified")
+	size, err := csiRequestedSize(req.GetCapacityRange())
+	if err != nil {
+		return nil, err
 	}
-	if req.GetCapacityRange().GetRequiredBytes() == 0 {
-		return nil, status.Error(codes.InvalidArgument, "Capacity range required bytes cannot be zero")
+	for _, capability := range req.GetVolumeCapabilities() {
+		if capability.GetBlock() != nil &&
+			s.driver.Type() != api.DriverType_DRIVER_TYPE_BLOCK {
+			return nil, status.Error(codes.InvalidArgument, volumeCapabilityMessageBlockNotSupported)
+		}
 	}
 
 	// Get parameters
@@ -338,23 +550,30 @@ func (s *OsdCsiServer) CreateVolume(
 		dlog.Errorln(e)
 		return nil, status.Error(codes.InvalidArgument, e)
 	}
+	contentID := csiContentSourceID(req.GetVolumeContentSource())
+	parentID := source.GetParent()
+	if len(contentID) != 0 {
+		parentID = contentID
+	}
+	// Clones have the size of their source unless a size is requested
+	sizeRequested := len(contentID) == 0 || req.GetCapacityRange() != nil
 
 	// Create response
-	volume := &csi.VolumeInfo{}
+	volume := &csi.Volume{}
 	resp := &csi.CreateVolumeResponse{
-		VolumeInfo: volume,
+		Volume: volume,
 	}
 
 	// Check if the volume has already been created or is in process of creation
 	v, err := util.VolumeFromName(s.driver, req.GetName())
 	if err == nil {
 		// Check the requested arguments match that of the existing volume
-		if v.GetSpec().GetSize() != req.GetCapacityRange().GetRequiredBytes() {
+		if sizeRequested && v.GetSpec().GetSize() != size {
 			return nil, status.Errorf(
 				codes.AlreadyExists,
 				"Existing volume has a size of %v which differs from requested size of %v",
 				v.GetSpec().GetSize(),
-				req.GetCapacityRange().GetRequiredBytes())
+				size)
 		}
 		if v.GetSpec().GetShared() != csiRequestsSharedVolume(req) {
 			return nil, status.Errorf(
@@ -363,18 +582,43 @@ func (s *OsdCsiServer) CreateVolume(
 				v.GetSpec().GetShared(),
 				csiRequestsSharedVolume(req))
 		}
-		if v.GetSource().GetParent() != source.GetParent() {
+		if v.GetSource().GetParent() != parentID {
 			return nil, status.Error(codes.AlreadyExists, "Existing volume has conflicting parent value")
 		}
 
 		// Return information on existing volume
-		osdToCsiVolumeInfo(volume, v)
+		osdToCsiVolume(volume, v)
+		volume.ContentSource = req.GetVolumeContentSource()
 		return resp, nil
 	}
 
-	// Check if the caller is asking to create a snapshot or for a new volume
+	// Check if the caller is asking to create a clone or for a new volume
 	var id string
-	if source != nil && len(source.GetParent()) != 0 {
+	if len(contentID) != 0 {
+		// Get source volume information
+		parent, err := s.contentSourceVolume(req.GetVolumeContentSource())
+		if err != nil {
+			return nil, err
+		}
+		if sizeRequested && parent.GetSpec().GetSize() != size {
+			return nil, status.Errorf(
+				codes.OutOfRange,
+				"Requested size of %v differs from the size of %v of the source %s",
+				size,
+				parent.GetSpec().GetSize(),
+				contentID)
+		}
+
+		// Create a writeable snapshot of the source
+		id, err = s.driver.Snapshot(parent.GetId(), false, &api.VolumeLocator{
+			Name: req.GetName(),
+		})
+		if err != nil {
+			e := fmt.Sprintf("unable to create volume from %s: %s\n", contentID, err.Error())
+			dlog.Errorln(e)
+			return nil, status.Error(codes.Internal, e)
+		}
+	} else if source != nil && len(source.GetParent()) != 0 {
 		// Get parent volume information
 		parent, err := util.VolumeFromName(s.driver, source.Parent)
 		if err != nil {
@@ -394,8 +638,9 @@ func (s *OsdCsiServer) CreateVolume(
 		}
 	} else {
 		// Get Capabilities and Size
-		spec.Size = req.GetCapacityRange().GetRequiredBytes()
+		spec.Size = size
 		spec.Shared = csiRequestsSharedVolume(req)
+		csiTopologyPlacement(req.GetAccessibilityRequirements(), spec, locator)
 
 		// Create the volume
 		locator.Name = req.GetName()
@@ -412,10 +657,124 @@ func (s *OsdCsiServer) CreateVolume(
 		dlog.Errorln(e)
 		return nil, status.Error(codes.Internal, e)
 	}
-	osdToCsiVolumeInfo(volume, v)
+	osdToCsiVolume(volume, v)
+	volume.ContentSource = req.GetVolumeContentSource()
 	return resp, nil
 }
 
+// csiTopologyPlacement places a new volume in the zones, racks, regions and
+// nodes of the topology segments required by the CO, preferred segments
+// first. Placement set by the parameters takes precedence. The replica set
+// is limited to the HA level of the volume.
+func csiTopologyPlacement(
+	req *csi.TopologyRequirement,
+	spec *api.VolumeSpec,
+	locator *api.VolumeLocator,
+) {
+	values := make(map[string][]string)
+	for _, t := range append(req.GetPreferred(), req.GetRequisite()...) {
+		for k, v := range t.GetSegments() {
+			if !containsString(values[k], v) {
+				values[k] = append(values[k], v)
+			}
+		}
+	}
+
+	for label, placement := range topologyPlacement {
+		segments := values[topologyKeyPrefix+label]
+		if len(segments) == 0 {
+			continue
+		}
+		if locator.VolumeLabels == nil {
+			locator.VolumeLabels = make(map[string]string)
+		}
+		if _, ok := locator.VolumeLabels[placement]; !ok {
+			locator.VolumeLabels[placement] = strings.Join(segments, ",")
+		}
+	}
+
+	if nodes := values[topologyKeyNode]; len(nodes) != 0 && spec.GetReplicaSet() == nil {
+		if ha := int(spec.GetHaLevel()); ha > 0 && len(nodes) > ha {
+			nodes = nodes[:ha]
+		}
+		spec.ReplicaSet = &api.ReplicaSet{Nodes: nodes}
+	}
+}
+
+// csiVolumeTopology returns the topology a volume was placed in: a segment
+// per node of its replica set, or else the segments of its zone, rack and
+// region placement. Volumes which were not placed have no topology.
+func csiVolumeTopology(v *api.Volume) []*csi.Topology {
+	var topology []*csi.Topology
+	if nodes := v.GetSpec().GetReplicaSet().GetNodes(); len(nodes) != 0 {
+		for _, node := range nodes {
+			topology = append(topology, &csi.Topology{
+				Segments: map[string]string{topologyKeyNode: node},
+			})
+		}
+		return topology
+	}
+
+	// A volume placed in several zones, racks or regions is in each
+	// combination of them.
+	segments := []map[string]string{map[string]string{}}
+	for _, label := range []string{"region", "zone", "rack"} {
+		placement := v.GetLocator().GetVolumeLabels()[topologyPlacement[label]]
+		if len(placement) == 0 {
+			continue
+		}
+		var combined []map[string]string
+		for _, segment := range segments {
+			for _, value := range strings.Split(placement, ",") {
+				c := map[string]string{topologyKeyPrefix + label: value}
+				for k, v := range segment {
+					c[k] = v
+				}
+				combined = append(combined, c)
+			}
+		}
+		segments = combined
+	}
+	if len(segments[0]) == 0 {
+		return nil
+	}
+	for _, segment := range segments {
+		topology = append(topology, &csi.Topology{Segments: segment})
+	}
+	return topology
+}
+
+// csiContentSourceID returns the id of the snapshot or volume a volume is
+// created from, or an empty string.
+func csiContentSourceID(source *csi.VolumeContentSource) string {
+	if snap := source.GetSnapshot(); snap != nil {
+		return snap.GetSnapshotId()
+	}
+	return source.GetVolume().GetVolumeId()
+}
+
+// contentSourceVolume returns the snapshot or volume of the content source.
+func (s *OsdCsiServer) contentSourceVolume(
+	source *csi.VolumeContentSource,
+) (*api.Volume, error) {
+	id := csiContentSourceID(source)
+	volumes, err := s.driver.Inspect([]string{id})
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal,
+			"Unable to get source %s: %s",
+			id,
+			err.Error())
+	}
+	if len(volumes) == 0 {
+		return nil, status.Errorf(codes.NotFound, "Source %s not found", id)
+	}
+	if source.GetSnapshot() != nil && !isCSISnapshot(volumes[0]) {
+		return nil, status.Errorf(codes.NotFound, "Snapshot %s not found", id)
+	}
+	return volumes[0], nil
+}
+
 // DeleteVolume is a CSI API which deletes a volume
 func (s *OsdCsiServer) DeleteVolume(
 	ctx context.Context,
@@ -426,15 +785,25 @@ func (s *OsdCsiServer) DeleteVolume(
 	dlog.Debugf("DeleteVolume req[%#v]", *req)
 
 	// Check arguments
-	if req.GetVersion() == nil {
-		return nil, status.Error(codes.InvalidArgument, "Version must be provided")
-	}
 	if len(req.GetVolumeId()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
 	}
 
-	err := s.driver.Delete(req.GetVolumeId())
-	if err != nil {
+	// Deleting a volume that does not exist succeeds
+	volumes, err := s.driver.Inspect([]string{req.GetVolumeId()})
+	if err == nil && len(volumes) == 0 {
+		dlog.Infof("Volume %s not found, it may have been deleted already",
+			req.GetVolumeId())
+		return &csi.DeleteVolumeResponse{}, nil
+	}
+
+	err = s.driver.Delete(req.GetVolumeId())
+	if err == volume.ErrVolHasSnaps {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"Volume %s has snapshots or clones",
+			req.GetVolumeId())
+	} else if err != nil {
 		e := fmt.Sprintf("Unable to delete volume with id %s: %s",
 			req.GetVolumeId(),
 			err.Error())
@@ -445,10 +814,36 @@ func (s *OsdCsiServer) DeleteVolume(
 	return &csi.DeleteVolumeResponse{}, nil
 }
 
-func osdToCsiVolumeInfo(dest *csi.VolumeInfo, src *api.Volume) {
-	dest.Id = src.GetId()
-	dest.CapacityBytes = src.Spec.GetSize()
-	dest.Attributes = osdVolumeAttributes(src)
+func osdToCsiVolume(dest *csi.Volume, src *api.Volume) {
+	dest.VolumeId = src.GetId()
+	dest.CapacityBytes = int64(src.Spec.GetSize())
+	dest.VolumeContext = osdVolumeAttributes(src)
+	dest.AccessibleTopology = csiVolumeTopology(src)
+}
+
+// csiRequestedSize returns the size of the volume requested by the capacity
+// range, the required bytes or else the limit bytes. Volumes are created with
+// defaultCSIVolumeSize when no range is given, or the limit allows it.
+func csiRequestedSize(r *csi.CapacityRange) (uint64, error) {
+	required, limit := r.GetRequiredBytes(), r.GetLimitBytes()
+	if required < 0 || limit < 0 {
+		return 0, status.Error(codes.InvalidArgument, "Capacity range cannot be negative")
+	}
+	if limit != 0 && required > limit {
+		return 0, status.Errorf(
+			codes.OutOfRange,
+			"Capacity range required bytes %v exceeds the limit of %v bytes",
+			required,
+			limit)
+	}
+	switch {
+	case required != 0:
+		return uint64(required), nil
+	case limit != 0 && limit < defaultCSIVolumeSize:
+		return uint64(limit), nil
+	default:
+		return defaultCSIVolumeSize, nil
+	}
 }
 
 func csiRequestsSharedVolume(req *csi.CreateVolumeRequest) bool {
@@ -463,9 +858,273 @@ func csiRequestsSharedVolume(req *csi.CreateVolumeRequest) bool {
 	return false
 }
 
-/*
-For next patches what still needs to be worked on in the Conroller server:
+// CreateSnapshot is a CSI API which takes a read only snapshot of a volume.
+// Calling it again with the same name and source volume returns the
+// existing snapshot.
+func (s *OsdCsiServer) CreateSnapshot(
+	ctx context.Context,
+	req *csi.CreateSnapshotRequest,
+) (*csi.CreateSnapshotResponse, error) {
 
-	GetCapacity(context.Context, *GetCapacityRequest) (*GetCapacityResponse, error)
-	ControllerGetCapabilities(context.Context, *ControllerGetCapabilitiesRequest) (*ControllerGetCapabilitiesResponse, error)
-*/
+	dlog.Debugf("CreateSnapshot req[%#v]", *req)
+
+	// Check arguments
+	if len(req.GetName()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Name must be provided")
+	}
+	if len(req.GetSourceVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Source volume id must be provided")
+	}
+
+	// Check if the snapshot has already been taken
+	if v, err := util.VolumeFromName(s.driver, req.GetName()); err == nil {
+		if !isCSISnapshot(v) || v.GetSource().GetParent() != req.GetSourceVolumeId() {
+			return nil, status.Errorf(
+				codes.AlreadyExists,
+				"Volume %s exists and is not a snapshot of volume %s",
+				req.GetName(),
+				req.GetSourceVolumeId())
+		}
+		return &csi.CreateSnapshotResponse{
+			Snapshot: osdToCsiSnapshot(v),
+		}, nil
+	}
+
+	// Get source volume information
+	volumes, err := s.driver.Inspect([]string{req.GetSourceVolumeId()})
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal,
+			"Unable to get volume %s: %s",
+			req.GetSourceVolumeId(),
+			err.Error())
+	}
+	if len(volumes) == 0 {
+		return nil, status.Errorf(codes.NotFound,
+			"Volume id %s not found",
+			req.GetSourceVolumeId())
+	}
+
+	id, err := s.driver.Snapshot(volumes[0].GetId(), true, &api.VolumeLocator{
+		Name: req.GetName(),
+	})
+	if err != nil {
+		e := fmt.Sprintf("Unable to create snapshot of volume %s: %s",
+			req.GetSourceVolumeId(),
+			err.Error())
+		dlog.Errorln(e)
+		return nil, status.Error(codes.Internal, e)
+	}
+
+	snapshots, err := s.driver.Inspect([]string{id})
+	if err != nil || len(snapshots) == 0 {
+		return nil, status.Errorf(codes.Internal,
+			"Unable to find newly created snapshot %s", id)
+	}
+
+	return &csi.CreateSnapshotResponse{
+		Snapshot: osdToCsiSnapshot(snapshots[0]),
+	}, nil
+}
+
+// DeleteSnapshot is a CSI API which deletes a snapshot. Snapshots with
+// volumes created from them cannot be deleted.
+func (s *OsdCsiServer) DeleteSnapshot(
+	ctx context.Context,
+	req *csi.DeleteSnapshotRequest,
+) (*csi.DeleteSnapshotResponse, error) {
+
+	dlog.Debugf("DeleteSnapshot req[%#v]", *req)
+
+	// Check arguments
+	if len(req.GetSnapshotId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Snapshot id must be provided")
+	}
+
+	// Deleting a snapshot that does not exist succeeds
+	volumes, err := s.driver.Inspect([]string{req.GetSnapshotId()})
+	if err == nil && len(volumes) == 0 {
+		dlog.Infof("Snapshot %s not found, it may have been deleted already",
+			req.GetSnapshotId())
+		return &csi.DeleteSnapshotResponse{}, nil
+	} else if err == nil && !isCSISnapshot(volumes[0]) {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Volume %s is not a snapshot",
+			req.GetSnapshotId())
+	}
+
+	err = s.driver.SnapDelete(req.GetSnapshotId(), false)
+	if err == volume.ErrVolHasSnaps {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"Snapshot %s is in use: %s",
+			req.GetSnapshotId(),
+			err.Error())
+	} else if err != nil {
+		e := fmt.Sprintf("Unable to delete snapshot with id %s: %s",
+			req.GetSnapshotId(),
+			err.Error())
+		dlog.Errorln(e)
+		return nil, status.Error(codes.Internal, e)
+	}
+
+	return &csi.DeleteSnapshotResponse{}, nil
+}
+
+// ListSnapshots is a CSI API which returns the snapshots on this cluster,
+// or the ones of the snapshot id or source volume id requested.
+//
+// When max_entries is set the snapshots are returned in pages ordered by
+// snapshot id, and next_token is the starting_token of the following page.
+func (s *OsdCsiServer) ListSnapshots(
+	ctx context.Context,
+	req *csi.ListSnapshotsRequest,
+) (*csi.ListSnapshotsResponse, error) {
+
+	dlog.Debugf("ListSnapshots req[%#v]", *req)
+
+	var (
+		volumes []*api.Volume
+		err     error
+	)
+	switch {
+	case len(req.GetSnapshotId()) != 0:
+		volumes, err = s.driver.Inspect([]string{req.GetSnapshotId()})
+	case len(req.GetSourceVolumeId()) != 0:
+		volumes, err = s.driver.SnapEnumerate([]string{req.GetSourceVolumeId()}, nil)
+	default:
+		volumes, err = s.driver.SnapEnumerate(nil, nil)
+	}
+	if err != nil {
+		errs := fmt.Sprintf("Unable to get list of snapshots: %s", err.Error())
+		dlog.Errorln(errs)
+		return nil, status.Error(codes.Internal, errs)
+	}
+
+	snapshots := make([]*api.Volume, 0, len(volumes))
+	for _, v := range volumes {
+		if !isCSISnapshot(v) || v.GetId() <= req.GetStartingToken() {
+			continue
+		}
+		if len(req.GetSourceVolumeId()) != 0 &&
+			v.GetSource().GetParent() != req.GetSourceVolumeId() {
+			continue
+		}
+		snapshots = append(snapshots, v)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].GetId() < snapshots[j].GetId()
+	})
+
+	resp := &csi.ListSnapshotsResponse{}
+	for _, v := range snapshots {
+		if req.GetMaxEntries() > 0 && len(resp.Entries) == int(req.GetMaxEntries()) {
+			// Continue after the last snapshot returned
+			resp.NextToken = resp.Entries[len(resp.Entries)-1].GetSnapshot().GetSnapshotId()
+			break
+		}
+		resp.Entries = append(resp.Entries, &csi.ListSnapshotsResponse_Entry{
+			Snapshot: osdToCsiSnapshot(v),
+		})
+	}
+
+	return resp, nil
+}
+
+// isCSISnapshot returns true if the volume is a read only snapshot of
+// another volume, as taken by CreateSnapshot.
+func isCSISnapshot(v *api.Volume) bool {
+	return v.GetReadonly() && len(v.GetSource().GetParent()) != 0
+}
+
+func osdToCsiSnapshot(v *api.Volume) *csi.Snapshot {
+	return &csi.Snapshot{
+		SnapshotId:     v.GetId(),
+		SourceVolumeId: v.GetSource().GetParent(),
+		SizeBytes:      int64(v.GetSpec().GetSize()),
+		CreationTime:   v.GetCtime(),
+		ReadyToUse: v.GetState() != api.VolumeState_VOLUME_STATE_PENDING &&
+			v.GetState() != api.VolumeState_VOLUME_STATE_ERROR &&
+			v.GetState() != api.VolumeState_VOLUME_STATE_RESTORE,
+	}
+}
+
+// ControllerExpandVolume is a CSI API which grows a volume to the size
+// requested by the capacity range. Volumes are never shrunk. Block drivers
+// grow the filesystem of volumes attached on the node that serves the call,
+// NodeExpandVolume grows it on the node the volume is published on.
+func (s *OsdCsiServer) ControllerExpandVolume(
+	ctx context.Context,
+	req *csi.ControllerExpandVolumeRequest,
+) (*csi.ControllerExpandVolumeResponse, error) {
+
+	dlog.Debugf("ControllerExpandVolume req[%#v]", *req)
+
+	// Check arguments
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
+	}
+	if req.GetCapacityRange() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Capacity range must be provided")
+	}
+	size, err := csiRequestedSize(req.GetCapacityRange())
+	if err != nil {
+		return nil, err
+	}
+
+	// Get volume information
+	volumes, err := s.driver.Inspect([]string{req.GetVolumeId()})
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal,
+			"Unable to get volume %s: %s",
+			req.GetVolumeId(),
+			err.Error())
+	}
+	if len(volumes) == 0 {
+		return nil, status.Errorf(codes.NotFound,
+			"Volume id %s not found",
+			req.GetVolumeId())
+	}
+	v := volumes[0]
+
+	limit := uint64(req.GetCapacityRange().GetLimitBytes())
+	if limit != 0 && v.GetSpec().GetSize() > limit {
+		return nil, status.Errorf(
+			codes.OutOfRange,
+			"Volume %s has a size of %v which exceeds the limit of %v bytes",
+			req.GetVolumeId(),
+			v.GetSpec().GetSize(),
+			limit)
+	}
+	if v.GetSpec().GetSize() >= size {
+		size = v.GetSpec().GetSize()
+	} else {
+		err = s.driver.Set(v.GetId(), nil, &api.VolumeSpec{Size: size})
+		if err == volume.ErrNotSupported {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"Volume %s cannot be expanded: %s",
+				req.GetVolumeId(),
+				err.Error())
+		} else if err != nil {
+			e := fmt.Sprintf("Unable to expand volume %s: %s",
+				req.GetVolumeId(),
+				err.Error())
+			dlog.Errorln(e)
+			return nil, status.Error(codes.Internal, e)
+		}
+	}
+
+	return &csi.ControllerExpandVolumeResponse{
+		CapacityBytes:         int64(size),
+		NodeExpansionRequired: s.driver.Type() == api.DriverType_DRIVER_TYPE_BLOCK,
+	}, nil
+}
+
+// GetCapacity is a CSI API which is not supported yet.
+func (s *OsdCsiServer) GetCapacity(
+	ctx context.Context,
+	req *csi.GetCapacityRequest,
+) (*csi.GetCapacityResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "GetCapacity is not supported")
+}
@@ -21,6 +21,9 @@ import (
 	"testing"
 
 	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/options"
+	"github.com/libopenstorage/openstorage/pkg/proto/time"
+	"github.com/libopenstorage/openstorage/volume"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/golang/mock/gomock"
@@ -36,6 +39,12 @@ func TestControllerGetCapabilities(t *testing.T) {
 	s := newTestServer(t)
 	defer s.Stop()
 
+	s.MockDriver().
+		EXPECT().
+		Type().
+		Return(api.DriverType_DRIVER_TYPE_BLOCK).
+		Times(1)
+
 	// Make a call
 	c := csi.NewControllerClient(s.Conn())
 	r, err := c.ControllerGetCapabilities(context.Background(), &csi.ControllerGetCapabilitiesRequest{})
@@ -45,6 +54,11 @@ func TestControllerGetCapabilities(t *testing.T) {
 	expectedValues := []csi.ControllerServiceCapability_RPC_Type{
 		csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
+		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 	}
 	caps := r.GetCapabilities()
 	assert.Len(t, caps, len(expectedValues))
@@ -60,71 +74,420 @@ func TestControllerGetCapabilities(t *testing.T) {
 	assert.Equal(t, found, len(expectedValues))
 }
 
-func TestControllerPublishVolume(t *testing.T) {
+func TestControllerGetCapabilitiesLocalAttach(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
 	defer s.Stop()
 
-	// Make a call
+	// Drivers which only attach on the local node are not published by
+	// the controller
+	s.server.(*OsdCsiServer).driver = s.MockDriver()
+
 	c := csi.NewControllerClient(s.Conn())
-	_, err := c.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{})
-	assert.NotNil(t, err)
+	r, err := c.ControllerGetCapabilities(context.Background(), &csi.ControllerGetCapabilitiesRequest{})
+	assert.Nil(t, err)
+	for _, cap := range r.GetCapabilities() {
+		assert.NotEqual(t,
+			csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+			cap.GetRpc().GetType())
+	}
 
-	serverError, ok := status.FromError(err)
-	assert.True(t, ok)
+	// Publishing such volumes leaves them to be attached by the node
+	name := "myvol"
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{name}).
+		Return([]*api.Volume{&api.Volume{Id: name}}, nil).
+		Times(1)
+	s.MockCluster().
+		EXPECT().
+		Inspect("node1").
+		Return(api.Node{Id: "node1"}, nil).
+		Times(1)
 
-	assert.Equal(t, serverError.Code(), codes.Unimplemented)
-	assert.Contains(t, serverError.Message(), "not supported")
+	resp, err := c.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{
+		VolumeId: name,
+		NodeId:   "node1",
+		VolumeCapability: &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, resp.GetPublishContext())
 }
 
-func TestControllerUnPublishVolume(t *testing.T) {
+func TestControllerPublishVolumeBadArguments(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
 	defer s.Stop()
 
-	// Make a call
 	c := csi.NewControllerClient(s.Conn())
-	_, err := c.ControllerUnpublishVolume(context.Background(), &csi.ControllerUnpublishVolumeRequest{})
-	assert.NotNil(t, err)
+	capability := &csi.VolumeCapability{
+		AccessMode: &csi.VolumeCapability_AccessMode{},
+	}
 
+	for _, test := range []struct {
+		req     *csi.ControllerPublishVolumeRequest
+		message string
+	}{
+		{
+			req:     &csi.ControllerPublishVolumeRequest{},
+			message: "Volume id",
+		},
+		{
+			req: &csi.ControllerPublishVolumeRequest{
+				VolumeId: "myvol",
+			},
+			message: "Node id",
+		},
+		{
+			req: &csi.ControllerPublishVolumeRequest{
+				VolumeId: "myvol",
+				NodeId:   "node1",
+			},
+			message: "access mode",
+		},
+		{
+			req: &csi.ControllerPublishVolumeRequest{
+				VolumeId:         "myvol",
+				NodeId:           "node1",
+				VolumeCapability: &csi.VolumeCapability{},
+			},
+			message: "access mode",
+		},
+	} {
+		_, err := c.ControllerPublishVolume(context.Background(), test.req)
+		assert.NotNil(t, err)
+		serverError, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, serverError.Code(), codes.InvalidArgument)
+		assert.Contains(t, serverError.Message(), test.message)
+	}
+
+	// Volume not found
+	id := "myvol"
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{id}).
+			Return(nil, fmt.Errorf("not found")).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Enumerate(&api.VolumeLocator{Name: id}, nil).
+			Return(nil, fmt.Errorf("not found")).
+			Times(1),
+	)
+	_, err := c.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{
+		VolumeId:         id,
+		NodeId:           "node1",
+		VolumeCapability: capability,
+	})
+	assert.NotNil(t, err)
 	serverError, ok := status.FromError(err)
 	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.NotFound)
 
-	assert.Equal(t, serverError.Code(), codes.Unimplemented)
-	assert.Contains(t, serverError.Message(), "not supported")
+	// Node not found
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{id}).
+		Return([]*api.Volume{&api.Volume{Id: id}}, nil).
+		Times(1)
+	s.MockCluster().
+		EXPECT().
+		Inspect("node1").
+		Return(api.Node{}, fmt.Errorf("not found")).
+		Times(1)
+	_, err = c.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{
+		VolumeId:         id,
+		NodeId:           "node1",
+		VolumeCapability: capability,
+	})
+	assert.NotNil(t, err)
+	serverError, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.NotFound)
+	assert.Contains(t, serverError.Message(), "Node id")
 }
 
-func TestControllerValidateVolumeCapabilitiesBadArguments(t *testing.T) {
+func TestControllerPublishVolume(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
 	defer s.Stop()
 
-	req := &csi.ValidateVolumeCapabilitiesRequest{}
+	c := csi.NewControllerClient(s.Conn())
+
+	id := "myvol"
+	devicePath := "/dev/myvol"
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{id}).
+			Return([]*api.Volume{
+				&api.Volume{
+					Id:    id,
+					State: api.VolumeState_VOLUME_STATE_DETACHED,
+				},
+			}, nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Attach(id, map[string]string{options.OptionsAttachNode: "node1"}).
+			Return(devicePath, nil).
+			Times(1),
+	)
+
+	s.MockCluster().
+		EXPECT().
+		Inspect(gomock.Any()).
+		Return(api.Node{}, nil).
+		AnyTimes()
+
+	r, err := c.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{
+		VolumeId: id,
+		NodeId:   "node1",
+		VolumeCapability: &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, devicePath, r.GetPublishContext()[publishInfoDevicePath])
+}
+
+func TestControllerPublishVolumeAttached(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
 
-	// Missing everything
 	c := csi.NewControllerClient(s.Conn())
-	_, err := c.ValidateVolumeCapabilities(context.Background(), req)
+
+	id := "myvol"
+	devicePath := "/dev/myvol"
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{id}).
+		Return([]*api.Volume{
+			&api.Volume{
+				Id:         id,
+				State:      api.VolumeState_VOLUME_STATE_ATTACHED,
+				AttachedOn: "node1",
+				DevicePath: devicePath,
+			},
+		}, nil).
+		Times(2)
+	s.MockDriver().
+		EXPECT().
+		Type().
+		Return(api.DriverType_DRIVER_TYPE_BLOCK).
+		Times(2)
+
+	s.MockCluster().
+		EXPECT().
+		Inspect(gomock.Any()).
+		Return(api.Node{}, nil).
+		AnyTimes()
+
+	req := &csi.ControllerPublishVolumeRequest{
+		VolumeId: id,
+		NodeId:   "node1",
+		VolumeCapability: &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	}
+
+	// Publishing on the same node again is not an error
+	r, err := c.ControllerPublishVolume(context.Background(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, devicePath, r.GetPublishContext()[publishInfoDevicePath])
+
+	// Publishing on another node is
+	req.NodeId = "node2"
+	_, err = c.ControllerPublishVolume(context.Background(), req)
 	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.FailedPrecondition)
+	assert.Contains(t, serverError.Message(), "node1")
+}
+
+func TestControllerPublishVolumeAttachedOnRemoteNode(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	c := csi.NewControllerClient(s.Conn())
+
+	id := "myvol"
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{id}).
+			Return([]*api.Volume{&api.Volume{Id: id}}, nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Attach(id, map[string]string{options.OptionsAttachNode: "node1"}).
+			Return("", volume.ErrVolAttachedOnRemoteNode).
+			Times(1),
+	)
 
+	s.MockCluster().
+		EXPECT().
+		Inspect(gomock.Any()).
+		Return(api.Node{}, nil).
+		AnyTimes()
+
+	_, err := c.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{
+		VolumeId: id,
+		NodeId:   "node1",
+		VolumeCapability: &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	})
+	assert.NotNil(t, err)
 	serverError, ok := status.FromError(err)
 	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.FailedPrecondition)
+}
 
+func TestControllerUnpublishVolumeBadArguments(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	c := csi.NewControllerClient(s.Conn())
+
+	_, err := c.ControllerUnpublishVolume(context.Background(), &csi.ControllerUnpublishVolumeRequest{})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
 	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
-	assert.Contains(t, serverError.Message(), "Version")
+	assert.Contains(t, serverError.Message(), "Volume id")
+}
+
+func TestControllerUnpublishVolume(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	c := csi.NewControllerClient(s.Conn())
+
+	id := "myvol"
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{id}).
+			Return([]*api.Volume{
+				&api.Volume{
+					Id:         id,
+					State:      api.VolumeState_VOLUME_STATE_ATTACHED,
+					AttachedOn: "node1",
+				},
+			}, nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Detach(id, map[string]string{options.OptionsAttachNode: "node1"}).
+			Return(nil).
+			Times(1),
+	)
+
+	_, err := c.ControllerUnpublishVolume(context.Background(), &csi.ControllerUnpublishVolumeRequest{
+		VolumeId: id,
+		NodeId:   "node1",
+	})
+	assert.Nil(t, err)
+}
+
+func TestControllerUnpublishVolumeNotPublished(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	c := csi.NewControllerClient(s.Conn())
+
+	// Detached volumes and volumes attached on other nodes are left alone
+	id := "myvol"
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{id}).
+			Return([]*api.Volume{
+				&api.Volume{
+					Id:    id,
+					State: api.VolumeState_VOLUME_STATE_DETACHED,
+				},
+			}, nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{id}).
+			Return([]*api.Volume{
+				&api.Volume{
+					Id:         id,
+					State:      api.VolumeState_VOLUME_STATE_ATTACHED,
+					AttachedOn: "node2",
+				},
+			}, nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+	)
+
+	req := &csi.ControllerUnpublishVolumeRequest{
+		VolumeId: id,
+		NodeId:   "node1",
+	}
+	_, err := c.ControllerUnpublishVolume(context.Background(), req)
+	assert.Nil(t, err)
+	_, err = c.ControllerUnpublishVolume(context.Background(), req)
+	assert.Nil(t, err)
+}
+
+func TestControllerValidateVolumeCapabilitiesBadArguments(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	req := &csi.ValidateVolumeCapabilitiesRequest{}
+
+	c := csi.NewControllerClient(s.Conn())
 
 	// Miss capabilities and id
-	req.Version = &csi.Version{}
-	_, err = c.ValidateVolumeCapabilities(context.Background(), req)
+	_, err := c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.NotNil(t, err)
 
-	serverError, ok = status.FromError(err)
+	serverError, ok := status.FromError(err)
 	assert.True(t, ok)
 
 	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
 	assert.Contains(t, serverError.Message(), "capabilities")
 
 	// Miss id and capabilities len is 0
-	req.Version = &csi.Version{}
 	req.VolumeCapabilities = []*csi.VolumeCapability{}
 	_, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.NotNil(t, err)
@@ -136,7 +499,6 @@ func TestControllerValidateVolumeCapabilitiesBadArguments(t *testing.T) {
 	assert.Contains(t, serverError.Message(), "capabilities")
 
 	// Miss id
-	req.Version = &csi.Version{}
 	req.VolumeCapabilities = []*csi.VolumeCapability{
 		&csi.VolumeCapability{},
 	}
@@ -201,7 +563,6 @@ func TestControllerValidateVolumeInvalidId(t *testing.T) {
 	)
 
 	req := &csi.ValidateVolumeCapabilitiesRequest{
-		Version: &csi.Version{},
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{},
 		},
@@ -269,7 +630,6 @@ func TestControllerValidateVolumeInvalidCapabilities(t *testing.T) {
 
 	// Setup request
 	req := &csi.ValidateVolumeCapabilitiesRequest{
-		Version: &csi.Version{},
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{},
 		},
@@ -288,6 +648,61 @@ func TestControllerValidateVolumeInvalidCapabilities(t *testing.T) {
 	assert.Contains(t, serverError.Message(), "Cannot have both")
 }
 
+func TestControllerValidateVolumeBlockAccess(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	id := "testvolumeid"
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{id}).
+		Return([]*api.Volume{
+			&api.Volume{
+				Id:   id,
+				Spec: &api.VolumeSpec{},
+			},
+		}, nil).
+		Times(2)
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_FILE).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+	)
+
+	req := &csi.ValidateVolumeCapabilitiesRequest{
+		VolumeCapabilities: []*csi.VolumeCapability{
+			&csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Block{
+					Block: &csi.VolumeCapability_BlockVolume{},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+				},
+			},
+		},
+		VolumeId: id,
+	}
+
+	// File drivers have no devices
+	c := csi.NewControllerClient(s.Conn())
+	r, err := c.ValidateVolumeCapabilities(context.Background(), req)
+	assert.Nil(t, err)
+	assert.Nil(t, r.GetConfirmed())
+	assert.Equal(t, volumeCapabilityMessageBlockNotSupported, r.GetMessage())
+
+	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
+	assert.Nil(t, err)
+	assert.NotNil(t, r.GetConfirmed())
+}
+
 func TestControllerValidateVolumeAccessModeSNWR(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
@@ -361,7 +776,6 @@ func TestControllerValidateVolumeAccessModeSNWR(t *testing.T) {
 
 	// Setup request
 	req := &csi.ValidateVolumeCapabilitiesRequest{
-		Version: &csi.Version{},
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{
 				AccessType: &csi.VolumeCapability_Mount{
@@ -379,22 +793,22 @@ func TestControllerValidateVolumeAccessModeSNWR(t *testing.T) {
 	c := csi.NewControllerClient(s.Conn())
 	r, err := c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.True(t, r.Supported)
+	assert.NotNil(t, r.GetConfirmed())
 
 	// Expect RO and non-SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 
 	// Expect non-RO and SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 
 	// Expect RO and SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 }
 
 func TestControllerValidateVolumeAccessModeSNRO(t *testing.T) {
@@ -470,7 +884,6 @@ func TestControllerValidateVolumeAccessModeSNRO(t *testing.T) {
 
 	// Setup request
 	req := &csi.ValidateVolumeCapabilitiesRequest{
-		Version: &csi.Version{},
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{
 				AccessType: &csi.VolumeCapability_Mount{
@@ -488,22 +901,22 @@ func TestControllerValidateVolumeAccessModeSNRO(t *testing.T) {
 	c := csi.NewControllerClient(s.Conn())
 	r, err := c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 
 	// Expect RO and non-SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.True(t, r.Supported)
+	assert.NotNil(t, r.GetConfirmed())
 
 	// Expect non-RO and SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 
 	// Expect RO and SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 }
 
 func TestControllerValidateVolumeAccessModeMNRO(t *testing.T) {
@@ -579,7 +992,6 @@ func TestControllerValidateVolumeAccessModeMNRO(t *testing.T) {
 
 	// Setup request
 	req := &csi.ValidateVolumeCapabilitiesRequest{
-		Version: &csi.Version{},
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{
 				AccessType: &csi.VolumeCapability_Mount{
@@ -597,22 +1009,22 @@ func TestControllerValidateVolumeAccessModeMNRO(t *testing.T) {
 	c := csi.NewControllerClient(s.Conn())
 	r, err := c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 
 	// Expect RO and non-SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 
 	// Expect non-RO and SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 
 	// Expect RO and SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.True(t, r.Supported)
+	assert.NotNil(t, r.GetConfirmed())
 }
 
 func TestControllerValidateVolumeAccessModeMNWR(t *testing.T) {
@@ -688,7 +1100,6 @@ func TestControllerValidateVolumeAccessModeMNWR(t *testing.T) {
 
 	// Setup request
 	req := &csi.ValidateVolumeCapabilitiesRequest{
-		Version: &csi.Version{},
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{
 				AccessType: &csi.VolumeCapability_Mount{
@@ -706,22 +1117,22 @@ func TestControllerValidateVolumeAccessModeMNWR(t *testing.T) {
 	c := csi.NewControllerClient(s.Conn())
 	r, err := c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 
 	// Expect RO and non-SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 
 	// Expect non-RO and SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.True(t, r.Supported)
+	assert.NotNil(t, r.GetConfirmed())
 
 	// Expect RO and SH
 	r, err = c.ValidateVolumeCapabilities(context.Background(), req)
 	assert.Nil(t, err)
-	assert.False(t, r.Supported)
+	assert.Nil(t, r.GetConfirmed())
 }
 
 func TestControllerValidateVolumeAccessModeUnknown(t *testing.T) {
@@ -747,7 +1158,6 @@ func TestControllerValidateVolumeAccessModeUnknown(t *testing.T) {
 
 	// Setup request
 	req := &csi.ValidateVolumeCapabilitiesRequest{
-		Version: &csi.Version{},
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{
 				AccessType: &csi.VolumeCapability_Mount{
@@ -776,24 +1186,20 @@ func TestControllerListVolumesInvalidArguments(t *testing.T) {
 	// Setup request
 	req := &csi.ListVolumesRequest{}
 
-	// Expect error without version
+	// Expect error with an invalid token
+	s.MockDriver().
+		EXPECT().
+		EnumerateWithFilter(&api.VolumeFilter{Limit: 1, Token: "bad"}).
+		Return(nil, volume.ErrInvalidToken).
+		Times(1)
+	req.MaxEntries = 1
+	req.StartingToken = "bad"
 	_, err := c.ListVolumes(context.Background(), req)
 	assert.NotNil(t, err)
 	serverError, ok := status.FromError(err)
 	assert.True(t, ok)
-	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
-	assert.Contains(t, serverError.Message(), "Version")
-
-	// Expect error with maxentries set
-	// To be removed once CSI Spec issue #138 is resolved
-	req.Version = &csi.Version{}
-	req.MaxEntries = 1
-	_, err = c.ListVolumes(context.Background(), req)
-	assert.NotNil(t, err)
-	serverError, ok = status.FromError(err)
-	assert.True(t, ok)
-	assert.Equal(t, serverError.Code(), codes.Unimplemented)
-	assert.Contains(t, serverError.Message(), "token")
+	assert.Equal(t, serverError.Code(), codes.Aborted)
+	assert.Contains(t, serverError.Message(), "starting_token")
 }
 
 func TestControllerListVolumesEnumerateError(t *testing.T) {
@@ -805,14 +1211,12 @@ func TestControllerListVolumesEnumerateError(t *testing.T) {
 	// Setup mock
 	s.MockDriver().
 		EXPECT().
-		Enumerate(gomock.Any(), gomock.Any()).
+		EnumerateWithFilter(gomock.Any()).
 		Return(nil, fmt.Errorf("TEST")).
 		Times(1)
 
 	// Setup request
-	req := &csi.ListVolumesRequest{
-		Version: &csi.Version{},
-	}
+	req := &csi.ListVolumesRequest{}
 
 	// Expect that the Enumerate call failed
 	_, err := c.ListVolumes(context.Background(), req)
@@ -869,16 +1273,13 @@ func TestControllerListVolumes(t *testing.T) {
 	}
 	s.MockDriver().
 		EXPECT().
-		Enumerate(gomock.Any(), gomock.Any()).
-		Return(mockVolumeList, nil).
+		EnumerateWithFilter(&api.VolumeFilter{}).
+		Return(&api.VolumeList{Volumes: mockVolumeList}, nil).
 		Times(1)
 
 	// Setup request
-	req := &csi.ListVolumesRequest{
-		Version: &csi.Version{},
-	}
+	req := &csi.ListVolumesRequest{}
 
-	// Expect error without version
 	r, err := c.ListVolumes(context.Background(), req)
 	assert.Nil(t, err)
 	assert.NotNil(t, r)
@@ -890,14 +1291,14 @@ func TestControllerListVolumes(t *testing.T) {
 	found := 0
 	for _, mv := range mockVolumeList {
 		for _, v := range volumes {
-			info := v.GetVolumeInfo()
+			info := v.GetVolume()
 			assert.NotNil(t, info)
 
-			if mv.GetId() == info.GetId() {
+			if mv.GetId() == info.GetVolumeId() {
 				found++
-				assert.Equal(t, info.GetCapacityBytes(), mv.GetSpec().GetSize())
+				assert.Equal(t, info.GetCapacityBytes(), int64(mv.GetSpec().GetSize()))
 
-				attributes := info.GetAttributes()
+				attributes := info.GetVolumeContext()
 				assert.Equal(t, attributes["readonly"], fmt.Sprintf("%v", mv.GetReadonly()))
 				assert.Equal(t, attributes[api.SpecShared], fmt.Sprintf("%v", mv.GetSpec().GetShared()))
 				assert.Equal(t, attributes["state"], mv.GetState().String())
@@ -912,6 +1313,35 @@ func TestControllerListVolumes(t *testing.T) {
 	assert.Equal(t, found, len(mockVolumeList))
 }
 
+func TestControllerListVolumesPaging(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	// Setup mock
+	s.MockDriver().
+		EXPECT().
+		EnumerateWithFilter(&api.VolumeFilter{Limit: 2, Token: "page2"}).
+		Return(&api.VolumeList{
+			Volumes: []*api.Volume{
+				&api.Volume{Id: "three", Spec: &api.VolumeSpec{}},
+				&api.Volume{Id: "four", Spec: &api.VolumeSpec{}},
+			},
+			NextToken: "page3",
+		}, nil).
+		Times(1)
+
+	r, err := c.ListVolumes(context.Background(), &csi.ListVolumesRequest{
+		MaxEntries:    2,
+		StartingToken: "page2",
+	})
+	assert.Nil(t, err)
+	assert.Len(t, r.GetEntries(), 2)
+	assert.Equal(t, "three", r.GetEntries()[0].GetVolume().GetVolumeId())
+	assert.Equal(t, "page3", r.GetNextToken())
+}
+
 func TestControllerCreateVolumeInvalidArguments(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
@@ -921,21 +1351,12 @@ func TestControllerCreateVolumeInvalidArguments(t *testing.T) {
 	// Setup request
 	req := &csi.CreateVolumeRequest{}
 
-	// No version
+	// No name
 	_, err := c.CreateVolume(context.Background(), req)
 	assert.NotNil(t, err)
 	serverError, ok := status.FromError(err)
 	assert.True(t, ok)
 	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
-	assert.Contains(t, serverError.Message(), "Version")
-
-	// No name
-	req.Version = &csi.Version{}
-	_, err = c.CreateVolume(context.Background(), req)
-	assert.NotNil(t, err)
-	serverError, ok = status.FromError(err)
-	assert.True(t, ok)
-	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
 	assert.Contains(t, serverError.Message(), "Name")
 
 	// No volume capabilities
@@ -957,27 +1378,46 @@ func TestControllerCreateVolumeInvalidArguments(t *testing.T) {
 	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
 	assert.Contains(t, serverError.Message(), "Volume capabilities")
 
-	// No CapacityRange
+	// Negative capacity range
 	req.VolumeCapabilities = []*csi.VolumeCapability{
 		&csi.VolumeCapability{
-		// purposely do not define anything to check if it panics accessing nil
+			// purposely do not define anything to check if it panics accessing nil
 		},
 	}
+	req.CapacityRange = &csi.CapacityRange{RequiredBytes: -1}
 	_, err = c.CreateVolume(context.Background(), req)
 	assert.NotNil(t, err)
 	serverError, ok = status.FromError(err)
 	assert.True(t, ok)
 	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
-	assert.Contains(t, serverError.Message(), "CapacityRange")
+	assert.Contains(t, serverError.Message(), "cannot be negative")
 
-	// RequiredBytes is zero
-	req.CapacityRange = &csi.CapacityRange{}
+	// RequiredBytes exceeds LimitBytes
+	req.CapacityRange = &csi.CapacityRange{RequiredBytes: 2, LimitBytes: 1}
 	_, err = c.CreateVolume(context.Background(), req)
 	assert.NotNil(t, err)
 	serverError, ok = status.FromError(err)
 	assert.True(t, ok)
-	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
-	assert.Contains(t, serverError.Message(), "cannot be zero")
+	assert.Equal(t, serverError.Code(), codes.OutOfRange)
+	assert.Contains(t, serverError.Message(), "exceeds the limit")
+}
+
+func TestCSIRequestedSize(t *testing.T) {
+	for _, test := range []struct {
+		r    *csi.CapacityRange
+		size uint64
+	}{
+		{nil, defaultCSIVolumeSize},
+		{&csi.CapacityRange{}, defaultCSIVolumeSize},
+		{&csi.CapacityRange{RequiredBytes: 1234}, 1234},
+		{&csi.CapacityRange{RequiredBytes: 1234, LimitBytes: 1234}, 1234},
+		{&csi.CapacityRange{LimitBytes: 1234}, 1234},
+		{&csi.CapacityRange{LimitBytes: 2 * defaultCSIVolumeSize}, defaultCSIVolumeSize},
+	} {
+		size, err := csiRequestedSize(test.r)
+		assert.NoError(t, err)
+		assert.Equal(t, test.size, size)
+	}
 }
 
 func TestControllerCreateVolumeFoundByVolumeFromNameConflict(t *testing.T) {
@@ -995,8 +1435,7 @@ func TestControllerCreateVolumeFoundByVolumeFromNameConflict(t *testing.T) {
 		{
 			name: "size",
 			req: &csi.CreateVolumeRequest{
-				Version: &csi.Version{},
-				Name:    "size",
+				Name: "size",
 				VolumeCapabilities: []*csi.VolumeCapability{
 					&csi.VolumeCapability{},
 				},
@@ -1021,8 +1460,7 @@ func TestControllerCreateVolumeFoundByVolumeFromNameConflict(t *testing.T) {
 		{
 			name: "shared",
 			req: &csi.CreateVolumeRequest{
-				Version: &csi.Version{},
-				Name:    "shared",
+				Name: "shared",
 				VolumeCapabilities: []*csi.VolumeCapability{
 					&csi.VolumeCapability{
 						AccessMode: &csi.VolumeCapability_AccessMode{
@@ -1052,8 +1490,7 @@ func TestControllerCreateVolumeFoundByVolumeFromNameConflict(t *testing.T) {
 		{
 			name: "parent",
 			req: &csi.CreateVolumeRequest{
-				Version: &csi.Version{},
-				Name:    "parent",
+				Name: "parent",
 				VolumeCapabilities: []*csi.VolumeCapability{
 					&csi.VolumeCapability{},
 				},
@@ -1111,13 +1548,12 @@ func TestControllerCreateVolumeFoundByVolumeFromName(t *testing.T) {
 	name := "myvol"
 	size := uint64(1234)
 	req := &csi.CreateVolumeRequest{
-		Version: &csi.Version{},
-		Name:    name,
+		Name: name,
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{},
 		},
 		CapacityRange: &csi.CapacityRange{
-			RequiredBytes: size,
+			RequiredBytes: int64(size),
 		},
 	}
 
@@ -1149,10 +1585,10 @@ func TestControllerCreateVolumeFoundByVolumeFromName(t *testing.T) {
 	r, err := c.CreateVolume(context.Background(), req)
 	assert.Nil(t, err)
 	assert.NotNil(t, r)
-	volumeInfo := r.GetVolumeInfo()
+	volumeInfo := r.GetVolume()
 
-	assert.Equal(t, name, volumeInfo.GetId())
-	assert.Equal(t, size, volumeInfo.GetCapacityBytes())
+	assert.Equal(t, name, volumeInfo.GetVolumeId())
+	assert.Equal(t, int64(size), volumeInfo.GetCapacityBytes())
 }
 
 func TestControllerCreateVolumeBadParameters(t *testing.T) {
@@ -1165,13 +1601,12 @@ func TestControllerCreateVolumeBadParameters(t *testing.T) {
 	name := "myvol"
 	size := uint64(1234)
 	req := &csi.CreateVolumeRequest{
-		Version: &csi.Version{},
-		Name:    name,
+		Name: name,
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{},
 		},
 		CapacityRange: &csi.CapacityRange{
-			RequiredBytes: size,
+			RequiredBytes: int64(size),
 		},
 		Parameters: map[string]string{
 			api.SpecFilesystem: "whatkindoffsisthis?",
@@ -1186,6 +1621,40 @@ func TestControllerCreateVolumeBadParameters(t *testing.T) {
 	assert.Contains(t, serverError.Message(), "get parameters")
 }
 
+func TestControllerCreateVolumeBlockOnFileDriver(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	s.MockDriver().
+		EXPECT().
+		Type().
+		Return(api.DriverType_DRIVER_TYPE_FILE).
+		Times(1)
+
+	req := &csi.CreateVolumeRequest{
+		Name: "myvol",
+		VolumeCapabilities: []*csi.VolumeCapability{
+			&csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Block{
+					Block: &csi.VolumeCapability_BlockVolume{},
+				},
+			},
+		},
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 1234,
+		},
+	}
+
+	_, err := c.CreateVolume(context.Background(), req)
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
+	assert.Contains(t, serverError.Message(), volumeCapabilityMessageBlockNotSupported)
+}
+
 func TestControllerCreateVolumeBadParentId(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
@@ -1197,13 +1666,12 @@ func TestControllerCreateVolumeBadParentId(t *testing.T) {
 	size := uint64(1234)
 	parent := "badid"
 	req := &csi.CreateVolumeRequest{
-		Version: &csi.Version{},
-		Name:    name,
+		Name: name,
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{},
 		},
 		CapacityRange: &csi.CapacityRange{
-			RequiredBytes: size,
+			RequiredBytes: int64(size),
 		},
 		Parameters: map[string]string{
 			api.SpecParent: parent,
@@ -1258,13 +1726,12 @@ func TestControllerCreateVolumeBadSnapshot(t *testing.T) {
 	size := uint64(1234)
 	parent := "parent"
 	req := &csi.CreateVolumeRequest{
-		Version: &csi.Version{},
-		Name:    name,
+		Name: name,
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{},
 		},
 		CapacityRange: &csi.CapacityRange{
-			RequiredBytes: size,
+			RequiredBytes: int64(size),
 		},
 		Parameters: map[string]string{
 			api.SpecParent: parent,
@@ -1341,8 +1808,7 @@ func TestControllerCreateVolumeWithSharedVolume(t *testing.T) {
 		name := "myvol"
 		size := uint64(1234)
 		req := &csi.CreateVolumeRequest{
-			Version: &csi.Version{},
-			Name:    name,
+			Name: name,
 			VolumeCapabilities: []*csi.VolumeCapability{
 				&csi.VolumeCapability{
 					AccessMode: &csi.VolumeCapability_AccessMode{
@@ -1351,7 +1817,7 @@ func TestControllerCreateVolumeWithSharedVolume(t *testing.T) {
 				},
 			},
 			CapacityRange: &csi.CapacityRange{
-				RequiredBytes: size,
+				RequiredBytes: int64(size),
 			},
 		}
 
@@ -1397,14 +1863,120 @@ func TestControllerCreateVolumeWithSharedVolume(t *testing.T) {
 		r, err := c.CreateVolume(context.Background(), req)
 		assert.Nil(t, err)
 		assert.NotNil(t, r)
-		volumeInfo := r.GetVolumeInfo()
+		volumeInfo := r.GetVolume()
 
-		assert.Equal(t, id, volumeInfo.GetId())
-		assert.Equal(t, size, volumeInfo.GetCapacityBytes())
-		assert.Equal(t, "true", volumeInfo.GetAttributes()[api.SpecShared])
+		assert.Equal(t, id, volumeInfo.GetVolumeId())
+		assert.Equal(t, int64(size), volumeInfo.GetCapacityBytes())
+		assert.Equal(t, "true", volumeInfo.GetVolumeContext()[api.SpecShared])
 	}
 }
 
+func TestControllerCreateVolumeWithTopology(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	// Setup request
+	name := "myvol"
+	size := uint64(1234)
+	requisite := []*csi.Topology{
+		&csi.Topology{
+			Segments: map[string]string{
+				topologyKeyNode:            "node1",
+				topologyKeyPrefix + "zone": "zone2",
+			},
+		},
+		&csi.Topology{
+			Segments: map[string]string{
+				topologyKeyNode: "node3",
+			},
+		},
+	}
+	req := &csi.CreateVolumeRequest{
+		Name: name,
+		VolumeCapabilities: []*csi.VolumeCapability{
+			&csi.VolumeCapability{
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+				},
+			},
+		},
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: int64(size),
+		},
+		Parameters: map[string]string{
+			api.SpecHaLevel: "2",
+			api.SpecRacks:   "rack1",
+		},
+		AccessibilityRequirements: &csi.TopologyRequirement{
+			Preferred: []*csi.Topology{
+				&csi.Topology{
+					Segments: map[string]string{
+						topologyKeyNode:            "node2",
+						topologyKeyPrefix + "zone": "zone1",
+						topologyKeyPrefix + "rack": "rack2",
+					},
+				},
+			},
+			Requisite: requisite,
+		},
+	}
+
+	// Setup mock functions
+	id := "myid"
+	var locator *api.VolumeLocator
+	var spec *api.VolumeSpec
+	placed := &api.Volume{Id: id}
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{name}).
+			Return(nil, fmt.Errorf("not found")).
+			Times(1),
+
+		s.MockDriver().
+			EXPECT().
+			Enumerate(&api.VolumeLocator{Name: name}, nil).
+			Return(nil, fmt.Errorf("not found")).
+			Times(1),
+
+		s.MockDriver().
+			EXPECT().
+			Create(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(l *api.VolumeLocator, _ *api.Source, sp *api.VolumeSpec) {
+				locator = l
+				spec = sp
+				placed.Locator = l
+				placed.Spec = sp
+			}).
+			Return(id, nil).
+			Times(1),
+
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{id}).
+			Return([]*api.Volume{placed}, nil).
+			Times(1),
+	)
+
+	r, err := c.CreateVolume(context.Background(), req)
+	assert.Nil(t, err)
+	assert.NotNil(t, r)
+
+	// The preferred segments come first and the parameters take precedence
+	assert.Equal(t, "zone1,zone2", locator.GetVolumeLabels()[api.SpecZones])
+	assert.Equal(t, "rack1", locator.GetVolumeLabels()[api.SpecRacks])
+	assert.NotContains(t, locator.GetVolumeLabels(), api.SpecRegions)
+	assert.Equal(t, []string{"node2", "node1"}, spec.GetReplicaSet().GetNodes())
+
+	// The volume is accessible from the nodes it was placed on
+	topology := r.GetVolume().GetAccessibleTopology()
+	assert.Len(t, topology, 2)
+	assert.Equal(t, map[string]string{topologyKeyNode: "node2"}, topology[0].GetSegments())
+	assert.Equal(t, map[string]string{topologyKeyNode: "node1"}, topology[1].GetSegments())
+}
+
 func TestControllerCreateVolumeFails(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
@@ -1415,13 +1987,12 @@ func TestControllerCreateVolumeFails(t *testing.T) {
 	name := "myvol"
 	size := uint64(1234)
 	req := &csi.CreateVolumeRequest{
-		Version: &csi.Version{},
-		Name:    name,
+		Name: name,
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{},
 		},
 		CapacityRange: &csi.CapacityRange{
-			RequiredBytes: size,
+			RequiredBytes: int64(size),
 		},
 	}
 
@@ -1464,13 +2035,73 @@ func TestControllerCreateVolumeNoNewVolumeInfo(t *testing.T) {
 	name := "myvol"
 	size := uint64(1234)
 	req := &csi.CreateVolumeRequest{
-		Version: &csi.Version{},
-		Name:    name,
+		Name: name,
+		VolumeCapabilities: []*csi.VolumeCapability{
+			&csi.VolumeCapability{},
+		},
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: int64(size),
+		},
+	}
+
+	// Setup mock functions
+	id := "myid"
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{name}).
+			Return(nil, fmt.Errorf("not found")).
+			Times(1),
+
+		s.MockDriver().
+			EXPECT().
+			Enumerate(&api.VolumeLocator{Name: name}, nil).
+			Return(nil, fmt.Errorf("not found")).
+			Times(1),
+
+		s.MockDriver().
+			EXPECT().
+			Create(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(id, nil).
+			Times(1),
+
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{id}).
+			Return(nil, fmt.Errorf("not found")).
+			Times(1),
+
+		s.MockDriver().
+			EXPECT().
+			Enumerate(&api.VolumeLocator{Name: id}, nil).
+			Return(nil, fmt.Errorf("not found")).
+			Times(1),
+	)
+
+	_, err := c.CreateVolume(context.Background(), req)
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.Internal)
+	assert.Contains(t, serverError.Message(), "not found")
+}
+
+func TestControllerCreateVolume(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	// Setup request
+	name := "myvol"
+	size := uint64(1234)
+	req := &csi.CreateVolumeRequest{
+		Name: name,
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{},
 		},
 		CapacityRange: &csi.CapacityRange{
-			RequiredBytes: size,
+			RequiredBytes: int64(size),
 		},
 	}
 
@@ -1498,25 +2129,31 @@ func TestControllerCreateVolumeNoNewVolumeInfo(t *testing.T) {
 		s.MockDriver().
 			EXPECT().
 			Inspect([]string{id}).
-			Return(nil, fmt.Errorf("not found")).
-			Times(1),
-
-		s.MockDriver().
-			EXPECT().
-			Enumerate(&api.VolumeLocator{Name: id}, nil).
-			Return(nil, fmt.Errorf("not found")).
+			Return([]*api.Volume{
+				&api.Volume{
+					Id: id,
+					Locator: &api.VolumeLocator{
+						Name: name,
+					},
+					Spec: &api.VolumeSpec{
+						Size: size,
+					},
+				},
+			}, nil).
 			Times(1),
 	)
 
-	_, err := c.CreateVolume(context.Background(), req)
-	assert.NotNil(t, err)
-	serverError, ok := status.FromError(err)
-	assert.True(t, ok)
-	assert.Equal(t, serverError.Code(), codes.Internal)
-	assert.Contains(t, serverError.Message(), "not found")
+	r, err := c.CreateVolume(context.Background(), req)
+	assert.Nil(t, err)
+	assert.NotNil(t, r)
+	volumeInfo := r.GetVolume()
+
+	assert.Equal(t, id, volumeInfo.GetVolumeId())
+	assert.Equal(t, int64(size), volumeInfo.GetCapacityBytes())
+	assert.NotEqual(t, "true", volumeInfo.GetVolumeContext()[api.SpecShared])
 }
 
-func TestControllerCreateVolume(t *testing.T) {
+func TestControllerCreateVolumePlacement(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
 	defer s.Stop()
@@ -1526,15 +2163,24 @@ func TestControllerCreateVolume(t *testing.T) {
 	name := "myvol"
 	size := uint64(1234)
 	req := &csi.CreateVolumeRequest{
-		Version: &csi.Version{},
-		Name:    name,
+		Name: name,
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{},
 		},
 		CapacityRange: &csi.CapacityRange{
-			RequiredBytes: size,
+			RequiredBytes: int64(size),
+		},
+		Parameters: map[string]string{
+			api.SpecZones:   "east",
+			api.SpecRacks:   "r1",
+			api.SpecRegions: "us",
 		},
 	}
+	placement := map[string]string{
+		api.SpecZones:   "east",
+		api.SpecRacks:   "r1",
+		api.SpecRegions: "us",
+	}
 
 	// Setup mock functions
 	id := "myid"
@@ -1553,7 +2199,10 @@ func TestControllerCreateVolume(t *testing.T) {
 
 		s.MockDriver().
 			EXPECT().
-			Create(gomock.Any(), gomock.Any(), gomock.Any()).
+			Create(&api.VolumeLocator{
+				Name:         name,
+				VolumeLabels: placement,
+			}, gomock.Any(), gomock.Any()).
 			Return(id, nil).
 			Times(1),
 
@@ -1564,7 +2213,8 @@ func TestControllerCreateVolume(t *testing.T) {
 				&api.Volume{
 					Id: id,
 					Locator: &api.VolumeLocator{
-						Name: name,
+						Name:         name,
+						VolumeLabels: placement,
 					},
 					Spec: &api.VolumeSpec{
 						Size: size,
@@ -1577,11 +2227,53 @@ func TestControllerCreateVolume(t *testing.T) {
 	r, err := c.CreateVolume(context.Background(), req)
 	assert.Nil(t, err)
 	assert.NotNil(t, r)
-	volumeInfo := r.GetVolumeInfo()
+	attributes := r.GetVolume().GetVolumeContext()
+	for k, v := range placement {
+		assert.Equal(t, v, attributes[k])
+	}
+	topology := r.GetVolume().GetAccessibleTopology()
+	assert.Len(t, topology, 1)
+	assert.Equal(t, map[string]string{
+		topologyKeyPrefix + "zone":   "east",
+		topologyKeyPrefix + "rack":   "r1",
+		topologyKeyPrefix + "region": "us",
+	}, topology[0].GetSegments())
+}
+
+func TestCSIVolumeTopology(t *testing.T) {
+	// Volumes which were not placed have no topology
+	assert.Nil(t, csiVolumeTopology(&api.Volume{Spec: &api.VolumeSpec{}}))
 
-	assert.Equal(t, id, volumeInfo.GetId())
-	assert.Equal(t, size, volumeInfo.GetCapacityBytes())
-	assert.NotEqual(t, "true", volumeInfo.GetAttributes()[api.SpecShared])
+	// A volume placed in several zones is in each of them
+	topology := csiVolumeTopology(&api.Volume{
+		Locator: &api.VolumeLocator{
+			VolumeLabels: map[string]string{
+				api.SpecZones:   "a,b",
+				api.SpecRegions: "us",
+			},
+		},
+	})
+	assert.Len(t, topology, 2)
+	assert.Equal(t, map[string]string{
+		topologyKeyPrefix + "zone":   "a",
+		topologyKeyPrefix + "region": "us",
+	}, topology[0].GetSegments())
+	assert.Equal(t, map[string]string{
+		topologyKeyPrefix + "zone":   "b",
+		topologyKeyPrefix + "region": "us",
+	}, topology[1].GetSegments())
+
+	// The nodes of the replica set are more precise than the placement
+	topology = csiVolumeTopology(&api.Volume{
+		Locator: &api.VolumeLocator{
+			VolumeLabels: map[string]string{api.SpecZones: "a"},
+		},
+		Spec: &api.VolumeSpec{
+			ReplicaSet: &api.ReplicaSet{Nodes: []string{"node1"}},
+		},
+	})
+	assert.Len(t, topology, 1)
+	assert.Equal(t, map[string]string{topologyKeyNode: "node1"}, topology[0].GetSegments())
 }
 
 func TestControllerCreateVolumeSnapshot(t *testing.T) {
@@ -1595,13 +2287,12 @@ func TestControllerCreateVolumeSnapshot(t *testing.T) {
 	name := "myvol"
 	size := uint64(1234)
 	req := &csi.CreateVolumeRequest{
-		Version: &csi.Version{},
-		Name:    name,
+		Name: name,
 		VolumeCapabilities: []*csi.VolumeCapability{
 			&csi.VolumeCapability{},
 		},
 		CapacityRange: &csi.CapacityRange{
-			RequiredBytes: size,
+			RequiredBytes: int64(size),
 		},
 		Parameters: map[string]string{
 			api.SpecParent: mockParentID,
@@ -1664,12 +2355,12 @@ func TestControllerCreateVolumeSnapshot(t *testing.T) {
 	r, err := c.CreateVolume(context.Background(), req)
 	assert.Nil(t, err)
 	assert.NotNil(t, r)
-	volumeInfo := r.GetVolumeInfo()
+	volumeInfo := r.GetVolume()
 
-	assert.Equal(t, id, volumeInfo.GetId())
-	assert.Equal(t, size, volumeInfo.GetCapacityBytes())
-	assert.NotEqual(t, "true", volumeInfo.GetAttributes()[api.SpecShared])
-	assert.Equal(t, mockParentID, volumeInfo.GetAttributes()[api.SpecParent])
+	assert.Equal(t, id, volumeInfo.GetVolumeId())
+	assert.Equal(t, int64(size), volumeInfo.GetCapacityBytes())
+	assert.NotEqual(t, "true", volumeInfo.GetVolumeContext()[api.SpecShared])
+	assert.Equal(t, mockParentID, volumeInfo.GetVolumeContext()[api.SpecParent])
 }
 
 func TestControllerDeleteVolumeInvalidArguments(t *testing.T) {
@@ -1678,22 +2369,13 @@ func TestControllerDeleteVolumeInvalidArguments(t *testing.T) {
 	defer s.Stop()
 	c := csi.NewControllerClient(s.Conn())
 
-	// No version
+	// No id
 	req := &csi.DeleteVolumeRequest{}
 	_, err := c.DeleteVolume(context.Background(), req)
 	assert.NotNil(t, err)
 	serverError, ok := status.FromError(err)
 	assert.True(t, ok)
 	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
-	assert.Contains(t, serverError.Message(), "Version")
-
-	// No id
-	req.Version = &csi.Version{}
-	_, err = c.DeleteVolume(context.Background(), req)
-	assert.NotNil(t, err)
-	serverError, ok = status.FromError(err)
-	assert.True(t, ok)
-	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
 	assert.Contains(t, serverError.Message(), "Volume id")
 }
 
@@ -1703,14 +2385,17 @@ func TestControllerDeleteVolumeError(t *testing.T) {
 	defer s.Stop()
 	c := csi.NewControllerClient(s.Conn())
 
-	// No version
 	myid := "myid"
 	req := &csi.DeleteVolumeRequest{
-		Version:  &csi.Version{},
 		VolumeId: myid,
 	}
 
 	// Setup mock
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{myid}).
+		Return([]*api.Volume{&api.Volume{Id: myid}}, nil).
+		Times(1)
 	s.MockDriver().EXPECT().Delete(myid).Return(fmt.Errorf("MOCKERRORTEST")).Times(1)
 
 	_, err := c.DeleteVolume(context.Background(), req)
@@ -1722,22 +2407,438 @@ func TestControllerDeleteVolumeError(t *testing.T) {
 	assert.Contains(t, serverError.Message(), "MOCKERRORTEST")
 }
 
+func TestControllerDeleteVolumeHasSnaps(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	myid := "myid"
+	req := &csi.DeleteVolumeRequest{
+		VolumeId: myid,
+	}
+
+	// Setup mock
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{myid}).
+		Return([]*api.Volume{&api.Volume{Id: myid}}, nil).
+		Times(1)
+	s.MockDriver().EXPECT().Delete(myid).Return(volume.ErrVolHasSnaps).Times(1)
+
+	_, err := c.DeleteVolume(context.Background(), req)
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.FailedPrecondition, serverError.Code())
+}
+
 func TestControllerDeleteVolume(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
 	defer s.Stop()
 	c := csi.NewControllerClient(s.Conn())
 
-	// No version
 	myid := "myid"
 	req := &csi.DeleteVolumeRequest{
-		Version:  &csi.Version{},
 		VolumeId: myid,
 	}
 
 	// Setup mock
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{myid}).
+		Return([]*api.Volume{&api.Volume{Id: myid}}, nil).
+		Times(1)
 	s.MockDriver().EXPECT().Delete(myid).Return(nil).Times(1)
 
 	_, err := c.DeleteVolume(context.Background(), req)
 	assert.Nil(t, err)
 }
+
+func TestControllerDeleteVolumeNotFound(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	myid := "myid"
+	req := &csi.DeleteVolumeRequest{
+		VolumeId: myid,
+	}
+
+	// Deleting a volume that is gone succeeds
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{myid}).
+		Return([]*api.Volume{}, nil).
+		Times(1)
+
+	_, err := c.DeleteVolume(context.Background(), req)
+	assert.Nil(t, err)
+}
+
+func TestControllerCreateVolumeFromSnapshot(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	// A volume created from a snapshot is a writeable clone of it
+	snapID := "snapid"
+	name := "myvol"
+	id := "myid"
+	size := uint64(1234)
+	contentSource := &csi.VolumeContentSource{
+		Type: &csi.VolumeContentSource_Snapshot{
+			Snapshot: &csi.VolumeContentSource_SnapshotSource{
+				SnapshotId: snapID,
+			},
+		},
+	}
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return(nil, fmt.Errorf("not found")),
+		s.MockDriver().EXPECT().Enumerate(&api.VolumeLocator{Name: name}, nil).Return(nil, fmt.Errorf("not found")),
+		s.MockDriver().EXPECT().Inspect([]string{snapID}).Return([]*api.Volume{
+			&api.Volume{
+				Id:       snapID,
+				Readonly: true,
+				Source:   &api.Source{Parent: "parentid"},
+				Spec:     &api.VolumeSpec{Size: size},
+			},
+		}, nil),
+		s.MockDriver().EXPECT().Snapshot(snapID, false, &api.VolumeLocator{Name: name}).Return(id, nil),
+		s.MockDriver().EXPECT().Inspect([]string{id}).Return([]*api.Volume{
+			&api.Volume{
+				Id:      id,
+				Locator: &api.VolumeLocator{Name: name},
+				Spec:    &api.VolumeSpec{Size: size},
+				Source:  &api.Source{Parent: snapID},
+			},
+		}, nil),
+	)
+
+	r, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name: name,
+		VolumeCapabilities: []*csi.VolumeCapability{
+			&csi.VolumeCapability{},
+		},
+		VolumeContentSource: contentSource,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, id, r.GetVolume().GetVolumeId())
+	assert.Equal(t, int64(size), r.GetVolume().GetCapacityBytes())
+	assert.Equal(t, snapID, r.GetVolume().GetContentSource().GetSnapshot().GetSnapshotId())
+}
+
+func TestControllerCreateVolumeFromSnapshotNotFound(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	// Volumes which are not snapshots are not snapshot sources
+	name := "myvol"
+	snapID := "snapid"
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return(nil, fmt.Errorf("not found")),
+		s.MockDriver().EXPECT().Enumerate(&api.VolumeLocator{Name: name}, nil).Return(nil, fmt.Errorf("not found")),
+		s.MockDriver().EXPECT().Inspect([]string{snapID}).Return([]*api.Volume{
+			&api.Volume{Id: snapID},
+		}, nil),
+	)
+
+	_, err := c.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name: name,
+		VolumeCapabilities: []*csi.VolumeCapability{
+			&csi.VolumeCapability{},
+		},
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Snapshot{
+				Snapshot: &csi.VolumeContentSource_SnapshotSource{
+					SnapshotId: snapID,
+				},
+			},
+		},
+	})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, serverError.Code())
+}
+
+func TestControllerCreateSnapshotBadArguments(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	for _, test := range []struct {
+		req     *csi.CreateSnapshotRequest
+		message string
+	}{
+		{
+			req:     &csi.CreateSnapshotRequest{SourceVolumeId: "myvol"},
+			message: "Name",
+		},
+		{
+			req:     &csi.CreateSnapshotRequest{Name: "mysnap"},
+			message: "Source volume id",
+		},
+	} {
+		_, err := c.CreateSnapshot(context.Background(), test.req)
+		assert.NotNil(t, err)
+		serverError, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, serverError.Code())
+		assert.Contains(t, serverError.Message(), test.message)
+	}
+}
+
+func TestControllerCreateSnapshot(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	name := "mysnap"
+	volID := "myvol"
+	id := "snapid"
+	snap := &api.Volume{
+		Id:       id,
+		Readonly: true,
+		Locator:  &api.VolumeLocator{Name: name},
+		Source:   &api.Source{Parent: volID},
+		Spec:     &api.VolumeSpec{Size: 1234},
+		Ctime:    prototime.Now(),
+		State:    api.VolumeState_VOLUME_STATE_AVAILABLE,
+	}
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return(nil, fmt.Errorf("not found")),
+		s.MockDriver().EXPECT().Enumerate(&api.VolumeLocator{Name: name}, nil).Return(nil, fmt.Errorf("not found")),
+		s.MockDriver().EXPECT().Inspect([]string{volID}).Return([]*api.Volume{&api.Volume{Id: volID}}, nil),
+		s.MockDriver().EXPECT().Snapshot(volID, true, &api.VolumeLocator{Name: name}).Return(id, nil),
+		s.MockDriver().EXPECT().Inspect([]string{id}).Return([]*api.Volume{snap}, nil),
+
+		// Taking the snapshot again returns the existing one
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return(nil, fmt.Errorf("not found")),
+		s.MockDriver().EXPECT().Enumerate(&api.VolumeLocator{Name: name}, nil).Return([]*api.Volume{snap}, nil),
+
+		// Unless it is of another volume
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return(nil, fmt.Errorf("not found")),
+		s.MockDriver().EXPECT().Enumerate(&api.VolumeLocator{Name: name}, nil).Return([]*api.Volume{snap}, nil),
+	)
+
+	for i := 0; i < 2; i++ {
+		r, err := c.CreateSnapshot(context.Background(), &csi.CreateSnapshotRequest{
+			Name:           name,
+			SourceVolumeId: volID,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, id, r.GetSnapshot().GetSnapshotId())
+		assert.Equal(t, volID, r.GetSnapshot().GetSourceVolumeId())
+		assert.Equal(t, int64(1234), r.GetSnapshot().GetSizeBytes())
+		assert.Equal(t, snap.GetCtime().GetSeconds(), r.GetSnapshot().GetCreationTime().GetSeconds())
+		assert.True(t, r.GetSnapshot().GetReadyToUse())
+	}
+
+	_, err := c.CreateSnapshot(context.Background(), &csi.CreateSnapshotRequest{
+		Name:           name,
+		SourceVolumeId: "othervol",
+	})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.AlreadyExists, serverError.Code())
+}
+
+func TestControllerCreateSnapshotVolumeNotFound(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	name := "mysnap"
+	volID := "myvol"
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return(nil, fmt.Errorf("not found")),
+		s.MockDriver().EXPECT().Enumerate(&api.VolumeLocator{Name: name}, nil).Return(nil, fmt.Errorf("not found")),
+		s.MockDriver().EXPECT().Inspect([]string{volID}).Return([]*api.Volume{}, nil),
+	)
+
+	_, err := c.CreateSnapshot(context.Background(), &csi.CreateSnapshotRequest{
+		Name:           name,
+		SourceVolumeId: volID,
+	})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, serverError.Code())
+}
+
+func TestControllerDeleteSnapshot(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	id := "snapid"
+	snap := &api.Volume{
+		Id:       id,
+		Readonly: true,
+		Source:   &api.Source{Parent: "myvol"},
+	}
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{id}).Return([]*api.Volume{snap}, nil),
+		s.MockDriver().EXPECT().SnapDelete(id, false).Return(nil),
+
+		// Snapshots with volumes created from them are in use
+		s.MockDriver().EXPECT().Inspect([]string{id}).Return([]*api.Volume{snap}, nil),
+		s.MockDriver().EXPECT().SnapDelete(id, false).Return(volume.ErrVolHasSnaps),
+
+		// Deleting a snapshot that does not exist succeeds
+		s.MockDriver().EXPECT().Inspect([]string{id}).Return([]*api.Volume{}, nil),
+	)
+
+	req := &csi.DeleteSnapshotRequest{SnapshotId: id}
+	_, err := c.DeleteSnapshot(context.Background(), req)
+	assert.Nil(t, err)
+
+	_, err = c.DeleteSnapshot(context.Background(), req)
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.FailedPrecondition, serverError.Code())
+
+	_, err = c.DeleteSnapshot(context.Background(), req)
+	assert.Nil(t, err)
+}
+
+func TestControllerListSnapshots(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	// Only read only snapshots are listed, in pages ordered by id
+	snapshots := []*api.Volume{
+		&api.Volume{Id: "c", Readonly: true, Source: &api.Source{Parent: "vol1"}},
+		&api.Volume{Id: "a", Readonly: true, Source: &api.Source{Parent: "vol1"}},
+		&api.Volume{Id: "clone", Source: &api.Source{Parent: "vol1"}},
+		&api.Volume{Id: "b", Readonly: true, Source: &api.Source{Parent: "vol2"}},
+	}
+	s.MockDriver().
+		EXPECT().
+		SnapEnumerate(nil, nil).
+		Return(snapshots, nil).
+		Times(2)
+	s.MockDriver().
+		EXPECT().
+		SnapEnumerate([]string{"vol1"}, nil).
+		Return([]*api.Volume{snapshots[0], snapshots[1], snapshots[2]}, nil).
+		Times(1)
+
+	r, err := c.ListSnapshots(context.Background(), &csi.ListSnapshotsRequest{
+		MaxEntries: 2,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, r.GetEntries(), 2)
+	assert.Equal(t, "a", r.GetEntries()[0].GetSnapshot().GetSnapshotId())
+	assert.Equal(t, "b", r.GetEntries()[1].GetSnapshot().GetSnapshotId())
+	assert.Equal(t, "b", r.GetNextToken())
+
+	r, err = c.ListSnapshots(context.Background(), &csi.ListSnapshotsRequest{
+		StartingToken: r.GetNextToken(),
+	})
+	assert.Nil(t, err)
+	assert.Len(t, r.GetEntries(), 1)
+	assert.Equal(t, "c", r.GetEntries()[0].GetSnapshot().GetSnapshotId())
+	assert.Empty(t, r.GetNextToken())
+
+	r, err = c.ListSnapshots(context.Background(), &csi.ListSnapshotsRequest{
+		SourceVolumeId: "vol1",
+	})
+	assert.Nil(t, err)
+	assert.Len(t, r.GetEntries(), 2)
+	for _, e := range r.GetEntries() {
+		assert.Equal(t, "vol1", e.GetSnapshot().GetSourceVolumeId())
+	}
+}
+
+func TestControllerExpandVolumeBadArguments(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	for _, test := range []struct {
+		req     *csi.ControllerExpandVolumeRequest
+		message string
+	}{
+		{
+			req: &csi.ControllerExpandVolumeRequest{
+				CapacityRange: &csi.CapacityRange{RequiredBytes: 1},
+			},
+			message: "Volume id",
+		},
+		{
+			req:     &csi.ControllerExpandVolumeRequest{VolumeId: "myvol"},
+			message: "Capacity range",
+		},
+	} {
+		_, err := c.ControllerExpandVolume(context.Background(), test.req)
+		assert.NotNil(t, err)
+		serverError, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, serverError.Code())
+		assert.Contains(t, serverError.Message(), test.message)
+	}
+}
+
+func TestControllerExpandVolume(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+	c := csi.NewControllerClient(s.Conn())
+
+	id := "myvol"
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{id}).Return([]*api.Volume{
+			&api.Volume{Id: id, Spec: &api.VolumeSpec{Size: 1024}},
+		}, nil),
+		s.MockDriver().EXPECT().Set(id, nil, &api.VolumeSpec{Size: 2048}).Return(nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_BLOCK),
+
+		// Volumes are not shrunk
+		s.MockDriver().EXPECT().Inspect([]string{id}).Return([]*api.Volume{
+			&api.Volume{Id: id, Spec: &api.VolumeSpec{Size: 4096}},
+		}, nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_FILE),
+
+		// Drivers which cannot expand volumes
+		s.MockDriver().EXPECT().Inspect([]string{id}).Return([]*api.Volume{
+			&api.Volume{Id: id, Spec: &api.VolumeSpec{Size: 1024}},
+		}, nil),
+		s.MockDriver().EXPECT().Set(id, nil, &api.VolumeSpec{Size: 2048}).Return(volume.ErrNotSupported),
+	)
+
+	req := &csi.ControllerExpandVolumeRequest{
+		VolumeId:      id,
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 2048},
+	}
+	r, err := c.ControllerExpandVolume(context.Background(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2048), r.GetCapacityBytes())
+	assert.True(t, r.GetNodeExpansionRequired())
+
+	r, err = c.ControllerExpandVolume(context.Background(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4096), r.GetCapacityBytes())
+	assert.False(t, r.GetNodeExpansionRequired())
+
+	_, err = c.ControllerExpandVolume(context.Background(), req)
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.FailedPrecondition, serverError.Code())
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package csi
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"sync"
@@ -24,10 +25,13 @@ import (
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"go.pedge.io/dlog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/libopenstorage/openstorage/api/spec"
 	"github.com/libopenstorage/openstorage/cluster"
+	"github.com/libopenstorage/openstorage/pkg/keylock"
+	"github.com/libopenstorage/openstorage/pkg/mount"
 	"github.com/libopenstorage/openstorage/volume"
 	volumedrivers "github.com/libopenstorage/openstorage/volume/drivers"
 )
@@ -39,6 +43,11 @@ type OsdCsiServerConfig struct {
 	Address    string
 	DriverName string
 	Cluster    cluster.Cluster
+	// TLS, if set, makes the server accept only TLS connections.
+	TLS *tls.Config
+	// StagingPath is where volumes are mounted once per node. It defaults
+	// to DefaultStagingPath.
+	StagingPath string
 }
 
 // OsdCsiServer is a OSD CSI compliant server which
@@ -49,10 +58,14 @@ type OsdCsiServer struct {
 	server      *grpc.Server
 	driver      volume.VolumeDriver
 	cluster     cluster.Cluster
+	tlsConfig   *tls.Config
 	wg          sync.WaitGroup
 	running     bool
 	lock        sync.Mutex
 	specHandler spec.SpecHandler
+	stagingPath string
+	mounter     mount.Manager
+	volumeLocks keylock.KeyLock
 }
 
 // NewOsdCsiServer creates a gRPC CSI complient server on the
@@ -77,6 +90,15 @@ func NewOsdCsiServer(config *OsdCsiServerConfig) (Server, error) {
 		return nil, fmt.Errorf("Unable to get driver %s info: %s", config.DriverName, err.Error())
 	}
 
+	stagingPath := config.StagingPath
+	if len(stagingPath) == 0 {
+		stagingPath = DefaultStagingPath
+	}
+	mounter, err := newBindMounter(stagingPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load bind mounts of %s: %s", stagingPath, err.Error())
+	}
+
 	l, err := net.Listen(config.Net, config.Address)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to setup server: %s", err.Error())
@@ -86,7 +108,11 @@ func NewOsdCsiServer(config *OsdCsiServerConfig) (Server, error) {
 		listener:    l,
 		driver:      d,
 		cluster:     config.Cluster,
+		tlsConfig:   config.TLS,
 		specHandler: spec.NewSpecHandler(),
+		stagingPath: stagingPath,
+		mounter:     mounter,
+		volumeLocks: keylock.New(),
 	}, nil
 }
 
@@ -100,7 +126,11 @@ func (s *OsdCsiServer) Start() error {
 		return fmt.Errorf("Server already running")
 	}
 
-	s.server = grpc.NewServer()
+	var opts []grpc.ServerOption
+	if s.tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	s.server = grpc.NewServer(opts...)
 
 	csi.RegisterIdentityServer(s.server, s)
 	csi.RegisterControllerServer(s.server, s)
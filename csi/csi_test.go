@@ -17,10 +17,24 @@ limitations under the License.
 package csi
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/golang/mock/gomock"
@@ -28,6 +42,7 @@ import (
 	"golang.org/x/net/context"
 
 	mockcluster "github.com/libopenstorage/openstorage/cluster/mock"
+	"github.com/libopenstorage/openstorage/pkg/chattr"
 	"github.com/libopenstorage/openstorage/volume"
 	volumedrivers "github.com/libopenstorage/openstorage/volume/drivers"
 	mockdriver "github.com/libopenstorage/openstorage/volume/drivers/mock"
@@ -45,11 +60,52 @@ type testServer struct {
 	m      *mockdriver.MockVolumeDriver
 	c      *mockcluster.MockCluster
 	mc     *gomock.Controller
+	dir    string
+	mounts *fakeMountImpl
+}
+
+// fakeMountImpl records the bind mounts made by the server instead of
+// mounting.
+type fakeMountImpl struct {
+	lock   sync.Mutex
+	mounts map[string]uintptr
+	err    error
+}
+
+func (f *fakeMountImpl) Mount(
+	source string,
+	target string,
+	fstype string,
+	flags uintptr,
+	data string,
+	timeout int,
+) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.mounts[target] = flags
+	return nil
+}
+
+func (f *fakeMountImpl) Unmount(target string, flags int, timeout int) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.mounts, target)
+	return nil
+}
+
+func (f *fakeMountImpl) flags(target string) (uintptr, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	flags, ok := f.mounts[target]
+	return flags, ok
 }
 
 func setupMockDriver(tester *testServer, t *testing.T) {
 	volumedrivers.Add(mockDriverName, func(map[string]string) (volume.VolumeDriver, error) {
-		return tester.m, nil
+		return &remoteAttachDriver{tester.m}, nil
 	})
 
 	var err error
@@ -59,6 +115,15 @@ func setupMockDriver(tester *testServer, t *testing.T) {
 	assert.Nil(t, err)
 }
 
+// remoteAttachDriver is a driver which attaches volumes on other nodes
+type remoteAttachDriver struct {
+	volume.VolumeDriver
+}
+
+func (d *remoteAttachDriver) AttachesRemotely() bool {
+	return true
+}
+
 func newTestServer(t *testing.T) *testServer {
 	tester := &testServer{}
 
@@ -70,14 +135,25 @@ func newTestServer(t *testing.T) *testServer {
 	setupMockDriver(tester, t)
 
 	var err error
+	tester.dir, err = ioutil.TempDir("", "osd-csi")
+	assert.Nil(t, err)
+
 	// Setup simple driver
 	tester.server, err = NewOsdCsiServer(&OsdCsiServerConfig{
-		DriverName: mockDriverName,
-		Net:        "tcp",
-		Address:    "127.0.0.1:0",
-		Cluster:    tester.c,
+		DriverName:  mockDriverName,
+		Net:         "tcp",
+		Address:     "127.0.0.1:0",
+		Cluster:     tester.c,
+		StagingPath: filepath.Join(tester.dir, "staging"),
 	})
 	assert.Nil(t, err)
+
+	// Record bind mounts instead of mounting
+	tester.mounts = &fakeMountImpl{mounts: make(map[string]uintptr)}
+	osdServer := tester.server.(*OsdCsiServer)
+	osdServer.mounter, err = newBindMounter(osdServer.stagingPath, tester.mounts)
+	assert.Nil(t, err)
+
 	err = tester.server.Start()
 	assert.Nil(t, err)
 
@@ -106,6 +182,29 @@ func (s *testServer) Stop() {
 
 	// Check mocks
 	s.mc.Finish()
+
+	// Target paths are immutable while mounted on
+	if len(s.dir) != 0 {
+		filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				chattr.RemoveImmutable(path)
+			}
+			return nil
+		})
+		os.RemoveAll(s.dir)
+	}
+}
+
+// TargetPath creates a target path for NodePublishVolume
+func (s *testServer) TargetPath(t *testing.T, name string) string {
+	path := filepath.Join(s.dir, "targets", name)
+	assert.Nil(t, os.MkdirAll(path, 0755))
+	return path
+}
+
+// StagingPath returns the staging path of the volume
+func (s *testServer) StagingPath(id string) string {
+	return s.server.(*OsdCsiServer).volumeStagingPath(id)
 }
 
 func (s *testServer) Conn() *grpc.ClientConn {
@@ -136,9 +235,7 @@ func TestCSIServerStart(t *testing.T) {
 	// Make a call
 	s.MockDriver().EXPECT().Name().Return("mock").Times(2)
 	c := csi.NewIdentityClient(s.Conn())
-	r, err := c.GetPluginInfo(context.Background(), &csi.GetPluginInfoRequest{
-		Version: &csi.Version{},
-	})
+	r, err := c.GetPluginInfo(context.Background(), &csi.GetPluginInfoRequest{})
 	assert.Nil(t, err)
 
 	// Verify
@@ -216,3 +313,88 @@ func TestNewCSIServerBadParameters(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "Unable to setup server")
 }
+
+// selfSigned returns a self-signed certificate for 127.0.0.1 that can be
+// used by both servers and clients, and a pool that trusts it.
+func selfSigned(t *testing.T, cn string) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth,
+		},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+func TestCSIServerTLS(t *testing.T) {
+	serverCert, serverPool := selfSigned(t, "server")
+	clientCert, clientPool := selfSigned(t, "client")
+
+	tester := &testServer{}
+	tester.mc = gomock.NewController(&utils.SafeGoroutineTester{})
+	tester.m = mockdriver.NewMockVolumeDriver(tester.mc)
+	tester.c = mockcluster.NewMockCluster(tester.mc)
+	setupMockDriver(tester, t)
+
+	var err error
+	tester.server, err = NewOsdCsiServer(&OsdCsiServerConfig{
+		DriverName: mockDriverName,
+		Net:        "tcp",
+		Address:    "127.0.0.1:0",
+		Cluster:    tester.c,
+		TLS: &tls.Config{
+			Certificates: []tls.Certificate{serverCert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientPool,
+		},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, tester.server.Start())
+
+	call := func(opt grpc.DialOption) error {
+		conn, err := grpc.Dial(tester.server.Address(), opt)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = csi.NewIdentityClient(conn).
+			Probe(ctx, &csi.ProbeRequest{})
+		return err
+	}
+
+	// Plain text and TLS without a client certificate are refused
+	assert.Error(t, call(grpc.WithInsecure()))
+	assert.Error(t, call(grpc.WithTransportCredentials(
+		credentials.NewTLS(&tls.Config{RootCAs: serverPool}))))
+
+	// Mutual TLS
+	tester.conn, err = grpc.Dial(tester.server.Address(),
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			RootCAs:      serverPool,
+			Certificates: []tls.Certificate{clientCert},
+		})))
+	assert.NoError(t, err)
+	defer tester.Stop()
+
+	_, err = csi.NewIdentityClient(tester.Conn()).
+		Probe(context.Background(), &csi.ProbeRequest{})
+	assert.NoError(t, err)
+}
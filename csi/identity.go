@@ -18,10 +18,9 @@ package csi
 
 import (
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"go.pedge.io/dlog"
 	"golang.org/x/net/context"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 const (
@@ -29,25 +28,6 @@ const (
 	csiDriverNamePrefix = "com.openstorage."
 )
 
-var (
-	csiVersion = &csi.Version{
-		Major: 0,
-		Minor: 1,
-		Patch: 0,
-	}
-)
-
-// GetSupportedVersions is a CSI API which returns the supported CSI version
-func (s *OsdCsiServer) GetSupportedVersions(
-	context.Context,
-	*csi.GetSupportedVersionsRequest) (*csi.GetSupportedVersionsResponse, error) {
-	return &csi.GetSupportedVersionsResponse{
-		SupportedVersions: []*csi.Version{
-			csiVersion,
-		},
-	}, nil
-}
-
 // GetPluginInfo is a CSI API which returns the information about the plugin.
 // This includes name, version, and any other OSD specific information
 func (s *OsdCsiServer) GetPluginInfo(
@@ -56,11 +36,6 @@ func (s *OsdCsiServer) GetPluginInfo(
 
 	dlog.Debugf("GetPluginInfo req[%#v]", req)
 
-	// Check arguments
-	if req.GetVersion() == nil {
-		return nil, status.Error(codes.InvalidArgument, "Version must be provided")
-	}
-
 	return &csi.GetPluginInfoResponse{
 		Name:          csiDriverNamePrefix + s.driver.Name(),
 		VendorVersion: csiDriverVersion,
@@ -71,3 +46,62 @@ func (s *OsdCsiServer) GetPluginInfo(
 		},
 	}, nil
 }
+
+// GetPluginCapabilities is a CSI API which returns the services the plugin
+// provides besides the identity and node services.
+func (s *OsdCsiServer) GetPluginCapabilities(
+	ctx context.Context,
+	req *csi.GetPluginCapabilitiesRequest,
+) (*csi.GetPluginCapabilitiesResponse, error) {
+
+	dlog.Debugf("GetPluginCapabilities req[%#v]", req)
+
+	// The controller service is provided
+	capController := &csi.PluginCapability{
+		Type: &csi.PluginCapability_Service_{
+			Service: &csi.PluginCapability_Service{
+				Type: csi.PluginCapability_Service_CONTROLLER_SERVICE,
+			},
+		},
+	}
+
+	// Volumes can be expanded while they are published
+	capOnlineExpansion := &csi.PluginCapability{
+		Type: &csi.PluginCapability_VolumeExpansion_{
+			VolumeExpansion: &csi.PluginCapability_VolumeExpansion{
+				Type: csi.PluginCapability_VolumeExpansion_ONLINE,
+			},
+		},
+	}
+
+	// Nodes report their topology, which places new volumes
+	capTopology := &csi.PluginCapability{
+		Type: &csi.PluginCapability_Service_{
+			Service: &csi.PluginCapability_Service{
+				Type: csi.PluginCapability_Service_VOLUME_ACCESSIBILITY_CONSTRAINTS,
+			},
+		},
+	}
+
+	return &csi.GetPluginCapabilitiesResponse{
+		Capabilities: []*csi.PluginCapability{
+			capController,
+			capOnlineExpansion,
+			capTopology,
+		},
+	}, nil
+}
+
+// Probe is a CSI API which lets the caller check that the plugin is ready
+// to serve requests. The plugin is ready once the server is running.
+func (s *OsdCsiServer) Probe(
+	ctx context.Context,
+	req *csi.ProbeRequest,
+) (*csi.ProbeResponse, error) {
+
+	dlog.Debugf("Probe req[%#v]", req)
+
+	return &csi.ProbeResponse{
+		Ready: &wrappers.BoolValue{Value: true},
+	}, nil
+}
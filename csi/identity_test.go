@@ -17,14 +17,11 @@ limitations under the License.
 package csi
 
 import (
-	"reflect"
 	"testing"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/net/context"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func TestNewCSIServerGetPluginInfo(t *testing.T) {
@@ -39,18 +36,7 @@ func TestNewCSIServerGetPluginInfo(t *testing.T) {
 	// Setup client
 	c := csi.NewIdentityClient(s.Conn())
 
-	// No version added
-	_, err := c.GetPluginInfo(context.Background(), &csi.GetPluginInfoRequest{})
-	assert.Error(t, err)
-	serverError, ok := status.FromError(err)
-	assert.True(t, ok)
-	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
-	assert.Contains(t, serverError.Message(), "Version")
-
-	// No version added
-	r, err := c.GetPluginInfo(context.Background(), &csi.GetPluginInfoRequest{
-		Version: &csi.Version{},
-	})
+	r, err := c.GetPluginInfo(context.Background(), &csi.GetPluginInfoRequest{})
 	assert.NoError(t, err)
 
 	// Verify
@@ -64,7 +50,7 @@ func TestNewCSIServerGetPluginInfo(t *testing.T) {
 	assert.Equal(t, manifest["driver"], "mock")
 }
 
-func TestNewCSIServerGetSupportedVersions(t *testing.T) {
+func TestNewCSIServerGetPluginCapabilities(t *testing.T) {
 
 	// Create server and client connection
 	s := newTestServer(t)
@@ -72,11 +58,36 @@ func TestNewCSIServerGetSupportedVersions(t *testing.T) {
 
 	// Make a call
 	c := csi.NewIdentityClient(s.Conn())
-	r, err := c.GetSupportedVersions(context.Background(), &csi.GetSupportedVersionsRequest{})
+	r, err := c.GetPluginCapabilities(
+		context.Background(),
+		&csi.GetPluginCapabilitiesRequest{})
 	assert.Nil(t, err)
 
 	// Verify
-	versions := r.GetSupportedVersions()
-	assert.Equal(t, len(versions), 1)
-	assert.True(t, reflect.DeepEqual(versions[0], csiVersion))
+	capabilities := r.GetCapabilities()
+	assert.Len(t, capabilities, 3)
+	assert.Equal(t,
+		csi.PluginCapability_Service_CONTROLLER_SERVICE,
+		capabilities[0].GetService().GetType())
+	assert.Equal(t,
+		csi.PluginCapability_VolumeExpansion_ONLINE,
+		capabilities[1].GetVolumeExpansion().GetType())
+	assert.Equal(t,
+		csi.PluginCapability_Service_VOLUME_ACCESSIBILITY_CONSTRAINTS,
+		capabilities[2].GetService().GetType())
+}
+
+func TestNewCSIServerProbe(t *testing.T) {
+
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewIdentityClient(s.Conn())
+	r, err := c.Probe(context.Background(), &csi.ProbeRequest{})
+	assert.Nil(t, err)
+
+	// Verify
+	assert.True(t, r.GetReady().GetValue())
 }
@@ -0,0 +1,151 @@
+/*
+Package csi is CSI driver interface for OSD
+Copyright 2017 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package csi
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	dockermount "github.com/docker/docker/pkg/mount"
+	"github.com/libopenstorage/openstorage/pkg/mount"
+)
+
+const (
+	// DefaultStagingPath is the directory volumes are mounted in once per
+	// node, before they are bind mounted into the target paths of
+	// NodePublishVolume.
+	DefaultStagingPath = "/var/lib/osd/csi/staging"
+)
+
+// bindMountImpl bind mounts directories. The read only flag is ignored when
+// a bind mount is created, so read only bind mounts are remounted.
+type bindMountImpl struct{}
+
+func (b *bindMountImpl) Mount(
+	source string,
+	target string,
+	fstype string,
+	flags uintptr,
+	data string,
+	timeout int,
+) error {
+	if err := syscall.Mount(source, target, "", syscall.MS_BIND, ""); err != nil {
+		return err
+	}
+	if flags&syscall.MS_RDONLY != 0 {
+		err := syscall.Mount("", target, "",
+			syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, "")
+		if err != nil {
+			syscall.Unmount(target, 0)
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *bindMountImpl) Unmount(target string, flags int, timeout int) error {
+	return syscall.Unmount(target, flags)
+}
+
+// newBindMounter returns a mount manager that keeps count of the bind
+// mounts of the staging paths under stagingPath.
+func newBindMounter(stagingPath string, impl mount.MountImpl) (mount.Manager, error) {
+	if impl == nil {
+		impl = &bindMountImpl{}
+	}
+	return mount.New(
+		mount.CustomMount,
+		impl,
+		[]string{stagingPath},
+		func() (mount.CustomLoad, mount.CustomReload) {
+			return loadBindMounts, reloadBindMounts
+		},
+		nil,
+		"")
+}
+
+// loadBindMounts adds the existing bind mounts of the staging paths under
+// dirs, and of device nodes, to the mount table. A bind mount has the device
+// and root of the staging path it was made from. A device node bind mount
+// has the root of the node in devtmpfs.
+func loadBindMounts(dirs []string, dm mount.DeviceMap, pm mount.PathMap) error {
+	mounts, err := dockermount.GetMounts()
+	if err != nil {
+		return err
+	}
+	addBindMounts(mounts, dirs, dm, pm)
+	return nil
+}
+
+func addBindMounts(
+	mounts []*dockermount.Info,
+	dirs []string,
+	dm mount.DeviceMap,
+	pm mount.PathMap,
+) {
+	staged := make(map[string]string)
+	for _, m := range mounts {
+		for _, dir := range dirs {
+			if isUnder(m.Mountpoint, dir) {
+				staged[mountKey(m)] = m.Mountpoint
+			}
+		}
+	}
+	for _, m := range mounts {
+		source, ok := staged[mountKey(m)]
+		if !ok && m.Fstype == "devtmpfs" && m.Root != "/" {
+			source, ok = filepath.Join("/dev", m.Root), true
+		}
+		if !ok || source == m.Mountpoint {
+			continue
+		}
+		info, ok := dm[source]
+		if !ok {
+			info = &mount.Info{
+				Device:     source,
+				Mountpoint: make([]*mount.PathInfo, 0),
+			}
+			dm[source] = info
+		}
+		if _, ok := pm[m.Mountpoint]; !ok {
+			info.Mountpoint = append(info.Mountpoint, &mount.PathInfo{Path: m.Mountpoint})
+			pm[m.Mountpoint] = source
+		}
+	}
+}
+
+// reloadBindMounts replaces the bind mounts of stagingPath in the mount
+// table with the ones found in the system mount table.
+func reloadBindMounts(stagingPath string, dm mount.DeviceMap, pm mount.PathMap) error {
+	if info, ok := dm[stagingPath]; ok {
+		for _, p := range info.Mountpoint {
+			delete(pm, p.Path)
+		}
+		delete(dm, stagingPath)
+	}
+	return loadBindMounts([]string{stagingPath}, dm, pm)
+}
+
+func mountKey(m *dockermount.Info) string {
+	return fmt.Sprintf("%d:%d:%s", m.Major, m.Minor, m.Root)
+}
+
+func isUnder(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, filepath.Clean(dir)+"/")
+}
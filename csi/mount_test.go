@@ -0,0 +1,61 @@
+/*
+CSI Interface for OSD
+Copyright 2017 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package csi
+
+import (
+	"testing"
+
+	dockermount "github.com/docker/docker/pkg/mount"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/libopenstorage/openstorage/pkg/mount"
+)
+
+func TestAddBindMounts(t *testing.T) {
+	staging := "/var/lib/osd/csi/staging"
+	mounts := []*dockermount.Info{
+		{Major: 8, Minor: 0, Root: "/", Mountpoint: "/"},
+		{Major: 250, Minor: 1, Root: "/", Mountpoint: staging + "/vol1"},
+		{Major: 250, Minor: 1, Root: "/", Mountpoint: "/pods/a/vol1"},
+		{Major: 250, Minor: 1, Root: "/", Mountpoint: "/pods/b/vol1"},
+		{Major: 8, Minor: 0, Root: "/vfs/vol2", Mountpoint: staging + "/vol2"},
+		{Major: 8, Minor: 0, Root: "/vfs/vol2", Mountpoint: "/pods/a/vol2"},
+		// Same device, different directory
+		{Major: 8, Minor: 0, Root: "/vfs/vol3", Mountpoint: "/pods/a/vol3"},
+		// Device nodes
+		{Major: 0, Minor: 6, Root: "/", Mountpoint: "/dev", Fstype: "devtmpfs"},
+		{Major: 0, Minor: 6, Root: "/nbd0", Mountpoint: "/pods/a/dev4", Fstype: "devtmpfs"},
+	}
+	dm := make(mount.DeviceMap)
+	pm := make(mount.PathMap)
+	addBindMounts(mounts, []string{staging}, dm, pm)
+
+	assert.Len(t, dm, 3)
+	assert.Len(t, dm[staging+"/vol1"].Mountpoint, 2)
+	assert.Len(t, dm[staging+"/vol2"].Mountpoint, 1)
+	assert.Len(t, dm["/dev/nbd0"].Mountpoint, 1)
+	assert.Equal(t, mount.PathMap{
+		"/pods/a/vol1": staging + "/vol1",
+		"/pods/b/vol1": staging + "/vol1",
+		"/pods/a/vol2": staging + "/vol2",
+		"/pods/a/dev4": "/dev/nbd0",
+	}, pm)
+
+	// Loading again does not count the bind mounts twice
+	addBindMounts(mounts, []string{staging}, dm, pm)
+	assert.Len(t, dm[staging+"/vol1"].Mountpoint, 2)
+}
@@ -19,10 +19,15 @@ package csi
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"syscall"
 
 	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/chattr"
 	"github.com/libopenstorage/openstorage/pkg/options"
 	"github.com/libopenstorage/openstorage/pkg/util"
+	"github.com/libopenstorage/openstorage/volume"
+	"github.com/libopenstorage/openstorage/volume/drivers/common"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"go.pedge.io/dlog"
@@ -31,22 +36,30 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// GetNodeID is a CSI API which gets the PX NodeId for the local node
-func (s *OsdCsiServer) GetNodeID(ctx context.Context, req *csi.GetNodeIDRequest) (*csi.GetNodeIDResponse, error) {
-	dlog.Debugf("GetNodeID req[%#v]", req)
+// NodeGetInfo is a CSI API which gets the PX NodeId for the local node
+// and its topology
+func (s *OsdCsiServer) NodeGetInfo(
+	ctx context.Context,
+	req *csi.NodeGetInfoRequest,
+) (*csi.NodeGetInfoResponse, error) {
 
-	// Check arguments
-	if req.GetVersion() == nil {
-		return nil, status.Error(codes.InvalidArgument, "Version must be provided")
-	}
+	dlog.Debugf("NodeGetInfo req[%#v]", req)
 
 	clus, err := s.cluster.Enumerate()
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Unable to Enumerate cluster: %s", err)
 	}
 
-	result := &csi.GetNodeIDResponse{
-		NodeId: clus.NodeId,
+	node := &api.Node{Id: clus.NodeId}
+	for i := range clus.Nodes {
+		if clus.Nodes[i].Id == clus.NodeId {
+			node = &clus.Nodes[i]
+		}
+	}
+
+	result := &csi.NodeGetInfoResponse{
+		NodeId:             clus.NodeId,
+		AccessibleTopology: csiNodeTopology(node),
 	}
 
 	dlog.Infof("NodeId is %s", result.NodeId)
@@ -54,11 +67,25 @@ func (s *OsdCsiServer) GetNodeID(ctx context.Context, req *csi.GetNodeIDRequest)
 	return result, nil
 }
 
+// csiNodeTopology returns the topology segments of a node: its id and the
+// node labels which map to volume placement parameters.
+func csiNodeTopology(node *api.Node) *csi.Topology {
+	segments := map[string]string{
+		topologyKeyNode: node.Id,
+	}
+	for label := range topologyPlacement {
+		if v, ok := node.NodeLabels[label]; ok && len(v) != 0 {
+			segments[topologyKeyPrefix+label] = v
+		}
+	}
+	return &csi.Topology{Segments: segments}
+}
+
 // NodePublishVolume is a CSI API call which mounts the volume on the specified
 // target path on the node.
 //
-// TODO: Support READ ONLY Mounts
-//
+// The volume is mounted by the driver once per node at a staging path, which
+// is then bind mounted onto every target path the volume is published at.
 func (s *OsdCsiServer) NodePublishVolume(
 	ctx context.Context,
 	req *csi.NodePublishVolumeRequest,
@@ -67,9 +94,6 @@ func (s *OsdCsiServer) NodePublishVolume(
 	dlog.Debugf("NodePublishVolume req[%#v]", req)
 
 	// Check arguments
-	if req.GetVersion() == nil {
-		return nil, status.Error(codes.InvalidArgument, "Version must be provided")
-	}
 	if len(req.GetVolumeId()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
 	}
@@ -89,12 +113,12 @@ func (s *OsdCsiServer) NodePublishVolume(
 	}
 
 	// Gather volume attributes
-	spec, _, _, err := s.specHandler.SpecFromOpts(req.GetVolumeAttributes())
+	spec, _, _, err := s.specHandler.SpecFromOpts(req.GetVolumeContext())
 	if err != nil {
 		return nil, status.Errorf(
 			codes.InvalidArgument,
-			"Invalid volume attributes: %#v",
-			req.GetVolumeAttributes())
+			"Invalid volume context: %#v",
+			req.GetVolumeContext())
 	}
 
 	// This seems weird as a way to change opts to map[string]string
@@ -103,9 +127,22 @@ func (s *OsdCsiServer) NodePublishVolume(
 		opts[options.OptionsSecret] = spec.GetPassphrase()
 	}
 
-	// Verify target location is an existing directory
-	// See: https://github.com/container-storage-interface/spec/issues/60
-	if err := verifyTargetLocation(req.GetTargetPath()); err != nil {
+	if req.GetVolumeCapability().GetBlock() != nil {
+		return s.nodePublishBlockVolume(req, v, opts)
+	}
+
+	// Volumes staged by NodeStageVolume must be mounted at the staging path
+	if len(req.GetStagingTargetPath()) != 0 &&
+		!containsString(v.GetAttachPath(), req.GetStagingTargetPath()) {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"Volume %s is not staged at %s",
+			req.GetVolumeId(),
+			req.GetStagingTargetPath())
+	}
+
+	// Create the target location unless it is an existing directory
+	if err := createTargetDir(req.GetTargetPath()); err != nil {
 		return nil, status.Errorf(
 			codes.Aborted,
 			"Failed to use target location %s: %s",
@@ -113,24 +150,41 @@ func (s *OsdCsiServer) NodePublishVolume(
 			err.Error())
 	}
 
-	// If this is for a block driver, first attach the volume
-	if s.driver.Type() == api.DriverType_DRIVER_TYPE_BLOCK {
-		if _, err := s.driver.Attach(req.GetVolumeId(), opts); err != nil {
-			return nil, status.Errorf(
-				codes.Internal,
-				"Unable to attach volume: %s",
-				err.Error())
+	h := s.volumeLocks.Acquire(v.GetId())
+	defer s.volumeLocks.Release(&h)
+
+	// Stage the volume unless the CO or another target path staged it
+	// already
+	stagingPath := req.GetStagingTargetPath()
+	if len(stagingPath) == 0 {
+		stagingPath = s.volumeStagingPath(v.GetId())
+	}
+	staged, attached := false, false
+	if !containsString(v.GetAttachPath(), stagingPath) {
+		// If this is for a block driver, first attach the volume unless
+		// ControllerPublishVolume attached it already
+		attached = s.driver.Type() == api.DriverType_DRIVER_TYPE_BLOCK &&
+			len(req.GetPublishContext()[publishInfoDevicePath]) == 0
+		if err := s.stageVolume(v.GetId(), stagingPath, opts, attached); err != nil {
+			return nil, err
 		}
+		staged = true
 	}
 
-	// Mount volume onto the path
-	if err := s.driver.Mount(req.GetVolumeId(), req.GetTargetPath(), nil); err != nil {
-		// Detach on error
-		detachErr := s.driver.Detach(v.GetId(), opts)
-		if detachErr != nil {
-			dlog.Errorf("Unable to detach volume %s: %s",
-				v.GetId(),
-				detachErr.Error())
+	// Bind mount the staging path onto the target path
+	var flags uintptr
+	if req.GetReadonly() {
+		flags = syscall.MS_RDONLY
+	}
+	err = s.mounter.Mount(0, stagingPath, req.GetTargetPath(), "", flags, "", 0, nil)
+	if err != nil {
+		if staged {
+			unstageErr := s.unstageVolume(v.GetId(), stagingPath, true, attached)
+			if unstageErr != nil {
+				dlog.Errorf("Unable to unstage volume %s: %s",
+					v.GetId(),
+					unstageErr.Error())
+			}
 		}
 		return nil, status.Errorf(
 			codes.Internal,
@@ -147,7 +201,9 @@ func (s *OsdCsiServer) NodePublishVolume(
 	return &csi.NodePublishVolumeResponse{}, nil
 }
 
-// NodeUnpublishVolume is a CSI API call which unmounts the volume.
+// NodeUnpublishVolume is a CSI API call which unmounts the volume and removes
+// the target path. The volume is unstaged once it is not mounted on any
+// target path.
 func (s *OsdCsiServer) NodeUnpublishVolume(
 	ctx context.Context,
 	req *csi.NodeUnpublishVolumeRequest,
@@ -156,9 +212,6 @@ func (s *OsdCsiServer) NodeUnpublishVolume(
 	dlog.Debugf("NodeUnPublishVolume req[%#v]", req)
 
 	// Check arguments
-	if req.GetVersion() == nil {
-		return nil, status.Error(codes.InvalidArgument, "Version must be provided")
-	}
 	if len(req.GetVolumeId()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
 	}
@@ -167,31 +220,230 @@ func (s *OsdCsiServer) NodeUnpublishVolume(
 	}
 
 	// Get volume information
-	_, err := util.VolumeFromName(s.driver, req.GetVolumeId())
+	v, err := util.VolumeFromName(s.driver, req.GetVolumeId())
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "Volume id %s not found: %s",
 			req.GetVolumeId(),
 			err.Error())
 	}
 
-	// Verify target location is an existing directory
-	// See: https://github.com/container-storage-interface/spec/issues/60
+	// Devices are bind mounted directly onto the target path
+	s.loadStagedMounts(v)
+	source, err := s.mounter.GetSourcePath(req.GetTargetPath())
+	if err == nil && !containsString(v.GetAttachPath(), source) {
+		return s.nodeUnpublishBlockVolume(req, v, source)
+	}
+
+	// Unpublishing from a target path that is gone succeeds
 	if err = verifyTargetLocation(req.GetTargetPath()); err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		dlog.Infof("Volume %s not published on %s: %s",
+			req.GetVolumeId(),
+			req.GetTargetPath(),
+			err.Error())
+		return &csi.NodeUnpublishVolumeResponse{}, nil
+	}
+
+	h := s.volumeLocks.Acquire(v.GetId())
+	defer s.volumeLocks.Release(&h)
+
+	stagingPath := s.volumeStagingPath(v.GetId())
+	if source, err := s.mounter.GetSourcePath(req.GetTargetPath()); err == nil {
+		err = s.mounter.Unmount(source, req.GetTargetPath(), 0, 0, nil)
+		if err != nil {
+			return nil, status.Errorf(
+				codes.Internal,
+				"Unable to unmount volume %s onto %s: %s",
+				req.GetVolumeId(),
+				req.GetTargetPath(),
+				err.Error())
+		}
+		// The target path was made immutable while it was mounted on
+		if err = chattr.RemoveImmutable(req.GetTargetPath()); err != nil {
+			dlog.Warnf("Unable to make %s writeable: %s",
+				req.GetTargetPath(),
+				err.Error())
+		}
+		if err = os.Remove(req.GetTargetPath()); err != nil && !os.IsNotExist(err) {
+			dlog.Warnf("Unable to remove %s: %s",
+				req.GetTargetPath(),
+				err.Error())
+		}
+		// Volumes staged by NodeStageVolume are unstaged by
+		// NodeUnstageVolume
+		if source != stagingPath {
+			dlog.Infof("Volume %s unmounted from %s",
+				req.GetVolumeId(),
+				req.GetTargetPath())
+			return &csi.NodeUnpublishVolumeResponse{}, nil
+		}
+	} else if containsString(v.GetAttachPath(), req.GetTargetPath()) {
+		// Mounted directly by the driver, without a staging path
+		err = s.driver.Unmount(v.GetId(), req.GetTargetPath(), nil)
+		if err != nil {
+			return nil, status.Errorf(
+				codes.Internal,
+				"Unable to unmount volume %s onto %s: %s",
+				req.GetVolumeId(),
+				req.GetTargetPath(),
+				err.Error())
+		}
+	}
+
+	// Keep the volume staged while other target paths use it, or while the
+	// CO keeps it staged
+	if s.mounter.HasMounts(stagingPath) != 0 ||
+		s.stagedByCO(v, stagingPath, req.GetTargetPath()) {
+		dlog.Infof("Volume %s unmounted from %s",
+			req.GetVolumeId(),
+			req.GetTargetPath())
+		return &csi.NodeUnpublishVolumeResponse{}, nil
+	}
+	// Volumes attached by ControllerPublishVolume are detached by
+	// ControllerUnpublishVolume
+	detach := s.driver.Type() == api.DriverType_DRIVER_TYPE_BLOCK &&
+		len(v.GetAttachInfo()[options.OptionsAttachNode]) == 0
+	mounted := containsString(v.GetAttachPath(), stagingPath)
+	if err = s.unstageVolume(v.GetId(), stagingPath, mounted, detach); err != nil {
+		return nil, err
+	}
+
+	dlog.Infof("Volume %s unmounted", req.GetVolumeId())
+
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
+
+// nodePublishBlockVolume attaches the volume, unless it is attached already,
+// and bind mounts its device onto the target path. The target path is
+// created as a file if it does not exist.
+func (s *OsdCsiServer) nodePublishBlockVolume(
+	req *csi.NodePublishVolumeRequest,
+	v *api.Volume,
+	opts map[string]string,
+) (*csi.NodePublishVolumeResponse, error) {
+	if s.driver.Type() != api.DriverType_DRIVER_TYPE_BLOCK {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			volumeCapabilityMessageBlockNotSupported)
+	}
+	if err := createTargetFile(req.GetTargetPath()); err != nil {
+		return nil, status.Errorf(
+			codes.Aborted,
+			"Failed to use target location %s: %s",
+			req.GetTargetPath(),
+			err.Error())
+	}
+
+	h := s.volumeLocks.Acquire(v.GetId())
+	defer s.volumeLocks.Release(&h)
+
+	if err := s.verifyAttachedLocally(v); err != nil {
+		return nil, err
+	}
+
+	// Use the device ControllerPublishVolume or an earlier publish attached
+	devicePath := req.GetPublishContext()[publishInfoDevicePath]
+	if len(devicePath) == 0 &&
+		v.GetState() == api.VolumeState_VOLUME_STATE_ATTACHED {
+		devicePath = v.GetDevicePath()
+	}
+	attached := false
+	if len(devicePath) == 0 {
+		var err error
+		if devicePath, err = s.driver.Attach(v.GetId(), opts); err != nil {
+			return nil, status.Errorf(
+				codes.Internal,
+				"Unable to attach volume: %s",
+				err.Error())
+		}
+		attached = true
+	}
+
+	var flags uintptr
+	if req.GetReadonly() {
+		flags = syscall.MS_RDONLY
+	}
+	err := s.mounter.Mount(0, devicePath, req.GetTargetPath(), "", flags, "", 0, nil)
+	if err != nil {
+		if attached {
+			if detachErr := s.driver.Detach(v.GetId(), opts); detachErr != nil {
+				dlog.Errorf("Unable to detach volume %s: %s",
+					v.GetId(),
+					detachErr.Error())
+			}
+		}
+		return nil, status.Errorf(
+			codes.Internal,
+			"Unable to mount device %s onto %s: %s",
+			devicePath,
+			req.GetTargetPath(),
+			err.Error())
+	}
+
+	dlog.Infof("Volume %s device %s mounted on %s",
+		req.GetVolumeId(),
+		devicePath,
+		req.GetTargetPath())
+
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// verifyAttachedLocally returns a FailedPrecondition error if the volume is
+// attached on another node, where its device cannot be used from.
+func (s *OsdCsiServer) verifyAttachedLocally(v *api.Volume) error {
+	if v.GetState() != api.VolumeState_VOLUME_STATE_ATTACHED ||
+		len(v.GetAttachedOn()) == 0 {
+		return nil
+	}
+	clus, err := s.cluster.Enumerate()
+	if err != nil {
+		return status.Errorf(codes.Internal, "Unable to Enumerate cluster: %s", err)
 	}
+	if v.GetAttachedOn() != clus.NodeId {
+		return status.Errorf(
+			codes.FailedPrecondition,
+			"Volume %s is attached on node %s, not on this node %s",
+			v.GetId(),
+			v.GetAttachedOn(),
+			clus.NodeId)
+	}
+	return nil
+}
 
-	// Mount volume onto the path
-	if err = s.driver.Unmount(req.GetVolumeId(), req.GetTargetPath(), nil); err != nil {
+// nodeUnpublishBlockVolume removes the bind mount of the device onto the
+// target path, and the target path. The volume is detached once its device
+// is not mounted on any target path.
+func (s *OsdCsiServer) nodeUnpublishBlockVolume(
+	req *csi.NodeUnpublishVolumeRequest,
+	v *api.Volume,
+	devicePath string,
+) (*csi.NodeUnpublishVolumeResponse, error) {
+	h := s.volumeLocks.Acquire(v.GetId())
+	defer s.volumeLocks.Release(&h)
+
+	err := s.mounter.Unmount(devicePath, req.GetTargetPath(), 0, 0, nil)
+	if err != nil {
 		return nil, status.Errorf(
 			codes.Internal,
-			"Unable to unmount volume %s onto %s: %s",
-			req.GetVolumeId(),
+			"Unable to unmount device %s from %s: %s",
+			devicePath,
+			req.GetTargetPath(),
+			err.Error())
+	}
+	if err = chattr.RemoveImmutable(req.GetTargetPath()); err != nil {
+		dlog.Warnf("Unable to make %s writeable: %s",
+			req.GetTargetPath(),
+			err.Error())
+	}
+	if err = os.Remove(req.GetTargetPath()); err != nil && !os.IsNotExist(err) {
+		dlog.Warnf("Unable to remove %s: %s",
 			req.GetTargetPath(),
 			err.Error())
 	}
 
-	if s.driver.Type() == api.DriverType_DRIVER_TYPE_BLOCK {
-		if err = s.driver.Detach(req.GetVolumeId(), nil); err != nil {
+	if s.mounter.HasMounts(devicePath) == 0 &&
+		s.driver.Type() == api.DriverType_DRIVER_TYPE_BLOCK &&
+		len(v.GetAttachInfo()[options.OptionsAttachNode]) == 0 {
+		if err = s.driver.Detach(v.GetId(), nil); err != nil {
 			return nil, status.Errorf(
 				codes.Internal,
 				"Unable to detach volume: %s",
@@ -199,34 +451,123 @@ func (s *OsdCsiServer) NodeUnpublishVolume(
 		}
 	}
 
-	dlog.Infof("Volume %s unmounted", req.GetVolumeId())
+	dlog.Infof("Volume %s device %s unmounted from %s",
+		req.GetVolumeId(),
+		devicePath,
+		req.GetTargetPath())
 
 	return &csi.NodeUnpublishVolumeResponse{}, nil
 }
 
-// NodeProbe is a CSI API function which asks the driver to check if the
-// node has all the necessary components to run successfully.
-func (s *OsdCsiServer) NodeProbe(
-	ctx context.Context,
-	req *csi.NodeProbeRequest,
-) (*csi.NodeProbeResponse, error) {
+// stageVolume attaches the volume if attach is set, and mounts it at the
+// staging path.
+func (s *OsdCsiServer) stageVolume(
+	id string,
+	stagingPath string,
+	opts map[string]string,
+	attach bool,
+) error {
+	if err := os.MkdirAll(stagingPath, 0750); err != nil {
+		return status.Errorf(
+			codes.Internal,
+			"Unable to create staging path %s: %s",
+			stagingPath,
+			err.Error())
+	}
 
-	dlog.Debugf("NodeProbe req[%#v]", req)
+	if attach {
+		if _, err := s.driver.Attach(id, opts); err != nil {
+			return status.Errorf(
+				codes.Internal,
+				"Unable to attach volume: %s",
+				err.Error())
+		}
+	}
 
-	// Check arguments
-	if req.GetVersion() == nil {
-		return nil, status.Error(codes.InvalidArgument, "Version must be provided")
+	// Mount volume onto the staging path
+	if err := s.driver.Mount(id, stagingPath, nil); err != nil {
+		// Detach on error
+		if attach {
+			detachErr := s.driver.Detach(id, opts)
+			if detachErr != nil {
+				dlog.Errorf("Unable to detach volume %s: %s",
+					id,
+					detachErr.Error())
+			}
+		}
+		return status.Errorf(
+			codes.Internal,
+			"Unable to mount volume %s onto %s: %s",
+			id,
+			stagingPath,
+			err.Error())
 	}
+	return nil
+}
+
+// unstageVolume unmounts the volume from the staging path if mounted is set,
+// and detaches it if detach is set.
+func (s *OsdCsiServer) unstageVolume(
+	id string,
+	stagingPath string,
+	mounted bool,
+	detach bool,
+) error {
+	if mounted {
+		if err := s.driver.Unmount(id, stagingPath, nil); err != nil {
+			return status.Errorf(
+				codes.Internal,
+				"Unable to unmount volume %s onto %s: %s",
+				id,
+				stagingPath,
+				err.Error())
+		}
+	}
+	if detach {
+		if err := s.driver.Detach(id, nil); err != nil {
+			return status.Errorf(
+				codes.Internal,
+				"Unable to detach volume: %s",
+				err.Error())
+		}
+	}
+	return nil
+}
+
+func (s *OsdCsiServer) volumeStagingPath(id string) string {
+	return filepath.Join(s.stagingPath, id)
+}
 
-	// TBD(lpabon) Here we can add support
-	// to scan th system in a future patch.
+// stagedByCO returns true if the volume is mounted at a staging path of
+// NodeStageVolume, other than the internal staging path and the target path.
+func (s *OsdCsiServer) stagedByCO(v *api.Volume, stagingPath, targetPath string) bool {
+	for _, p := range v.GetAttachPath() {
+		if p != stagingPath && p != targetPath {
+			return true
+		}
+	}
+	return false
+}
 
-	// For now return 'ok'.
-	return &csi.NodeProbeResponse{}, nil
+// loadStagedMounts adds the bind mounts of the staging paths the volume is
+// mounted at to the mount table. Only the internal staging paths are loaded
+// when the server starts, the staging paths of NodeStageVolume are chosen
+// by the CO.
+func (s *OsdCsiServer) loadStagedMounts(v *api.Volume) {
+	sources := s.mounter.GetSourcePaths()
+	for _, p := range v.GetAttachPath() {
+		if containsString(sources, p) {
+			continue
+		}
+		if err := s.mounter.Reload(p); err != nil {
+			dlog.Warnf("Unable to load bind mounts of %s: %s", p, err.Error())
+		}
+	}
 }
 
-// NodeGetCapabilities is a CSI API function which seems to be setup for
-// future patches
+// NodeGetCapabilities is a CSI API function which returns the optional
+// node RPCs supported. Volumes published without a staging path are staged
+// by NodePublishVolume.
 func (s *OsdCsiServer) NodeGetCapabilities(
 	ctx context.Context,
 	req *csi.NodeGetCapabilitiesRequest,
@@ -234,17 +575,26 @@ func (s *OsdCsiServer) NodeGetCapabilities(
 
 	dlog.Debugf("NodeGetCapabilities req[%#v]", req)
 
-	// Check arguments
-	if req.GetVersion() == nil {
-		return nil, status.Error(codes.InvalidArgument, "Version must be provided")
-	}
-
 	return &csi.NodeGetCapabilitiesResponse{
 		Capabilities: []*csi.NodeServiceCapability{
-			{
+			&csi.NodeServiceCapability{
 				Type: &csi.NodeServiceCapability_Rpc{
 					Rpc: &csi.NodeServiceCapability_RPC{
-						Type: csi.NodeServiceCapability_RPC_UNKNOWN,
+						Type: csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+					},
+				},
+			},
+			&csi.NodeServiceCapability{
+				Type: &csi.NodeServiceCapability_Rpc{
+					Rpc: &csi.NodeServiceCapability_RPC{
+						Type: csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+					},
+				},
+			},
+			&csi.NodeServiceCapability{
+				Type: &csi.NodeServiceCapability_Rpc{
+					Rpc: &csi.NodeServiceCapability_RPC{
+						Type: csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
 					},
 				},
 			},
@@ -252,6 +602,345 @@ func (s *OsdCsiServer) NodeGetCapabilities(
 	}, nil
 }
 
+// NodeStageVolume is a CSI API call which attaches the volume, and mounts it
+// at the staging path unless it is a block volume. The staging path is then
+// bind mounted onto the target paths of NodePublishVolume.
+func (s *OsdCsiServer) NodeStageVolume(
+	ctx context.Context,
+	req *csi.NodeStageVolumeRequest,
+) (*csi.NodeStageVolumeResponse, error) {
+
+	dlog.Debugf("NodeStageVolume req[%#v]", req)
+
+	// Check arguments
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
+	}
+	if len(req.GetStagingTargetPath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Staging target path must be provided")
+	}
+	if req.GetVolumeCapability() == nil || req.GetVolumeCapability().GetAccessMode() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Volume access mode must be provided")
+	}
+
+	// Get volume information
+	v, err := util.VolumeFromName(s.driver, req.GetVolumeId())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Volume id %s not found: %s",
+			req.GetVolumeId(),
+			err.Error())
+	}
+
+	// Gather volume attributes
+	spec, _, _, err := s.specHandler.SpecFromOpts(req.GetVolumeContext())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"Invalid volume context: %#v",
+			req.GetVolumeContext())
+	}
+	opts := make(map[string]string)
+	if len(spec.GetPassphrase()) != 0 {
+		opts[options.OptionsSecret] = spec.GetPassphrase()
+	}
+
+	block := s.driver.Type() == api.DriverType_DRIVER_TYPE_BLOCK
+	if req.GetVolumeCapability().GetBlock() != nil && !block {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			volumeCapabilityMessageBlockNotSupported)
+	}
+
+	h := s.volumeLocks.Acquire(v.GetId())
+	defer s.volumeLocks.Release(&h)
+
+	if err := s.verifyAttachedLocally(v); err != nil {
+		return nil, err
+	}
+
+	// Attach the volume unless ControllerPublishVolume or an earlier call
+	// attached it already
+	attach := block &&
+		len(req.GetPublishContext()[publishInfoDevicePath]) == 0 &&
+		v.GetState() != api.VolumeState_VOLUME_STATE_ATTACHED
+
+	// Block volumes are bind mounted from their device by NodePublishVolume
+	if req.GetVolumeCapability().GetBlock() != nil {
+		if attach {
+			if _, err := s.driver.Attach(v.GetId(), opts); err != nil {
+				return nil, status.Errorf(
+					codes.Internal,
+					"Unable to attach volume: %s",
+					err.Error())
+			}
+		}
+		dlog.Infof("Volume %s staged", req.GetVolumeId())
+		return &csi.NodeStageVolumeResponse{}, nil
+	}
+
+	if containsString(v.GetAttachPath(), req.GetStagingTargetPath()) {
+		dlog.Infof("Volume %s is staged at %s already",
+			req.GetVolumeId(),
+			req.GetStagingTargetPath())
+		return &csi.NodeStageVolumeResponse{}, nil
+	}
+	err = s.stageVolume(v.GetId(), req.GetStagingTargetPath(), opts, attach)
+	if err != nil {
+		return nil, err
+	}
+
+	dlog.Infof("Volume %s staged at %s",
+		req.GetVolumeId(),
+		req.GetStagingTargetPath())
+
+	return &csi.NodeStageVolumeResponse{}, nil
+}
+
+// NodeUnstageVolume is a CSI API call which unmounts the volume from the
+// staging path and detaches it. The volume must be unpublished from every
+// target path first.
+func (s *OsdCsiServer) NodeUnstageVolume(
+	ctx context.Context,
+	req *csi.NodeUnstageVolumeRequest,
+) (*csi.NodeUnstageVolumeResponse, error) {
+
+	dlog.Debugf("NodeUnstageVolume req[%#v]", req)
+
+	// Check arguments
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
+	}
+	if len(req.GetStagingTargetPath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Staging target path must be provided")
+	}
+
+	// Get volume information
+	v, err := util.VolumeFromName(s.driver, req.GetVolumeId())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Volume id %s not found: %s",
+			req.GetVolumeId(),
+			err.Error())
+	}
+
+	h := s.volumeLocks.Acquire(v.GetId())
+	defer s.volumeLocks.Release(&h)
+
+	s.loadStagedMounts(v)
+	stagingPath := req.GetStagingTargetPath()
+	if s.mounter.HasMounts(stagingPath) != 0 ||
+		(len(v.GetDevicePath()) != 0 && s.mounter.HasMounts(v.GetDevicePath()) != 0) {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"Volume %s is still published",
+			req.GetVolumeId())
+	}
+
+	// Keep the volume attached while it is mounted elsewhere, and volumes
+	// attached by ControllerPublishVolume until ControllerUnpublishVolume
+	mounted := containsString(v.GetAttachPath(), stagingPath)
+	detach := s.driver.Type() == api.DriverType_DRIVER_TYPE_BLOCK &&
+		v.GetState() == api.VolumeState_VOLUME_STATE_ATTACHED &&
+		len(v.GetAttachInfo()[options.OptionsAttachNode]) == 0
+	for _, p := range v.GetAttachPath() {
+		if p != stagingPath {
+			detach = false
+		}
+	}
+	if err = s.unstageVolume(v.GetId(), stagingPath, mounted, detach); err != nil {
+		return nil, err
+	}
+
+	dlog.Infof("Volume %s unstaged from %s",
+		req.GetVolumeId(),
+		stagingPath)
+
+	return &csi.NodeUnstageVolumeResponse{}, nil
+}
+
+// NodeGetVolumeStats is a CSI API call which returns the bytes and inodes
+// used by the volume published or staged at the volume path. Drivers which
+// do not report the size used are asked through statfs of the volume path.
+func (s *OsdCsiServer) NodeGetVolumeStats(
+	ctx context.Context,
+	req *csi.NodeGetVolumeStatsRequest,
+) (*csi.NodeGetVolumeStatsResponse, error) {
+
+	dlog.Debugf("NodeGetVolumeStats req[%#v]", req)
+
+	// Check arguments
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
+	}
+	if len(req.GetVolumePath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume path must be provided")
+	}
+
+	// Get volume information
+	v, err := util.VolumeFromName(s.driver, req.GetVolumeId())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Volume id %s not found: %s",
+			req.GetVolumeId(),
+			err.Error())
+	}
+
+	// The volume path must be a staging path or target path of the volume
+	source, ok := s.volumeMountSource(v, req.GetVolumePath())
+	if !ok {
+		return nil, status.Errorf(
+			codes.NotFound,
+			"Volume %s is not published at %s",
+			req.GetVolumeId(),
+			req.GetVolumePath())
+	}
+	if source == v.GetDevicePath() {
+		// Block volumes have no filesystem to report on
+		return &csi.NodeGetVolumeStatsResponse{
+			Usage: []*csi.VolumeUsage{
+				&csi.VolumeUsage{
+					Unit:  csi.VolumeUsage_BYTES,
+					Total: int64(v.GetSpec().GetSize()),
+				},
+			},
+		}, nil
+	}
+
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(req.GetVolumePath(), &fs); err != nil {
+		return nil, status.Errorf(
+			codes.Internal,
+			"Unable to get stats of %s: %s",
+			req.GetVolumePath(),
+			err.Error())
+	}
+	total := int64(fs.Blocks) * int64(fs.Bsize)
+	used := total - int64(fs.Bfree)*int64(fs.Bsize)
+	available := int64(fs.Bavail) * int64(fs.Bsize)
+
+	// Prefer the size and usage reported by the driver
+	usedSize, err := s.driver.UsedSize(v.GetId())
+	if err == nil {
+		total = int64(v.GetSpec().GetSize())
+		used = int64(usedSize)
+		available = total - used
+		if available < 0 {
+			available = 0
+		}
+	} else if err != volume.ErrNotSupported {
+		return nil, status.Errorf(
+			codes.Internal,
+			"Unable to get used size of volume %s: %s",
+			req.GetVolumeId(),
+			err.Error())
+	}
+
+	return &csi.NodeGetVolumeStatsResponse{
+		Usage: []*csi.VolumeUsage{
+			&csi.VolumeUsage{
+				Unit:      csi.VolumeUsage_BYTES,
+				Total:     total,
+				Used:      used,
+				Available: available,
+			},
+			&csi.VolumeUsage{
+				Unit:      csi.VolumeUsage_INODES,
+				Total:     int64(fs.Files),
+				Used:      int64(fs.Files - fs.Ffree),
+				Available: int64(fs.Ffree),
+			},
+		},
+	}, nil
+}
+
+// NodeExpandVolume is a CSI API call which grows the filesystem of a volume
+// expanded by ControllerExpandVolume to the size of its device. Only volumes
+// of block drivers, attached and mounted on this node, have a filesystem to
+// grow.
+func (s *OsdCsiServer) NodeExpandVolume(
+	ctx context.Context,
+	req *csi.NodeExpandVolumeRequest,
+) (*csi.NodeExpandVolumeResponse, error) {
+
+	dlog.Debugf("NodeExpandVolume req[%#v]", req)
+
+	// Check arguments
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume id must be provided")
+	}
+	if len(req.GetVolumePath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume path must be provided")
+	}
+
+	// Get volume information
+	v, err := util.VolumeFromName(s.driver, req.GetVolumeId())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Volume id %s not found: %s",
+			req.GetVolumeId(),
+			err.Error())
+	}
+
+	h := s.volumeLocks.Acquire(v.GetId())
+	defer s.volumeLocks.Release(&h)
+
+	source, ok := s.volumeMountSource(v, req.GetVolumePath())
+	if !ok {
+		return nil, status.Errorf(
+			codes.NotFound,
+			"Volume %s is not published at %s",
+			req.GetVolumeId(),
+			req.GetVolumePath())
+	}
+	if s.driver.Type() == api.DriverType_DRIVER_TYPE_BLOCK &&
+		len(v.GetDevicePath()) != 0 &&
+		source != v.GetDevicePath() {
+		err = common.GrowFilesystem(v.GetSpec().GetFormat(), v.GetDevicePath(), source)
+		if err != nil {
+			return nil, status.Errorf(
+				codes.Internal,
+				"Unable to grow filesystem of volume %s: %s",
+				req.GetVolumeId(),
+				err.Error())
+		}
+	}
+
+	dlog.Infof("Volume %s expanded on %s",
+		req.GetVolumeId(),
+		req.GetVolumePath())
+
+	return &csi.NodeExpandVolumeResponse{
+		CapacityBytes: int64(v.GetSpec().GetSize()),
+	}, nil
+}
+
+// volumeMountSource returns the path the volume is mounted at by the driver
+// for the staging or target path, or the device bind mounted onto the
+// target path of a block volume. It returns false if the volume is neither
+// staged nor published at path.
+func (s *OsdCsiServer) volumeMountSource(v *api.Volume, path string) (string, bool) {
+	s.loadStagedMounts(v)
+	if containsString(v.GetAttachPath(), path) {
+		return path, true
+	}
+	source, err := s.mounter.GetSourcePath(path)
+	if err != nil {
+		return "", false
+	}
+	if containsString(v.GetAttachPath(), source) ||
+		(len(v.GetDevicePath()) != 0 && source == v.GetDevicePath()) {
+		return source, true
+	}
+	return "", false
+}
+
+// createTargetDir creates the target path of a mounted volume unless it is
+// an existing directory. Its parent directory must exist.
+func createTargetDir(targetPath string) error {
+	err := os.Mkdir(targetPath, 0750)
+	if err == nil || !os.IsExist(err) {
+		return err
+	}
+	return verifyTargetLocation(targetPath)
+}
+
 func verifyTargetLocation(targetPath string) error {
 	fileInfo, err := os.Stat(targetPath)
 	if err != nil && os.IsNotExist(err) {
@@ -268,3 +957,31 @@ func verifyTargetLocation(targetPath string) error {
 
 	return nil
 }
+
+// createTargetFile creates the target path of a block volume unless it
+// is an existing file.
+func createTargetFile(targetPath string) error {
+	fileInfo, err := os.Stat(targetPath)
+	if err == nil {
+		if fileInfo.IsDir() {
+			return fmt.Errorf("Target location %s is a directory", targetPath)
+		}
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	f, err := os.OpenFile(targetPath, os.O_CREATE|os.O_EXCL, 0640)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func containsString(set []string, s string) bool {
+	for _, v := range set {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
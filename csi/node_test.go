@@ -18,18 +18,23 @@ package csi
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/golang/mock/gomock"
 	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/options"
+	"github.com/libopenstorage/openstorage/volume"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-func TestNewCSIServerGetNodeId(t *testing.T) {
+func TestNewCSIServerNodeGetInfo(t *testing.T) {
 
 	// Create server and client connection
 	s := newTestServer(t)
@@ -49,20 +54,21 @@ func TestNewCSIServerGetNodeId(t *testing.T) {
 		Times(1)
 
 	// Setup request
-	req := &csi.GetNodeIDRequest{
-		Version: &csi.Version{},
-	}
+	req := &csi.NodeGetInfoRequest{}
 
-	r, err := c.GetNodeID(context.Background(), req)
+	r, err := c.NodeGetInfo(context.Background(), req)
 	assert.Nil(t, err)
 	assert.NotNil(t, r)
 
 	// Verify
 	nodeid := r.GetNodeId()
 	assert.Equal(t, nodeid, "pwx-testnodeid")
+	assert.Equal(t,
+		map[string]string{topologyKeyNode: "pwx-testnodeid"},
+		r.GetAccessibleTopology().GetSegments())
 }
 
-func TestNewCSIServerGetNodeIdNoVersion(t *testing.T) {
+func TestNewCSIServerNodeGetInfoTopology(t *testing.T) {
 
 	// Create server and client connection
 	s := newTestServer(t)
@@ -71,20 +77,44 @@ func TestNewCSIServerGetNodeIdNoVersion(t *testing.T) {
 	// Make a call
 	c := csi.NewNodeClient(s.Conn())
 
-	// Setup request
-	req := &csi.GetNodeIDRequest{}
+	s.MockCluster().
+		EXPECT().
+		Enumerate().
+		Return(api.Cluster{
+			Status: api.Status_STATUS_OK,
+			Id:     "pwx-testcluster",
+			NodeId: "pwx-testnodeid",
+			Nodes: []api.Node{
+				api.Node{
+					Id:         "pwx-othernodeid",
+					NodeLabels: map[string]string{"zone": "zone2"},
+				},
+				api.Node{
+					Id: "pwx-testnodeid",
+					NodeLabels: map[string]string{
+						"zone":  "zone1",
+						"rack":  "rack1",
+						"other": "label",
+					},
+				},
+			},
+		}, nil).
+		Times(1)
 
-	// Expect error without version
-	_, err := c.GetNodeID(context.Background(), req)
+	r, err := c.NodeGetInfo(context.Background(), &csi.NodeGetInfoRequest{})
+	assert.Nil(t, err)
 
-	assert.NotNil(t, err)
-	serverError, ok := status.FromError(err)
-	assert.True(t, ok)
-	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
-	assert.Contains(t, serverError.Message(), "Version")
+	// Only the placement labels of the local node are reported
+	assert.Equal(t,
+		map[string]string{
+			topologyKeyNode:            "pwx-testnodeid",
+			topologyKeyPrefix + "zone": "zone1",
+			topologyKeyPrefix + "rack": "rack1",
+		},
+		r.GetAccessibleTopology().GetSegments())
 }
 
-func TestNewCSIServerGetNodeIdEnumerateError(t *testing.T) {
+func TestNewCSIServerNodeGetInfoEnumerateError(t *testing.T) {
 
 	// Create server and client connection
 	s := newTestServer(t)
@@ -100,12 +130,9 @@ func TestNewCSIServerGetNodeIdEnumerateError(t *testing.T) {
 		Times(1)
 
 	// Setup request
-	req := &csi.GetNodeIDRequest{
-		Version: &csi.Version{},
-	}
+	req := &csi.NodeGetInfoRequest{}
 
-	// Expect error without version
-	_, err := c.GetNodeID(context.Background(), req)
+	_, err := c.NodeGetInfo(context.Background(), req)
 
 	assert.NotNil(t, err)
 	serverError, ok := status.FromError(err)
@@ -126,27 +153,19 @@ func TestNodePublishVolumeBadArguments(t *testing.T) {
 		expectedErrorContains string
 		req                   *csi.NodePublishVolumeRequest
 	}{
-		{
-			expectedErrorContains: "Version",
-			req: &csi.NodePublishVolumeRequest{},
-		},
 		{
 			expectedErrorContains: "Volume id",
-			req: &csi.NodePublishVolumeRequest{
-				Version: &csi.Version{},
-			},
+			req:                   &csi.NodePublishVolumeRequest{},
 		},
 		{
 			expectedErrorContains: "Target path",
 			req: &csi.NodePublishVolumeRequest{
-				Version:  &csi.Version{},
 				VolumeId: "abc",
 			},
 		},
 		{
 			expectedErrorContains: "Volume access mode",
 			req: &csi.NodePublishVolumeRequest{
-				Version:    &csi.Version{},
 				VolumeId:   "abc",
 				TargetPath: "mypath",
 			},
@@ -154,7 +173,6 @@ func TestNodePublishVolumeBadArguments(t *testing.T) {
 		{
 			expectedErrorContains: "Volume access mode",
 			req: &csi.NodePublishVolumeRequest{
-				Version:          &csi.Version{},
 				VolumeId:         "abc",
 				TargetPath:       "mypath",
 				VolumeCapability: &csi.VolumeCapability{},
@@ -197,7 +215,6 @@ func TestNodePublishVolumeVolumeNotFound(t *testing.T) {
 	)
 
 	req := &csi.NodePublishVolumeRequest{
-		Version:    &csi.Version{},
 		VolumeId:   name,
 		TargetPath: "mypath",
 		VolumeCapability: &csi.VolumeCapability{
@@ -240,7 +257,6 @@ func TestNodePublishVolumeBadAttribute(t *testing.T) {
 		Times(1)
 
 	req := &csi.NodePublishVolumeRequest{
-		Version:    &csi.Version{},
 		VolumeId:   name,
 		TargetPath: "mypath",
 		VolumeCapability: &csi.VolumeCapability{
@@ -248,7 +264,7 @@ func TestNodePublishVolumeBadAttribute(t *testing.T) {
 		},
 
 		// This will cause an error
-		VolumeAttributes: map[string]string{
+		VolumeContext: map[string]string{
 			api.SpecFilesystem: "whatkindoffsisthis?",
 		},
 	}
@@ -258,7 +274,7 @@ func TestNodePublishVolumeBadAttribute(t *testing.T) {
 	serverError, ok := status.FromError(err)
 	assert.True(t, ok)
 	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
-	assert.Contains(t, serverError.Message(), "Invalid volume attributes")
+	assert.Contains(t, serverError.Message(), "Invalid volume context")
 }
 
 func TestNodePublishVolumeInvalidTargetLocation(t *testing.T) {
@@ -271,7 +287,7 @@ func TestNodePublishVolumeInvalidTargetLocation(t *testing.T) {
 		targetPath            string
 	}{
 		{
-			expectedErrorContains: "does not exist",
+			expectedErrorContains: "no such file or directory",
 			targetPath:            "////a/sdf//fd/asdf/as/f/asdfasf/fds",
 		},
 		{
@@ -293,7 +309,6 @@ func TestNodePublishVolumeInvalidTargetLocation(t *testing.T) {
 		Times(len(testargs))
 
 	req := &csi.NodePublishVolumeRequest{
-		Version:  &csi.Version{},
 		VolumeId: name,
 		VolumeCapability: &csi.VolumeCapability{
 			AccessMode: &csi.VolumeCapability_AccessMode{},
@@ -350,7 +365,6 @@ func TestNodePublishVolumeFailedToAttach(t *testing.T) {
 	)
 
 	req := &csi.NodePublishVolumeRequest{
-		Version:    &csi.Version{},
 		VolumeId:   name,
 		TargetPath: "/mnt",
 		VolumeCapability: &csi.VolumeCapability{
@@ -406,7 +420,7 @@ func TestNodePublishVolumeFailedMount(t *testing.T) {
 			Times(1),
 		s.MockDriver().
 			EXPECT().
-			Mount(name, targetPath, nil).
+			Mount(name, s.StagingPath(name), nil).
 			Return(fmt.Errorf("MOUNT ERROR")).
 			Times(1),
 		s.MockDriver().
@@ -417,7 +431,6 @@ func TestNodePublishVolumeFailedMount(t *testing.T) {
 	)
 
 	req := &csi.NodePublishVolumeRequest{
-		Version:    &csi.Version{},
 		VolumeId:   name,
 		TargetPath: targetPath,
 		VolumeCapability: &csi.VolumeCapability{
@@ -434,6 +447,67 @@ func TestNodePublishVolumeFailedMount(t *testing.T) {
 	assert.Contains(t, serverError.Message(), "MOUNT ERROR")
 }
 
+func TestNodePublishVolumeFailedBindMount(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	// The volume is unstaged again when the bind mount fails
+	name := "myvol"
+	targetPath := s.TargetPath(t, "a")
+	s.mounts.err = fmt.Errorf("BIND ERROR")
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{name}).
+			Return([]*api.Volume{&api.Volume{Id: name}}, nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Attach(name, gomock.Any()).
+			Return("", nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Mount(name, s.StagingPath(name), nil).
+			Return(nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Unmount(name, s.StagingPath(name), nil).
+			Return(nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Detach(name, gomock.Any()).
+			Return(nil).
+			Times(1),
+	)
+
+	req := &csi.NodePublishVolumeRequest{
+		VolumeId:   name,
+		TargetPath: targetPath,
+		VolumeCapability: &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	}
+
+	_, err := c.NodePublishVolume(context.Background(), req)
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.Internal)
+	assert.Contains(t, serverError.Message(), "BIND ERROR")
+}
+
 func TestNodePublishVolumeMount(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
@@ -444,7 +518,7 @@ func TestNodePublishVolumeMount(t *testing.T) {
 
 	name := "myvol"
 	size := uint64(10)
-	targetPath := "/mnt"
+	targetPath := s.TargetPath(t, "a")
 	gomock.InOrder(
 		s.MockDriver().
 			EXPECT().
@@ -473,15 +547,104 @@ func TestNodePublishVolumeMount(t *testing.T) {
 			Times(1),
 		s.MockDriver().
 			EXPECT().
-			Mount(name, targetPath, nil).
+			Mount(name, s.StagingPath(name), nil).
+			Return(nil).
+			Times(1),
+	)
+
+	req := &csi.NodePublishVolumeRequest{
+		VolumeId:   name,
+		TargetPath: targetPath,
+		VolumeCapability: &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	}
+
+	r, err := c.NodePublishVolume(context.Background(), req)
+	assert.Nil(t, err)
+	assert.NotNil(t, r)
+
+	flags, ok := s.mounts.flags(targetPath)
+	assert.True(t, ok)
+	assert.Equal(t, uintptr(0), flags)
+}
+
+func TestNodePublishVolumeStaged(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	// A volume mounted at its staging path is only bind mounted
+	name := "myvol"
+	targetPath := s.TargetPath(t, "b")
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{name}).
+		Return([]*api.Volume{
+			&api.Volume{
+				Id:         name,
+				AttachPath: []string{s.StagingPath(name)},
+			},
+		}, nil).
+		Times(1)
+
+	req := &csi.NodePublishVolumeRequest{
+		VolumeId:   name,
+		TargetPath: targetPath,
+		Readonly:   true,
+		VolumeCapability: &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	}
+
+	r, err := c.NodePublishVolume(context.Background(), req)
+	assert.Nil(t, err)
+	assert.NotNil(t, r)
+
+	flags, ok := s.mounts.flags(targetPath)
+	assert.True(t, ok)
+	assert.Equal(t, uintptr(syscall.MS_RDONLY), flags)
+}
+
+func TestNodePublishVolumeControllerPublished(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	// The volume was attached by ControllerPublishVolume, so it is only
+	// mounted here
+	name := "myvol"
+	targetPath := s.TargetPath(t, "a")
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{name}).
+			Return([]*api.Volume{&api.Volume{Id: name}}, nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Mount(name, s.StagingPath(name), nil).
 			Return(nil).
 			Times(1),
 	)
 
 	req := &csi.NodePublishVolumeRequest{
-		Version:    &csi.Version{},
 		VolumeId:   name,
 		TargetPath: targetPath,
+		PublishContext: map[string]string{
+			publishInfoDevicePath: "/dev/myvol",
+		},
 		VolumeCapability: &csi.VolumeCapability{
 			AccessMode: &csi.VolumeCapability_AccessMode{},
 		},
@@ -492,6 +655,113 @@ func TestNodePublishVolumeMount(t *testing.T) {
 	assert.NotNil(t, r)
 }
 
+func TestNodePublishVolumeBlockOnFileDriver(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	name := "myvol"
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{name}).
+			Return([]*api.Volume{&api.Volume{Id: name}}, nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_FILE).
+			Times(1),
+	)
+
+	_, err := c.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+		VolumeId:   name,
+		TargetPath: filepath.Join(s.TargetPath(t, "a"), "dev"),
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Block{
+				Block: &csi.VolumeCapability_BlockVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
+	assert.Contains(t, serverError.Message(), volumeCapabilityMessageBlockNotSupported)
+}
+
+func TestNodePublishVolumeBlock(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	// Publish the device on two target files. It is attached once, and
+	// detached when the last target file is unpublished.
+	name := "myvol"
+	devicePath := "/dev/myvol"
+	targetA := filepath.Join(s.TargetPath(t, "a"), "dev")
+	targetB := filepath.Join(s.TargetPath(t, "b"), "dev")
+	detached := &api.Volume{Id: name}
+	attached := &api.Volume{
+		Id:         name,
+		State:      api.VolumeState_VOLUME_STATE_ATTACHED,
+		DevicePath: devicePath,
+	}
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{detached}, nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_BLOCK),
+		s.MockDriver().EXPECT().Attach(name, gomock.Any()).Return(devicePath, nil),
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{attached}, nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_BLOCK),
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{attached}, nil),
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{attached}, nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_BLOCK),
+		s.MockDriver().EXPECT().Detach(name, gomock.Any()).Return(nil),
+	)
+
+	for _, target := range []string{targetA, targetB} {
+		_, err := c.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+			VolumeId:   name,
+			TargetPath: target,
+			Readonly:   target == targetB,
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Block{
+					Block: &csi.VolumeCapability_BlockVolume{},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{},
+			},
+		})
+		assert.Nil(t, err)
+
+		fileInfo, err := os.Stat(target)
+		assert.Nil(t, err)
+		assert.False(t, fileInfo.IsDir())
+	}
+	flags, ok := s.mounts.flags(targetB)
+	assert.True(t, ok)
+	assert.Equal(t, uintptr(syscall.MS_RDONLY), flags)
+	assert.Equal(t, 2, s.server.(*OsdCsiServer).mounter.HasMounts(devicePath))
+
+	for _, target := range []string{targetA, targetB} {
+		_, err := c.NodeUnpublishVolume(context.Background(), &csi.NodeUnpublishVolumeRequest{
+			VolumeId:   name,
+			TargetPath: target,
+		})
+		assert.Nil(t, err)
+
+		_, err = os.Stat(target)
+		assert.True(t, os.IsNotExist(err))
+	}
+	assert.Equal(t, 0, s.server.(*OsdCsiServer).mounter.HasMounts(devicePath))
+}
+
 func TestNodeUnpublishVolumeVolumeNotFound(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
@@ -517,7 +787,6 @@ func TestNodeUnpublishVolumeVolumeNotFound(t *testing.T) {
 	)
 
 	req := &csi.NodeUnpublishVolumeRequest{
-		Version:    &csi.Version{},
 		VolumeId:   name,
 		TargetPath: "mypath",
 	}
@@ -530,25 +799,11 @@ func TestNodeUnpublishVolumeVolumeNotFound(t *testing.T) {
 	assert.Contains(t, serverError.Message(), "not found")
 }
 
-func TestNodeUnpublishVolumeInvalidTargetLocation(t *testing.T) {
+func TestNodeUnpublishVolumeTargetGone(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
 	defer s.Stop()
 
-	testargs := []struct {
-		expectedErrorContains string
-		targetPath            string
-	}{
-		{
-			expectedErrorContains: "does not exist",
-			targetPath:            "////a/sdf//fd/asdf/as/f/asdfasf/fds",
-		},
-		{
-			expectedErrorContains: "not a directory",
-			targetPath:            "/etc/hosts",
-		},
-	}
-
 	c := csi.NewNodeClient(s.Conn())
 	name := "myvol"
 	s.MockDriver().
@@ -559,21 +814,19 @@ func TestNodeUnpublishVolumeInvalidTargetLocation(t *testing.T) {
 				Id: name,
 			},
 		}, nil).
-		Times(len(testargs))
+		Times(2)
 
+	// Unpublishing from a missing or non directory target path succeeds
 	req := &csi.NodeUnpublishVolumeRequest{
-		Version:  &csi.Version{},
 		VolumeId: name,
 	}
-
-	for _, testarg := range testargs {
-		req.TargetPath = testarg.targetPath
+	for _, targetPath := range []string{
+		"////a/sdf//fd/asdf/as/f/asdfasf/fds",
+		"/etc/hosts",
+	} {
+		req.TargetPath = targetPath
 		_, err := c.NodeUnpublishVolume(context.Background(), req)
-		assert.NotNil(t, err)
-		serverError, ok := status.FromError(err)
-		assert.True(t, ok)
-		assert.Equal(t, serverError.Code(), codes.NotFound)
-		assert.Contains(t, serverError.Message(), testarg.expectedErrorContains)
+		assert.Nil(t, err)
 	}
 }
 
@@ -586,35 +839,33 @@ func TestNodeUnpublishVolumeFailedToUnmount(t *testing.T) {
 	c := csi.NewNodeClient(s.Conn())
 
 	name := "myvol"
-	targetPath := "/mnt"
-	size := uint64(10)
+	targetPath := s.TargetPath(t, "a")
 	gomock.InOrder(
 		s.MockDriver().
 			EXPECT().
 			Inspect([]string{name}).
 			Return([]*api.Volume{
 				&api.Volume{
-					Id: name,
-					Locator: &api.VolumeLocator{
-						Name: name,
-					},
-					Spec: &api.VolumeSpec{
-						Size: size,
-					},
+					Id:         name,
+					AttachPath: []string{s.StagingPath(name)},
 				},
 			}, nil).
 			Times(1),
 		s.MockDriver().
 			EXPECT().
-			Unmount(name, targetPath, nil).
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Unmount(name, s.StagingPath(name), nil).
 			Return(fmt.Errorf("TEST")).
 			Times(1),
 	)
 
 	req := &csi.NodeUnpublishVolumeRequest{
-		Version:    &csi.Version{},
 		VolumeId:   name,
-		TargetPath: "/mnt",
+		TargetPath: targetPath,
 	}
 
 	_, err := c.NodeUnpublishVolume(context.Background(), req)
@@ -635,34 +886,28 @@ func TestNodeUnpublishVolumeFailedDetach(t *testing.T) {
 	c := csi.NewNodeClient(s.Conn())
 
 	name := "myvol"
-	size := uint64(10)
-	targetPath := "/mnt"
+	targetPath := s.TargetPath(t, "a")
 	gomock.InOrder(
 		s.MockDriver().
 			EXPECT().
 			Inspect([]string{name}).
 			Return([]*api.Volume{
 				&api.Volume{
-					Id: name,
-					Locator: &api.VolumeLocator{
-						Name: name,
-					},
-					Spec: &api.VolumeSpec{
-						Size: size,
-					},
+					Id:         name,
+					AttachPath: []string{s.StagingPath(name)},
 				},
 			}, nil).
 			Times(1),
-		s.MockDriver().
-			EXPECT().
-			Unmount(name, targetPath, nil).
-			Return(nil).
-			Times(1),
 		s.MockDriver().
 			EXPECT().
 			Type().
 			Return(api.DriverType_DRIVER_TYPE_BLOCK).
 			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Unmount(name, s.StagingPath(name), nil).
+			Return(nil).
+			Times(1),
 		s.MockDriver().
 			EXPECT().
 			Detach(name, gomock.Any()).
@@ -671,7 +916,6 @@ func TestNodeUnpublishVolumeFailedDetach(t *testing.T) {
 	)
 
 	req := &csi.NodeUnpublishVolumeRequest{
-		Version:    &csi.Version{},
 		VolumeId:   name,
 		TargetPath: targetPath,
 	}
@@ -693,22 +937,72 @@ func TestNodeUnpublishVolumeUnmount(t *testing.T) {
 	// Make a call
 	c := csi.NewNodeClient(s.Conn())
 
+	// Publish the volume on two target paths. It is staged once, and
+	// unstaged when the last target path is unpublished.
 	name := "myvol"
-	size := uint64(10)
-	targetPath := "/mnt"
+	stagingPath := s.StagingPath(name)
+	targetA := s.TargetPath(t, "a")
+	targetB := s.TargetPath(t, "b")
+	unstaged := &api.Volume{Id: name}
+	staged := &api.Volume{Id: name, AttachPath: []string{stagingPath}}
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{unstaged}, nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_BLOCK),
+		s.MockDriver().EXPECT().Attach(name, gomock.Any()).Return("", nil),
+		s.MockDriver().EXPECT().Mount(name, stagingPath, nil).Return(nil),
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{staged}, nil),
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{staged}, nil),
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{staged}, nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_BLOCK),
+		s.MockDriver().EXPECT().Unmount(name, stagingPath, nil).Return(nil),
+		s.MockDriver().EXPECT().Detach(name, gomock.Any()).Return(nil),
+	)
+
+	for _, target := range []string{targetA, targetB} {
+		_, err := c.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+			VolumeId:   name,
+			TargetPath: target,
+			VolumeCapability: &csi.VolumeCapability{
+				AccessMode: &csi.VolumeCapability_AccessMode{},
+			},
+		})
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 2, s.server.(*OsdCsiServer).mounter.HasMounts(stagingPath))
+
+	for _, target := range []string{targetA, targetB} {
+		r, err := c.NodeUnpublishVolume(context.Background(), &csi.NodeUnpublishVolumeRequest{
+			VolumeId:   name,
+			TargetPath: target,
+		})
+		assert.Nil(t, err)
+		assert.NotNil(t, r)
+		_, mounted := s.mounts.flags(target)
+		assert.False(t, mounted)
+	}
+	assert.Equal(t, 0, s.server.(*OsdCsiServer).mounter.HasMounts(stagingPath))
+}
+
+func TestNodeUnpublishVolumeUnstaged(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	// Volumes mounted by the driver directly at the target path are
+	// unmounted from there
+	name := "myvol"
+	targetPath := s.TargetPath(t, "a")
 	gomock.InOrder(
 		s.MockDriver().
 			EXPECT().
 			Inspect([]string{name}).
 			Return([]*api.Volume{
 				&api.Volume{
-					Id: name,
-					Locator: &api.VolumeLocator{
-						Name: name,
-					},
-					Spec: &api.VolumeSpec{
-						Size: size,
-					},
+					Id:         name,
+					AttachPath: []string{targetPath},
 				},
 			}, nil).
 			Times(1),
@@ -730,7 +1024,6 @@ func TestNodeUnpublishVolumeUnmount(t *testing.T) {
 	)
 
 	req := &csi.NodeUnpublishVolumeRequest{
-		Version:    &csi.Version{},
 		VolumeId:   name,
 		TargetPath: targetPath,
 	}
@@ -740,7 +1033,7 @@ func TestNodeUnpublishVolumeUnmount(t *testing.T) {
 	assert.NotNil(t, r)
 }
 
-func TestNodeProbe(t *testing.T) {
+func TestNodeUnpublishVolumeControllerPublished(t *testing.T) {
 	// Create server and client connection
 	s := newTestServer(t)
 	defer s.Stop()
@@ -748,17 +1041,45 @@ func TestNodeProbe(t *testing.T) {
 	// Make a call
 	c := csi.NewNodeClient(s.Conn())
 
-	// No version
-	_, err := c.NodeProbe(context.Background(), &csi.NodeProbeRequest{})
-	assert.Error(t, err)
+	// Volumes attached by ControllerPublishVolume are only unmounted
+	name := "myvol"
+	targetPath := s.TargetPath(t, "a")
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{name}).
+			Return([]*api.Volume{
+				&api.Volume{
+					Id:         name,
+					State:      api.VolumeState_VOLUME_STATE_ATTACHED,
+					AttachedOn: "node1",
+					AttachPath: []string{s.StagingPath(name)},
+					AttachInfo: map[string]string{
+						options.OptionsAttachNode: "node1",
+					},
+				},
+			}, nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Unmount(name, s.StagingPath(name), nil).
+			Return(nil).
+			Times(1),
+	)
 
-	// Get Capabilities
-	_, err = c.NodeProbe(
-		context.Background(),
-		&csi.NodeProbeRequest{
-			Version: &csi.Version{},
-		})
-	assert.NoError(t, err)
+	req := &csi.NodeUnpublishVolumeRequest{
+		VolumeId:   name,
+		TargetPath: targetPath,
+	}
+
+	r, err := c.NodeUnpublishVolume(context.Background(), req)
+	assert.Nil(t, err)
+	assert.NotNil(t, r)
 }
 
 func TestNodeGetCapabilities(t *testing.T) {
@@ -769,22 +1090,440 @@ func TestNodeGetCapabilities(t *testing.T) {
 	// Make a call
 	c := csi.NewNodeClient(s.Conn())
 
-	// No version
-	_, err := c.NodeGetCapabilities(
-		context.Background(),
-		&csi.NodeGetCapabilitiesRequest{})
-	assert.Error(t, err)
-
 	// Get Capabilities
 	r, err := c.NodeGetCapabilities(
 		context.Background(),
-		&csi.NodeGetCapabilitiesRequest{
-			Version: &csi.Version{},
-		})
+		&csi.NodeGetCapabilitiesRequest{})
 	assert.NoError(t, err)
-	assert.Len(t, r.GetCapabilities(), 1)
-	assert.Equal(
-		t,
-		csi.NodeServiceCapability_RPC_UNKNOWN,
+	assert.Len(t, r.GetCapabilities(), 3)
+	assert.Equal(t,
+		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
 		r.GetCapabilities()[0].GetRpc().GetType())
+	assert.Equal(t,
+		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+		r.GetCapabilities()[1].GetRpc().GetType())
+	assert.Equal(t,
+		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+		r.GetCapabilities()[2].GetRpc().GetType())
+}
+
+func TestNodeStageVolumeBadArguments(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	req := &csi.NodeStageVolumeRequest{}
+	_, err := c.NodeStageVolume(context.Background(), req)
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
+	assert.Contains(t, serverError.Message(), "Volume id")
+
+	req.VolumeId = "myvol"
+	_, err = c.NodeStageVolume(context.Background(), req)
+	assert.NotNil(t, err)
+	serverError, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
+	assert.Contains(t, serverError.Message(), "Staging target path")
+
+	req.StagingTargetPath = s.StagingPath("myvol")
+	_, err = c.NodeStageVolume(context.Background(), req)
+	assert.NotNil(t, err)
+	serverError, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.InvalidArgument)
+	assert.Contains(t, serverError.Message(), "access mode")
+}
+
+func TestNodeStageVolume(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	// The volume is attached and mounted at the staging path of the CO,
+	// and only bind mounted from there by NodePublishVolume
+	name := "myvol"
+	stagingPath := s.TargetPath(t, "staging")
+	targetPath := s.TargetPath(t, "a")
+	unstaged := &api.Volume{Id: name}
+	staged := &api.Volume{
+		Id:         name,
+		State:      api.VolumeState_VOLUME_STATE_ATTACHED,
+		AttachPath: []string{stagingPath},
+	}
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{unstaged}, nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_BLOCK),
+		s.MockDriver().EXPECT().Attach(name, gomock.Any()).Return("", nil),
+		s.MockDriver().EXPECT().Mount(name, stagingPath, nil).Return(nil),
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{staged}, nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_BLOCK),
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{staged}, nil),
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{staged}, nil),
+	)
+
+	capability := &csi.VolumeCapability{
+		AccessMode: &csi.VolumeCapability_AccessMode{},
+	}
+	_, err := c.NodeStageVolume(context.Background(), &csi.NodeStageVolumeRequest{
+		VolumeId:          name,
+		StagingTargetPath: stagingPath,
+		VolumeCapability:  capability,
+	})
+	assert.Nil(t, err)
+
+	// Staging again succeeds without mounting again
+	_, err = c.NodeStageVolume(context.Background(), &csi.NodeStageVolumeRequest{
+		VolumeId:          name,
+		StagingTargetPath: stagingPath,
+		VolumeCapability:  capability,
+	})
+	assert.Nil(t, err)
+
+	_, err = c.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+		VolumeId:          name,
+		StagingTargetPath: stagingPath,
+		TargetPath:        targetPath,
+		VolumeCapability:  capability,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, s.server.(*OsdCsiServer).mounter.HasMounts(stagingPath))
+
+	// Unpublishing keeps the volume staged
+	_, err = c.NodeUnpublishVolume(context.Background(), &csi.NodeUnpublishVolumeRequest{
+		VolumeId:   name,
+		TargetPath: targetPath,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, s.server.(*OsdCsiServer).mounter.HasMounts(stagingPath))
+}
+
+func TestNodePublishVolumeNotStaged(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	name := "myvol"
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{name}).
+		Return([]*api.Volume{&api.Volume{Id: name}}, nil).
+		Times(1)
+
+	_, err := c.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+		VolumeId:          name,
+		StagingTargetPath: s.TargetPath(t, "staging"),
+		TargetPath:        s.TargetPath(t, "a"),
+		VolumeCapability: &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.FailedPrecondition)
+	assert.Contains(t, serverError.Message(), "not staged")
+}
+
+func TestNodeUnstageVolumeStillPublished(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	name := "myvol"
+	stagingPath := s.TargetPath(t, "staging")
+	targetPath := s.TargetPath(t, "a")
+	staged := &api.Volume{
+		Id:         name,
+		State:      api.VolumeState_VOLUME_STATE_ATTACHED,
+		AttachPath: []string{stagingPath},
+	}
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{name}).
+		Return([]*api.Volume{staged}, nil).
+		Times(2)
+	s.MockDriver().
+		EXPECT().
+		Type().
+		Return(api.DriverType_DRIVER_TYPE_BLOCK).
+		AnyTimes()
+
+	_, err := c.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+		VolumeId:          name,
+		StagingTargetPath: stagingPath,
+		TargetPath:        targetPath,
+		VolumeCapability: &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	})
+	assert.Nil(t, err)
+
+	_, err = c.NodeUnstageVolume(context.Background(), &csi.NodeUnstageVolumeRequest{
+		VolumeId:          name,
+		StagingTargetPath: stagingPath,
+	})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, serverError.Code(), codes.FailedPrecondition)
+}
+
+func TestNodeUnstageVolume(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	// The volume is unmounted from the staging path and detached
+	name := "myvol"
+	stagingPath := s.TargetPath(t, "staging")
+	gomock.InOrder(
+		s.MockDriver().
+			EXPECT().
+			Inspect([]string{name}).
+			Return([]*api.Volume{
+				&api.Volume{
+					Id:         name,
+					State:      api.VolumeState_VOLUME_STATE_ATTACHED,
+					AttachPath: []string{stagingPath},
+				},
+			}, nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Unmount(name, stagingPath, nil).
+			Return(nil).
+			Times(1),
+		s.MockDriver().
+			EXPECT().
+			Detach(name, gomock.Any()).
+			Return(nil).
+			Times(1),
+	)
+
+	r, err := c.NodeUnstageVolume(context.Background(), &csi.NodeUnstageVolumeRequest{
+		VolumeId:          name,
+		StagingTargetPath: stagingPath,
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, r)
+}
+
+func TestNodeGetVolumeStatsBadArguments(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	_, err := c.NodeGetVolumeStats(context.Background(), &csi.NodeGetVolumeStatsRequest{
+		VolumePath: "some/path",
+	})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, serverError.Code())
+	assert.Contains(t, serverError.Message(), "Volume id")
+
+	_, err = c.NodeGetVolumeStats(context.Background(), &csi.NodeGetVolumeStatsRequest{
+		VolumeId: "myvol",
+	})
+	assert.NotNil(t, err)
+	serverError, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, serverError.Code())
+	assert.Contains(t, serverError.Message(), "Volume path")
+}
+
+func TestNodeGetVolumeStatsNotPublished(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	name := "myvol"
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{name}).
+		Return([]*api.Volume{
+			&api.Volume{
+				Id:         name,
+				AttachPath: []string{s.StagingPath(name)},
+			},
+		}, nil).
+		Times(1)
+
+	_, err := c.NodeGetVolumeStats(context.Background(), &csi.NodeGetVolumeStatsRequest{
+		VolumeId:   name,
+		VolumePath: "some/path",
+	})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, serverError.Code())
+}
+
+func TestNodeGetVolumeStats(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	// The size used is reported by the driver, and inodes by statfs
+	name := "myvol"
+	stagingPath := s.TargetPath(t, "staging")
+	v := &api.Volume{
+		Id:         name,
+		AttachPath: []string{stagingPath},
+		Spec:       &api.VolumeSpec{Size: 1000},
+	}
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{v}, nil),
+		s.MockDriver().EXPECT().UsedSize(name).Return(uint64(400), nil),
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{v}, nil),
+		s.MockDriver().EXPECT().UsedSize(name).Return(uint64(0), volume.ErrNotSupported),
+	)
+
+	r, err := c.NodeGetVolumeStats(context.Background(), &csi.NodeGetVolumeStatsRequest{
+		VolumeId:   name,
+		VolumePath: stagingPath,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, r.GetUsage(), 2)
+	assert.Equal(t, csi.VolumeUsage_BYTES, r.GetUsage()[0].GetUnit())
+	assert.Equal(t, int64(1000), r.GetUsage()[0].GetTotal())
+	assert.Equal(t, int64(400), r.GetUsage()[0].GetUsed())
+	assert.Equal(t, int64(600), r.GetUsage()[0].GetAvailable())
+	assert.Equal(t, csi.VolumeUsage_INODES, r.GetUsage()[1].GetUnit())
+	assert.NotZero(t, r.GetUsage()[1].GetTotal())
+
+	// Drivers which do not report the size used fall back to statfs
+	var fs syscall.Statfs_t
+	assert.NoError(t, syscall.Statfs(stagingPath, &fs))
+	r, err = c.NodeGetVolumeStats(context.Background(), &csi.NodeGetVolumeStatsRequest{
+		VolumeId:   name,
+		VolumePath: stagingPath,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(fs.Blocks)*int64(fs.Bsize), r.GetUsage()[0].GetTotal())
+}
+
+func TestNodeExpandVolume(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	// Volumes without a filesystem have nothing to grow
+	name := "myvol"
+	stagingPath := s.TargetPath(t, "staging")
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{
+			&api.Volume{
+				Id:         name,
+				DevicePath: "/dev/myvol",
+				AttachPath: []string{stagingPath},
+				Spec: &api.VolumeSpec{
+					Size:   2048,
+					Format: api.FSType_FS_TYPE_NONE,
+				},
+			},
+		}, nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_BLOCK),
+	)
+
+	r, err := c.NodeExpandVolume(context.Background(), &csi.NodeExpandVolumeRequest{
+		VolumeId:   name,
+		VolumePath: stagingPath,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2048), r.GetCapacityBytes())
+
+	// Volume paths must be a staging path or target path of the volume
+	s.MockDriver().
+		EXPECT().
+		Inspect([]string{name}).
+		Return([]*api.Volume{&api.Volume{Id: name}}, nil).
+		Times(1)
+
+	_, err = c.NodeExpandVolume(context.Background(), &csi.NodeExpandVolumeRequest{
+		VolumeId:   name,
+		VolumePath: stagingPath,
+	})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, serverError.Code())
+}
+
+func TestNodePublishVolumeBlockAttachedOnRemoteNode(t *testing.T) {
+	// Create server and client connection
+	s := newTestServer(t)
+	defer s.Stop()
+
+	// Make a call
+	c := csi.NewNodeClient(s.Conn())
+
+	// The device of a volume attached on another node cannot be used here
+	name := "myvol"
+	gomock.InOrder(
+		s.MockDriver().EXPECT().Inspect([]string{name}).Return([]*api.Volume{
+			&api.Volume{
+				Id:         name,
+				State:      api.VolumeState_VOLUME_STATE_ATTACHED,
+				AttachedOn: "node2",
+				DevicePath: "/dev/myvol",
+			},
+		}, nil),
+		s.MockDriver().EXPECT().Type().Return(api.DriverType_DRIVER_TYPE_BLOCK),
+		s.MockCluster().EXPECT().Enumerate().Return(api.Cluster{NodeId: "node1"}, nil),
+	)
+
+	_, err := c.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
+		VolumeId:   name,
+		TargetPath: filepath.Join(s.TargetPath(t, "a"), "dev"),
+		PublishContext: map[string]string{
+			publishInfoDevicePath: "/dev/myvol",
+		},
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Block{
+				Block: &csi.VolumeCapability_BlockVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{},
+		},
+	})
+	assert.NotNil(t, err)
+	serverError, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.FailedPrecondition, serverError.Code())
+	assert.Contains(t, serverError.Message(), "node2")
 }
@@ -0,0 +1,87 @@
+/*
+CSI Interface for OSD
+Copyright 2017 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package csi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	csisanity "github.com/kubernetes-csi/csi-test/pkg/sanity"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/libopenstorage/openstorage/api"
+	mockcluster "github.com/libopenstorage/openstorage/cluster/mock"
+	volumedrivers "github.com/libopenstorage/openstorage/volume/drivers"
+	"github.com/libopenstorage/openstorage/volume/drivers/fake"
+)
+
+// TestCSISanity runs the CSI sanity tests against a server of the fake
+// driver on a single node cluster.
+func TestCSISanity(t *testing.T) {
+	mc := gomock.NewController(t)
+	defer mc.Finish()
+	c := mockcluster.NewMockCluster(mc)
+	nodeID := "sanity-node"
+	c.EXPECT().
+		Enumerate().
+		Return(api.Cluster{NodeId: nodeID}, nil).
+		AnyTimes()
+	c.EXPECT().
+		Inspect(nodeID).
+		Return(api.Node{Id: nodeID}, nil).
+		AnyTimes()
+	c.EXPECT().
+		Inspect(gomock.Not(nodeID)).
+		Return(api.Node{}, fmt.Errorf("Node not found")).
+		AnyTimes()
+
+	assert.NoError(t, volumedrivers.Register(fake.Name, nil))
+	defer volumedrivers.Remove(fake.Name)
+
+	dir, err := ioutil.TempDir("", "osd-csi-sanity")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	server, err := NewOsdCsiServer(&OsdCsiServerConfig{
+		DriverName:  fake.Name,
+		Net:         "tcp",
+		Address:     "127.0.0.1:0",
+		Cluster:     c,
+		StagingPath: filepath.Join(dir, "staging"),
+	})
+	assert.NoError(t, err)
+
+	// Record bind mounts instead of mounting
+	osdServer := server.(*OsdCsiServer)
+	osdServer.mounter, err = newBindMounter(
+		osdServer.stagingPath,
+		&fakeMountImpl{mounts: make(map[string]uintptr)})
+	assert.NoError(t, err)
+
+	assert.NoError(t, server.Start())
+	defer server.Stop()
+
+	csisanity.Test(t, &csisanity.Config{
+		Address:     server.Address(),
+		TargetPath:  filepath.Join(dir, "target"),
+		StagingPath: filepath.Join(dir, "csi-staging"),
+	})
+}
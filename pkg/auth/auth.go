@@ -0,0 +1,165 @@
+// Package auth verifies the bearer tokens presented to the OSD REST API and
+// maps them to the roles that routes require.
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// Role is the level of access granted by a token. Each role includes the
+// access of the roles below it.
+type Role int
+
+const (
+	// RoleNone is required by routes that are open to everyone.
+	RoleNone Role = iota
+	// RoleViewer can read volumes, snapshots and cluster state.
+	RoleViewer
+	// RoleOperator can also create, change and delete volumes and snapshots.
+	RoleOperator
+	// RoleAdmin can also manage credentials, nodes and the cluster.
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleNone:     "none",
+	RoleViewer:   "viewer",
+	RoleOperator: "operator",
+	RoleAdmin:    "admin",
+}
+
+var (
+	// ErrNoToken is returned when a request carries no bearer token.
+	ErrNoToken = errors.New("Missing bearer token")
+	// ErrInvalidToken is returned for tokens that fail verification.
+	ErrInvalidToken = errors.New("Invalid token")
+	// ErrNoKey is returned when a JwtConfig has no key to verify tokens.
+	ErrNoKey = errors.New("No shared secret or public key configured")
+)
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// ParseRole returns the Role with the given name.
+func ParseRole(name string) (Role, error) {
+	for r, n := range roleNames {
+		if strings.EqualFold(n, name) {
+			return r, nil
+		}
+	}
+	return RoleNone, fmt.Errorf("Unknown role %q", name)
+}
+
+// Claims are the claims carried by an OSD token.
+type Claims struct {
+	jwt.StandardClaims
+	// Roles granted to the subject of the token.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Role returns the highest role among the claimed roles. Unknown role names
+// are ignored.
+func (c *Claims) Role() Role {
+	role := RoleNone
+	for _, name := range c.Roles {
+		if r, err := ParseRole(name); err == nil && r > role {
+			role = r
+		}
+	}
+	return role
+}
+
+// Authenticator verifies tokens.
+type Authenticator interface {
+	// AuthenticateToken verifies token and returns its claims.
+	AuthenticateToken(token string) (*Claims, error)
+}
+
+// JwtConfig configures the verification of JSON web tokens. HMAC signed
+// tokens are verified with SharedSecret and RSA signed tokens with the PEM
+// encoded public key in RsaPublicKeyFile. At least one of them must be set.
+type JwtConfig struct {
+	// SharedSecret verifies HS256, HS384 and HS512 tokens.
+	SharedSecret string `yaml:"shared_secret"`
+	// RsaPublicKeyFile verifies RS256, RS384 and RS512 tokens.
+	RsaPublicKeyFile string `yaml:"rsa_public_key_file"`
+	// Issuer, if set, must match the iss claim.
+	Issuer string `yaml:"issuer"`
+	// Audience, if set, must match the aud claim.
+	Audience string `yaml:"audience"`
+}
+
+type jwtAuthenticator struct {
+	config    JwtConfig
+	secret    []byte
+	publicKey *rsa.PublicKey
+}
+
+// NewJwtAuthenticator returns an Authenticator for JSON web tokens.
+func NewJwtAuthenticator(config *JwtConfig) (Authenticator, error) {
+	a := &jwtAuthenticator{config: *config}
+	if config.SharedSecret != "" {
+		a.secret = []byte(config.SharedSecret)
+	}
+	if config.RsaPublicKeyFile != "" {
+		pem, err := ioutil.ReadFile(config.RsaPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read public key %v: %v",
+				config.RsaPublicKeyFile, err)
+		}
+		if a.publicKey, err = jwt.ParseRSAPublicKeyFromPEM(pem); err != nil {
+			return nil, fmt.Errorf("Unable to parse public key %v: %v",
+				config.RsaPublicKeyFile, err)
+		}
+	}
+	if a.secret == nil && a.publicKey == nil {
+		return nil, ErrNoKey
+	}
+	return a, nil
+}
+
+func (a *jwtAuthenticator) AuthenticateToken(token string) (*Claims, error) {
+	if token == "" {
+		return nil, ErrNoToken
+	}
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, a.key)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidToken, err)
+	}
+	if a.config.Issuer != "" && !claims.VerifyIssuer(a.config.Issuer, true) {
+		return nil, fmt.Errorf("%v: unexpected issuer %q", ErrInvalidToken,
+			claims.Issuer)
+	}
+	if a.config.Audience != "" && !claims.VerifyAudience(a.config.Audience, true) {
+		return nil, fmt.Errorf("%v: unexpected audience %q", ErrInvalidToken,
+			claims.Audience)
+	}
+	return claims, nil
+}
+
+// key returns the key to verify token with. Only the signing methods for
+// which a key is configured are accepted.
+func (a *jwtAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if a.secret != nil {
+			return a.secret, nil
+		}
+	case *jwt.SigningMethodRSA:
+		if a.publicKey != nil {
+			return a.publicKey, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, claims *Claims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func TestRole(t *testing.T) {
+	r, err := ParseRole("Operator")
+	require.NoError(t, err)
+	require.Equal(t, RoleOperator, r)
+	_, err = ParseRole("root")
+	require.Error(t, err)
+
+	c := &Claims{Roles: []string{"viewer", "bogus", "admin"}}
+	require.Equal(t, RoleAdmin, c.Role())
+	require.Equal(t, RoleNone, (&Claims{}).Role())
+	require.True(t, RoleAdmin > RoleOperator && RoleOperator > RoleViewer)
+}
+
+func TestHMAC(t *testing.T) {
+	_, err := NewJwtAuthenticator(&JwtConfig{})
+	require.Equal(t, ErrNoKey, err)
+
+	a, err := NewJwtAuthenticator(&JwtConfig{
+		SharedSecret: "secret",
+		Issuer:       "osd-test",
+	})
+	require.NoError(t, err)
+
+	claims := &Claims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    "osd-test",
+			Subject:   "alice",
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+		Roles: []string{"operator"},
+	}
+	got, err := a.AuthenticateToken(sign(t, jwt.SigningMethodHS256, []byte("secret"), claims))
+	require.NoError(t, err)
+	require.Equal(t, "alice", got.Subject)
+	require.Equal(t, RoleOperator, got.Role())
+
+	_, err = a.AuthenticateToken("")
+	require.Equal(t, ErrNoToken, err)
+
+	_, err = a.AuthenticateToken(sign(t, jwt.SigningMethodHS256, []byte("wrong"), claims))
+	require.Error(t, err)
+
+	expired := *claims
+	expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	_, err = a.AuthenticateToken(sign(t, jwt.SigningMethodHS256, []byte("secret"), &expired))
+	require.Error(t, err)
+
+	other := *claims
+	other.Issuer = "someone-else"
+	_, err = a.AuthenticateToken(sign(t, jwt.SigningMethodHS256, []byte("secret"), &other))
+	require.Error(t, err)
+
+	none := sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims)
+	_, err = a.AuthenticateToken(none)
+	require.Error(t, err)
+}
+
+func TestRSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	f, err := ioutil.TempFile("", "osd-auth-test")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	require.NoError(t, pem.Encode(f, &pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	f.Close()
+
+	a, err := NewJwtAuthenticator(&JwtConfig{
+		RsaPublicKeyFile: f.Name(),
+		Audience:         "osd",
+	})
+	require.NoError(t, err)
+
+	claims := &Claims{
+		StandardClaims: jwt.StandardClaims{Audience: "osd"},
+		Roles:          []string{"viewer"},
+	}
+	got, err := a.AuthenticateToken(sign(t, jwt.SigningMethodRS256, key, claims))
+	require.NoError(t, err)
+	require.Equal(t, RoleViewer, got.Role())
+
+	// HMAC tokens are refused when no shared secret is configured, even
+	// if they are signed with the public key.
+	_, err = a.AuthenticateToken(sign(t, jwt.SigningMethodHS256, der, claims))
+	require.Error(t, err)
+
+	claims.Audience = "other"
+	_, err = a.AuthenticateToken(sign(t, jwt.SigningMethodRS256, key, claims))
+	require.Error(t, err)
+}
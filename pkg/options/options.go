@@ -21,6 +21,9 @@ const (
 	OptionsDeviceFuseMount = "DEV_FUSE_MOUNT"
 	// OptionsForceDetach Forcefully detach device from kernel
 	OptionsForceDetach = "FORCE_DETACH"
+	// OptionsAttachNode ID of the node a volume is attached on, when not
+	// the node handling the request
+	OptionsAttachNode = "ATTACH_NODE"
 )
 
 func IsBoolOptionSet(options map[string]string, key string) bool {
@@ -20,6 +20,7 @@ import (
 	"sync"
 	"testing"
 
+	csisanity "github.com/kubernetes-csi/csi-test/pkg/sanity"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -29,12 +30,24 @@ var (
 	lock       sync.Mutex
 )
 
-// Test will test the CSI driver at the specified address
+// Test will test the OSD at the specified address
 func Test(t *testing.T, address string) {
+	TestWithCSI(t, address, nil)
+}
+
+// TestWithCSI will test the OSD at the specified address, and its CSI
+// driver with the CSI sanity tests if csiConfig is set.
+func TestWithCSI(t *testing.T, address string, csiConfig *csisanity.Config) {
 	lock.Lock()
 	defer lock.Unlock()
 
 	osdAddress = address
+	if csiConfig != nil {
+		if csiConfig.IDGen == nil {
+			csiConfig.IDGen = &csisanity.DefaultIDGenerator{}
+		}
+		csisanity.GinkgoTest(csiConfig)
+	}
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "OSD API Test Suite")
 }
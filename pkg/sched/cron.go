@@ -0,0 +1,244 @@
+package sched
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CronType = "cron"
+	// cronTZPrefix optionally precedes a cron expression in the CLI form to
+	// give its time zone, e.g. "CRON_TZ=Europe/Berlin 30 2 * * 1-5".
+	cronTZPrefix = "CRON_TZ="
+	// cronSearchYears bounds the search for the next matching time. Any
+	// valid expression matches at least once in this many years.
+	cronSearchYears = 8
+)
+
+// cronField describes the values allowed in one field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMonthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	cronDayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+	cronFields = []cronField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: cronMonthNames},
+		// 7 is accepted as Sunday and folded into 0.
+		{name: "day of week", min: 0, max: 7, names: cronDayNames},
+	}
+)
+
+// cron is an Interval described by a standard 5 field cron expression
+// evaluated in a given time zone.
+type cron struct {
+	expr string
+	loc  *time.Location
+	// minute, hour, dom, month and dow are bit sets of the matching values.
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are true if the field is "*". When both day
+	// fields are restricted a day matches if either of them does.
+	domStar, dowStar bool
+}
+
+func (c cron) nextAfter(t time.Time) time.Time {
+	t = t.In(c.loc)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0,
+		c.loc).Add(time.Minute)
+	end := t.AddDate(cronSearchYears, 0, 0)
+	for t.Before(end) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0,
+				0, c.loc)
+			if !next.After(t) {
+				// Repeated hour at the end of daylight saving time.
+				next = t.Add(time.Hour)
+			}
+			t = next
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	// Unreachable for expressions accepted by Cron.
+	return end
+}
+
+func (c cron) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func (c cron) String() string {
+	return fmt.Sprintf("%s %s (%s)", CronType, c.expr, c.loc)
+}
+
+func (c cron) IntervalType() string {
+	return CronType
+}
+
+func (c cron) Spec() IntervalSpec {
+	return IntervalSpec{Freq: CronType, Cron: c.expr, Location: c.loc.String()}
+}
+
+// Cron returns an Interval that fires at the times matched by the 5 field
+// cron expression expr (minute, hour, day of month, month, day of week) in
+// time zone loc. Fields accept "*", values, ranges "a-b", steps "*/n" and
+// "a-b/n", and comma separated lists of these. Months and days of the week
+// may also be given by their three letter English names. A nil loc means
+// local time.
+func Cron(expr string, loc *time.Location) (Interval, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("Invalid cron expression %q: expected %d fields",
+			expr, len(cronFields))
+	}
+	sets := make([]uint64, len(fields))
+	for i, f := range fields {
+		set, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid cron expression %q: %v", expr, err)
+		}
+		sets[i] = set
+	}
+	// Fold Sunday as 7 into 0.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = (sets[4] &^ (1 << 7)) | 1
+	}
+	c := cron{
+		expr:    strings.Join(fields, " "),
+		loc:     loc,
+		minute:  sets[0],
+		hour:    sets[1],
+		dom:     sets[2],
+		month:   sets[3],
+		dow:     sets[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}
+	now := time.Now()
+	if !c.nextAfter(now).Before(now.AddDate(cronSearchYears, 0, 0)) {
+		return nil, fmt.Errorf("Cron expression %q never matches", expr)
+	}
+	return c, nil
+}
+
+func parseCronField(field string, desc cronField) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			rng = item[:i]
+			s, err := strconv.Atoi(item[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("invalid step in %s %q", desc.name, item)
+			}
+			step = s
+		}
+		lo, hi := desc.min, desc.max
+		if rng != "*" {
+			var err error
+			bounds := strings.SplitN(rng, "-", 2)
+			if lo, err = cronValue(bounds[0], desc); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = cronValue(bounds[1], desc); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// "a/n" means every n starting at a.
+				hi = desc.max
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("invalid range in %s %q", desc.name, item)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func cronValue(s string, desc cronField) (int, error) {
+	if v, ok := desc.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < desc.min || v > desc.max {
+		return 0, fmt.Errorf("invalid %s %q", desc.name, s)
+	}
+	return v, nil
+}
+
+// cronLocation returns the time zone named in an IntervalSpec.
+func cronLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// parseCron item [CRON_TZ=location ]min hour dom month dow[ r]
+func parseCron(cronStr string) (RetainIntervalSpec, error) {
+	r := RetainIntervalSpec{}
+	fields := strings.Fields(cronStr)
+	loc := time.Local
+	if len(fields) > 0 && strings.HasPrefix(fields[0], cronTZPrefix) {
+		var err error
+		loc, err = time.LoadLocation(strings.TrimPrefix(fields[0], cronTZPrefix))
+		if err != nil {
+			return r, fmt.Errorf("Invalid time zone in %s: %v", cronStr, err)
+		}
+		fields = fields[1:]
+	}
+	if len(fields) == len(cronFields)+1 {
+		retain, err := strconv.Atoi(fields[len(cronFields)])
+		if err != nil {
+			return r, fmt.Errorf("Invalid number: %s", fields[len(cronFields)])
+		} else if retain <= 0 {
+			return r, fmt.Errorf("Keep number should be greater than 0")
+		}
+		r.Retain = uint32(retain)
+		fields = fields[:len(cronFields)]
+	}
+	iv, err := Cron(strings.Join(fields, " "), loc)
+	if err != nil {
+		return RetainIntervalSpec{}, err
+	}
+	r.IntervalSpec = iv.Spec()
+	return r, nil
+}
@@ -0,0 +1,136 @@
+package sched
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCronNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		expr string
+		loc  *time.Location
+		from time.Time
+		next time.Time
+	}{
+		{"*/15 * * * *", time.UTC,
+			time.Date(2018, 3, 1, 10, 7, 30, 0, time.UTC),
+			time.Date(2018, 3, 1, 10, 15, 0, 0, time.UTC)},
+		{"0 2 * * 1-5", time.UTC,
+			time.Date(2018, 3, 2, 2, 0, 0, 0, time.UTC), // Friday
+			time.Date(2018, 3, 5, 2, 0, 0, 0, time.UTC)},
+		{"30 22 1,15 * *", time.UTC,
+			time.Date(2018, 3, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, 3, 15, 22, 30, 0, 0, time.UTC)},
+		{"0 0 1 jan-mar/2 *", time.UTC,
+			time.Date(2018, 1, 5, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * sun", time.UTC,
+			time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, 3, 4, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.UTC,
+			time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, 3, 4, 12, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 13th or any Friday.
+		{"0 0 13 * 5", time.UTC,
+			time.Date(2018, 3, 3, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.UTC,
+			time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// 2am New York is 7am UTC in winter and 6am UTC in summer.
+		{"0 2 * * *", ny,
+			time.Date(2018, 3, 1, 8, 0, 0, 0, time.UTC),
+			time.Date(2018, 3, 2, 7, 0, 0, 0, time.UTC)},
+		{"0 2 * * *", ny,
+			time.Date(2018, 6, 1, 8, 0, 0, 0, time.UTC),
+			time.Date(2018, 6, 2, 6, 0, 0, 0, time.UTC)},
+		// 2:30am does not exist on the day daylight saving time starts.
+		{"30 2 * * *", ny,
+			time.Date(2018, 3, 10, 12, 0, 0, 0, time.UTC),
+			time.Date(2018, 3, 12, 6, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		iv, err := Cron(tt.expr, tt.loc)
+		require.NoError(t, err, tt.expr)
+		require.True(t, tt.next.Equal(iv.nextAfter(tt.from)),
+			"%s after %v: got %v, want %v", tt.expr, tt.from,
+			iv.nextAfter(tt.from), tt.next)
+	}
+}
+
+func TestCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *",
+		"* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *",
+		"*/0 * * * *", "a * * * *", "* * * foo *", "0 0 30 2 *",
+	} {
+		_, err := Cron(expr, time.UTC)
+		require.Error(t, err, "%q parsed as valid", expr)
+	}
+}
+
+func TestCronRoundTrip(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	iv, err := Cron("0  3 * * mon-fri", berlin)
+	require.NoError(t, err)
+	intv := &RetainIntervalImpl{iv: iv, retain: 10}
+
+	// IntervalSpec YAML.
+	out, err := yaml.Marshal([]RetainIntervalSpec{intv.RetainIntervalSpec()})
+	require.NoError(t, err)
+	var specs []RetainIntervalSpec
+	require.NoError(t, yaml.Unmarshal(out, &specs))
+	require.Len(t, specs, 1)
+	require.Equal(t, CronType, specs[0].Freq)
+	require.Equal(t, "0 3 * * mon-fri", specs[0].Cron)
+	require.Equal(t, "Europe/Berlin", specs[0].Location)
+	require.Equal(t, uint32(10), specs[0].Retain)
+
+	// ScheduleString and ParseSchedule.
+	str, err := ScheduleStringRetainInv([]RetainInterval{intv}, nil)
+	require.NoError(t, err)
+	parsed, err := ParseSchedule(str)
+	require.NoError(t, err)
+	require.Len(t, parsed, 1)
+	require.Equal(t, intv.RetainIntervalSpec(), parsed[0].RetainIntervalSpec())
+	from := time.Date(2018, 3, 3, 0, 0, 0, 0, time.UTC)
+	require.True(t, iv.nextAfter(from).Equal(parsed[0].nextAfter(from)))
+
+	require.Equal(t, "cron 0 3 * * mon-fri (Europe/Berlin),keep last 10",
+		ScheduleSummary(parsed, nil))
+
+	// CLI form, mixed with other schedules and policies.
+	intvs, policies, err := ParseScheduleAndPolicies(
+		"cron=CRON_TZ=Europe/Berlin 0 3 * * mon-fri 10;daily=10:43,5;policy=p1")
+	require.NoError(t, err)
+	require.Len(t, intvs, 2)
+	require.Equal(t, intv.RetainIntervalSpec(), intvs[0].RetainIntervalSpec())
+	require.Equal(t, []string{"p1"}, policies.Names)
+	require.Equal(t,
+		"policy=p1;cron 0 3 * * mon-fri (Europe/Berlin),keep last 10, "+
+			"daily @10:43,keep last 5",
+		ScheduleSummary(intvs, policies))
+
+	intvs, _, err = ParseScheduleAndPolicies("cron=*/5 * * * 1,3")
+	require.NoError(t, err)
+	require.Len(t, intvs, 1)
+	require.Equal(t, "*/5 * * * 1,3", intvs[0].Spec().Cron)
+	require.Equal(t, "Local", intvs[0].Spec().Location)
+	require.Equal(t, uint32(WeeklyRetain),
+		SetupIntvWithDefaults(intvs)[0].RetainNumber())
+
+	for _, s := range []string{
+		"cron=", "cron=* * * *", "cron=* * * * * 0",
+		"cron=CRON_TZ=Nowhere/Land * * * * *",
+	} {
+		_, _, err := ParseScheduleAndPolicies(s)
+		require.Error(t, err, "%q parsed as valid", s)
+	}
+}
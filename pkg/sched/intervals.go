@@ -45,6 +45,10 @@ type IntervalSpec struct {
 	Day     int    `yaml:"day,omitempty"`
 	Hour    int    `yaml:"hour,omitempty"`
 	Minute  int    `yaml:"minute,omitempty"`
+	// Cron is the expression of a cron interval.
+	Cron string `yaml:"cron,omitempty"`
+	// Location is the time zone of a cron interval, local time if empty.
+	Location string `yaml:"location,omitempty"`
 }
 
 type Interval interface {
@@ -189,6 +193,12 @@ func parseSpec(spec *IntervalSpec) (Interval, error) {
 			spec.Day = 1
 		}
 		return Monthly(spec.Day, spec.Hour, spec.Minute), nil
+	case CronType:
+		loc, err := cronLocation(spec.Location)
+		if err != nil {
+			return nil, err
+		}
+		return Cron(spec.Cron, loc)
 	}
 	return nil, fmt.Errorf("Invalid schedule spec")
 }
@@ -202,7 +212,7 @@ func parseRetainSpec(spec *RetainIntervalSpec) (RetainInterval, error) {
 }
 
 func parseNonYamlSchedule(schedule string) (RetainIntervalSpec, error) {
-	parts := strings.Split(schedule, nonYamlTypeSeparator)
+	parts := strings.SplitN(schedule, nonYamlTypeSeparator, 2)
 	if len(parts) != 2 || !IsIntervalType(parts[0]) {
 		return RetainIntervalSpec{},
 			fmt.Errorf("Invalid schedule specification: %s", schedule)
@@ -481,6 +491,7 @@ var ParseCLI = map[string]func(string) (RetainIntervalSpec, error){
 	DailyType:   parseDaily,
 	WeeklyType:  parseWeekly,
 	MonthlyType: parseMonthly,
+	CronType:    parseCron,
 }
 
 func IntervalType(interval Interval) string {
@@ -488,7 +499,8 @@ func IntervalType(interval Interval) string {
 }
 
 func IsIntervalType(t string) bool {
-	knownTypes := []string{PeriodicType, DailyType, WeeklyType, MonthlyType}
+	knownTypes := []string{PeriodicType, DailyType, WeeklyType, MonthlyType,
+		CronType}
 	for _, p := range knownTypes {
 		if p == t {
 			return true
@@ -558,7 +570,7 @@ func SetupIntvWithDefaults(intvs []RetainInterval) []RetainInterval {
 			switch intv.IntervalType() {
 			case DailyType:
 				p.retain = DailyRetain
-			case WeeklyType, PeriodicType:
+			case WeeklyType, PeriodicType, CronType:
 				p.retain = WeeklyRetain
 			case MonthlyType:
 				p.retain = MonthlyRetain
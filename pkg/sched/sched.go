@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/libopenstorage/openstorage/pkg/dbg"
+	"go.pedge.io/dlog"
 )
 
 type TaskID uint64
@@ -27,6 +28,16 @@ type Scheduler interface {
 	Schedule(task ScheduleTask, interval Interval,
 		runAt time.Time, onlyOnce bool) (TaskID, error)
 
+	// ScheduleNamed schedules given task like Schedule, under a name that
+	// identifies it across restarts. If the scheduler has a TaskStore and
+	// a task of this name was stored before with the same interval, runAt
+	// is ignored: the stored schedule is resumed and runs missed in the
+	// meantime are handled according to policy. A task stored with another
+	// interval starts afresh at runAt. Returns TaskNone if a missed one time
+	// task is skipped.
+	ScheduleNamed(name string, task ScheduleTask, interval Interval,
+		runAt time.Time, onlyOnce bool, policy CatchUpPolicy) (TaskID, error)
+
 	// Cancel given task.
 	Cancel(taskID TaskID) error
 
@@ -52,10 +63,18 @@ type taskInfo struct {
 	onlyOnce bool
 	// valid is true until task is not cancelled
 	valid bool
-	// lock for the enqueued member
+	// lock for the enqueued and valid members
 	lock sync.Mutex
 	// enqueued is true if task is scheduled to run
 	enqueued bool
+	// name identifies a task scheduled with ScheduleNamed
+	name string
+	// policy is the catch up policy of a named task
+	policy CatchUpPolicy
+	// lastRun is the time the task last started running
+	lastRun time.Time
+	// pending is the number of missed runs still to be caught up
+	pending int
 }
 
 type manager struct {
@@ -76,6 +95,10 @@ type manager struct {
 	cv *sync.Cond
 	// enqueuedTasks is list of tasks that must be run now
 	enqueuedTasks *list.List
+	// store persists named tasks, nil if they are kept in memory only
+	store TaskStore
+	// maxCatchUp caps the missed runs of a task run by CatchUpAll
+	maxCatchUp int
 }
 
 func (s *manager) Schedule(
@@ -87,26 +110,131 @@ func (s *manager) Schedule(
 	s.Lock()
 	defer s.Unlock()
 
+	if err := validateTask(task, interval); err != nil {
+		return TaskNone, err
+	}
+	t := s.addTask(task, interval, interval.nextAfter(runAt), onlyOnce)
+	return t.ID, nil
+}
+
+func (s *manager) ScheduleNamed(
+	name string,
+	task ScheduleTask,
+	interval Interval,
+	runAt time.Time,
+	onlyOnce bool,
+	policy CatchUpPolicy,
+) (TaskID, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if name == "" {
+		return TaskNone, fmt.Errorf("Task name is required")
+	}
+	if err := validateTask(task, interval); err != nil {
+		return TaskNone, err
+	}
+	for e := s.tasks.Front(); e != nil; e = e.Next() {
+		if e.Value.(*taskInfo).name == name {
+			return TaskNone, fmt.Errorf("Task %v already scheduled", name)
+		}
+	}
+
+	next := interval.nextAfter(runAt)
+	var lastRun time.Time
+	pending := 0
+	if s.store != nil {
+		stored, err := s.store.Get(name)
+		if err != nil && err != ErrTaskNotFound {
+			return TaskNone, err
+		}
+		// The runs of another schedule are not carried over to this one.
+		if stored != nil && !stored.NextRun.IsZero() &&
+			stored.Interval == retainIntervalSpec(interval) &&
+			stored.OnlyOnce == onlyOnce {
+			now := time.Now()
+			lastRun = stored.LastRun
+			next = stored.NextRun
+			missed := missedRuns(interval, next, now, onlyOnce, s.maxCatchUp)
+			if missed > 0 {
+				switch policy {
+				case CatchUpSkip:
+					if onlyOnce {
+						return TaskNone, s.store.Delete(name)
+					}
+					next = interval.nextAfter(now)
+				case CatchUpOnce:
+					next = now
+				case CatchUpAll:
+					next = now
+					pending = missed - 1
+				default:
+					return TaskNone, fmt.Errorf("Invalid catch up policy %v",
+						policy)
+				}
+			}
+		}
+	}
+
+	t := s.addTask(task, interval, next, onlyOnce)
+	t.name = name
+	t.policy = policy
+	t.lastRun = lastRun
+	t.pending = pending
+	if err := s.persist(t); err != nil {
+		t.valid = false
+		s.tasks.Remove(s.tasks.Back())
+		return TaskNone, err
+	}
+	return t.ID, nil
+}
+
+func validateTask(task ScheduleTask, interval Interval) error {
 	if task == nil {
-		return TaskNone, fmt.Errorf("Invalid task specified")
+		return fmt.Errorf("Invalid task specified")
 	}
 	now := time.Now()
 	if interval.nextAfter(now).Sub(now) < time.Second {
-		return TaskNone, fmt.Errorf("Minimum interval is a second")
+		return fmt.Errorf("Minimum interval is a second")
 	}
+	return nil
+}
 
+// addTask adds a task to run at runAt. Must be called with s locked.
+func (s *manager) addTask(
+	task ScheduleTask,
+	interval Interval,
+	runAt time.Time,
+	onlyOnce bool,
+) *taskInfo {
 	s.currTaskID++
 	t := &taskInfo{ID: s.currTaskID,
 		task:     task,
 		interval: interval,
-		runAt:    interval.nextAfter(runAt),
+		runAt:    runAt,
 		valid:    true,
 		onlyOnce: onlyOnce,
 		lock:     sync.Mutex{},
 		enqueued: false}
 
 	s.tasks.PushBack(t)
-	return t.ID, nil
+	return t
+}
+
+// persist saves a named task to the store. Must be called with t.lock held
+// or before t is visible to the runners.
+func (s *manager) persist(t *taskInfo) error {
+	if s.store == nil || t.name == "" {
+		return nil
+	}
+	return s.store.Put(&StoredTask{
+		Name:     t.name,
+		Interval: retainIntervalSpec(t.interval),
+		OnlyOnce: t.onlyOnce,
+		Policy:   t.policy,
+		LastRun:  t.lastRun,
+		NextRun:  t.runAt,
+	})
 }
 
 func (s *manager) Cancel(
@@ -118,8 +246,13 @@ func (s *manager) Cancel(
 	for e := s.tasks.Front(); e != nil; e = e.Next() {
 		t := e.Value.(*taskInfo)
 		if t.ID == taskID {
+			t.lock.Lock()
 			t.valid = false
+			t.lock.Unlock()
 			s.tasks.Remove(e)
+			if s.store != nil && t.name != "" {
+				return s.store.Delete(t.name)
+			}
 			return nil
 		}
 	}
@@ -191,23 +324,65 @@ func (s *manager) runTasks() {
 		}
 		s.cv.L.Unlock()
 		if t != nil && t.valid {
+			start := time.Now()
 			t.task(t.interval)
 			t.lock.Lock()
-			t.runAt = t.interval.nextAfter(time.Now())
+			t.lastRun = start
+			if t.pending > 0 {
+				t.pending--
+				t.runAt = time.Now()
+			} else {
+				t.runAt = t.interval.nextAfter(time.Now())
+			}
 			t.enqueued = false
+			s.persistRun(t)
 			t.lock.Unlock()
 		}
 	}
 }
 
+// persistRun records a run of a named task. A one time task is removed from
+// the store once it ran. Must be called with t.lock held.
+func (s *manager) persistRun(t *taskInfo) {
+	if s.store == nil || t.name == "" {
+		return
+	}
+	var err error
+	if t.onlyOnce {
+		err = s.store.Delete(t.name)
+	} else if t.valid {
+		err = s.persist(t)
+	}
+	if err != nil {
+		dlog.Warnf("Failed to store run of task %v: %v", t.name, err)
+	}
+}
+
 func New(minimumInterval time.Duration) Scheduler {
+	return NewWithStore(minimumInterval, nil, 0)
+}
+
+// NewWithStore returns a scheduler that keeps tasks scheduled with
+// ScheduleNamed in store, so that they can be resumed after a restart.
+// CatchUpAll runs at most maxCatchUp missed runs of a task, or
+// DefaultMaxCatchUp if maxCatchUp is not positive.
+func NewWithStore(
+	minimumInterval time.Duration,
+	store TaskStore,
+	maxCatchUp int,
+) Scheduler {
+	if maxCatchUp <= 0 {
+		maxCatchUp = DefaultMaxCatchUp
+	}
 	m := &manager{
 		tasks:             list.New(),
 		currTaskID:        0,
 		minimumInterval:   minimumInterval,
 		ticker:            time.NewTicker(minimumInterval),
 		enqueuedTasksLock: sync.Mutex{},
-		enqueuedTasks:     list.New()}
+		enqueuedTasks:     list.New(),
+		store:             store,
+		maxCatchUp:        maxCatchUp}
 	m.cv = sync.NewCond(&m.enqueuedTasksLock)
 	for i := 0; i < numGoRoutines; i++ {
 		go m.runTasks()
@@ -0,0 +1,141 @@
+package sched
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/portworx/kvdb"
+)
+
+// CatchUpPolicy decides what happens to the runs of a named task that fell
+// due while the scheduler was not running.
+type CatchUpPolicy int
+
+const (
+	// CatchUpSkip drops missed runs; the task runs next at its next
+	// interval after now.
+	CatchUpSkip CatchUpPolicy = iota
+	// CatchUpOnce runs the task once right away if any run was missed.
+	CatchUpOnce
+	// CatchUpAll runs the task once for every missed run, right away, up
+	// to the maximum of the scheduler.
+	CatchUpAll
+)
+
+// DefaultMaxCatchUp is the default maximum of missed runs of a task run by
+// CatchUpAll.
+const DefaultMaxCatchUp = 10
+
+var (
+	// ErrTaskNotFound is returned by a TaskStore for an unknown task name.
+	ErrTaskNotFound = errors.New("Task not found")
+)
+
+// StoredTask is the persisted definition and run state of a named task.
+type StoredTask struct {
+	// Name uniquely identifies the task across restarts.
+	Name string
+	// Interval at which the task is scheduled.
+	Interval RetainIntervalSpec
+	// OnlyOnce is true for one time tasks.
+	OnlyOnce bool
+	// Policy for runs missed while the scheduler was down.
+	Policy CatchUpPolicy
+	// LastRun is when the task last started running, zero if it never ran.
+	LastRun time.Time
+	// NextRun is when the task is due next.
+	NextRun time.Time
+}
+
+// RetainInterval returns the interval the task is scheduled at.
+func (t *StoredTask) RetainInterval() (RetainInterval, error) {
+	return parseRetainSpec(&t.Interval)
+}
+
+// TaskStore persists named tasks so that they survive scheduler restarts.
+type TaskStore interface {
+	// Get returns the stored task with the given name or ErrTaskNotFound.
+	Get(name string) (*StoredTask, error)
+	// Put creates or updates a stored task.
+	Put(task *StoredTask) error
+	// Delete removes a stored task.
+	Delete(name string) error
+	// Enumerate returns all stored tasks.
+	Enumerate() ([]*StoredTask, error)
+}
+
+type kvdbTaskStore struct {
+	kv     kvdb.Kvdb
+	prefix string
+}
+
+// NewKvdbTaskStore returns a TaskStore that keeps tasks in kv under prefix.
+func NewKvdbTaskStore(kv kvdb.Kvdb, prefix string) TaskStore {
+	return &kvdbTaskStore{kv: kv, prefix: prefix}
+}
+
+func (k *kvdbTaskStore) key(name string) string {
+	return k.prefix + "/" + name
+}
+
+func (k *kvdbTaskStore) Get(name string) (*StoredTask, error) {
+	task := &StoredTask{}
+	if _, err := k.kv.GetVal(k.key(name), task); err != nil {
+		if err == kvdb.ErrNotFound {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+	return task, nil
+}
+
+func (k *kvdbTaskStore) Put(task *StoredTask) error {
+	_, err := k.kv.Put(k.key(task.Name), task, 0)
+	return err
+}
+
+func (k *kvdbTaskStore) Delete(name string) error {
+	if _, err := k.kv.Delete(k.key(name)); err != nil && err != kvdb.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+func (k *kvdbTaskStore) Enumerate() ([]*StoredTask, error) {
+	kvps, err := k.kv.Enumerate(k.prefix + "/")
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*StoredTask, 0, len(kvps))
+	for _, kvp := range kvps {
+		task := &StoredTask{}
+		if err := json.Unmarshal(kvp.Value, task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// retainIntervalSpec returns the serializable form of interval.
+func retainIntervalSpec(interval Interval) RetainIntervalSpec {
+	if r, ok := interval.(RetainInterval); ok {
+		return r.RetainIntervalSpec()
+	}
+	return RetainIntervalSpec{IntervalSpec: interval.Spec()}
+}
+
+// missedRuns returns the number of runs due at or after next and not later
+// than now, up to max.
+func missedRuns(interval Interval, next, now time.Time, onlyOnce bool, max int) int {
+	missed := 0
+	for missed < max && !next.After(now) {
+		missed++
+		if onlyOnce {
+			break
+		}
+		next = interval.nextAfter(next)
+	}
+	return missed
+}
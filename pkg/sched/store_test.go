@@ -0,0 +1,205 @@
+package sched
+
+import (
+	"testing"
+	"time"
+
+	"github.com/portworx/kvdb"
+	"github.com/portworx/kvdb/mem"
+	"github.com/stretchr/testify/require"
+	"go.pedge.io/dlog"
+)
+
+func newTestStore(t *testing.T) TaskStore {
+	kv, err := kvdb.New(mem.Name, "sched_test", []string{}, nil, dlog.Panicf)
+	require.NoError(t, err)
+	return NewKvdbTaskStore(kv, "tasks")
+}
+
+func TestTaskStore(t *testing.T) {
+	store := newTestStore(t)
+
+	_, err := store.Get("missing")
+	require.Equal(t, ErrTaskNotFound, err)
+
+	next := time.Now().Add(time.Hour)
+	require.NoError(t, store.Put(&StoredTask{
+		Name:     "daily",
+		Interval: RetainIntervalSpec{IntervalSpec: Daily(2, 30).Spec(), Retain: 4},
+		Policy:   CatchUpOnce,
+		NextRun:  next,
+	}))
+	task, err := store.Get("daily")
+	require.NoError(t, err)
+	require.Equal(t, CatchUpOnce, task.Policy)
+	require.True(t, next.Equal(task.NextRun))
+	iv, err := task.RetainInterval()
+	require.NoError(t, err)
+	require.Equal(t, DailyType, iv.IntervalType())
+	require.Equal(t, uint32(4), iv.RetainNumber())
+
+	tasks, err := store.Enumerate()
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	require.NoError(t, store.Delete("daily"))
+	require.NoError(t, store.Delete("daily"))
+	tasks, err = store.Enumerate()
+	require.NoError(t, err)
+	require.Len(t, tasks, 0)
+}
+
+func TestRestartCatchUp(t *testing.T) {
+	store := newTestStore(t)
+	interval := Periodic(10 * time.Second)
+	policies := map[string]CatchUpPolicy{
+		"skip": CatchUpSkip,
+		"once": CatchUpOnce,
+		"all":  CatchUpAll,
+	}
+
+	// First run of the daemon registers the tasks and then goes down in
+	// the middle of their schedule.
+	s := NewWithStore(100*time.Millisecond, store, 0)
+	for name, policy := range policies {
+		_, err := s.ScheduleNamed(name, func(Interval) {}, interval,
+			time.Now(), false, policy)
+		require.NoError(t, err)
+	}
+	_, err := s.ScheduleNamed("skip", func(Interval) {}, interval,
+		time.Now(), false, CatchUpSkip)
+	require.Error(t, err, "duplicate task name")
+	s.Stop()
+
+	// While the daemon was down three runs, 25, 15 and 5 seconds ago,
+	// were missed.
+	for name := range policies {
+		task, err := store.Get(name)
+		require.NoError(t, err)
+		task.NextRun = time.Now().Add(-25 * time.Second)
+		require.NoError(t, store.Put(task))
+	}
+
+	s = NewWithStore(100*time.Millisecond, store, 0)
+	counters := make(map[string]*testCounter)
+	for name, policy := range policies {
+		tc := &testCounter{}
+		counters[name] = tc
+		_, err := s.ScheduleNamed(name, func(Interval) { tc.incr() }, interval,
+			time.Now(), false, policy)
+		require.NoError(t, err)
+	}
+	time.Sleep(3 * time.Second)
+	s.Stop()
+
+	require.Equal(t, 0, counters["skip"].count, "skip policy")
+	require.Equal(t, 1, counters["once"].count, "once policy")
+	require.Equal(t, 3, counters["all"].count, "all policy")
+	for name := range policies {
+		task, err := store.Get(name)
+		require.NoError(t, err)
+		require.True(t, task.NextRun.After(time.Now()), "next run of %v", name)
+		if name != "skip" {
+			require.False(t, task.LastRun.IsZero(), "last run of %v", name)
+		}
+	}
+
+	// Restarting again before the next run is due does not run anything.
+	s = NewWithStore(100*time.Millisecond, store, 0)
+	tc := &testCounter{}
+	taskID, err := s.ScheduleNamed("all", func(Interval) { tc.incr() }, interval,
+		time.Now(), false, CatchUpAll)
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+	require.Equal(t, 0, tc.count, "no missed runs")
+
+	// Cancelling a task removes it from the store.
+	require.NoError(t, s.Cancel(taskID))
+	_, err = store.Get("all")
+	require.Equal(t, ErrTaskNotFound, err)
+	s.Stop()
+}
+
+func TestRestartOnlyOnce(t *testing.T) {
+	store := newTestStore(t)
+	interval := Periodic(time.Hour)
+	require.NoError(t, store.Put(&StoredTask{
+		Name:     "once",
+		Interval: retainIntervalSpec(interval),
+		OnlyOnce: true,
+		NextRun:  time.Now().Add(-time.Minute),
+	}))
+	require.NoError(t, store.Put(&StoredTask{
+		Name:     "skipped",
+		Interval: retainIntervalSpec(interval),
+		OnlyOnce: true,
+		NextRun:  time.Now().Add(-time.Minute),
+	}))
+
+	s := NewWithStore(100*time.Millisecond, store, 0)
+	tc := &testCounter{}
+	_, err := s.ScheduleNamed("once", func(Interval) { tc.incr() }, interval,
+		time.Now(), true, CatchUpAll)
+	require.NoError(t, err)
+	taskID, err := s.ScheduleNamed("skipped", func(Interval) { tc.incr() },
+		interval, time.Now(), true, CatchUpSkip)
+	require.NoError(t, err)
+	require.False(t, ValidTaskID(taskID))
+	time.Sleep(time.Second)
+	s.Stop()
+
+	require.Equal(t, 1, tc.count)
+	tasks, err := store.Enumerate()
+	require.NoError(t, err)
+	require.Len(t, tasks, 0)
+}
+
+func TestRestartCatchUpMax(t *testing.T) {
+	store := newTestStore(t)
+	interval := Periodic(10 * time.Second)
+	require.NoError(t, store.Put(&StoredTask{
+		Name:     "all",
+		Interval: retainIntervalSpec(interval),
+		Policy:   CatchUpAll,
+		NextRun:  time.Now().Add(-time.Hour),
+	}))
+
+	// An hour of missed runs is capped at the maximum.
+	s := NewWithStore(100*time.Millisecond, store, 2)
+	tc := &testCounter{}
+	_, err := s.ScheduleNamed("all", func(Interval) { tc.incr() }, interval,
+		time.Now(), false, CatchUpAll)
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+	s.Stop()
+	require.Equal(t, 2, tc.count)
+}
+
+func TestRestartIntervalChanged(t *testing.T) {
+	store := newTestStore(t)
+	lastRun := time.Now().Add(-time.Hour)
+	require.NoError(t, store.Put(&StoredTask{
+		Name:     "changed",
+		Interval: retainIntervalSpec(Periodic(10 * time.Second)),
+		Policy:   CatchUpOnce,
+		LastRun:  lastRun,
+		NextRun:  lastRun.Add(10 * time.Second),
+	}))
+
+	// The runs missed on the old interval are not caught up on the new one.
+	s := NewWithStore(100*time.Millisecond, store, 0)
+	tc := &testCounter{}
+	interval := Periodic(time.Hour)
+	_, err := s.ScheduleNamed("changed", func(Interval) { tc.incr() }, interval,
+		time.Now(), false, CatchUpOnce)
+	require.NoError(t, err)
+	time.Sleep(time.Second)
+	s.Stop()
+	require.Equal(t, 0, tc.count)
+
+	task, err := store.Get("changed")
+	require.NoError(t, err)
+	require.Equal(t, retainIntervalSpec(interval), task.Interval)
+	require.True(t, task.LastRun.IsZero())
+	require.True(t, task.NextRun.After(time.Now().Add(59*time.Minute)))
+}
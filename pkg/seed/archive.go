@@ -0,0 +1,172 @@
+package seed
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const sha256Prefix = "sha256:"
+
+// loadArchive extracts the tar or tar.gz archive read from r into dest. The
+// archive is verified against checksum, if it is not empty, before anything
+// is extracted. It returns the digest of the archive.
+func loadArchive(r io.Reader, checksum string, dest string) (string, error) {
+	f, err := ioutil.TempFile("", "osd-seed")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), r); err != nil {
+		return "", err
+	}
+	digest := sha256Prefix + hex.EncodeToString(h.Sum(nil))
+	if checksum != "" {
+		if !strings.HasPrefix(checksum, sha256Prefix) {
+			checksum = sha256Prefix + checksum
+		}
+		if !strings.EqualFold(checksum, digest) {
+			return "", fmt.Errorf("Checksum mismatch: expected %v, got %v",
+				checksum, digest)
+		}
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return "", err
+	}
+	if err := extractTar(f, dest); err != nil {
+		return "", err
+	}
+	return digest, nil
+}
+
+// extractTar extracts the tar archive read from r, which may be gzipped,
+// into dest. Entries that would land outside of dest, or be written through
+// a symlink, and symlinks that point outside of dest are rejected.
+func extractTar(r io.Reader, dest string) error {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+
+	dest = filepath.Clean(dest)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := archivePath(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+		if err := checkNoSymlink(dest, target); err != nil {
+			return err
+		}
+		mode := os.FileMode(hdr.Mode).Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// Parent references only lead a clean relative path, and the
+			// parents of target are not symlinks, so a link that stays in
+			// dest lexically cannot resolve outside of it.
+			link := filepath.Clean(hdr.Linkname)
+			if filepath.IsAbs(link) ||
+				!inDir(dest, filepath.Join(filepath.Dir(target), link)) {
+				return fmt.Errorf("Invalid link %v of %v in archive",
+					hdr.Linkname, hdr.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			link, err := archivePath(dest, hdr.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := checkNoSymlink(dest, link); err != nil {
+				return err
+			}
+			if err := os.Link(link, target); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("Unsupported type %c of %v in archive",
+				hdr.Typeflag, hdr.Name)
+		}
+	}
+}
+
+func archivePath(dest string, name string) (string, error) {
+	target := filepath.Join(dest, name)
+	if !inDir(dest, target) {
+		return "", fmt.Errorf("Invalid path %v in archive", name)
+	}
+	return target, nil
+}
+
+// inDir returns whether the clean path p is dir or below it.
+func inDir(dir string, p string) bool {
+	return p == dir || strings.HasPrefix(p, dir+string(filepath.Separator))
+}
+
+// checkNoSymlink returns an error if target, or any of its parents below
+// dest, is a symlink, so that nothing is written through a symlink.
+func checkNoSymlink(dest string, target string) error {
+	for p := target; p != dest && inDir(dest, p); p = filepath.Dir(p) {
+		fi, err := os.Lstat(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("Invalid path %v in archive: %v is a symlink",
+				strings.TrimPrefix(target, dest+string(filepath.Separator)), p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package seed
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/libopenstorage/openstorage/volume/drivers/common"
+)
+
+// File loads a local directory or a local tar or tar.gz archive from a
+// file:// URI.
+type File struct {
+	provenance
+	path     string
+	checksum string
+}
+
+// String representation of this source
+func (f *File) String() string {
+	return "file://" + f.path
+}
+
+// Load copies the directory, or extracts the archive, into dest. The path
+// must still be under a file root once its symlinks are resolved.
+func (f *File) Load(dest string) error {
+	path, err := filepath.EvalSymlinks(f.path)
+	if err != nil {
+		return err
+	}
+	if !allowedPath(path) {
+		return ErrNotAllowed
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		if err := common.CloneTree(path, dest); err != nil {
+			return fmt.Errorf("Failed to copy %v: %v", f.path, err)
+		}
+		return nil
+	}
+	r, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	digest, err := loadArchive(r, f.checksum, dest)
+	if err != nil {
+		return fmt.Errorf("Failed to load %v: %v", f.path, err)
+	}
+	f.md.Digest = digest
+	return nil
+}
+
+// NewFileSource returns a source for a local directory or archive under one
+// of the file roots. The Checksum option, if set, is verified for archives.
+func NewFileSource(uri string, options map[string]string) (Source, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "file" || u.Path == "" {
+		return nil, ErrUnsupported
+	}
+	if !allowedPath(u.Path) {
+		return nil, ErrNotAllowed
+	}
+	return &File{
+		provenance: provenance{md: Metadata{URI: uri}},
+		path:       u.Path,
+		checksum:   options[Checksum],
+	}, nil
+}
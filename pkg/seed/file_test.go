@@ -0,0 +1,69 @@
+package seed
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osd-seed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = New("file://", nil, nil)
+	assert.Equal(t, ErrUnsupported, err)
+
+	// Only paths under the file roots are allowed.
+	source := filepath.Join(dir, "source")
+	_, err = New("file://"+source, nil, nil)
+	assert.Equal(t, ErrNotAllowed, err)
+	SetFileRoots([]string{dir})
+	defer SetFileRoots(nil)
+	_, err = New("file://"+dir+"/../etc", nil, nil)
+	assert.Equal(t, ErrNotAllowed, err)
+	_, err = New("file://"+dir+"x", nil, nil)
+	assert.Equal(t, ErrNotAllowed, err)
+
+	// Symlinks out of the roots are not followed.
+	escape := filepath.Join(dir, "escape")
+	require.NoError(t, os.Symlink("/etc", escape))
+	s, err := New("file://"+escape, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ErrNotAllowed, s.Load(filepath.Join(dir, "escaped")))
+
+	// A directory is copied.
+	require.NoError(t, os.MkdirAll(filepath.Join(source, "sub"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(source, "sub", "file"), []byte("file"), 0644))
+	s, err = New("file://"+source, nil, nil)
+	require.NoError(t, err)
+	dest := filepath.Join(dir, "fromdir")
+	require.NoError(t, s.Load(dest))
+	data, err := ioutil.ReadFile(filepath.Join(dest, "sub", "file"))
+	require.NoError(t, err)
+	assert.Equal(t, "file", string(data))
+
+	// An archive is extracted.
+	archive := newArchive(t, map[string]string{"archived": "archived"})
+	archivePath := filepath.Join(dir, "seed.tar.gz")
+	require.NoError(t, ioutil.WriteFile(archivePath, archive, 0644))
+	s, err = New("file://"+archivePath, map[string]string{Checksum: digestOf(archive)}, nil)
+	require.NoError(t, err)
+	dest = filepath.Join(dir, "fromarchive")
+	require.NoError(t, s.Load(dest))
+	data, err = ioutil.ReadFile(filepath.Join(dest, "archived"))
+	require.NoError(t, err)
+	assert.Equal(t, "archived", string(data))
+
+	md, err := s.MetadataRead(dest)
+	require.NoError(t, err)
+	assert.Empty(t, md, "nothing was recorded yet")
+	require.NoError(t, s.MetadataWrite(dest))
+	md, err = s.MetadataRead(dest)
+	require.NoError(t, err)
+	assert.Contains(t, md, digestOf(archive))
+}
@@ -5,11 +5,13 @@ import (
 	"net/url"
 	"os/exec"
 	"path"
+	"strings"
 )
 
 const GitRevision = "revision"
 
 type Git struct {
+	provenance
 	host     string
 	revision string
 	ready    bool
@@ -31,7 +33,7 @@ func (g *Git) Load(dest string) error {
 	}
 	if len(g.revision) == 0 {
 		g.ready = true
-		return nil
+		return g.readRevision(dest)
 	}
 	cmd = exec.Command("git", "checkout", g.revision)
 	cmd.Dir = dest
@@ -45,16 +47,18 @@ func (g *Git) Load(dest string) error {
 	}
 
 	g.ready = true
-	return nil
-}
-
-// Metadata for this source.
-func (g *Git) MetadataRead(mdDir string) (string, error) {
-	return "", nil
+	return g.readRevision(dest)
 }
 
-// MetadataWrite for this source.
-func (g *Git) MetadataWrite(mdDir string) error {
+// readRevision records the commit checked out in dest.
+func (g *Git) readRevision(dest string) error {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dest
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("wd %v 'git rev-parse HEAD': %s", cmd.Dir, err)
+	}
+	g.md.Revision = strings.TrimSpace(string(output))
 	return nil
 }
 
@@ -67,7 +71,8 @@ func NewGitSource(uri string, options map[string]string) (Source, error) {
 		return nil, ErrUnsupported
 	}
 	return &Git{
-		host:     "http://" + path.Join(u.Host, u.Path),
-		revision: options[GitRevision],
+		provenance: provenance{md: Metadata{URI: uri}},
+		host:       "http://" + path.Join(u.Host, u.Path),
+		revision:   options[GitRevision],
 	}, nil
 }
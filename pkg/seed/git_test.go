@@ -17,9 +17,9 @@ var s Source
 
 func TestSetup(t *testing.T) {
 	var err error
-	s, err = New("badscheme://github.com/libopenstorage/openstorage", nil)
+	s, err = New("badscheme://github.com/libopenstorage/openstorage", nil, nil)
 	assert.Error(t, err, "invalid schemme should fail")
-	s, err = New(source, map[string]string{GitRevision: goodRev})
+	s, err = New(source, map[string]string{GitRevision: goodRev}, nil)
 	if err != nil {
 		t.Fatalf("Failed to setup test %v", err)
 	}
@@ -0,0 +1,62 @@
+package seed
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// httpTimeout bounds the download of an archive, so that a stalled server
+// does not hold up the creation of the volume forever.
+const httpTimeout = 30 * time.Minute
+
+var httpClient = &http.Client{Timeout: httpTimeout}
+
+// HTTP loads a tar or tar.gz archive from an http:// or https:// URI.
+type HTTP struct {
+	provenance
+	uri      string
+	checksum string
+}
+
+// String representation of this source
+func (h *HTTP) String() string {
+	return h.uri
+}
+
+// Load downloads the archive, verifies its checksum and extracts it into
+// dest.
+func (h *HTTP) Load(dest string) error {
+	resp, err := httpClient.Get(h.uri)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Failed to download %v: %v", h.uri, resp.Status)
+	}
+	digest, err := loadArchive(resp.Body, h.checksum, dest)
+	if err != nil {
+		return fmt.Errorf("Failed to load %v: %v", h.uri, err)
+	}
+	h.md.Digest = digest
+	return nil
+}
+
+// NewHTTPSource returns a source for an archive at an http:// or https://
+// URI. The Checksum option, if set, is verified before extraction.
+func NewHTTPSource(uri string, options map[string]string) (Source, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, ErrUnsupported
+	}
+	return &HTTP{
+		provenance: provenance{md: Metadata{URI: uri}},
+		uri:        uri,
+		checksum:   options[Checksum],
+	}, nil
+}
@@ -0,0 +1,153 @@
+package seed
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newArchive returns a gzipped tar archive of files, keyed by path.
+func newArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func digestOf(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestHTTPSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osd-seed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	archive := newArchive(t, map[string]string{
+		"top":        "top",
+		"sub/nested": "nested",
+	})
+	bad := newArchive(t, map[string]string{"../escape": "escape"})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/seed.tar.gz":
+			w.Write(archive)
+		case "/bad.tar.gz":
+			w.Write(bad)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	uri := ts.URL + "/seed.tar.gz"
+	s, err := New(uri, map[string]string{Checksum: "sha256:" + digestOf(archive)}, nil)
+	require.NoError(t, err)
+	dest := filepath.Join(dir, "dest")
+	require.NoError(t, s.Load(dest))
+	data, err := ioutil.ReadFile(filepath.Join(dest, "sub", "nested"))
+	require.NoError(t, err)
+	assert.Equal(t, "nested", string(data))
+
+	require.NoError(t, s.MetadataWrite(dir))
+	md, err := s.MetadataRead(dir)
+	require.NoError(t, err)
+	assert.Contains(t, md, uri)
+	assert.Contains(t, md, "sha256:"+digestOf(archive))
+
+	// Nothing is extracted from an archive that does not match.
+	s, err = New(uri, map[string]string{Checksum: digestOf(bad)}, nil)
+	require.NoError(t, err)
+	mismatch := filepath.Join(dir, "mismatch")
+	assert.Error(t, s.Load(mismatch))
+	_, err = os.Stat(mismatch)
+	assert.True(t, os.IsNotExist(err))
+
+	s, err = New(ts.URL+"/missing.tar.gz", nil, nil)
+	require.NoError(t, err)
+	assert.Error(t, s.Load(filepath.Join(dir, "missing")))
+
+	s, err = New(ts.URL+"/bad.tar.gz", nil, nil)
+	require.NoError(t, err)
+	assert.Error(t, s.Load(filepath.Join(dir, "bad")))
+	_, err = os.Stat(filepath.Join(dir, "escape"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestExtractTarSymlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osd-seed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	extract := func(dest string, hdrs ...*tar.Header) error {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, hdr := range hdrs {
+			hdr.Mode = 0644
+			require.NoError(t, tw.WriteHeader(hdr))
+			if hdr.Typeflag == tar.TypeReg {
+				_, err := tw.Write([]byte("data"))
+				require.NoError(t, err)
+			}
+		}
+		require.NoError(t, tw.Close())
+		return extractTar(&buf, filepath.Join(dir, dest))
+	}
+	symlink := func(name, link string) *tar.Header {
+		return &tar.Header{Name: name, Linkname: link, Typeflag: tar.TypeSymlink}
+	}
+	file := func(name string) *tar.Header {
+		return &tar.Header{Name: name, Size: 4, Typeflag: tar.TypeReg}
+	}
+
+	// Links within dest are extracted.
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "ok"), 0755))
+	assert.NoError(t, extract("ok",
+		file("sub/file"),
+		symlink("sub/link", "../sub/./file"),
+		symlink("top", "sub")))
+	data, err := ioutil.ReadFile(filepath.Join(dir, "ok", "sub", "link"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	// Links that leave dest are rejected.
+	for i, link := range []string{"/etc", "../..", "sub/../../..", "../../ok"} {
+		dest := fmt.Sprintf("escape%d", i)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, dest), 0755))
+		assert.Error(t, extract(dest, symlink("sub/link", link)), link)
+	}
+
+	// Nothing is written through a symlink, even one within dest.
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "through"), 0755))
+	assert.Error(t, extract("through",
+		symlink("link", "."),
+		file("link/file")))
+	assert.Error(t, extract("through", file("link")))
+	_, err = os.Stat(filepath.Join(dir, "through", "file"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -1,8 +1,16 @@
 package seed
 
 import (
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/libopenstorage/openstorage/volume"
 )
 
 // Source defines the interface for keep track of volume driver mounts.
@@ -11,19 +19,86 @@ type Source interface {
 	String() string
 	// Load from URI into dest.
 	Load(dest string) error
+	// Metadata returns what was loaded by Load.
+	Metadata() (string, error)
 	// Metadata for this source.
 	MetadataRead(mdDir string) (string, error)
 	// MetadataWrite for this source.
 	MetadataWrite(mdDir string) error
 }
 
+const (
+	// Checksum is the option holding the expected digest of an archive,
+	// as "sha256:<hex>" or just the hex digest.
+	Checksum = "checksum"
+	// MetadataFile is the file in the metadata directory that records what
+	// was seeded.
+	MetadataFile = "seed.json"
+)
+
 var (
 	// ErrUnsupported is returned for an unsupported seed source.
 	ErrUnsupported = errors.New("Not supported")
+	// ErrNotAllowed is returned for a file:// seed outside of the file roots.
+	ErrNotAllowed = errors.New("Seed path is not under an allowed root")
+
+	rootsLock sync.RWMutex
+	fileRoots []string
 )
 
-// New returns a new instance of Source
-func New(uri string, options map[string]string) (Source, error) {
+// SetFileRoots sets the directories file:// seeds may be loaded from. No
+// file:// seeds are allowed until it is called with at least one root.
+func SetFileRoots(roots []string) {
+	rootsLock.Lock()
+	defer rootsLock.Unlock()
+	fileRoots = make([]string, 0, len(roots))
+	for _, root := range roots {
+		if !filepath.IsAbs(root) {
+			continue
+		}
+		fileRoots = append(fileRoots, filepath.Clean(root))
+		// Paths are checked again once their symlinks are resolved.
+		if resolved, err := filepath.EvalSymlinks(root); err == nil {
+			fileRoots = append(fileRoots, resolved)
+		}
+	}
+}
+
+// allowedPath returns true if path is an absolute path under one of the
+// file roots.
+func allowedPath(path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	path = filepath.Clean(path)
+	rootsLock.RLock()
+	defer rootsLock.RUnlock()
+	for _, root := range fileRoots {
+		if path == root || strings.HasPrefix(path, strings.TrimSuffix(root, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Metadata records what a volume was seeded from.
+type Metadata struct {
+	// URI of the source.
+	URI string `json:"uri"`
+	// Revision of the source that was loaded, if it is versioned.
+	Revision string `json:"revision,omitempty"`
+	// Digest of the loaded archive, if the source is an archive.
+	Digest string `json:"digest,omitempty"`
+}
+
+// New returns a new instance of Source. file:// URIs must be under one of
+// the roots set by SetFileRoots. volume:// URIs are loaded from the volumes
+// of d, as NewVolumeSource, which may be nil if they are not supported.
+func New(
+	uri string,
+	options map[string]string,
+	d volume.VolumeDriver,
+) (Source, error) {
 	u, err := url.Parse(uri)
 	if err != nil {
 		return nil, err
@@ -31,6 +106,49 @@ func New(uri string, options map[string]string) (Source, error) {
 	switch u.Scheme {
 	case "github":
 		return NewGitSource(uri, options)
+	case "http", "https":
+		return NewHTTPSource(uri, options)
+	case "file":
+		return NewFileSource(uri, options)
+	case "volume":
+		return NewVolumeSource(uri, d)
 	}
 	return nil, ErrUnsupported
 }
+
+// provenance implements the metadata methods of Source for the Metadata
+// filled in by Load.
+type provenance struct {
+	md Metadata
+}
+
+// Metadata returns the JSON encoding of the Metadata filled in by Load.
+func (p *provenance) Metadata() (string, error) {
+	b, err := json.Marshal(&p.md)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// MetadataRead returns the metadata written to mdDir, or an empty string if
+// nothing was seeded there.
+func (p *provenance) MetadataRead(mdDir string) (string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(mdDir, MetadataFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// MetadataWrite records what was loaded in mdDir.
+func (p *provenance) MetadataWrite(mdDir string) error {
+	md, err := p.Metadata()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(mdDir, MetadataFile), []byte(md), 0644)
+}
@@ -0,0 +1,180 @@
+package seed
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/volume"
+	"github.com/libopenstorage/openstorage/volume/drivers/common"
+)
+
+// RuntimeStateKey is the key of the runtime state of a volume that holds
+// the metadata of its seed.
+const RuntimeStateKey = "seed"
+
+// Volume loads the files of another volume from a volume://<id> URI.
+type Volume struct {
+	provenance
+	volumeID string
+	d        volume.VolumeDriver
+}
+
+// String representation of this source
+func (v *Volume) String() string {
+	return "volume://" + v.volumeID
+}
+
+// Load copies the files of the volume into dest. A volume that is not
+// mounted is mounted on a temporary path for the copy.
+func (v *Volume) Load(dest string) error {
+	vols, err := v.d.Inspect([]string{v.volumeID})
+	if err != nil {
+		return err
+	}
+	if len(vols) != 1 {
+		return fmt.Errorf("Cannot locate volume %v", v.volumeID)
+	}
+	return withMountedVolume(v.d, vols[0], func(source string) error {
+		return v.copy(source, dest)
+	})
+}
+
+func (v *Volume) copy(source string, dest string) error {
+	if err := common.CloneTree(source, dest); err != nil {
+		return fmt.Errorf("Failed to copy volume %v: %v", v.volumeID, err)
+	}
+	return nil
+}
+
+// NewVolumeSource returns a source for the volume of d named in a
+// volume://<id> URI. It copies the data of the volume, so callers must
+// check that the volume may be inspected by whoever asked for the seed.
+func NewVolumeSource(uri string, d volume.VolumeDriver) (Source, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "volume" || u.Host == "" || d == nil {
+		return nil, ErrUnsupported
+	}
+	return &Volume{
+		provenance: provenance{md: Metadata{URI: uri}},
+		volumeID:   u.Host,
+		d:          d,
+	}, nil
+}
+
+// VolumeSourceID returns the ID of the volume named by a volume://<id>
+// URI, or an empty string for other URIs.
+func VolumeSourceID(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "volume" {
+		return ""
+	}
+	return u.Host
+}
+
+// LoadVolume loads the seed in the source of the volume volumeID of d into
+// the volume, and records the metadata of the seed in its runtime state
+// under RuntimeStateKey. Nothing but the seed is written to the volume. The
+// volume is mounted on a temporary path while it is seeded. Volumes without
+// a seed are left alone.
+func LoadVolume(
+	d volume.VolumeDriver,
+	store volume.Store,
+	volumeID string,
+	options map[string]string,
+) error {
+	return LoadVolumeDir(d, store, volumeID, "", options)
+}
+
+// LoadVolumeDir is LoadVolume for drivers that keep the data of a volume in
+// the directory dir of its mount path.
+func LoadVolumeDir(
+	d volume.VolumeDriver,
+	store volume.Store,
+	volumeID string,
+	dir string,
+	options map[string]string,
+) error {
+	v, err := store.GetVol(volumeID)
+	if err != nil {
+		return err
+	}
+	uri := v.GetSource().GetSeed()
+	if uri == "" {
+		return nil
+	}
+	s, err := New(uri, options, d)
+	if err != nil {
+		return fmt.Errorf("Failed to initialize seed from %v: %v", uri, err)
+	}
+	err = withMountedVolume(d, v, func(mountPath string) error {
+		if err := s.Load(filepath.Join(mountPath, dir)); err != nil {
+			return fmt.Errorf("Failed to seed volume %v from %v: %v",
+				volumeID, uri, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	md, err := s.Metadata()
+	if err != nil {
+		return err
+	}
+
+	if v, err = store.GetVol(volumeID); err != nil {
+		return err
+	}
+	v.RuntimeState = append(v.RuntimeState, &api.RuntimeStateMap{
+		RuntimeState: map[string]string{RuntimeStateKey: md},
+	})
+	return store.UpdateVol(v)
+}
+
+// withMountedVolume calls fn with the path v is mounted on. A volume that is
+// not mounted is mounted on a temporary path for the call, after attaching
+// it if the driver is a block driver, and unmounted afterwards.
+func withMountedVolume(
+	d volume.VolumeDriver,
+	v *api.Volume,
+	fn func(mountPath string) error,
+) (err error) {
+	if paths := v.GetAttachPath(); len(paths) > 0 {
+		return fn(paths[0])
+	}
+
+	if d.Type() == api.DriverType_DRIVER_TYPE_BLOCK &&
+		v.GetState() != api.VolumeState_VOLUME_STATE_ATTACHED {
+		if _, err := d.Attach(v.GetId(), nil); err != nil {
+			return err
+		}
+		defer func() {
+			if detachErr := d.Detach(v.GetId(), nil); err == nil {
+				err = detachErr
+			}
+		}()
+	}
+	mountPath, err := ioutil.TempDir("", "osd-seed")
+	if err != nil {
+		return err
+	}
+	if err := d.Mount(v.GetId(), mountPath, nil); err != nil {
+		os.Remove(mountPath)
+		return err
+	}
+	err = fn(mountPath)
+	if unmountErr := d.Unmount(v.GetId(), mountPath, nil); unmountErr != nil {
+		if err == nil {
+			err = unmountErr
+		}
+		return err
+	}
+	os.Remove(mountPath)
+	return err
+}
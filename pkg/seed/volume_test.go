@@ -0,0 +1,183 @@
+package seed
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/volume/drivers/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osd-seed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	d := mock.NewMockVolumeDriver(ctrl)
+
+	_, err = New("volume://myvol", nil, nil)
+	assert.Equal(t, ErrUnsupported, err)
+
+	// A mounted volume is copied from where it is mounted.
+	mounted := filepath.Join(dir, "mounted")
+	require.NoError(t, os.MkdirAll(mounted, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(mounted, "file"), []byte("mounted"), 0644))
+	d.EXPECT().
+		Inspect([]string{"myvol"}).
+		Return([]*api.Volume{{Id: "myvol", AttachPath: []string{mounted}}}, nil)
+
+	s, err := New("volume://myvol", nil, d)
+	require.NoError(t, err)
+	dest := filepath.Join(dir, "frommounted")
+	require.NoError(t, s.Load(dest))
+	data, err := ioutil.ReadFile(filepath.Join(dest, "file"))
+	require.NoError(t, err)
+	assert.Equal(t, "mounted", string(data))
+
+	// A detached volume of a block driver is attached and mounted for the
+	// copy, and then unmounted and detached.
+	var mountPath string
+	gomock.InOrder(
+		d.EXPECT().
+			Inspect([]string{"myvol"}).
+			Return([]*api.Volume{{Id: "myvol"}}, nil),
+		d.EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_BLOCK),
+		d.EXPECT().
+			Attach("myvol", nil).
+			Return("/dev/fake", nil),
+		d.EXPECT().
+			Mount("myvol", gomock.Any(), nil).
+			Do(func(id string, path string, options map[string]string) {
+				mountPath = path
+				ioutil.WriteFile(filepath.Join(path, "file"), []byte("detached"), 0644)
+			}).
+			Return(nil),
+		d.EXPECT().
+			Unmount("myvol", gomock.Any(), nil).
+			Do(func(id string, path string, options map[string]string) {
+				assert.Equal(t, mountPath, path)
+				os.Remove(filepath.Join(path, "file"))
+			}).
+			Return(nil),
+		d.EXPECT().
+			Detach("myvol", nil).
+			Return(nil),
+	)
+	dest = filepath.Join(dir, "fromdetached")
+	require.NoError(t, s.Load(dest))
+	data, err = ioutil.ReadFile(filepath.Join(dest, "file"))
+	require.NoError(t, err)
+	assert.Equal(t, "detached", string(data))
+	_, err = os.Stat(mountPath)
+	assert.True(t, os.IsNotExist(err), "temporary mount path should be removed")
+}
+
+// memStore is a volume.Store that keeps volumes in memory.
+type memStore map[string]*api.Volume
+
+func (m memStore) Lock(volumeID string) (interface{}, error) { return nil, nil }
+func (m memStore) Unlock(token interface{}) error            { return nil }
+func (m memStore) CreateVol(vol *api.Volume) error           { m[vol.Id] = vol; return nil }
+func (m memStore) UpdateVol(vol *api.Volume) error           { m[vol.Id] = vol; return nil }
+func (m memStore) DeleteVol(volumeID string) error           { delete(m, volumeID); return nil }
+
+func (m memStore) GetVol(volumeID string) (*api.Volume, error) {
+	if v, ok := m[volumeID]; ok {
+		return v, nil
+	}
+	return nil, os.ErrNotExist
+}
+
+func TestLoadVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osd-seed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	d := mock.NewMockVolumeDriver(ctrl)
+
+	SetFileRoots([]string{dir})
+	defer SetFileRoots(nil)
+	source := filepath.Join(dir, "source")
+	require.NoError(t, os.MkdirAll(source, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(source, "file"), []byte("seeded"), 0644))
+	store := memStore{
+		"plain":  {Id: "plain", Source: &api.Source{}},
+		"seeded": {Id: "seeded", Source: &api.Source{Seed: "file://" + source}},
+		"broken": {Id: "broken", Source: &api.Source{Seed: "file://" + filepath.Join(dir, "missing")}},
+	}
+
+	// Volumes without a seed are not mounted.
+	require.NoError(t, LoadVolume(d, store, "plain", nil))
+	assert.Empty(t, store["plain"].RuntimeState)
+
+	gomock.InOrder(
+		d.EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_FILE),
+		d.EXPECT().
+			Mount("seeded", gomock.Any(), nil).
+			Return(nil),
+		d.EXPECT().
+			Unmount("seeded", gomock.Any(), nil).
+			Do(func(id string, path string, options map[string]string) {
+				data, err := ioutil.ReadFile(filepath.Join(path, "file"))
+				assert.NoError(t, err)
+				assert.Equal(t, "seeded", string(data))
+				_, err = os.Stat(filepath.Join(path, MetadataFile))
+				assert.True(t, os.IsNotExist(err), "nothing but the seed is written")
+				os.RemoveAll(path)
+			}).
+			Return(nil),
+	)
+	require.NoError(t, LoadVolume(d, store, "seeded", nil))
+	require.Len(t, store["seeded"].RuntimeState, 1)
+	assert.Contains(t, store["seeded"].RuntimeState[0].RuntimeState[RuntimeStateKey], source)
+
+	// The seed is loaded into the data directory of the volume if it has one.
+	store["datadir"] = &api.Volume{Id: "datadir", Source: &api.Source{Seed: "file://" + source}}
+	gomock.InOrder(
+		d.EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_FILE),
+		d.EXPECT().
+			Mount("datadir", gomock.Any(), nil).
+			Return(nil),
+		d.EXPECT().
+			Unmount("datadir", gomock.Any(), nil).
+			Do(func(id string, path string, options map[string]string) {
+				data, err := ioutil.ReadFile(filepath.Join(path, ".data", "file"))
+				assert.NoError(t, err)
+				assert.Equal(t, "seeded", string(data))
+				os.RemoveAll(path)
+			}).
+			Return(nil),
+	)
+	require.NoError(t, LoadVolumeDir(d, store, "datadir", ".data", nil))
+	require.Len(t, store["datadir"].RuntimeState, 1)
+
+	// The volume is unmounted when the seed fails to load.
+	gomock.InOrder(
+		d.EXPECT().
+			Type().
+			Return(api.DriverType_DRIVER_TYPE_FILE),
+		d.EXPECT().
+			Mount("broken", gomock.Any(), nil).
+			Return(nil),
+		d.EXPECT().
+			Unmount("broken", gomock.Any(), nil).
+			Return(nil),
+	)
+	assert.Error(t, LoadVolume(d, store, "broken", nil))
+	assert.Empty(t, store["broken"].RuntimeState)
+}
@@ -0,0 +1,142 @@
+package aws
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/libopenstorage/openstorage/pkg/storageops"
+	"github.com/portworx/sched-ops/task"
+)
+
+// The vendored aws-sdk-go predates elastic volumes, so the ModifyVolume and
+// DescribeVolumesModifications calls are built here on the generic EC2 query
+// protocol handlers of the client.
+const (
+	opModifyVolume                 = "ModifyVolume"
+	opDescribeVolumesModifications = "DescribeVolumesModifications"
+
+	modificationStateOptimizing = "optimizing"
+	modificationStateCompleted  = "completed"
+	modificationStateFailed     = "failed"
+
+	// modifyVolumeTimeout bounds the wait for a modification to take effect.
+	modifyVolumeTimeout = 5 * time.Minute
+)
+
+type modifyVolumeInput struct {
+	_ struct{} `type:"structure"`
+
+	// Size is the target size of the volume in GiB.
+	Size *int64 `type:"integer"`
+
+	// VolumeId is the ID of the volume to modify.
+	VolumeId *string `type:"string" required:"true"`
+}
+
+type modifyVolumeOutput struct {
+	_ struct{} `type:"structure"`
+
+	VolumeModification *volumeModification `locationName:"volumeModification" type:"structure"`
+}
+
+type describeVolumesModificationsInput struct {
+	_ struct{} `type:"structure"`
+
+	VolumeIds []*string `locationName:"VolumeId" locationNameList:"VolumeId" type:"list"`
+}
+
+type describeVolumesModificationsOutput struct {
+	_ struct{} `type:"structure"`
+
+	VolumesModifications []*volumeModification `locationName:"volumeModificationSet" locationNameList:"item" type:"list"`
+}
+
+type volumeModification struct {
+	_ struct{} `type:"structure"`
+
+	ModificationState *string `locationName:"modificationState" type:"string"`
+
+	StatusMessage *string `locationName:"statusMessage" type:"string"`
+
+	TargetSize *int64 `locationName:"targetSize" type:"integer"`
+
+	VolumeId *string `locationName:"volumeId" type:"string"`
+}
+
+func (s *ec2Ops) Expand(volumeID string, newSizeInGiB uint64) (uint64, error) {
+	vol, err := s.refreshVol(&volumeID)
+	if err != nil {
+		return 0, err
+	}
+	if vol.Size != nil && uint64(*vol.Size) >= newSizeInGiB {
+		return uint64(*vol.Size), nil
+	}
+
+	size := int64(newSizeInGiB)
+	input := &modifyVolumeInput{VolumeId: &volumeID, Size: &size}
+	output := &modifyVolumeOutput{}
+	op := &request.Operation{
+		Name:       opModifyVolume,
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	if err := s.ec2.NewRequest(op, input, output).Send(); err != nil {
+		return 0, err
+	}
+
+	if err := s.waitModification(volumeID); err != nil {
+		return 0, err
+	}
+	return newSizeInGiB, nil
+}
+
+// waitModification waits until the latest modification of the volume is
+// optimizing or completed. The new size can be used from then on.
+func (s *ec2Ops) waitModification(volumeID string) error {
+	id := volumeID
+	input := &describeVolumesModificationsInput{VolumeIds: []*string{&id}}
+	op := &request.Operation{
+		Name:       opDescribeVolumesModifications,
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+
+	_, err := task.DoRetryWithTimeout(
+		func() (interface{}, bool, error) {
+			output := &describeVolumesModificationsOutput{}
+			if err := s.ec2.NewRequest(op, input, output).Send(); err != nil {
+				return nil, true, err
+			}
+
+			if len(output.VolumesModifications) != 1 ||
+				output.VolumesModifications[0].ModificationState == nil {
+				return nil, true, fmt.Errorf(
+					"No modification state for volume %v", volumeID)
+			}
+
+			mod := output.VolumesModifications[0]
+			switch *mod.ModificationState {
+			case modificationStateOptimizing, modificationStateCompleted:
+				return nil, false, nil
+			case modificationStateFailed:
+				msg := ""
+				if mod.StatusMessage != nil {
+					msg = *mod.StatusMessage
+				}
+				return nil, false, storageops.NewStorageError(
+					storageops.ErrVolInval,
+					fmt.Sprintf("Modification of volume %v failed: %v",
+						volumeID, msg),
+					"")
+			}
+
+			return nil, true, fmt.Errorf(
+				"Volume %v modification is %v", volumeID,
+				*mod.ModificationState)
+		},
+		modifyVolumeTimeout,
+		storageops.ProviderOpsRetryInterval)
+
+	return err
+}
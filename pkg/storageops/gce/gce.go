@@ -191,6 +191,30 @@ func (s *gceOps) Delete(id string) error {
 	return err
 }
 
+func (s *gceOps) Expand(diskName string, newSizeInGiB uint64) (uint64, error) {
+	d, err := s.service.Disks.Get(s.inst.Project, s.inst.Zone, diskName).Do()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(d.SizeGb) >= newSizeInGiB {
+		return uint64(d.SizeGb), nil
+	}
+
+	_, err = s.service.Disks.Resize(
+		s.inst.Project,
+		s.inst.Zone,
+		diskName,
+		&compute.DisksResizeRequest{SizeGb: int64(newSizeInGiB)}).Do()
+	if err != nil {
+		return 0, err
+	}
+
+	if err = s.waitForResize(diskName, newSizeInGiB); err != nil {
+		return 0, err
+	}
+	return newSizeInGiB, nil
+}
+
 func (s *gceOps) Detach(devicePath string) error {
 	_, err := s.service.Instances.DetachDisk(
 		s.inst.Project,
@@ -539,6 +563,29 @@ func (s *gceOps) rollbackCreate(id string, createErr error) error {
 	return createErr
 }
 
+// waitForResize checks if given disk is ready at the given size
+func (s *gceOps) waitForResize(diskName string, sizeInGiB uint64) error {
+	_, err := task.DoRetryWithTimeout(
+		func() (interface{}, bool, error) {
+			d, err := s.service.Disks.Get(s.inst.Project, s.inst.Zone, diskName).Do()
+			if err != nil {
+				return nil, true, err
+			}
+
+			if d.Status != STATUS_READY || uint64(d.SizeGb) < sizeInGiB {
+				return nil, true,
+					fmt.Errorf("disk: %s is %s at %d GiB. expected: %s at %d GiB",
+						diskName, d.Status, d.SizeGb, STATUS_READY, sizeInGiB)
+			}
+
+			return nil, false, nil
+		},
+		storageops.ProviderOpsTimeout,
+		storageops.ProviderOpsRetryInterval)
+
+	return err
+}
+
 // waitForAttach checks if given disk is detached from the local instance
 func (s *gceOps) waitForDetach(
 	diskURL string,
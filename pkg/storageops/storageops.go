@@ -43,6 +43,9 @@ type Ops interface {
 	Detach(volumeID string) error
 	// Delete volumeID.
 	Delete(volumeID string) error
+	// Expand grows volumeID to newSizeInGiB and returns its size in GiB once
+	// the new size is usable. Requests to shrink the volume are ignored.
+	Expand(volumeID string, newSizeInGiB uint64) (uint64, error)
 	// FreeDevices returns free block devices on the instance.
 	// blockDeviceMappings is a data structure that contains all block devices on
 	// the instance and where they are mapped to
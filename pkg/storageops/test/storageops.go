@@ -24,6 +24,7 @@ func RunTest(drivers map[string]storageops.Ops,
 			inspect(t, d, diskName)
 			attach(t, d, diskName)
 			devicePath(t, d, diskName)
+			expand(t, d, diskName)
 			teardown(t, d, diskName)
 		}
 	}
@@ -112,6 +113,16 @@ func devicePath(t *testing.T, driver storageops.Ops, diskName string) {
 	require.NotEmpty(t, devPath, "received empty devicePath")
 }
 
+func expand(t *testing.T, driver storageops.Ops, diskName string) {
+	size, err := driver.Expand(diskName, 0)
+	require.NoError(t, err, "shrinking disk returned error")
+	require.NotZero(t, size, "got zero disk size")
+
+	newSize, err := driver.Expand(diskName, size+1)
+	require.NoError(t, err, "failed to expand disk")
+	require.Equal(t, size+1, newSize, "disk size did not change")
+}
+
 func teardown(t *testing.T, driver storageops.Ops, diskName string) {
 	err := driver.Detach(diskName)
 	require.NoError(t, err, "disk detach returned error")
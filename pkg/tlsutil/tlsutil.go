@@ -0,0 +1,181 @@
+// Package tlsutil sets up TLS servers from PEM files and reloads the files
+// while the servers keep running.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/Sirupsen/logrus"
+)
+
+var (
+	// ErrNoCertificate is returned for a Config without certificate or key.
+	ErrNoCertificate = errors.New("Certificate and key files must be provided")
+	// ErrNoClientCertificate is returned to clients that do not present a
+	// certificate when a client CA is configured.
+	ErrNoClientCertificate = errors.New("Client certificate required")
+)
+
+// Config names the PEM files a TLS server is set up from.
+type Config struct {
+	// CertFile holds the server certificate, followed by any intermediates.
+	CertFile string `yaml:"cert_file"`
+	// KeyFile holds the private key of the server certificate.
+	KeyFile string `yaml:"key_file"`
+	// CAFile, if set, holds the CAs that client certificates must chain up
+	// to. Clients must then present a certificate.
+	CAFile string `yaml:"ca_file"`
+}
+
+// Reloader holds the certificates named in a Config. The tls.Config it
+// returns always uses the most recently loaded certificates, so that
+// certificates can be rotated without restarting the server.
+type Reloader struct {
+	config    Config
+	lock      sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	stop      chan struct{}
+}
+
+// NewReloader loads the files named in config.
+func NewReloader(config *Config) (*Reloader, error) {
+	if config == nil || config.CertFile == "" || config.KeyFile == "" {
+		return nil, ErrNoCertificate
+	}
+	r := &Reloader{config: *config}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the certificate, key and client CAs again. The previously
+// loaded ones are kept if any of the files is invalid.
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("Unable to load certificate %v: %v",
+			r.config.CertFile, err)
+	}
+	var pool *x509.CertPool
+	if r.config.CAFile != "" {
+		pem, err := ioutil.ReadFile(r.config.CAFile)
+		if err != nil {
+			return fmt.Errorf("Unable to read CA file %v: %v",
+				r.config.CAFile, err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("No certificates found in CA file %v",
+				r.config.CAFile)
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cert = &cert
+	r.clientCAs = pool
+	return nil
+}
+
+// TLSConfig returns a server configuration that presents the current
+// certificate and, if a CA file is configured, requires client certificates
+// signed by one of the current CAs.
+func (r *Reloader) TLSConfig() *tls.Config {
+	c := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.getCertificate,
+	}
+	if r.config.CAFile != "" {
+		// The CAs are checked by verifyClient so that they can change
+		// without changing the configuration.
+		c.ClientAuth = tls.RequireAnyClientCert
+		c.VerifyPeerCertificate = r.verifyClient
+	}
+	return c
+}
+
+// ReloadOnSignal reloads the files every time the process receives one of
+// sigs, or SIGHUP if none are given, until Close is called.
+func (r *Reloader) ReloadOnSignal(sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop != nil {
+		return
+	}
+	r.stop = make(chan struct{})
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	go func(stop chan struct{}) {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ch:
+				if err := r.Reload(); err != nil {
+					logrus.Warnf("Failed to reload TLS certificates: %v", err)
+				} else {
+					logrus.Infof("Reloaded TLS certificate %v",
+						r.config.CertFile)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}(r.stop)
+}
+
+// Close stops reloading on signals.
+func (r *Reloader) Close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
+
+func (r *Reloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.cert, nil
+}
+
+func (r *Reloader) verifyClient(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return ErrNoClientCertificate
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+	r.lock.RLock()
+	roots := r.clientCAs
+	r.lock.RUnlock()
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(opts)
+	return err
+}
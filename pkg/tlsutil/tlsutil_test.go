@@ -0,0 +1,239 @@
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+// newCert returns a certificate for cn signed by parent, or a self-signed
+// CA certificate if parent is nil.
+func newCert(t *testing.T, cn string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+		tmpl.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) certPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der})
+}
+
+func (c *testCert) keyPEM(t *testing.T) []byte {
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func (c *testCert) tlsCertificate(t *testing.T) tls.Certificate {
+	cert, err := tls.X509KeyPair(c.certPEM(), c.keyPEM(t))
+	require.NoError(t, err)
+	return cert
+}
+
+func (c *testCert) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(c.cert)
+	return pool
+}
+
+// writeFiles writes the server certificate and key, and the client CA if
+// not nil, to dir and returns the Config naming them.
+func writeFiles(t *testing.T, dir string, server, clientCA *testCert) *Config {
+	config := &Config{
+		CertFile: filepath.Join(dir, "server.crt"),
+		KeyFile:  filepath.Join(dir, "server.key"),
+	}
+	require.NoError(t, ioutil.WriteFile(config.CertFile, server.certPEM(), 0600))
+	require.NoError(t, ioutil.WriteFile(config.KeyFile, server.keyPEM(t), 0600))
+	if clientCA != nil {
+		config.CAFile = filepath.Join(dir, "ca.crt")
+		require.NoError(t, ioutil.WriteFile(config.CAFile, clientCA.certPEM(), 0600))
+	}
+	return config
+}
+
+// serve accepts TLS connections on a local port and writes "ok" to every
+// client that completes the handshake.
+func serve(t *testing.T, r *Reloader) net.Listener {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", r.TLSConfig())
+	require.NoError(t, err)
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			if err := c.(*tls.Conn).Handshake(); err == nil {
+				c.Write([]byte("ok"))
+			}
+			c.Close()
+		}
+	}()
+	return l
+}
+
+// dial connects to l and returns the server certificate once the server
+// has accepted the client.
+func dial(l net.Listener, roots *x509.CertPool, client *tls.Certificate) (*x509.Certificate, error) {
+	config := &tls.Config{RootCAs: roots}
+	if client != nil {
+		config.Certificates = []tls.Certificate{*client}
+	}
+	c, err := tls.Dial("tcp", l.Addr().String(), config)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	if _, err := ioutil.ReadAll(c); err != nil {
+		return nil, err
+	}
+	return c.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestServerTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osd-tlsutil")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = NewReloader(&Config{CertFile: "server.crt"})
+	require.Equal(t, ErrNoCertificate, err)
+	_, err = NewReloader(&Config{
+		CertFile: filepath.Join(dir, "missing.crt"),
+		KeyFile:  filepath.Join(dir, "missing.key"),
+	})
+	require.Error(t, err)
+
+	ca := newCert(t, "ca", nil, 0)
+	server := newCert(t, "server", ca, x509.ExtKeyUsageServerAuth)
+	r, err := NewReloader(writeFiles(t, dir, server, nil))
+	require.NoError(t, err)
+	l := serve(t, r)
+	defer l.Close()
+
+	cert, err := dial(l, ca.pool(), nil)
+	require.NoError(t, err)
+	require.Equal(t, "server", cert.Subject.CommonName)
+
+	_, err = dial(l, x509.NewCertPool(), nil)
+	require.Error(t, err)
+}
+
+func TestClientCertificateRequired(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osd-tlsutil")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newCert(t, "ca", nil, 0)
+	server := newCert(t, "server", ca, x509.ExtKeyUsageServerAuth)
+	r, err := NewReloader(writeFiles(t, dir, server, ca))
+	require.NoError(t, err)
+	l := serve(t, r)
+	defer l.Close()
+
+	_, err = dial(l, ca.pool(), nil)
+	require.Error(t, err)
+
+	client := newCert(t, "client", ca, x509.ExtKeyUsageClientAuth).tlsCertificate(t)
+	_, err = dial(l, ca.pool(), &client)
+	require.NoError(t, err)
+
+	// Client certificates must be issued by the configured CA.
+	other := newCert(t, "other-ca", nil, 0)
+	stranger := newCert(t, "client", other, x509.ExtKeyUsageClientAuth).tlsCertificate(t)
+	_, err = dial(l, ca.pool(), &stranger)
+	require.Error(t, err)
+
+	// Server certificates are not accepted as client certificates.
+	serverAsClient := server.tlsCertificate(t)
+	_, err = dial(l, ca.pool(), &serverAsClient)
+	require.Error(t, err)
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osd-tlsutil")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newCert(t, "ca", nil, 0)
+	r, err := NewReloader(writeFiles(t, dir,
+		newCert(t, "one", ca, x509.ExtKeyUsageServerAuth), ca))
+	require.NoError(t, err)
+	r.ReloadOnSignal()
+	defer r.Close()
+	l := serve(t, r)
+	defer l.Close()
+
+	client := newCert(t, "client", ca, x509.ExtKeyUsageClientAuth).tlsCertificate(t)
+	cert, err := dial(l, ca.pool(), &client)
+	require.NoError(t, err)
+	require.Equal(t, "one", cert.Subject.CommonName)
+
+	// Rotate the server certificate and the client CA.
+	newCA := newCert(t, "new-ca", nil, 0)
+	writeFiles(t, dir, newCert(t, "two", newCA, x509.ExtKeyUsageServerAuth), newCA)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	newClient := newCert(t, "client", newCA, x509.ExtKeyUsageClientAuth).tlsCertificate(t)
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		cert, err = dial(l, newCA.pool(), &newClient)
+		if err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	require.NoError(t, err)
+	require.Equal(t, "two", cert.Subject.CommonName)
+
+	_, err = dial(l, newCA.pool(), &client)
+	require.Error(t, err)
+
+	// Invalid files leave the loaded certificates in place.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "server.crt"), []byte("junk"), 0600))
+	require.Error(t, r.Reload())
+	_, err = dial(l, newCA.pool(), &newClient)
+	require.NoError(t, err)
+}
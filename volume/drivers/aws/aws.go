@@ -358,6 +358,28 @@ func (d *Driver) Restore(volumeID string, snapID string) error {
 	return volume.ErrNotSupported
 }
 
+// SnapDelete deletes the EBS snapshot. EBS snapshots cannot be snapshotted
+// themselves, so there is never anything to cascade to.
+func (d *Driver) SnapDelete(snapID string, cascade bool) error {
+	snap, err := d.GetVol(snapID)
+	if err != nil {
+		return volume.ErrEnoEnt
+	}
+	if snap.Source == nil || snap.Source.Parent == "" {
+		return volume.ErrEinval
+	}
+	if err := d.ops.SnapshotDelete(snapID); err != nil {
+		return err
+	}
+	return d.DeleteVol(snapID)
+}
+
+func (d *Driver) Promote(cloneID string) error {
+	// EBS snapshots are not clones, volumes created from them are
+	// independent to begin with.
+	return volume.ErrNotSupported
+}
+
 func (d *Driver) Attach(
 	volumeID string,
 	attachOptions map[string]string,
@@ -479,19 +501,80 @@ func (d *Driver) Mount(volumeID string, mountpath string, options map[string]str
 	if err != nil {
 		return err
 	}
-	return nil
+	// The volume may have been expanded while it was not attached here.
+	if err := common.GrowFilesystem(volume.Spec.Format, devicePath, mountpath); err != nil {
+		dlog.Warnf("Failed to grow filesystem of volume %v: %v", volumeID, err)
+	}
+	volume.AttachPath = append(volume.AttachPath, mountpath)
+	return d.UpdateVol(volume)
 }
 
 func (d *Driver) Unmount(volumeID string, mountpath string, options map[string]string) error {
 	// XXX:  determine if valid mount path
-	err := syscall.Unmount(mountpath, 0)
-	return err
+	if err := syscall.Unmount(mountpath, 0); err != nil {
+		return err
+	}
+	volume, err := d.GetVol(volumeID)
+	if err != nil {
+		return nil
+	}
+	for i, p := range volume.AttachPath {
+		if p == mountpath {
+			volume.AttachPath = append(volume.AttachPath[:i], volume.AttachPath[i+1:]...)
+			return d.UpdateVol(volume)
+		}
+	}
+	return nil
 }
 
 func (d *Driver) Shutdown() {
 	dlog.Printf("%s Shutting down", Name)
 }
 
+// Set updates the locator. Of the spec only the size can be changed, which
+// expands the EBS volume and, if it is attached here, its filesystem.
 func (d *Driver) Set(volumeID string, locator *api.VolumeLocator, spec *api.VolumeSpec) error {
-	return volume.ErrNotSupported
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return err
+	}
+	grow, err := common.ValidateSizeOnlyResize(v, spec)
+	if err != nil {
+		return err
+	}
+	if grow {
+		if err := d.expand(v, spec.Size); err != nil {
+			return err
+		}
+		v.Spec.Size = spec.Size
+	}
+	if locator != nil {
+		v.Locator = locator
+	}
+	return d.UpdateVol(v)
+}
+
+// expand grows the EBS volume to at least size bytes. Volumes that are not
+// attached to this instance get their filesystem grown on the next mount.
+func (d *Driver) expand(v *api.Volume, size uint64) error {
+	// Spec size is in bytes, translate to GiB rounding up.
+	sz := (size + (1<<30 - 1)) / (1 << 30)
+	if _, err := d.ops.Expand(v.Id, sz); err != nil {
+		dlog.Warnf("Failed to expand volume %v to %v GiB: %v", v.Id, sz, err)
+		return err
+	}
+	devicePath, err := d.ops.DevicePath(v.Id)
+	if err != nil {
+		if serr, ok := err.(*storageops.StorageError); ok &&
+			(serr.Code == storageops.ErrVolDetached ||
+				serr.Code == storageops.ErrVolAttachedOnRemoteNode) {
+			return nil
+		}
+		return err
+	}
+	mountPath := ""
+	if len(v.AttachPath) > 0 {
+		mountPath = v.AttachPath[0]
+	}
+	return common.GrowFilesystem(v.Spec.Format, devicePath, mountPath)
 }
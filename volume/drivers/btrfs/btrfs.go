@@ -4,7 +4,9 @@ package btrfs
 
 import (
 	"fmt"
+	"os/exec"
 	"path/filepath"
+	"strconv"
 	"syscall"
 
 	"go.pedge.io/proto/time"
@@ -13,6 +15,7 @@ import (
 	"github.com/docker/docker/daemon/graphdriver/btrfs"
 	"github.com/libopenstorage/openstorage/api"
 	"github.com/libopenstorage/openstorage/pkg/chaos"
+	"github.com/libopenstorage/openstorage/pkg/seed"
 	"github.com/libopenstorage/openstorage/volume"
 	"github.com/libopenstorage/openstorage/volume/drivers/common"
 	"github.com/pborman/uuid"
@@ -97,10 +100,20 @@ func (d *driver) Create(
 		return volume.Id, err
 	}
 	volume.DevicePath = devicePath
-	return volume.Id, d.UpdateVol(volume)
+	if err := d.UpdateVol(volume); err != nil {
+		return volume.Id, err
+	}
+	if err := seed.LoadVolume(d, d, volume.Id, spec.VolumeLabels); err != nil {
+		d.Delete(volume.Id)
+		return "", err
+	}
+	return volume.Id, nil
 }
 
 func (d *driver) Delete(volumeID string) error {
+	if err := common.CheckNoSnaps(d, volumeID); err != nil {
+		return err
+	}
 	if err := d.DeleteVol(volumeID); err != nil {
 		return err
 	}
@@ -135,20 +148,45 @@ func (d *driver) Unmount(volumeID string, mountpath string) error {
 	return d.UpdateVol(v)
 }
 
+// Set updates the locator. Of the spec only the size can be changed, which
+// raises the qgroup limit of the subvolume.
 func (d *driver) Set(volumeID string, locator *api.VolumeLocator, spec *api.VolumeSpec) error {
-	if spec != nil {
-		return volume.ErrNotSupported
-	}
 	v, err := d.GetVol(volumeID)
 	if err != nil {
 		return err
 	}
+	grow, err := common.ValidateSizeOnlyResize(v, spec)
+	if err != nil {
+		return err
+	}
+	if grow {
+		if err := d.limit(v, spec.Size); err != nil {
+			return err
+		}
+		v.Spec.Size = spec.Size
+	}
 	if locator != nil {
 		v.Locator = locator
 	}
 	return d.UpdateVol(v)
 }
 
+// limit sets the qgroup limit of the volume's subvolume to size bytes.
+// Quotas are enabled on the volumes filesystem on first use.
+func (d *driver) limit(v *api.Volume, size uint64) error {
+	home := filepath.Join(d.root, Volumes)
+	if o, err := exec.Command("btrfs", "quota", "enable", home).CombinedOutput(); err != nil {
+		return fmt.Errorf("Failed to enable quotas on %v: %v: %s", home, err, o)
+	}
+	o, err := exec.Command("btrfs", "qgroup", "limit",
+		strconv.FormatUint(size, 10), v.DevicePath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Failed to limit %v to %v bytes: %v: %s",
+			v.DevicePath, size, err, o)
+	}
+	return nil
+}
+
 // Snapshot create new subvolume from volume
 func (d *driver) Snapshot(volumeID string, readonly bool, locator *api.VolumeLocator) (string, error) {
 	vols, err := d.Inspect([]string{volumeID})
@@ -175,6 +213,14 @@ func (d *driver) Snapshot(volumeID string, readonly bool, locator *api.VolumeLoc
 	return vols[0].Id, nil
 }
 
+func (d *driver) SnapDelete(snapID string, cascade bool) error {
+	return common.SnapDelete(d, snapID, cascade)
+}
+
+func (d *driver) Promote(cloneID string) error {
+	return common.Promote(d, cloneID)
+}
+
 func (d *driver) Stats(volumeID string) (*api.Stats, error) {
 	return nil, nil
 }
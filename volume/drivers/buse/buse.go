@@ -13,6 +13,7 @@ import (
 
 	"github.com/libopenstorage/openstorage/api"
 	"github.com/libopenstorage/openstorage/cluster"
+	"github.com/libopenstorage/openstorage/pkg/seed"
 	"github.com/libopenstorage/openstorage/volume"
 	"github.com/libopenstorage/openstorage/volume/drivers/common"
 	"github.com/pborman/uuid"
@@ -165,9 +166,25 @@ func (d *driver) Create(
 	if spec.Format == api.FSType_FS_TYPE_NONE {
 		return "", fmt.Errorf("Missing volume format: buse")
 	}
-	// Create a file on the local buse path with this UUID.
+	// Create a file on the local buse path with this UUID. A clone starts as
+	// a copy-on-write copy of the file of its parent, which already holds a
+	// filesystem.
 	buseFile := path.Join(BuseMountPath, volumeID)
-	f, err := os.Create(buseFile)
+	var parent *api.Volume
+	if parentID := source.GetParent(); parentID != "" {
+		var err error
+		if parent, err = d.GetVol(parentID); err != nil {
+			return "", err
+		}
+		if spec.Size < parent.GetSpec().GetSize() {
+			return "", volume.ErrVolShrink
+		}
+		if err := common.CloneFile(path.Join(BuseMountPath, parentID), buseFile); err != nil {
+			os.Remove(buseFile)
+			return "", fmt.Errorf("Failed to clone volume %v: %v", parentID, err)
+		}
+	}
+	f, err := os.OpenFile(buseFile, os.O_RDWR|os.O_CREATE, 0666)
 	if err != nil {
 		dlog.Println(err)
 		return "", err
@@ -192,12 +209,18 @@ func (d *driver) Create(
 		return "", err
 	}
 
-	dlog.Infof("Formatting %s with %v", dev, spec.Format)
-	cmd := "/sbin/mkfs." + spec.Format.SimpleString()
-	o, err := exec.Command(cmd, dev).Output()
-	if err != nil {
-		dlog.Warnf("Failed to run command %v %v: %v", cmd, dev, o)
-		return "", err
+	if parent == nil {
+		dlog.Infof("Formatting %s with %v", dev, spec.Format)
+		cmd := "/sbin/mkfs." + spec.Format.SimpleString()
+		o, err := exec.Command(cmd, dev).Output()
+		if err != nil {
+			dlog.Warnf("Failed to run command %v %v: %v", cmd, dev, o)
+			return "", err
+		}
+	} else if spec.Size > parent.GetSpec().GetSize() {
+		if err := common.GrowFilesystem(spec.Format, dev, ""); err != nil {
+			return "", err
+		}
 	}
 
 	dlog.Infof("BUSE mapped NBD device %s (size=%v) to block file %s", dev,
@@ -218,7 +241,12 @@ func (d *driver) Create(
 	if err != nil {
 		return "", err
 	}
-	return v.Id, err
+	// The device holds a filesystem by now, so it can be mounted for seeding.
+	if err := seed.LoadVolume(d, d, v.Id, spec.VolumeLabels); err != nil {
+		d.Delete(v.Id)
+		return "", err
+	}
+	return v.Id, nil
 }
 
 func (d *driver) Delete(volumeID string) error {
@@ -227,6 +255,9 @@ func (d *driver) Delete(volumeID string) error {
 		dlog.Println(err)
 		return err
 	}
+	if err := common.CheckNoSnaps(d, volumeID); err != nil {
+		return err
+	}
 
 	bd, ok := d.buseDevices[v.DevicePath]
 	if !ok {
@@ -299,19 +330,14 @@ func (d *driver) Snapshot(volumeID string, readonly bool, locator *api.VolumeLoc
 		return "", nil
 	}
 
+	// BUSE does not support snapshots, so the snapshot is a clone of the
+	// block file.
 	source := &api.Source{Parent: volumeID}
 	newVolumeID, err := d.Create(locator, source, vols[0].Spec)
 	if err != nil {
 		return "", nil
 	}
 
-	// BUSE does not support snapshots, so just copy the block files.
-	err = copyFile(BuseMountPath+volumeID, BuseMountPath+newVolumeID)
-	if err != nil {
-		d.Delete(newVolumeID)
-		return "", nil
-	}
-
 	return newVolumeID, nil
 }
 
@@ -324,20 +350,60 @@ func (d *driver) Restore(volumeID string, snapID string) error {
 	return copyFile(BuseMountPath+snapID, BuseMountPath+volumeID)
 }
 
+func (d *driver) SnapDelete(snapID string, cascade bool) error {
+	return common.SnapDelete(d, snapID, cascade)
+}
+
+func (d *driver) Promote(cloneID string) error {
+	return common.Promote(d, cloneID)
+}
+
+// Set updates the locator. Of the spec only the size can be changed, which
+// grows the block file, the NBD device and the filesystem on it.
 func (d *driver) Set(volumeID string, locator *api.VolumeLocator, spec *api.VolumeSpec) error {
-	if spec != nil {
-		return volume.ErrNotSupported
-	}
 	v, err := d.GetVol(volumeID)
 	if err != nil {
 		return err
 	}
+	grow, err := common.ValidateSizeOnlyResize(v, spec)
+	if err != nil {
+		return err
+	}
+	if grow {
+		if err := d.expand(v, spec.Size); err != nil {
+			return err
+		}
+		v.Spec.Size = spec.Size
+	}
 	if locator != nil {
 		v.Locator = locator
 	}
 	return d.UpdateVol(v)
 }
 
+func (d *driver) expand(v *api.Volume, size uint64) error {
+	bd, ok := d.buseDevices[v.DevicePath]
+	if !ok {
+		return fmt.Errorf("Cannot locate a BUSE device for %s", v.DevicePath)
+	}
+	if err := bd.f.Truncate(int64(size)); err != nil {
+		return err
+	}
+	if err := bd.nbd.Size(int64(size)); err != nil {
+		return err
+	}
+	mountPath := ""
+	if len(v.AttachPath) > 0 {
+		mountPath = v.AttachPath[0]
+	}
+	if err := common.GrowFilesystem(v.Spec.Format, v.DevicePath, mountPath); err != nil {
+		return err
+	}
+	dlog.Infof("BUSE expanded volume %v at NBD device %s to %v bytes",
+		v.Id, v.DevicePath, size)
+	return nil
+}
+
 func (d *driver) Attach(volumeID string, attachOptions map[string]string) (string, error) {
 	// Nothing to do on attach.
 	return path.Join(BuseMountPath, volumeID), nil
@@ -148,6 +148,8 @@ func (nbd *NBD) Size(size int64) (err error) {
 			Path: "ioctl NBD_SET_SIZE_BLOCKS",
 			Err:  err,
 		}
+	} else {
+		nbd.size = size
 	}
 
 	return err
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// ficlone is the FICLONE ioctl, which makes the destination file share the
+// extents of the source file on filesystems that support reflinks.
+const ficlone = 0x40049409
+
+// CloneFile copies source to dest. The data is shared copy-on-write where
+// the filesystem supports reflinks and copied otherwise. dest is replaced if
+// it exists and gets the permissions of source.
+func CloneFile(source string, dest string) error {
+	src, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, dst.Fd(), ficlone, src.Fd())
+	if errno != 0 {
+		if _, err := io.Copy(dst, src); err != nil {
+			dst.Close()
+			return err
+		}
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(dest, info.Mode().Perm())
+}
+
+// CloneTree copies the directory tree at source into dest, which is created
+// if it does not exist. Files are copied with CloneFile and symlinks are
+// recreated. Other special files are not supported.
+func CloneTree(source string, dest string) error {
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, mode.Perm()); err != nil {
+				return err
+			}
+			return os.Chmod(target, mode.Perm())
+		case mode.IsRegular():
+			return CloneFile(path, target)
+		case mode&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		return fmt.Errorf("Cannot clone %v: unsupported file type %v", path, info.Mode())
+	})
+}
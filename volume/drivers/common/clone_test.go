@@ -0,0 +1,47 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osd-clone")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "source")
+	require.NoError(t, os.MkdirAll(filepath.Join(source, "a", "b"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(source, "top"), []byte("top"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(source, "a", "b", "deep"), []byte("deep"), 0644))
+	require.NoError(t, os.Symlink("a/b/deep", filepath.Join(source, "link")))
+
+	dest := filepath.Join(dir, "dest")
+	require.NoError(t, CloneTree(source, dest))
+
+	data, err := ioutil.ReadFile(filepath.Join(dest, "top"))
+	require.NoError(t, err)
+	assert.Equal(t, "top", string(data))
+	info, err := os.Stat(filepath.Join(dest, "top"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	data, err = ioutil.ReadFile(filepath.Join(dest, "a", "b", "deep"))
+	require.NoError(t, err)
+	assert.Equal(t, "deep", string(data))
+
+	link, err := os.Readlink(filepath.Join(dest, "link"))
+	require.NoError(t, err)
+	assert.Equal(t, "a/b/deep", link)
+
+	// The clone is independent of its source.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dest, "top"), []byte("changed"), 0600))
+	data, err = ioutil.ReadFile(filepath.Join(source, "top"))
+	require.NoError(t, err)
+	assert.Equal(t, "top", string(data))
+}
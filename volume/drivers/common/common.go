@@ -28,7 +28,63 @@ func NewVolume(
 	}
 }
 
+// SnapDelete deletes the snapshot or clone snapID through the driver's Delete.
+// Descendants of snapID are deleted first, deepest first, if cascade is set,
+// otherwise ErrVolHasSnaps is returned when there are any. A volume without
+// a parent is only deleted, with all its descendants, if cascade is set.
+func SnapDelete(driver volume.VolumeDriver, snapID string, cascade bool) error {
+	tree, err := driver.SnapTree(snapID)
+	if err != nil {
+		return err
+	}
+	if (tree.Volume.Source == nil || tree.Volume.Source.Parent == "") && !cascade {
+		return volume.ErrEinval
+	}
+	if len(tree.Children) > 0 && !cascade {
+		return volume.ErrVolHasSnaps
+	}
+	return deleteTree(driver, tree)
+}
+
+// CheckNoSnaps returns ErrVolHasSnaps if volumeID has snapshots or clones.
+// Drivers check it on Delete, so that descendants are only deleted along
+// with their parent by SnapDelete with cascade.
+func CheckNoSnaps(store volume.StoreEnumerator, volumeID string) error {
+	snaps, err := store.SnapEnumerate([]string{volumeID}, nil)
+	if err != nil {
+		return err
+	}
+	if len(snaps) > 0 {
+		return volume.ErrVolHasSnaps
+	}
+	return nil
+}
+
+// Promote makes the clone cloneID independent of its parent by dropping the
+// parent from its source. This is only valid for drivers whose clones do not
+// share data with their parent once created.
+func Promote(store volume.Store, cloneID string) error {
+	v, err := store.GetVol(cloneID)
+	if err != nil {
+		return volume.ErrEnoEnt
+	}
+	if !v.IsClone() {
+		return volume.ErrEinval
+	}
+	v.Source.Parent = ""
+	return store.UpdateVol(v)
+}
+
 // NewDefaultStoreEnumerator returns a default store enumerator
 func NewDefaultStoreEnumerator(driver string, kvdb kvdb.Kvdb) volume.StoreEnumerator {
 	return newDefaultStoreEnumerator(driver, kvdb)
 }
+
+func deleteTree(driver volume.VolumeDriver, tree *api.SnapshotTree) error {
+	for _, child := range tree.Children {
+		if err := deleteTree(driver, child); err != nil {
+			return err
+		}
+	}
+	return driver.Delete(tree.Volume.Id)
+}
@@ -1,14 +1,17 @@
 package common
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	// TODO(pedge): what is this for?
-	_ "sync"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/portworx/kvdb"
 
 	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/volume"
 )
 
 const (
@@ -18,6 +21,10 @@ const (
 type defaultStoreEnumerator struct {
 	driver string
 	kvdb   kvdb.Kvdb
+	// indexLock protects indexed, which is set once the volumes stored
+	// before the ID index existed have been added to it.
+	indexLock sync.Mutex
+	indexed   bool
 }
 
 func newDefaultStoreEnumerator(driver string, kvdb kvdb.Kvdb) *defaultStoreEnumerator {
@@ -43,7 +50,10 @@ func (e *defaultStoreEnumerator) Unlock(token interface{}) error {
 
 // CreateVol returns error if volume with the same ID already existe.
 func (e *defaultStoreEnumerator) CreateVol(vol *api.Volume) error {
-	_, err := e.kvdb.Create(e.volKey(vol.Id), vol, 0)
+	if _, err := e.kvdb.Create(e.volKey(vol.Id), vol, 0); err != nil {
+		return err
+	}
+	_, err := e.kvdb.Put(e.idKey(vol.Id), vol.Id, 0)
 	return err
 }
 
@@ -62,8 +72,13 @@ func (e *defaultStoreEnumerator) UpdateVol(vol *api.Volume) error {
 
 // DeleteVol. Returns error if volume does not exist.
 func (e *defaultStoreEnumerator) DeleteVol(volumeID string) error {
-	_, err := e.kvdb.Delete(e.volKey(volumeID))
-	return err
+	if _, err := e.kvdb.Delete(e.volKey(volumeID)); err != nil {
+		return err
+	}
+	if _, err := e.kvdb.Delete(e.idKey(volumeID)); err != nil && err != kvdb.ErrNotFound {
+		return err
+	}
+	return nil
 }
 
 // Inspect specified volumes.
@@ -105,6 +120,86 @@ func (e *defaultStoreEnumerator) Enumerate(
 	return volumes, nil
 }
 
+// EnumerateWithFilter returns a page of the volumes that match filter.
+// Volumes are ordered by ID. The IDs are listed from the ID index, and only
+// the volumes after the continuation token are read, up to the end of the
+// page.
+func (e *defaultStoreEnumerator) EnumerateWithFilter(
+	filter *api.VolumeFilter,
+) (*api.VolumeList, error) {
+	if filter == nil {
+		filter = &api.VolumeFilter{}
+	}
+	after, err := decodeToken(filter.Token)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.indexVolumes(); err != nil {
+		return nil, err
+	}
+
+	prefix := e.idKeyPrefix()
+	kvp, err := e.kvdb.Enumerate(prefix)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(kvp))
+	for _, v := range kvp {
+		id := strings.TrimPrefix(v.Key, prefix)
+		if after != "" && id <= after {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	list := &api.VolumeList{Volumes: make([]*api.Volume, 0)}
+	for i, id := range ids {
+		if filter.Limit > 0 && len(list.Volumes) == filter.Limit {
+			// Continue after the last volume looked at.
+			list.NextToken = encodeToken(ids[i-1])
+			break
+		}
+		elem := &api.Volume{}
+		if _, err := e.kvdb.GetVal(e.volKey(id), elem); err == kvdb.ErrNotFound {
+			// Deleted since the IDs were listed
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if filter.Matches(elem) {
+			list.Volumes = append(list.Volumes, elem)
+		}
+	}
+	return list, nil
+}
+
+// indexVolumes adds the volumes stored before the ID index existed to the
+// index. The volume keys are only enumerated on the first call.
+func (e *defaultStoreEnumerator) indexVolumes() error {
+	e.indexLock.Lock()
+	defer e.indexLock.Unlock()
+	if e.indexed {
+		return nil
+	}
+	prefix := e.volKeyPrefix()
+	kvp, err := e.kvdb.Enumerate(prefix)
+	if err != nil {
+		return err
+	}
+	for _, v := range kvp {
+		id := strings.TrimPrefix(v.Key, prefix)
+		if strings.HasSuffix(id, ".lock") {
+			continue
+		}
+		if _, err := e.kvdb.Put(e.idKey(id), id, 0); err != nil {
+			return err
+		}
+	}
+	e.indexed = true
+	return nil
+}
+
 // SnapEnumerate for specified volume
 func (e *defaultStoreEnumerator) SnapEnumerate(
 	volumeIDs []string,
@@ -132,6 +227,49 @@ func (e *defaultStoreEnumerator) SnapEnumerate(
 	return volumes, nil
 }
 
+// SnapTree returns the snapshots and clones descending from volumeID.
+func (e *defaultStoreEnumerator) SnapTree(volumeID string) (*api.SnapshotTree, error) {
+	root, err := e.GetVol(volumeID)
+	if err != nil {
+		return nil, volume.ErrEnoEnt
+	}
+	kvp, err := e.kvdb.Enumerate(e.volKeyPrefix())
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[string][]*api.Volume)
+	for _, v := range kvp {
+		elem := &api.Volume{}
+		if err := json.Unmarshal(v.Value, elem); err != nil {
+			return nil, err
+		}
+		if elem.Source == nil || elem.Source.Parent == "" {
+			continue
+		}
+		children[elem.Source.Parent] = append(children[elem.Source.Parent], elem)
+	}
+	return snapTree(root, children, make(map[string]bool)), nil
+}
+
+// SnapChain returns the ancestors of volumeID, closest first.
+func (e *defaultStoreEnumerator) SnapChain(volumeID string) ([]*api.Volume, error) {
+	v, err := e.GetVol(volumeID)
+	if err != nil {
+		return nil, volume.ErrEnoEnt
+	}
+	chain := make([]*api.Volume, 0)
+	visited := map[string]bool{v.Id: true}
+	for v.Source != nil && v.Source.Parent != "" && !visited[v.Source.Parent] {
+		// The chain ends early if an ancestor has already been deleted.
+		if v, err = e.GetVol(v.Source.Parent); err != nil {
+			break
+		}
+		visited[v.Id] = true
+		chain = append(chain, v)
+	}
+	return chain, nil
+}
+
 func (e *defaultStoreEnumerator) lockKey(volumeID string) string {
 	return e.volKeyPrefix() + volumeID + ".lock"
 }
@@ -140,6 +278,10 @@ func (e *defaultStoreEnumerator) volKey(volumeID string) string {
 	return e.volKeyPrefix() + volumeID
 }
 
+func (e *defaultStoreEnumerator) idKey(volumeID string) string {
+	return e.idKeyPrefix() + volumeID
+}
+
 // TODO(pedge): not used - bug?
 func (e *defaultStoreEnumerator) lockKeyPrefix() string {
 	return fmt.Sprintf("%s/%s/locks/", keyBase, e.driver)
@@ -149,6 +291,62 @@ func (e *defaultStoreEnumerator) volKeyPrefix() string {
 	return fmt.Sprintf("%s/%s/volumes/", keyBase, e.driver)
 }
 
+// idKeyPrefix is the prefix of the ID index, which holds a key per volume
+// so that the volume IDs can be listed without reading the volumes.
+func (e *defaultStoreEnumerator) idKeyPrefix() string {
+	return fmt.Sprintf("%s/%s/volume_ids/", keyBase, e.driver)
+}
+
+// encodeToken returns the continuation token for the volumes after id.
+func encodeToken(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// decodeToken returns the volume ID in token, or "" for an empty token.
+func decodeToken(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	id, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(id) == 0 {
+		return "", volume.ErrInvalidToken
+	}
+	return string(id), nil
+}
+
+func snapTree(
+	root *api.Volume,
+	children map[string][]*api.Volume,
+	visited map[string]bool,
+) *api.SnapshotTree {
+	visited[root.Id] = true
+	tree := &api.SnapshotTree{Volume: root, Children: make([]*api.SnapshotTree, 0)}
+	snaps := children[root.Id]
+	sort.Sort(ByCtime(snaps))
+	for _, snap := range snaps {
+		if !visited[snap.Id] {
+			tree.Children = append(tree.Children, snapTree(snap, children, visited))
+		}
+	}
+	return tree
+}
+
+// ByCtime sorts volumes by creation time, oldest first, and then by ID.
+type ByCtime []*api.Volume
+
+func (b ByCtime) Len() int      { return len(b) }
+func (b ByCtime) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b ByCtime) Less(i, j int) bool {
+	ti, tj := b[i].GetCtime(), b[j].GetCtime()
+	if ti.GetSeconds() != tj.GetSeconds() {
+		return ti.GetSeconds() < tj.GetSeconds()
+	}
+	if ti.GetNanos() != tj.GetNanos() {
+		return ti.GetNanos() < tj.GetNanos()
+	}
+	return b[i].Id < b[j].Id
+}
+
 func hasSubset(set map[string]string, subset map[string]string) bool {
 	if subset == nil || len(subset) == 0 {
 		return true
@@ -2,7 +2,9 @@ package common
 
 import (
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"go.pedge.io/dlog"
 
 	"github.com/libopenstorage/openstorage/api"
@@ -116,6 +118,117 @@ func TestSnapEnumerate(t *testing.T) {
 	assert.NoError(t, err, "Failed in Delete")
 }
 
+func TestEnumerateWithFilter(t *testing.T) {
+	ids := []string{"filter-a", "filter-b", "filter-c", "filter-d", "filter-e"}
+	for i, id := range ids {
+		v := newTestVolume(id)
+		v.Locator.VolumeLabels = map[string]string{
+			"tier": []string{"web", "db"}[i%2],
+		}
+		v.Ctime = &timestamp.Timestamp{Seconds: int64(1000 + i)}
+		if i == 0 {
+			v.State = api.VolumeState_VOLUME_STATE_ATTACHED
+			v.AttachedOn = "node1"
+		}
+		assert.NoError(t, testEnumerator.CreateVol(v), "Failed in CreateVol")
+		defer testEnumerator.DeleteVol(id)
+	}
+
+	enumerate := func(f *api.VolumeFilter) []string {
+		list, err := testEnumerator.EnumerateWithFilter(f)
+		assert.NoError(t, err, "Failed in EnumerateWithFilter")
+		found := make([]string, len(list.Volumes))
+		for i, v := range list.Volumes {
+			found[i] = v.Id
+		}
+		return found
+	}
+	selector, err := api.ParseLabelSelector("tier,tier!=db")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"filter-a", "filter-c", "filter-e"},
+		enumerate(&api.VolumeFilter{Labels: selector}))
+	assert.Equal(t, []string{"filter-a"}, enumerate(&api.VolumeFilter{
+		States:     []api.VolumeState{api.VolumeState_VOLUME_STATE_ATTACHED},
+		AttachedOn: "node1",
+	}))
+	assert.Equal(t, []string{"filter-b", "filter-c"}, enumerate(&api.VolumeFilter{
+		CreatedAfter:  time.Unix(1000, 0),
+		CreatedBefore: time.Unix(1003, 0),
+	}))
+
+	// Page through the web tier two at a time.
+	selector, err = api.ParseLabelSelector("tier in (web)")
+	assert.NoError(t, err)
+	filter := &api.VolumeFilter{Labels: selector, Limit: 2}
+	var pages [][]*api.Volume
+	for {
+		list, err := testEnumerator.EnumerateWithFilter(filter)
+		assert.NoError(t, err, "Failed in EnumerateWithFilter")
+		pages = append(pages, list.Volumes)
+		if list.NextToken == "" {
+			break
+		}
+		filter.Token = list.NextToken
+	}
+	assert.Len(t, pages, 2)
+	assert.Len(t, pages[0], 2)
+	assert.Equal(t, "filter-c", pages[0][1].Id)
+	assert.Len(t, pages[1], 1)
+	assert.Equal(t, "filter-e", pages[1][0].Id)
+
+	_, err = testEnumerator.EnumerateWithFilter(&api.VolumeFilter{Token: "%%%"})
+	assert.Equal(t, volume.ErrInvalidToken, err)
+}
+
+func TestEnumerateWithFilterIndex(t *testing.T) {
+	ids := []string{"index-a", "index-b", "index-c", "index-d", "index-e"}
+	for _, id := range ids {
+		assert.NoError(t, testEnumerator.CreateVol(newTestVolume(id)), "Failed in CreateVol")
+		defer testEnumerator.DeleteVol(id)
+	}
+	kv := kvdb.Instance()
+	e := newDefaultStoreEnumerator("enumerator_test", kv)
+
+	// Only the volumes on the page are read, so a volume after the page
+	// which cannot be read does not fail it.
+	_, err := kv.Put(e.volKey("index-e"), "not a volume", 0)
+	assert.NoError(t, err)
+	filter := &api.VolumeFilter{Token: encodeToken("index"), Limit: 2}
+	list, err := e.EnumerateWithFilter(filter)
+	assert.NoError(t, err, "Failed in EnumerateWithFilter")
+	assert.Len(t, list.Volumes, 2)
+	assert.Equal(t, "index-a", list.Volumes[0].Id)
+
+	filter.Token = list.NextToken
+	list, err = e.EnumerateWithFilter(filter)
+	assert.NoError(t, err, "Failed in EnumerateWithFilter")
+	assert.Len(t, list.Volumes, 2)
+	assert.Equal(t, "index-c", list.Volumes[0].Id)
+
+	filter.Token = list.NextToken
+	_, err = e.EnumerateWithFilter(filter)
+	assert.Error(t, err)
+
+	// Deleted volumes are removed from the index.
+	assert.NoError(t, e.DeleteVol("index-e"))
+	_, err = kv.Get(e.idKey("index-e"))
+	assert.Equal(t, kvdb.ErrNotFound, err)
+}
+
+func TestEnumerateWithFilterUnindexed(t *testing.T) {
+	// Volumes stored before the index existed are added to it.
+	kv := kvdb.Instance()
+	e := newDefaultStoreEnumerator("enumerator_unindexed_test", kv)
+	_, err := kv.Put(e.volKey("unindexed"), newTestVolume("unindexed"), 0)
+	assert.NoError(t, err)
+	defer e.DeleteVol("unindexed")
+
+	list, err := e.EnumerateWithFilter(nil)
+	assert.NoError(t, err, "Failed in EnumerateWithFilter")
+	assert.Len(t, list.Volumes, 1)
+	assert.Equal(t, "unindexed", list.Volumes[0].Id)
+}
+
 func newTestVolume(id string) *api.Volume {
 	return &api.Volume{
 		Id:      id,
@@ -0,0 +1,111 @@
+package common
+
+import (
+	"fmt"
+	"os/exec"
+	"reflect"
+	"syscall"
+
+	"go.pedge.io/dlog"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/volume"
+)
+
+// ValidateResize checks the size requested by spec in a Set call on v.
+// It returns true if the volume has to grow. Unchanged or unspecified sizes
+// return false and a smaller size returns ErrVolShrink.
+func ValidateResize(v *api.Volume, spec *api.VolumeSpec) (bool, error) {
+	if spec == nil || spec.Size == 0 || v.Spec == nil || spec.Size == v.Spec.Size {
+		return false, nil
+	}
+	if spec.Size < v.Spec.Size {
+		return false, volume.ErrVolShrink
+	}
+	return true, nil
+}
+
+// ValidateSizeOnlyResize checks the spec of a Set call on v for drivers which
+// can only change the size of their volumes. A zero size, or a field other
+// than the size which is set and differs from v, returns ErrNotSupported.
+// Otherwise it returns whether the volume has to grow, as ValidateResize.
+func ValidateSizeOnlyResize(v *api.Volume, spec *api.VolumeSpec) (bool, error) {
+	if spec == nil {
+		return false, nil
+	}
+	if spec.Size == 0 {
+		return false, volume.ErrNotSupported
+	}
+	current := v.GetSpec()
+	if current == nil {
+		current = &api.VolumeSpec{}
+	}
+	requested, existing := reflect.ValueOf(spec).Elem(), reflect.ValueOf(current).Elem()
+	for i := 0; i < requested.NumField(); i++ {
+		field := requested.Field(i)
+		if requested.Type().Field(i).Name == "Size" || isUnset(field) {
+			continue
+		}
+		if !reflect.DeepEqual(field.Interface(), existing.Field(i).Interface()) {
+			return false, volume.ErrNotSupported
+		}
+	}
+	return ValidateResize(v, spec)
+}
+
+func isUnset(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	default:
+		return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+	}
+}
+
+// GrowFilesystem grows the filesystem on devicePath to the size of the
+// device. mountPath is where the filesystem is mounted, or empty if it is not
+// mounted. ext4 is grown online or offline, xfs can only be grown while
+// mounted and is left alone otherwise.
+func GrowFilesystem(format api.FSType, devicePath string, mountPath string) error {
+	switch format {
+	case api.FSType_FS_TYPE_NONE:
+		return nil
+	case api.FSType_FS_TYPE_EXT4:
+		if mountPath == "" {
+			// resize2fs refuses to grow an unmounted filesystem that was
+			// not checked since it was last mounted. e2fsck exits with 1
+			// when it corrected errors.
+			err := run("/sbin/e2fsck", "-f", "-y", devicePath)
+			if exitErr, ok := err.(*exec.ExitError); err != nil &&
+				(!ok || exitErr.Sys().(syscall.WaitStatus).ExitStatus() != 1) {
+				return fmt.Errorf("Failed to check filesystem on %v: %v",
+					devicePath, err)
+			}
+		}
+		if err := run("/sbin/resize2fs", devicePath); err != nil {
+			return fmt.Errorf("Failed to grow filesystem on %v: %v",
+				devicePath, err)
+		}
+	case api.FSType_FS_TYPE_XFS:
+		if mountPath == "" {
+			dlog.Infof("xfs on %v is not mounted and will not be grown", devicePath)
+			return nil
+		}
+		if err := run("/sbin/xfs_growfs", mountPath); err != nil {
+			return fmt.Errorf("Failed to grow filesystem on %v: %v",
+				devicePath, err)
+		}
+	default:
+		return fmt.Errorf("Growing a %v filesystem is not supported",
+			format.SimpleString())
+	}
+	return nil
+}
+
+func run(name string, args ...string) error {
+	o, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		dlog.Warnf("Failed to run command %v %v: %s", name, args, o)
+	}
+	return err
+}
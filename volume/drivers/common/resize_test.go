@@ -0,0 +1,74 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/volume"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateResize(t *testing.T) {
+	v := &api.Volume{Spec: &api.VolumeSpec{Size: 1024}}
+
+	grow, err := ValidateResize(v, nil)
+	assert.NoError(t, err)
+	assert.False(t, grow, "nil spec should not grow the volume")
+
+	grow, err = ValidateResize(v, &api.VolumeSpec{})
+	assert.NoError(t, err)
+	assert.False(t, grow, "zero size should not grow the volume")
+
+	grow, err = ValidateResize(v, &api.VolumeSpec{Size: 1024})
+	assert.NoError(t, err)
+	assert.False(t, grow, "same size should not grow the volume")
+
+	grow, err = ValidateResize(v, &api.VolumeSpec{Size: 2048})
+	assert.NoError(t, err)
+	assert.True(t, grow, "larger size should grow the volume")
+
+	_, err = ValidateResize(v, &api.VolumeSpec{Size: 512})
+	assert.Equal(t, volume.ErrVolShrink, err)
+
+	assert.NoError(t, GrowFilesystem(api.FSType_FS_TYPE_NONE, "/dev/null", ""))
+	assert.Error(t, GrowFilesystem(api.FSType_FS_TYPE_NFS, "/dev/null", ""))
+}
+
+func TestValidateSizeOnlyResize(t *testing.T) {
+	v := &api.Volume{Spec: &api.VolumeSpec{
+		Size:         1024,
+		HaLevel:      2,
+		VolumeLabels: map[string]string{"a": "b"},
+	}}
+
+	grow, err := ValidateSizeOnlyResize(v, nil)
+	assert.NoError(t, err)
+	assert.False(t, grow, "nil spec should not grow the volume")
+
+	_, err = ValidateSizeOnlyResize(v, &api.VolumeSpec{})
+	assert.Equal(t, volume.ErrNotSupported, err, "zero size is not supported")
+
+	grow, err = ValidateSizeOnlyResize(v, &api.VolumeSpec{Size: 2048})
+	assert.NoError(t, err)
+	assert.True(t, grow, "unset fields should be ignored")
+
+	grow, err = ValidateSizeOnlyResize(v, &api.VolumeSpec{
+		Size:         2048,
+		HaLevel:      2,
+		VolumeLabels: map[string]string{"a": "b"},
+	})
+	assert.NoError(t, err)
+	assert.True(t, grow, "unchanged fields should be ignored")
+
+	_, err = ValidateSizeOnlyResize(v, &api.VolumeSpec{Size: 2048, HaLevel: 3})
+	assert.Equal(t, volume.ErrNotSupported, err, "changing the HA level is not supported")
+
+	_, err = ValidateSizeOnlyResize(v, &api.VolumeSpec{
+		Size:         2048,
+		VolumeLabels: map[string]string{"a": "c"},
+	})
+	assert.Equal(t, volume.ErrNotSupported, err, "changing the labels is not supported")
+
+	_, err = ValidateSizeOnlyResize(v, &api.VolumeSpec{Size: 512})
+	assert.Equal(t, volume.ErrVolShrink, err)
+}
@@ -227,6 +227,14 @@ func (d *driver) Restore(volumeID string, snapID string) error {
 	return volume.ErrNotSupported
 }
 
+func (d *driver) SnapDelete(snapID string, cascade bool) error {
+	return volume.ErrNotSupported
+}
+
+func (d *driver) Promote(cloneID string) error {
+	return volume.ErrNotSupported
+}
+
 func (d *driver) Status() [][2]string {
 	return [][2]string{}
 }
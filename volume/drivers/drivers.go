@@ -9,6 +9,7 @@ import (
 	"github.com/libopenstorage/openstorage/volume/drivers/btrfs"
 	"github.com/libopenstorage/openstorage/volume/drivers/buse"
 	"github.com/libopenstorage/openstorage/volume/drivers/coprhd"
+	"github.com/libopenstorage/openstorage/volume/drivers/fake"
 	"github.com/libopenstorage/openstorage/volume/drivers/nfs"
 	"github.com/libopenstorage/openstorage/volume/drivers/pwx"
 	"github.com/libopenstorage/openstorage/volume/drivers/vfs"
@@ -32,6 +33,8 @@ var (
 		{DriverType: buse.Type, Name: buse.Name},
 		// COPRHD driver
 		{DriverType: coprhd.Type, Name: coprhd.Name},
+		// FAKE driver keeps volumes in memory, intended for testing.
+		{DriverType: fake.Type, Name: fake.Name},
 		// NFS driver provisions storage from an NFS server.
 		{DriverType: nfs.Type, Name: nfs.Name},
 		// PWX driver provisions storage from PWX cluster.
@@ -46,6 +49,7 @@ var (
 			btrfs.Name:  btrfs.Init,
 			buse.Name:   buse.Init,
 			coprhd.Name: coprhd.Init,
+			fake.Name:   fake.Init,
 			nfs.Name:    nfs.Init,
 			pwx.Name:    pwx.Init,
 			vfs.Name:    vfs.Init,
@@ -73,6 +77,11 @@ func Remove(name string) {
 	volumeDriverRegistry.Remove(name)
 }
 
+// Wrap replaces a registered driver with the driver wrap returns for it.
+func Wrap(name string, wrap func(volume.VolumeDriver) volume.VolumeDriver) error {
+	return volumeDriverRegistry.Wrap(name, wrap)
+}
+
 // Shutdown stops the volume driver registry
 func Shutdown() error {
 	return volumeDriverRegistry.Shutdown()
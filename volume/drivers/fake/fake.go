@@ -0,0 +1,337 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"go.pedge.io/dlog"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/options"
+	"github.com/libopenstorage/openstorage/volume"
+	"github.com/libopenstorage/openstorage/volume/drivers/common"
+	"github.com/pborman/uuid"
+	"github.com/portworx/kvdb"
+	"github.com/portworx/kvdb/mem"
+)
+
+const (
+	// Name of the driver
+	Name = "fake"
+	// Type of the driver
+	Type = api.DriverType_DRIVER_TYPE_BLOCK
+	// DevicePathBase is the prefix of the fake device paths returned on attach
+	DevicePathBase = "/dev/fake/"
+	// credsKeyPrefix is where credentials are kept in the in-memory kvdb
+	credsKeyPrefix = "fake/credentials/"
+)
+
+// driver is a pure in-memory volume driver. It does not touch the host:
+// attach hands out fake device paths and mount only records the path.
+// It is meant for exercising the REST API, CSI server and docker plugin
+// end to end without root privileges or cloud accounts.
+type driver struct {
+	volume.IODriver
+	volume.StoreEnumerator
+	volume.QuiesceDriver
+	kv kvdb.Kvdb
+}
+
+// Init initializes the fake driver on its own in-memory kvdb.
+func Init(params map[string]string) (volume.VolumeDriver, error) {
+	kv, err := kvdb.New(mem.Name, Name, []string{}, nil, dlog.Panicf)
+	if err != nil {
+		return nil, err
+	}
+	dlog.Infof("%s driver initialized", Name)
+	return &driver{
+		IODriver:        volume.IONotSupported,
+		StoreEnumerator: common.NewDefaultStoreEnumerator(Name, kv),
+		QuiesceDriver:   volume.QuiesceNotSupported,
+		kv:              kv,
+	}, nil
+}
+
+func (d *driver) Name() string {
+	return Name
+}
+
+func (d *driver) Type() api.DriverType {
+	return Type
+}
+
+func (d *driver) Status() [][2]string {
+	return [][2]string{}
+}
+
+func (d *driver) Shutdown() {}
+
+func (d *driver) Create(
+	locator *api.VolumeLocator,
+	source *api.Source,
+	spec *api.VolumeSpec,
+) (string, error) {
+	if spec == nil {
+		return "", volume.ErrEinval
+	}
+	if source != nil && source.Parent != "" {
+		if _, err := d.GetVol(source.Parent); err != nil {
+			return "", volume.ErrEnoEnt
+		}
+	}
+	volumeID := strings.TrimSuffix(uuid.New(), "\n")
+	v := common.NewVolume(
+		volumeID,
+		spec.Format,
+		locator,
+		source,
+		spec,
+	)
+	if err := d.CreateVol(v); err != nil {
+		return "", err
+	}
+	return v.Id, nil
+}
+
+func (d *driver) Delete(volumeID string) error {
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return volume.ErrEnoEnt
+	}
+	if len(v.AttachPath) > 0 {
+		return volume.ErrVolBusy
+	}
+	if err := common.CheckNoSnaps(d, volumeID); err != nil {
+		return err
+	}
+	return d.DeleteVol(volumeID)
+}
+
+func (d *driver) Set(volumeID string, locator *api.VolumeLocator, spec *api.VolumeSpec) error {
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return volume.ErrEnoEnt
+	}
+	if _, err := common.ValidateResize(v, spec); err != nil {
+		return err
+	}
+	if locator != nil {
+		v.Locator = locator
+	}
+	if spec != nil {
+		if spec.Size != 0 {
+			v.Spec.Size = spec.Size
+		}
+		if spec.HaLevel != 0 {
+			v.Spec.HaLevel = spec.HaLevel
+		}
+		if spec.SnapshotInterval != 0 {
+			v.Spec.SnapshotInterval = spec.SnapshotInterval
+		}
+		if spec.SnapshotSchedule != "" {
+			v.Spec.SnapshotSchedule = spec.SnapshotSchedule
+		}
+		if spec.VolumeLabels != nil {
+			v.Spec.VolumeLabels = spec.VolumeLabels
+		}
+	}
+	return d.UpdateVol(v)
+}
+
+// Attach hands out a fake device path. Attaching an attached volume on the
+// same node returns the same device path.
+func (d *driver) Attach(volumeID string, attachOptions map[string]string) (string, error) {
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return "", volume.ErrEnoEnt
+	}
+	node := attachOptions[options.OptionsAttachNode]
+	if v.State == api.VolumeState_VOLUME_STATE_ATTACHED {
+		if v.AttachedOn != node {
+			return "", volume.ErrVolAttachedOnRemoteNode
+		}
+		return v.DevicePath, nil
+	}
+	v.DevicePath = path.Join(DevicePathBase, volumeID)
+	v.State = api.VolumeState_VOLUME_STATE_ATTACHED
+	v.AttachedOn = node
+	v.AttachInfo = attachOptions
+	if err := d.UpdateVol(v); err != nil {
+		return "", err
+	}
+	return v.DevicePath, nil
+}
+
+// AttachesRemotely returns true, Attach records the node it is asked to
+// attach on.
+func (d *driver) AttachesRemotely() bool {
+	return true
+}
+
+func (d *driver) Detach(volumeID string, options map[string]string) error {
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return volume.ErrEnoEnt
+	}
+	if v.State != api.VolumeState_VOLUME_STATE_ATTACHED {
+		return nil
+	}
+	if len(v.AttachPath) > 0 {
+		return volume.ErrVolBusy
+	}
+	v.DevicePath = ""
+	v.AttachedOn = ""
+	v.AttachInfo = nil
+	v.State = api.VolumeState_VOLUME_STATE_DETACHED
+	return d.UpdateVol(v)
+}
+
+func (d *driver) MountedAt(mountpath string) string {
+	volumes, err := d.Enumerate(&api.VolumeLocator{}, nil)
+	if err != nil {
+		return ""
+	}
+	for _, v := range volumes {
+		for _, p := range v.AttachPath {
+			if p == mountpath {
+				return v.Id
+			}
+		}
+	}
+	return ""
+}
+
+// Mount records mountpath against the volume. Nothing is mounted on the host.
+func (d *driver) Mount(volumeID string, mountpath string, options map[string]string) error {
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return volume.ErrEnoEnt
+	}
+	if v.State != api.VolumeState_VOLUME_STATE_ATTACHED {
+		return volume.ErrVolDetached
+	}
+	if id := d.MountedAt(mountpath); id != "" && id != volumeID {
+		return fmt.Errorf("Path %q already in use by volume %q", mountpath, id)
+	}
+	for _, p := range v.AttachPath {
+		if p == mountpath {
+			return nil
+		}
+	}
+	v.AttachPath = append(v.AttachPath, mountpath)
+	return d.UpdateVol(v)
+}
+
+func (d *driver) Unmount(volumeID string, mountpath string, options map[string]string) error {
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return volume.ErrEnoEnt
+	}
+	for i, p := range v.AttachPath {
+		if p == mountpath {
+			v.AttachPath = append(v.AttachPath[:i], v.AttachPath[i+1:]...)
+			return d.UpdateVol(v)
+		}
+	}
+	return fmt.Errorf("Volume %q not mounted at %q", volumeID, mountpath)
+}
+
+func (d *driver) Snapshot(volumeID string, readonly bool, locator *api.VolumeLocator) (string, error) {
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return "", volume.ErrEnoEnt
+	}
+	snap := common.NewVolume(
+		strings.TrimSuffix(uuid.New(), "\n"),
+		v.Format,
+		locator,
+		&api.Source{Parent: volumeID},
+		v.Spec.Copy(),
+	)
+	snap.Readonly = readonly
+	snap.Usage = v.Usage
+	if err := d.CreateVol(snap); err != nil {
+		return "", err
+	}
+	return snap.Id, nil
+}
+
+func (d *driver) Restore(volumeID string, snapshotID string) error {
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return volume.ErrEnoEnt
+	}
+	snap, err := d.GetVol(snapshotID)
+	if err != nil {
+		return volume.ErrEnoEnt
+	}
+	if snap.Source == nil || snap.Source.Parent != volumeID {
+		return volume.ErrEinval
+	}
+	v.Usage = snap.Usage
+	return d.UpdateVol(v)
+}
+
+func (d *driver) SnapDelete(snapID string, cascade bool) error {
+	return common.SnapDelete(d, snapID, cascade)
+}
+
+func (d *driver) Promote(cloneID string) error {
+	return common.Promote(d, cloneID)
+}
+
+func (d *driver) Stats(volumeID string, cumulative bool) (*api.Stats, error) {
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return nil, volume.ErrEnoEnt
+	}
+	return &api.Stats{BytesUsed: v.Usage}, nil
+}
+
+func (d *driver) UsedSize(volumeID string) (uint64, error) {
+	v, err := d.GetVol(volumeID)
+	if err != nil {
+		return 0, volume.ErrEnoEnt
+	}
+	return v.Usage, nil
+}
+
+func (d *driver) GetActiveRequests() (*api.ActiveRequests, error) {
+	return &api.ActiveRequests{}, nil
+}
+
+func (d *driver) CredsCreate(params map[string]string) (string, error) {
+	credUUID := strings.TrimSuffix(uuid.New(), "\n")
+	if _, err := d.kv.Create(credsKeyPrefix+credUUID, params, 0); err != nil {
+		return "", err
+	}
+	return credUUID, nil
+}
+
+func (d *driver) CredsEnumerate() (map[string]interface{}, error) {
+	kvp, err := d.kv.Enumerate(credsKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	creds := make(map[string]interface{}, len(kvp))
+	for _, v := range kvp {
+		params := make(map[string]string)
+		if err := json.Unmarshal(v.Value, &params); err != nil {
+			return nil, err
+		}
+		creds[path.Base(v.Key)] = params
+	}
+	return creds, nil
+}
+
+func (d *driver) CredsDelete(credUUID string) error {
+	_, err := d.kv.Delete(credsKeyPrefix + credUUID)
+	return err
+}
+
+func (d *driver) CredsValidate(credUUID string) error {
+	_, err := d.kv.Get(credsKeyPrefix + credUUID)
+	return err
+}
@@ -0,0 +1,174 @@
+package fake
+
+import (
+	"testing"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/options"
+	"github.com/libopenstorage/openstorage/volume"
+	"github.com/libopenstorage/openstorage/volume/drivers/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAll(t *testing.T) {
+	d, err := Init(map[string]string{})
+	require.NoError(t, err, "Failed to initialize Volume Driver")
+
+	ctx := test.NewContext(d)
+	ctx.Filesystem = api.FSType_FS_TYPE_EXT4
+	test.Run(t, ctx)
+}
+
+func TestMountBookkeeping(t *testing.T) {
+	d, err := Init(map[string]string{})
+	require.NoError(t, err)
+
+	volumeID, err := d.Create(
+		&api.VolumeLocator{Name: "mountvol"},
+		nil,
+		&api.VolumeSpec{Size: 1024, Format: api.FSType_FS_TYPE_EXT4},
+	)
+	require.NoError(t, err)
+
+	err = d.Mount(volumeID, "/mnt/a", nil)
+	require.Equal(t, volume.ErrVolDetached, err)
+
+	devicePath, err := d.Attach(volumeID, nil)
+	require.NoError(t, err)
+	require.Equal(t, DevicePathBase+volumeID, devicePath)
+
+	require.NoError(t, d.Mount(volumeID, "/mnt/a", nil))
+	require.NoError(t, d.Mount(volumeID, "/mnt/b", nil))
+	require.Equal(t, volumeID, d.MountedAt("/mnt/b"))
+	require.Equal(t, volume.ErrVolBusy, d.Detach(volumeID, nil))
+	require.Equal(t, volume.ErrVolBusy, d.Delete(volumeID))
+
+	require.NoError(t, d.Unmount(volumeID, "/mnt/a", nil))
+	require.Error(t, d.Unmount(volumeID, "/mnt/a", nil))
+	require.NoError(t, d.Unmount(volumeID, "/mnt/b", nil))
+	require.Equal(t, "", d.MountedAt("/mnt/b"))
+
+	require.NoError(t, d.Detach(volumeID, nil))
+
+	node1 := map[string]string{options.OptionsAttachNode: "node1"}
+	_, err = d.Attach(volumeID, node1)
+	require.NoError(t, err)
+	_, err = d.Attach(volumeID, node1)
+	require.NoError(t, err)
+	_, err = d.Attach(volumeID, map[string]string{options.OptionsAttachNode: "node2"})
+	require.Equal(t, volume.ErrVolAttachedOnRemoteNode, err)
+	require.NoError(t, d.Detach(volumeID, nil))
+
+	require.NoError(t, d.Delete(volumeID))
+	_, err = d.Attach(volumeID, nil)
+	require.Equal(t, volume.ErrEnoEnt, err)
+}
+
+func TestCreds(t *testing.T) {
+	d, err := Init(map[string]string{})
+	require.NoError(t, err)
+
+	credUUID, err := d.CredsCreate(map[string]string{api.OptCredType: "s3"})
+	require.NoError(t, err)
+	require.NoError(t, d.CredsValidate(credUUID))
+
+	creds, err := d.CredsEnumerate()
+	require.NoError(t, err)
+	require.Len(t, creds, 1)
+	require.Contains(t, creds, credUUID)
+
+	require.NoError(t, d.CredsDelete(credUUID))
+	require.Error(t, d.CredsValidate(credUUID))
+}
+
+func TestSnapDeleteCascade(t *testing.T) {
+	d, err := Init(map[string]string{})
+	require.NoError(t, err)
+
+	volumeID, err := d.Create(
+		&api.VolumeLocator{Name: "parent"},
+		nil,
+		&api.VolumeSpec{Size: 1024, Format: api.FSType_FS_TYPE_EXT4},
+	)
+	require.NoError(t, err)
+	snapID, err := d.Snapshot(volumeID, true, &api.VolumeLocator{Name: "snap"})
+	require.NoError(t, err)
+	cloneID, err := d.Snapshot(snapID, false, &api.VolumeLocator{Name: "clone"})
+	require.NoError(t, err)
+
+	chain, err := d.SnapChain(cloneID)
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+	require.Equal(t, snapID, chain[0].Id)
+	require.Equal(t, volumeID, chain[1].Id)
+
+	require.Equal(t, volume.ErrVolHasSnaps, d.Delete(snapID))
+	require.Equal(t, volume.ErrVolHasSnaps, d.SnapDelete(snapID, false))
+	require.NoError(t, d.SnapDelete(snapID, true))
+
+	vols, err := d.Inspect([]string{snapID, cloneID})
+	require.NoError(t, err)
+	require.Len(t, vols, 0)
+	tree, err := d.SnapTree(volumeID)
+	require.NoError(t, err)
+	require.Len(t, tree.Children, 0)
+
+	// A volume is deleted with its descendants with cascade only
+	snapID, err = d.Snapshot(volumeID, true, &api.VolumeLocator{Name: "snap"})
+	require.NoError(t, err)
+	require.Equal(t, volume.ErrVolHasSnaps, d.Delete(volumeID))
+	require.Equal(t, volume.ErrEinval, d.SnapDelete(volumeID, false))
+	require.NoError(t, d.SnapDelete(volumeID, true))
+	vols, err = d.Inspect([]string{volumeID, snapID})
+	require.NoError(t, err)
+	require.Len(t, vols, 0)
+}
+
+func TestPromote(t *testing.T) {
+	d, err := Init(map[string]string{})
+	require.NoError(t, err)
+
+	volumeID, err := d.Create(
+		&api.VolumeLocator{Name: "parent"},
+		nil,
+		&api.VolumeSpec{Size: 1024, Format: api.FSType_FS_TYPE_EXT4},
+	)
+	require.NoError(t, err)
+	snapID, err := d.Snapshot(volumeID, true, &api.VolumeLocator{Name: "snap"})
+	require.NoError(t, err)
+	cloneID, err := d.Snapshot(volumeID, false, &api.VolumeLocator{Name: "clone"})
+	require.NoError(t, err)
+
+	require.Equal(t, volume.ErrEinval, d.Promote(snapID))
+	require.NoError(t, d.Promote(cloneID))
+
+	chain, err := d.SnapChain(cloneID)
+	require.NoError(t, err)
+	require.Len(t, chain, 0)
+	tree, err := d.SnapTree(volumeID)
+	require.NoError(t, err)
+	require.Len(t, tree.Children, 1)
+	require.Equal(t, snapID, tree.Children[0].Volume.Id)
+}
+
+func TestResize(t *testing.T) {
+	d, err := Init(map[string]string{})
+	require.NoError(t, err)
+
+	volumeID, err := d.Create(
+		&api.VolumeLocator{Name: "resizevol"},
+		nil,
+		&api.VolumeSpec{Size: 1024, Format: api.FSType_FS_TYPE_EXT4},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, volume.ErrVolShrink,
+		d.Set(volumeID, nil, &api.VolumeSpec{Size: 512}))
+	require.NoError(t, d.Set(volumeID, nil, &api.VolumeSpec{Size: 1024}))
+	require.NoError(t, d.Set(volumeID, nil, &api.VolumeSpec{Size: 4096}))
+
+	vols, err := d.Inspect([]string{volumeID})
+	require.NoError(t, err)
+	require.Len(t, vols, 1)
+	require.Equal(t, uint64(4096), vols[0].Spec.Size)
+}
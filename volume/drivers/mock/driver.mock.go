@@ -146,6 +146,19 @@ func (mr *MockVolumeDriverMockRecorder) Enumerate(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enumerate", reflect.TypeOf((*MockVolumeDriver)(nil).Enumerate), arg0, arg1)
 }
 
+// EnumerateWithFilter mocks base method
+func (m *MockVolumeDriver) EnumerateWithFilter(arg0 *api.VolumeFilter) (*api.VolumeList, error) {
+	ret := m.ctrl.Call(m, "EnumerateWithFilter", arg0)
+	ret0, _ := ret[0].(*api.VolumeList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnumerateWithFilter indicates an expected call of EnumerateWithFilter
+func (mr *MockVolumeDriverMockRecorder) EnumerateWithFilter(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnumerateWithFilter", reflect.TypeOf((*MockVolumeDriver)(nil).EnumerateWithFilter), arg0)
+}
+
 // Flush mocks base method
 func (m *MockVolumeDriver) Flush(arg0 string) error {
 	ret := m.ctrl.Call(m, "Flush", arg0)
@@ -220,6 +233,18 @@ func (mr *MockVolumeDriverMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockVolumeDriver)(nil).Name))
 }
 
+// Promote mocks base method
+func (m *MockVolumeDriver) Promote(arg0 string) error {
+	ret := m.ctrl.Call(m, "Promote", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Promote indicates an expected call of Promote
+func (mr *MockVolumeDriverMockRecorder) Promote(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Promote", reflect.TypeOf((*MockVolumeDriver)(nil).Promote), arg0)
+}
+
 // Quiesce mocks base method
 func (m *MockVolumeDriver) Quiesce(arg0 string, arg1 uint64, arg2 string) error {
 	ret := m.ctrl.Call(m, "Quiesce", arg0, arg1, arg2)
@@ -279,6 +304,31 @@ func (mr *MockVolumeDriverMockRecorder) Shutdown() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockVolumeDriver)(nil).Shutdown))
 }
 
+// SnapChain mocks base method
+func (m *MockVolumeDriver) SnapChain(arg0 string) ([]*api.Volume, error) {
+	ret := m.ctrl.Call(m, "SnapChain", arg0)
+	ret0, _ := ret[0].([]*api.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapChain indicates an expected call of SnapChain
+func (mr *MockVolumeDriverMockRecorder) SnapChain(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapChain", reflect.TypeOf((*MockVolumeDriver)(nil).SnapChain), arg0)
+}
+
+// SnapDelete mocks base method
+func (m *MockVolumeDriver) SnapDelete(arg0 string, arg1 bool) error {
+	ret := m.ctrl.Call(m, "SnapDelete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SnapDelete indicates an expected call of SnapDelete
+func (mr *MockVolumeDriverMockRecorder) SnapDelete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapDelete", reflect.TypeOf((*MockVolumeDriver)(nil).SnapDelete), arg0, arg1)
+}
+
 // SnapEnumerate mocks base method
 func (m *MockVolumeDriver) SnapEnumerate(arg0 []string, arg1 map[string]string) ([]*api.Volume, error) {
 	ret := m.ctrl.Call(m, "SnapEnumerate", arg0, arg1)
@@ -292,6 +342,19 @@ func (mr *MockVolumeDriverMockRecorder) SnapEnumerate(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapEnumerate", reflect.TypeOf((*MockVolumeDriver)(nil).SnapEnumerate), arg0, arg1)
 }
 
+// SnapTree mocks base method
+func (m *MockVolumeDriver) SnapTree(arg0 string) (*api.SnapshotTree, error) {
+	ret := m.ctrl.Call(m, "SnapTree", arg0)
+	ret0, _ := ret[0].(*api.SnapshotTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapTree indicates an expected call of SnapTree
+func (mr *MockVolumeDriverMockRecorder) SnapTree(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapTree", reflect.TypeOf((*MockVolumeDriver)(nil).SnapTree), arg0)
+}
+
 // Snapshot mocks base method
 func (m *MockVolumeDriver) Snapshot(arg0 string, arg1 bool, arg2 *api.VolumeLocator) (string, error) {
 	ret := m.ctrl.Call(m, "Snapshot", arg0, arg1, arg2)
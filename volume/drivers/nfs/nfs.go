@@ -136,30 +136,24 @@ func (d *driver) Create(
 		return "", errors.New("Volume with that name already exists")
 	}
 
-	// Create a directory on the NFS server with this UUID.
+	// Create a directory on the NFS server with this UUID. A clone starts as
+	// a copy of the directory of its parent.
 	volPath := path.Join(nfsMountPath, volumeID)
+	if parentID := source.GetParent(); parentID != "" {
+		if _, err := d.GetVol(parentID); err != nil {
+			return "", err
+		}
+		err := common.CloneTree(path.Join(nfsMountPath, parentID), volPath)
+		if err != nil {
+			os.RemoveAll(volPath)
+			return "", fmt.Errorf("Failed to clone volume %v: %v", parentID, err)
+		}
+	}
 	err := os.MkdirAll(volPath, 0744)
 	if err != nil {
 		dlog.Println(err)
 		return "", err
 	}
-	if source != nil {
-		if len(source.Seed) != 0 {
-			seed, err := seed.New(source.Seed, spec.VolumeLabels)
-			if err != nil {
-				dlog.Warnf("Failed to initailize seed from %q : %v",
-					source.Seed, err)
-				return "", err
-			}
-			err = seed.Load(path.Join(volPath, config.DataDir))
-			if err != nil {
-				dlog.Warnf("Failed to  seed from %q to %q: %v",
-					source.Seed, nfsMountPath, err)
-				return "", err
-			}
-		}
-	}
-
 	f, err := os.Create(path.Join(nfsMountPath, volumeID+nfsBlockFile))
 	if err != nil {
 		dlog.Println(err)
@@ -184,7 +178,14 @@ func (d *driver) Create(
 	if err := d.CreateVol(v); err != nil {
 		return "", err
 	}
-	return v.Id, err
+	err = seed.LoadVolumeDir(d, d, v.Id, config.DataDir, spec.VolumeLabels)
+	if err != nil {
+		dlog.Warnf("Failed to seed volume %q from %q: %v",
+			volumeID, source.GetSeed(), err)
+		d.Delete(v.Id)
+		return "", err
+	}
+	return v.Id, nil
 }
 
 func (d *driver) Delete(volumeID string) error {
@@ -193,6 +194,9 @@ func (d *driver) Delete(volumeID string) error {
 		dlog.Println(err)
 		return err
 	}
+	if err := common.CheckNoSnaps(d, volumeID); err != nil {
+		return err
+	}
 
 	// Delete the simulated block volume
 	os.Remove(v.DevicePath)
@@ -269,17 +273,13 @@ func (d *driver) Snapshot(volumeID string, readonly bool, locator *api.VolumeLoc
 	if err != nil {
 		return "", nil
 	}
+	// NFS does not support snapshots, so the snapshot is a clone of the
+	// files.
 	source := &api.Source{Parent: volumeID}
 	newVolumeID, err := d.Create(locator, source, vols[0].Spec)
 	if err != nil {
 		return "", nil
 	}
-
-	// NFS does not support snapshots, so just copy the files.
-	if err := copyDir(nfsMountPath+volumeID, nfsMountPath+newVolumeID); err != nil {
-		d.Delete(newVolumeID)
-		return "", nil
-	}
 	return newVolumeID, nil
 }
 
@@ -295,6 +295,14 @@ func (d *driver) Restore(volumeID string, snapID string) error {
 	return nil
 }
 
+func (d *driver) SnapDelete(snapID string, cascade bool) error {
+	return common.SnapDelete(d, snapID, cascade)
+}
+
+func (d *driver) Promote(cloneID string) error {
+	return common.Promote(d, cloneID)
+}
+
 func (d *driver) Attach(volumeID string, attachOptions map[string]string) (string, error) {
 	return path.Join(nfsMountPath, volumeID+nfsBlockFile), nil
 }
@@ -303,14 +311,25 @@ func (d *driver) Detach(volumeID string, options map[string]string) error {
 	return nil
 }
 
+// Set updates the locator. Of the spec only the size can be changed. The data
+// of the volume is a directory on the NFS share, which is not limited in
+// size and has no filesystem of its own to grow, so the new size is recorded
+// and the simulated block device is grown to match it.
 func (d *driver) Set(volumeID string, locator *api.VolumeLocator, spec *api.VolumeSpec) error {
-	if spec != nil {
-		return volume.ErrNotSupported
-	}
 	v, err := d.GetVol(volumeID)
 	if err != nil {
 		return err
 	}
+	grow, err := common.ValidateSizeOnlyResize(v, spec)
+	if err != nil {
+		return err
+	}
+	if grow {
+		if err := os.Truncate(v.DevicePath, int64(spec.Size)); err != nil {
+			return err
+		}
+		v.Spec.Size = spec.Size
+	}
 	if locator != nil {
 		v.Locator = locator
 	}
@@ -352,6 +352,16 @@ func snapEnumerate(t *testing.T, ctx *Context) {
 	require.NotNil(t, snaps, "Nil snaps")
 	require.Equal(t, len(snaps), 1, "Expect 1 snap actual %v snaps", len(snaps))
 	require.Equal(t, snaps[0].Id, ctx.snapID, "Expect snapID %v actual %v", ctx.snapID, snaps[0].Id)
+
+	tree, err := ctx.SnapTree(ctx.volID)
+	require.NoError(t, err, "Failed in snapTree")
+	require.Equal(t, 1, len(tree.Children), "Expect 1 snap actual %v snaps", len(tree.Children))
+	require.Equal(t, tree.Children[0].Volume.Id, ctx.snapID, "Expect snapID %v actual %v", ctx.snapID, tree.Children[0].Volume.Id)
+
+	chain, err := ctx.SnapChain(ctx.snapID)
+	require.NoError(t, err, "Failed in snapChain")
+	require.Equal(t, 1, len(chain), "Expect 1 ancestor actual %v ancestors", len(chain))
+	require.Equal(t, chain[0].Id, ctx.volID, "Expect volID %v actual %v", ctx.volID, chain[0].Id)
 }
 
 func snapDiff(t *testing.T, ctx *Context) {
@@ -360,4 +370,17 @@ func snapDiff(t *testing.T, ctx *Context) {
 
 func snapDelete(t *testing.T, ctx *Context) {
 	fmt.Println("snapDelete")
+
+	err := ctx.SnapDelete(ctx.volID, false)
+	if err == volume.ErrNotSupported {
+		return
+	}
+	require.Equal(t, volume.ErrEinval, err, "SnapDelete of a volume that is not a snap must fail")
+
+	err = ctx.SnapDelete(ctx.snapID, false)
+	require.NoError(t, err, "Failed in snapDelete")
+
+	snaps, err := ctx.Inspect([]string{ctx.snapID})
+	require.Equal(t, 0, len(snaps), "Expect 0 snaps actual %v snaps", len(snaps))
+	ctx.snapID = ""
 }
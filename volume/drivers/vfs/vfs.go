@@ -12,6 +12,7 @@ import (
 	"go.pedge.io/dlog"
 
 	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/seed"
 	"github.com/libopenstorage/openstorage/volume"
 	"github.com/libopenstorage/openstorage/volume/drivers/common"
 	"github.com/pborman/uuid"
@@ -58,8 +59,19 @@ func (d *driver) Type() api.DriverType {
 
 func (d *driver) Create(locator *api.VolumeLocator, source *api.Source, spec *api.VolumeSpec) (string, error) {
 	volumeID := strings.TrimSuffix(uuid.New(), "\n")
-	// Create a directory on the Local machine with this UUID.
-	if err := os.MkdirAll(filepath.Join(volume.VolumeBase, string(volumeID)), 0744); err != nil {
+	volumePath := filepath.Join(volume.VolumeBase, volumeID)
+	// Create a directory on the Local machine with this UUID. A clone starts
+	// as a copy of the directory of its parent.
+	if parentID := source.GetParent(); parentID != "" {
+		if _, err := d.GetVol(parentID); err != nil {
+			return "", err
+		}
+		parentPath := filepath.Join(volume.VolumeBase, parentID)
+		if err := common.CloneTree(parentPath, volumePath); err != nil {
+			os.RemoveAll(volumePath)
+			return "", fmt.Errorf("Failed to clone volume %v: %v", parentID, err)
+		}
+	} else if err := os.MkdirAll(volumePath, 0744); err != nil {
 		return "", err
 	}
 	v := common.NewVolume(
@@ -69,17 +81,25 @@ func (d *driver) Create(locator *api.VolumeLocator, source *api.Source, spec *ap
 		source,
 		spec,
 	)
-	v.DevicePath = filepath.Join(volume.VolumeBase, volumeID)
+	v.DevicePath = volumePath
 	if err := d.CreateVol(v); err != nil {
+		os.RemoveAll(volumePath)
+		return "", err
+	}
+	if err := seed.LoadVolume(d, d, v.Id, spec.VolumeLabels); err != nil {
+		d.Delete(v.Id)
 		return "", err
 	}
-	return v.Id, d.UpdateVol(v)
+	return v.Id, nil
 }
 
 func (d *driver) Delete(volumeID string) error {
 	if _, err := d.GetVol(volumeID); err != nil {
 		return err
 	}
+	if err := common.CheckNoSnaps(d, volumeID); err != nil {
+		return err
+	}
 	os.RemoveAll(filepath.Join(volume.VolumeBase, string(volumeID)))
 	if err := d.DeleteVol(volumeID); err != nil {
 		return err
@@ -141,14 +161,20 @@ func (d *driver) Unmount(volumeID string, mountpath string, options map[string]s
 	return d.UpdateVol(v)
 }
 
+// Set updates the locator. Of the spec only the size can be changed. The
+// volume is a plain directory, so the new size is only recorded.
 func (d *driver) Set(volumeID string, locator *api.VolumeLocator, spec *api.VolumeSpec) error {
-	if spec != nil {
-		return volume.ErrNotSupported
-	}
 	v, err := d.GetVol(volumeID)
 	if err != nil {
 		return err
 	}
+	grow, err := common.ValidateSizeOnlyResize(v, spec)
+	if err != nil {
+		return err
+	}
+	if grow {
+		v.Spec.Size = spec.Size
+	}
 	if locator != nil {
 		v.Locator = locator
 	}
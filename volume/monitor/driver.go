@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"fmt"
+	"sync"
+
+	"go.pedge.io/dlog"
+
+	"github.com/libopenstorage/openstorage/alert"
+	"github.com/libopenstorage/openstorage/volume"
+)
+
+// driverTypes are the alert types Driver raises.
+var driverTypes = []int64{
+	alert.TypeVolumeAttachFailed,
+	alert.TypeVolumeMountFailed,
+	alert.TypeVolumeUnmountFailed,
+}
+
+// driver raises alerts on the failed operations of the volume driver it
+// embeds.
+type driver struct {
+	volume.VolumeDriver
+	alert alert.Alert
+	lock  sync.Mutex
+	// raised is keyed by volume ID and alert type. It is true for the
+	// alerts raised and false for those cleared since this node started.
+	raised map[string]map[int64]bool
+}
+
+// NewDriver returns d with attach, mount and unmount failures raised as
+// alerts in a.
+func NewDriver(d volume.VolumeDriver, a alert.Alert) volume.VolumeDriver {
+	return &driver{
+		VolumeDriver: d,
+		alert:        a,
+		raised:       make(map[string]map[int64]bool),
+	}
+}
+
+func (d *driver) Attach(volumeID string, attachOptions map[string]string) (string, error) {
+	devicePath, err := d.VolumeDriver.Attach(volumeID, attachOptions)
+	d.update(alert.TypeVolumeAttachFailed, volumeID, err,
+		"Failed to attach volume %v: %v")
+	return devicePath, err
+}
+
+func (d *driver) Mount(volumeID string, mountPath string, options map[string]string) error {
+	err := d.VolumeDriver.Mount(volumeID, mountPath, options)
+	d.update(alert.TypeVolumeMountFailed, volumeID, err,
+		"Failed to mount volume %v: %v")
+	return err
+}
+
+func (d *driver) Unmount(volumeID string, mountPath string, options map[string]string) error {
+	err := d.VolumeDriver.Unmount(volumeID, mountPath, options)
+	d.update(alert.TypeVolumeUnmountFailed, volumeID, err,
+		"Failed to unmount volume %v: %v")
+	return err
+}
+
+func (d *driver) Delete(volumeID string) error {
+	if err := d.VolumeDriver.Delete(volumeID); err != nil {
+		return err
+	}
+	for _, alertType := range driverTypes {
+		d.update(alertType, volumeID, nil, "")
+	}
+	d.lock.Lock()
+	delete(d.raised, volumeID)
+	d.lock.Unlock()
+	return nil
+}
+
+// AttachesRemotely forwards volume.RemoteAttacher, which embedding the
+// driver hides.
+func (d *driver) AttachesRemotely() bool {
+	r, ok := d.VolumeDriver.(volume.RemoteAttacher)
+	return ok && r.AttachesRemotely()
+}
+
+// update raises the alert of alertType on volumeID if err is set and clears
+// it otherwise. format takes the volume ID and err. There is no volume to
+// raise an alert on if it does not exist. An alert is only cleared if it
+// was raised, or once after this node started in case it was raised
+// before. The lock is only held to read and record what was raised, not
+// while the alert is written.
+func (d *driver) update(alertType int64, volumeID string, err error, format string) {
+	if err == volume.ErrEnoEnt {
+		return
+	}
+	if err != nil {
+		message := fmt.Sprintf(format, volumeID, err)
+		if err := alert.RaiseType(d.alert, alertType, volumeID, message); err != nil {
+			dlog.Warnf("Failed to raise alert %v on volume %v: %v",
+				alertType, volumeID, err)
+			return
+		}
+		d.setRaised(volumeID, alertType, true)
+		return
+	}
+	d.lock.Lock()
+	wasRaised, ok := d.raised[volumeID][alertType]
+	d.lock.Unlock()
+	if ok && !wasRaised {
+		return
+	}
+	if err := alert.ClearType(d.alert, alertType, volumeID); err != nil {
+		dlog.Warnf("Failed to clear alert %v on volume %v: %v",
+			alertType, volumeID, err)
+		return
+	}
+	d.setRaised(volumeID, alertType, false)
+}
+
+// setRaised records whether the alert of alertType on volumeID is raised.
+func (d *driver) setRaised(volumeID string, alertType int64, raised bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if _, ok := d.raised[volumeID]; !ok {
+		d.raised[volumeID] = make(map[int64]bool)
+	}
+	d.raised[volumeID][alertType] = raised
+}
@@ -0,0 +1,104 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/libopenstorage/openstorage/alert"
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/volume"
+	"github.com/libopenstorage/openstorage/volume/drivers/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver(t *testing.T) {
+	fd, err := fake.Init(map[string]string{})
+	require.NoError(t, err)
+	a := newAlert(t, "driver")
+	d := NewDriver(fd, a)
+
+	volumeID, err := d.Create(
+		&api.VolumeLocator{Name: "monitored"},
+		nil,
+		&api.VolumeSpec{Size: 1024},
+	)
+	require.NoError(t, err)
+
+	// A detached volume does not mount.
+	require.Error(t, d.Mount(volumeID, "/mnt/monitored", nil))
+	require.Equal(t, []int64{alert.TypeVolumeMountFailed}, activeTypes(t, a, volumeID))
+
+	_, err = d.Attach(volumeID, nil)
+	require.NoError(t, err)
+	require.NoError(t, d.Mount(volumeID, "/mnt/monitored", nil))
+	require.Empty(t, activeTypes(t, a, volumeID))
+
+	require.Error(t, d.Unmount(volumeID, "/mnt/other", nil))
+	require.Equal(t, []int64{alert.TypeVolumeUnmountFailed}, activeTypes(t, a, volumeID))
+
+	// Nothing is raised on volumes that do not exist.
+	_, err = d.Attach("missing", nil)
+	require.Equal(t, volume.ErrEnoEnt, err)
+	require.Empty(t, activeTypes(t, a, "missing"))
+
+	require.NoError(t, d.Unmount(volumeID, "/mnt/monitored", nil))
+	require.NoError(t, d.Detach(volumeID, nil))
+	require.NoError(t, d.Delete(volumeID))
+	require.Empty(t, activeTypes(t, a, volumeID))
+}
+
+// clearCounter counts the alerts cleared by unique tag.
+type clearCounter struct {
+	alert.Alert
+	clears int
+}
+
+func (c *clearCounter) ClearByUniqueTag(
+	resourceType api.ResourceType,
+	resourceID string,
+	uniqueTag string,
+	ttl uint64,
+) error {
+	c.clears++
+	return c.Alert.ClearByUniqueTag(resourceType, resourceID, uniqueTag, ttl)
+}
+
+func TestDriverClearsRaisedOnly(t *testing.T) {
+	fd, err := fake.Init(map[string]string{})
+	require.NoError(t, err)
+	a := &clearCounter{Alert: newAlert(t, "driverclears")}
+	d := NewDriver(fd, a)
+
+	volumeID, err := d.Create(
+		&api.VolumeLocator{Name: "cleared"},
+		nil,
+		&api.VolumeSpec{Size: 1024},
+	)
+	require.NoError(t, err)
+
+	// The first success clears an alert raised before, the next do not.
+	for i := 0; i < 3; i++ {
+		_, err = d.Attach(volumeID, nil)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, a.clears)
+
+	// A failure is cleared by the next success only.
+	require.Error(t, d.Unmount(volumeID, "/mnt/other", nil))
+	require.NoError(t, d.Mount(volumeID, "/mnt/cleared", nil))
+	require.NoError(t, d.Unmount(volumeID, "/mnt/cleared", nil))
+	require.NoError(t, d.Mount(volumeID, "/mnt/cleared", nil))
+	require.NoError(t, d.Unmount(volumeID, "/mnt/cleared", nil))
+	require.Equal(t, 3, a.clears)
+	require.Empty(t, activeTypes(t, a, volumeID))
+}
+
+func TestDriverRemoteAttacher(t *testing.T) {
+	fd, err := fake.Init(map[string]string{})
+	require.NoError(t, err)
+	d := NewDriver(fd, newAlert(t, "driverremote"))
+
+	// The fake driver attaches remotely, which is not hidden.
+	r, ok := d.(volume.RemoteAttacher)
+	require.True(t, ok)
+	require.True(t, r.AttachesRemotely())
+}
@@ -0,0 +1,263 @@
+// Package monitor raises and clears alerts on the health of volumes.
+//
+// Driver wraps a volume driver so that a failed attach, mount or unmount
+// raises an alert on the volume, which is cleared once the same operation
+// succeeds. The mount failures reported by pkg/mount reach the alerts this
+// way, as only the driver knows the volume a path belongs to.
+//
+// Monitor polls the volumes of the drivers for their status and usage. It
+// raises an alert when a volume goes down or degraded, or fills up past
+// usageHigh or usageCritical percent, and clears it when the volume
+// recovers. The alerts carry the stable types and unique tags of package
+// alert, so every node that sees a change updates the same alert.
+package monitor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.pedge.io/dlog"
+
+	"github.com/libopenstorage/openstorage/alert"
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/volume"
+)
+
+const (
+	// usageHigh is the percentage of its size a volume uses when
+	// TypeVolumeUsageHigh is raised on it.
+	usageHigh = 80
+	// usageCritical is the percentage of its size a volume uses when
+	// TypeVolumeUsageCritical is raised on it.
+	usageCritical = 90
+)
+
+var (
+	// checkInterval is how often the volumes are checked.
+	checkInterval = time.Minute
+
+	// statusTypes are the alert types Monitor raises on the status of a
+	// volume.
+	statusTypes = []int64{
+		alert.TypeVolumeDown,
+		alert.TypeVolumeDegraded,
+	}
+	// usageTypes are the alert types Monitor raises on the usage of a
+	// volume.
+	usageTypes = []int64{
+		alert.TypeVolumeUsageHigh,
+		alert.TypeVolumeUsageCritical,
+	}
+)
+
+// Monitor raises alerts on the status and usage of volumes.
+type Monitor interface {
+	// Start checks the volumes in the background every checkInterval.
+	Start()
+	// Stop stops checking the volumes.
+	Stop()
+}
+
+// volumeAlerts are the alert types raised on a volume.
+type volumeAlerts struct {
+	driver   string
+	volumeID string
+	raised   map[int64]bool
+}
+
+type monitor struct {
+	sync.Mutex
+	alert   alert.Alert
+	drivers []volume.VolumeDriver
+	// volumes is keyed by driver name and volume ID.
+	volumes map[string]*volumeAlerts
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// New returns a monitor of the volumes provisioned by drivers that raises
+// its alerts in a.
+func New(a alert.Alert, drivers []volume.VolumeDriver) Monitor {
+	return &monitor{
+		alert:   a,
+		drivers: drivers,
+		volumes: make(map[string]*volumeAlerts),
+	}
+}
+
+func (m *monitor) Start() {
+	m.Lock()
+	defer m.Unlock()
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go m.run(m.stop, m.done)
+}
+
+func (m *monitor) Stop() {
+	m.Lock()
+	if m.stop == nil {
+		m.Unlock()
+		return
+	}
+	close(m.stop)
+	done := m.done
+	m.stop = nil
+	m.Unlock()
+	<-done
+}
+
+func (m *monitor) run(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		m.check()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check raises the alerts of the volumes that became unhealthy since the
+// last check and clears those of the volumes that recovered.
+func (m *monitor) check() {
+	seen := make(map[string]bool)
+	for _, d := range m.drivers {
+		vols, err := d.Enumerate(&api.VolumeLocator{}, nil)
+		if err != nil {
+			dlog.Warnf("Failed to enumerate %v volumes for alerts: %v",
+				d.Name(), err)
+			// Keep the alerts as they are rather than clear them on a
+			// transient error.
+			for k, va := range m.volumes {
+				if va.driver == d.Name() {
+					seen[k] = true
+				}
+			}
+			continue
+		}
+		for _, v := range vols {
+			if v.IsSnapshot() {
+				continue
+			}
+			k := d.Name() + "/" + v.Id
+			seen[k] = true
+			va, ok := m.volumes[k]
+			if !ok {
+				va = &volumeAlerts{
+					driver:   d.Name(),
+					volumeID: v.Id,
+					raised:   make(map[int64]bool),
+				}
+				m.volumes[k] = va
+			}
+			m.update(va, v, d, !ok)
+		}
+	}
+
+	// The volumes that are gone have nothing left to alert on.
+	for k, va := range m.volumes {
+		if seen[k] {
+			continue
+		}
+		for alertType := range va.raised {
+			m.clear(alertType, va.volumeID)
+		}
+		delete(m.volumes, k)
+	}
+}
+
+// update raises the alerts that v calls for and were not raised yet, and
+// clears the others. The first time a volume is seen, every alert it does
+// not call for is cleared, in case it was raised before this node started.
+func (m *monitor) update(
+	va *volumeAlerts,
+	v *api.Volume,
+	d volume.VolumeDriver,
+	first bool,
+) {
+	wanted := statusAlerts(v)
+	checked := append([]int64{}, statusTypes...)
+	if usage, ok := usageAlerts(v, d); ok {
+		for alertType, message := range usage {
+			wanted[alertType] = message
+		}
+		checked = append(checked, usageTypes...)
+	}
+
+	for alertType, message := range wanted {
+		if va.raised[alertType] {
+			continue
+		}
+		err := alert.RaiseType(m.alert, alertType, va.volumeID, message)
+		if err != nil {
+			dlog.Warnf("Failed to raise alert %v on volume %v: %v",
+				alertType, va.volumeID, err)
+			continue
+		}
+		va.raised[alertType] = true
+	}
+	for _, alertType := range checked {
+		if _, ok := wanted[alertType]; ok || (!first && !va.raised[alertType]) {
+			continue
+		}
+		if m.clear(alertType, va.volumeID) {
+			delete(va.raised, alertType)
+		}
+	}
+}
+
+// clear clears the alert of alertType on volumeID and returns true if it
+// succeeded.
+func (m *monitor) clear(alertType int64, volumeID string) bool {
+	if err := alert.ClearType(m.alert, alertType, volumeID); err != nil {
+		dlog.Warnf("Failed to clear alert %v on volume %v: %v",
+			alertType, volumeID, err)
+		return false
+	}
+	return true
+}
+
+// statusAlerts returns the messages of the alerts the status of v calls
+// for, keyed by alert type.
+func statusAlerts(v *api.Volume) map[int64]string {
+	wanted := make(map[int64]string)
+	switch v.Status {
+	case api.VolumeStatus_VOLUME_STATUS_DOWN:
+		wanted[alert.TypeVolumeDown] = fmt.Sprintf("Volume %v is down", v.Id)
+	case api.VolumeStatus_VOLUME_STATUS_DEGRADED:
+		wanted[alert.TypeVolumeDegraded] = fmt.Sprintf("Volume %v is degraded", v.Id)
+	}
+	return wanted
+}
+
+// usageAlerts returns the messages of the alerts the usage of v calls for,
+// keyed by alert type. It returns false if the usage of v is not known.
+func usageAlerts(v *api.Volume, d volume.VolumeDriver) (map[int64]string, bool) {
+	if v.Spec == nil || v.Spec.Size == 0 {
+		return nil, false
+	}
+	used, err := d.UsedSize(v.Id)
+	if err != nil {
+		if err != volume.ErrNotSupported {
+			dlog.Warnf("Failed to get used size of volume %v: %v", v.Id, err)
+		}
+		return nil, false
+	}
+	wanted := make(map[int64]string)
+	percent := used * 100 / v.Spec.Size
+	message := fmt.Sprintf("Volume %v is %d%% full", v.Id, percent)
+	if percent >= usageCritical {
+		wanted[alert.TypeVolumeUsageCritical] = message
+	} else if percent >= usageHigh {
+		wanted[alert.TypeVolumeUsageHigh] = message
+	}
+	return wanted, true
+}
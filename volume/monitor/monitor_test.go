@@ -0,0 +1,109 @@
+package monitor
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"go.pedge.io/dlog"
+
+	"github.com/libopenstorage/openstorage/alert"
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/volume"
+	"github.com/libopenstorage/openstorage/volume/drivers/mock"
+	"github.com/portworx/kvdb"
+	"github.com/portworx/kvdb/mem"
+	"github.com/stretchr/testify/require"
+)
+
+// newAlert returns alerts of clusterID in a kvdb of their own.
+func newAlert(t *testing.T, clusterID string) alert.Alert {
+	kv, err := kvdb.New(mem.Name, "monitor_test/"+clusterID, []string{}, nil, dlog.Panicf)
+	require.NoError(t, err)
+	a, err := alert.New(alert.Name, clusterID, kv)
+	require.NoError(t, err)
+	return a
+}
+
+// activeTypes returns the types of the alerts raised and not cleared on
+// volumeID, in ascending order.
+func activeTypes(t *testing.T, a alert.Alert, volumeID string) []int64 {
+	cleared := false
+	list, err := a.EnumerateWithFilter(&api.AlertFilter{
+		Resource:   api.ResourceType_RESOURCE_TYPE_VOLUME,
+		ResourceId: volumeID,
+		Cleared:    &cleared,
+	})
+	require.NoError(t, err)
+	types := make([]int64, 0, len(list.Alerts))
+	for _, a := range list.Alerts {
+		types = append(types, a.AlertType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+func TestCheck(t *testing.T) {
+	mc := gomock.NewController(t)
+	defer mc.Finish()
+	d := mock.NewMockVolumeDriver(mc)
+	d.EXPECT().Name().Return("mock").AnyTimes()
+
+	a := newAlert(t, "check")
+	m := New(a, []volume.VolumeDriver{d}).(*monitor)
+
+	vol := &api.Volume{
+		Id:     "vol1",
+		Status: api.VolumeStatus_VOLUME_STATUS_DEGRADED,
+		Spec:   &api.VolumeSpec{Size: 100},
+	}
+	snap := &api.Volume{
+		Id:       "snap1",
+		Status:   api.VolumeStatus_VOLUME_STATUS_DOWN,
+		Readonly: true,
+		Source:   &api.Source{Parent: "vol1"},
+	}
+	enumerate := func(vols ...*api.Volume) {
+		d.EXPECT().Enumerate(gomock.Any(), gomock.Any()).Return(vols, nil)
+	}
+
+	enumerate(vol, snap)
+	d.EXPECT().UsedSize("vol1").Return(uint64(85), nil)
+	m.check()
+	require.Equal(t,
+		[]int64{alert.TypeVolumeDegraded, alert.TypeVolumeUsageHigh},
+		activeTypes(t, a, "vol1"))
+	require.Empty(t, activeTypes(t, a, "snap1"))
+
+	// The volume recovers and keeps filling up.
+	vol.Status = api.VolumeStatus_VOLUME_STATUS_UP
+	enumerate(vol)
+	d.EXPECT().UsedSize("vol1").Return(uint64(95), nil)
+	m.check()
+	require.Equal(t,
+		[]int64{alert.TypeVolumeUsageCritical},
+		activeTypes(t, a, "vol1"))
+
+	// The usage is left alone while it is not known.
+	vol.Status = api.VolumeStatus_VOLUME_STATUS_DOWN
+	enumerate(vol)
+	d.EXPECT().UsedSize("vol1").Return(uint64(0), volume.ErrNotSupported)
+	m.check()
+	require.Equal(t,
+		[]int64{alert.TypeVolumeDown, alert.TypeVolumeUsageCritical},
+		activeTypes(t, a, "vol1"))
+
+	// A monitor started later clears what is no longer the case.
+	vol.Status = api.VolumeStatus_VOLUME_STATUS_UP
+	enumerate(vol)
+	d.EXPECT().UsedSize("vol1").Return(uint64(95), nil)
+	New(a, []volume.VolumeDriver{d}).(*monitor).check()
+	require.Equal(t,
+		[]int64{alert.TypeVolumeUsageCritical},
+		activeTypes(t, a, "vol1"))
+
+	// The alerts of a deleted volume are cleared.
+	enumerate()
+	m.check()
+	require.Empty(t, activeTypes(t, a, "vol1"))
+}
@@ -0,0 +1,395 @@
+// Package snapscheduler takes the snapshots requested by the
+// snapshot_interval and snapshot_schedule fields of each volume's spec.
+//
+// Exactly one node in the cluster drives the schedules at any time. The
+// scheduler that holds the kvdb lock at lockKey is the leader; the others
+// block on the lock and take over when the leader goes away. The time of
+// the last snapshot taken for every (volume, interval) pair is kept in
+// kvdb so a new leader, or a restarted one, picks up where the previous
+// one stopped.
+package snapscheduler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"go.pedge.io/dlog"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/sched"
+	"github.com/libopenstorage/openstorage/volume"
+	"github.com/libopenstorage/openstorage/volume/drivers/common"
+	"github.com/portworx/kvdb"
+)
+
+const (
+	// ScheduleLabel is set on every scheduled snapshot. Its value identifies
+	// the interval that created the snapshot and is used for retention.
+	ScheduleLabel = "snapshot-schedule"
+
+	keyBase       = "snapscheduler/"
+	lockKey       = keyBase + "lock"
+	lastRunPrefix = keyBase + "lastrun/"
+	timeFormat    = "2006_01_02_15_04_05"
+)
+
+var (
+	// reconcileInterval is how often the leader re-reads volume schedules.
+	reconcileInterval = time.Minute
+)
+
+// SnapScheduler takes scheduled snapshots of volumes.
+type SnapScheduler interface {
+	// Start contends for leadership in the background and, once elected,
+	// starts taking snapshots.
+	Start()
+	// Stop cancels all schedules and gives up leadership.
+	Stop()
+	// IsLeader returns true if this scheduler is currently taking snapshots.
+	IsLeader() bool
+}
+
+// task is a schedule installed in the task scheduler for one volume interval.
+type task struct {
+	id       sched.TaskID
+	driver   volume.VolumeDriver
+	volumeID string
+	key      string
+	// retain is updated in place when only the retain count changes.
+	retain uint32
+}
+
+type snapScheduler struct {
+	sync.Mutex
+	kv      kvdb.Kvdb
+	nodeID  string
+	drivers []volume.VolumeDriver
+	sched   sched.Scheduler
+	// tasks is keyed by driver name, volume ID and interval key.
+	tasks  map[string]*task
+	leader bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// New returns a snapshot scheduler for volumes provisioned by drivers.
+// nodeID identifies this node as the lock holder.
+func New(
+	kv kvdb.Kvdb,
+	nodeID string,
+	drivers []volume.VolumeDriver,
+	s sched.Scheduler,
+) SnapScheduler {
+	return &snapScheduler{
+		kv:      kv,
+		nodeID:  nodeID,
+		drivers: drivers,
+		sched:   s,
+		tasks:   make(map[string]*task),
+	}
+}
+
+func (s *snapScheduler) Start() {
+	s.Lock()
+	defer s.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+func (s *snapScheduler) Stop() {
+	s.Lock()
+	if s.stop == nil {
+		s.Unlock()
+		return
+	}
+	close(s.stop)
+	done := s.done
+	s.stop = nil
+	s.Unlock()
+	<-done
+}
+
+func (s *snapScheduler) IsLeader() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.leader
+}
+
+func (s *snapScheduler) run(stop, done chan struct{}) {
+	defer close(done)
+
+	kvp := s.acquireLock(stop)
+	if kvp == nil {
+		return
+	}
+	defer s.kv.Unlock(kvp)
+
+	dlog.Infof("Node %v is now the snapshot scheduler", s.nodeID)
+	s.Lock()
+	s.leader = true
+	s.Unlock()
+
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+	for {
+		s.reconcile()
+		select {
+		case <-stop:
+			s.cancelAll()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// acquireLock blocks until this node holds the scheduler lock. It returns nil
+// if the scheduler was stopped in the meantime.
+func (s *snapScheduler) acquireLock(stop chan struct{}) *kvdb.KVPair {
+	for {
+		locked := make(chan *kvdb.KVPair, 1)
+		go func() {
+			kvp, err := s.kv.LockWithID(lockKey, s.nodeID)
+			if err != nil {
+				dlog.Warnf("Failed to acquire snapshot scheduler lock: %v", err)
+				kvp = nil
+			}
+			locked <- kvp
+		}()
+		select {
+		case kvp := <-locked:
+			if kvp != nil {
+				return kvp
+			}
+		case <-stop:
+			// The lock may still be granted after we give up on it.
+			go func() {
+				if kvp := <-locked; kvp != nil {
+					s.kv.Unlock(kvp)
+				}
+			}()
+			return nil
+		}
+	}
+}
+
+// reconcile makes the installed tasks match the schedules of all volumes.
+func (s *snapScheduler) reconcile() {
+	wanted := make(map[string]bool)
+	for _, d := range s.drivers {
+		vols, err := d.Enumerate(&api.VolumeLocator{}, nil)
+		if err != nil {
+			dlog.Warnf("Failed to enumerate %v volumes for snapshot "+
+				"schedules: %v", d.Name(), err)
+			// Keep the existing tasks rather than cancel them on a
+			// transient error.
+			s.Lock()
+			for k, t := range s.tasks {
+				if t.driver == d {
+					wanted[k] = true
+				}
+			}
+			s.Unlock()
+			continue
+		}
+		for _, v := range vols {
+			if v.IsSnapshot() || v.Spec == nil {
+				continue
+			}
+			intvs, err := Intervals(v.Spec)
+			if err != nil {
+				dlog.Warnf("Invalid snapshot schedule on volume %v: %v",
+					v.Id, err)
+				continue
+			}
+			for _, iv := range intvs {
+				k := d.Name() + "/" + v.Id + "/" + IntervalKey(iv)
+				wanted[k] = true
+				s.ensureTask(k, d, v.Id, iv)
+			}
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	for k, t := range s.tasks {
+		if !wanted[k] {
+			s.sched.Cancel(t.id)
+			delete(s.tasks, k)
+		}
+	}
+}
+
+func (s *snapScheduler) ensureTask(
+	k string,
+	d volume.VolumeDriver,
+	volumeID string,
+	iv sched.RetainInterval,
+) {
+	s.Lock()
+	defer s.Unlock()
+
+	if t, ok := s.tasks[k]; ok {
+		t.retain = iv.RetainNumber()
+		return
+	}
+	t := &task{
+		driver:   d,
+		volumeID: volumeID,
+		key:      IntervalKey(iv),
+		retain:   iv.RetainNumber(),
+	}
+	lastRun, err := s.lastRun(t)
+	if err != nil {
+		dlog.Warnf("Failed to read last snapshot time of volume %v: %v",
+			volumeID, err)
+		return
+	}
+	id, err := s.sched.Schedule(
+		func(sched.Interval) { s.snapshot(t) },
+		iv,
+		lastRun,
+		false,
+	)
+	if err != nil {
+		dlog.Warnf("Failed to schedule snapshots of volume %v: %v",
+			volumeID, err)
+		return
+	}
+	t.id = id
+	s.tasks[k] = t
+}
+
+func (s *snapScheduler) cancelAll() {
+	s.Lock()
+	defer s.Unlock()
+	for k, t := range s.tasks {
+		s.sched.Cancel(t.id)
+		delete(s.tasks, k)
+	}
+	s.leader = false
+}
+
+func (s *snapScheduler) lastRunKey(t *task) string {
+	return lastRunPrefix + t.volumeID + "/" + t.key
+}
+
+// lastRun returns the time of the last scheduled snapshot of t. The first
+// time a schedule is seen the current time is recorded, so that a restart
+// before the first snapshot does not skip it.
+func (s *snapScheduler) lastRun(t *task) (time.Time, error) {
+	var last time.Time
+	if _, err := s.kv.GetVal(s.lastRunKey(t), &last); err == nil {
+		return last, nil
+	} else if err != kvdb.ErrNotFound {
+		return last, err
+	}
+	last = time.Now()
+	if _, err := s.kv.Create(s.lastRunKey(t), last, 0); err != nil &&
+		err != kvdb.ErrExist {
+		return last, err
+	}
+	return last, nil
+}
+
+// snapshot takes a scheduled snapshot of the task's volume and prunes the
+// snapshots that fall outside the retain count.
+func (s *snapScheduler) snapshot(t *task) {
+	now := time.Now()
+	vols, err := t.driver.Inspect([]string{t.volumeID})
+	if err != nil || len(vols) != 1 {
+		dlog.Warnf("Scheduled snapshot of volume %v skipped: %v",
+			t.volumeID, err)
+		return
+	}
+	v := vols[0]
+	name := v.Id
+	if v.Locator != nil && v.Locator.Name != "" {
+		name = v.Locator.Name
+	}
+	locator := &api.VolumeLocator{
+		Name:         fmt.Sprintf("%s_%s", name, now.Format(timeFormat)),
+		VolumeLabels: map[string]string{ScheduleLabel: t.key},
+	}
+	snapID, err := t.driver.Snapshot(t.volumeID, true, locator)
+	if err != nil {
+		dlog.Warnf("Scheduled snapshot of volume %v failed: %v",
+			t.volumeID, err)
+		return
+	}
+	dlog.Infof("Created scheduled snapshot %v of volume %v", snapID, t.volumeID)
+	if _, err := s.kv.Put(s.lastRunKey(t), now, 0); err != nil {
+		dlog.Warnf("Failed to record snapshot time of volume %v: %v",
+			t.volumeID, err)
+	}
+
+	s.Lock()
+	retain := t.retain
+	s.Unlock()
+	s.prune(t, retain)
+}
+
+func (s *snapScheduler) prune(t *task, retain uint32) {
+	snaps, err := t.driver.SnapEnumerate(
+		[]string{t.volumeID},
+		map[string]string{ScheduleLabel: t.key},
+	)
+	if err != nil {
+		dlog.Warnf("Failed to enumerate scheduled snapshots of volume %v: %v",
+			t.volumeID, err)
+		return
+	}
+	if uint32(len(snaps)) <= retain {
+		return
+	}
+	sort.Sort(common.ByCtime(snaps))
+	for _, snap := range snaps[:len(snaps)-int(retain)] {
+		if err := t.driver.Delete(snap.Id); err != nil {
+			dlog.Warnf("Failed to delete expired snapshot %v: %v",
+				snap.Id, err)
+		}
+	}
+}
+
+// Intervals returns the snapshot intervals requested by spec, with the
+// default retain counts filled in.
+func Intervals(spec *api.VolumeSpec) ([]sched.RetainInterval, error) {
+	intvs, _, err := sched.ParseScheduleAndPolicies(spec.SnapshotSchedule)
+	if err != nil {
+		return nil, err
+	}
+	if spec.SnapshotInterval != 0 {
+		intvs = append(intvs, sched.NewRetainInterval(
+			sched.Periodic(time.Duration(spec.SnapshotInterval)*time.Minute)))
+	}
+	return sched.SetupIntvWithDefaults(intvs), nil
+}
+
+// IntervalKey returns a stable identifier of an interval, ignoring its
+// retain count.
+func IntervalKey(iv sched.Interval) string {
+	spec := iv.Spec()
+	switch spec.Freq {
+	case sched.PeriodicType:
+		return fmt.Sprintf("%s-%d", spec.Freq, spec.Period)
+	case sched.DailyType:
+		return fmt.Sprintf("%s-%02d%02d", spec.Freq, spec.Hour, spec.Minute)
+	case sched.WeeklyType:
+		return fmt.Sprintf("%s-%d-%02d%02d",
+			spec.Freq, spec.Weekday, spec.Hour, spec.Minute)
+	case sched.CronType:
+		// Cron expressions and time zones contain characters that do not
+		// belong in kvdb keys and labels.
+		h := fnv.New32a()
+		h.Write([]byte(spec.Cron + "@" + spec.Location))
+		return fmt.Sprintf("%s-%08x", spec.Freq, h.Sum32())
+	}
+	return fmt.Sprintf("%s-%d-%02d%02d",
+		spec.Freq, spec.Day, spec.Hour, spec.Minute)
+}
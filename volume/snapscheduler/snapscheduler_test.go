@@ -0,0 +1,139 @@
+package snapscheduler
+
+import (
+	"testing"
+	"time"
+
+	"go.pedge.io/dlog"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/sched"
+	"github.com/libopenstorage/openstorage/volume"
+	"github.com/libopenstorage/openstorage/volume/drivers/fake"
+	"github.com/portworx/kvdb"
+	"github.com/portworx/kvdb/mem"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// twoSecondSchedule snapshots every two seconds and keeps two.
+	twoSecondSchedule = "- freq: periodic\n  period: 2000000000\n  retain: 2\n"
+	// hourlySchedule snapshots every hour and keeps the default count.
+	hourlySchedule = "- freq: periodic\n  period: 3600000000000\n"
+)
+
+func init() {
+	reconcileInterval = 100 * time.Millisecond
+}
+
+func setup(t *testing.T, schedule string) (kvdb.Kvdb, volume.VolumeDriver, string) {
+	kv, err := kvdb.New(mem.Name, "snapscheduler_test", []string{}, nil, dlog.Panicf)
+	require.NoError(t, err)
+	d, err := fake.Init(map[string]string{})
+	require.NoError(t, err)
+	volumeID, err := d.Create(
+		&api.VolumeLocator{Name: "scheduled"},
+		nil,
+		&api.VolumeSpec{Size: 1024, SnapshotSchedule: schedule},
+	)
+	require.NoError(t, err)
+	return kv, d, volumeID
+}
+
+func waitForLeader(t *testing.T, s SnapScheduler) {
+	for i := 0; i < 50 && !s.IsLeader(); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.True(t, s.IsLeader(), "scheduler did not become leader")
+}
+
+func TestIntervals(t *testing.T) {
+	intvs, err := Intervals(&api.VolumeSpec{
+		SnapshotInterval: 15,
+		SnapshotSchedule: "daily=02:30,3",
+	})
+	require.NoError(t, err)
+	require.Len(t, intvs, 2)
+	require.Equal(t, "daily-0230", IntervalKey(intvs[0]))
+	require.Equal(t, uint32(3), intvs[0].RetainNumber())
+	require.Equal(t, sched.PeriodicType, intvs[1].IntervalType())
+	require.Equal(t, uint32(sched.WeeklyRetain), intvs[1].RetainNumber())
+
+	intvs, err = Intervals(&api.VolumeSpec{
+		SnapshotSchedule: "cron=CRON_TZ=UTC */30 * * * *",
+	})
+	require.NoError(t, err)
+	require.Len(t, intvs, 1)
+	require.Regexp(t, "^cron-[0-9a-f]{8}$", IntervalKey(intvs[0]))
+
+	_, err = Intervals(&api.VolumeSpec{SnapshotSchedule: "hourly=1"})
+	require.Error(t, err)
+}
+
+func TestRetain(t *testing.T) {
+	kv, d, volumeID := setup(t, twoSecondSchedule)
+	s := New(kv, "node1", []volume.VolumeDriver{d}, sched.New(100*time.Millisecond))
+	s.Start()
+	defer s.Stop()
+	waitForLeader(t, s)
+
+	time.Sleep(7 * time.Second)
+	snaps, err := d.SnapEnumerate([]string{volumeID}, nil)
+	require.NoError(t, err)
+	require.Len(t, snaps, 2)
+	for _, snap := range snaps {
+		require.Equal(t, "periodic-2000000000", snap.Locator.VolumeLabels[ScheduleLabel])
+	}
+}
+
+func TestSingleLeader(t *testing.T) {
+	kv, d, _ := setup(t, "")
+	s1 := New(kv, "node1", []volume.VolumeDriver{d}, sched.New(time.Second))
+	s2 := New(kv, "node2", []volume.VolumeDriver{d}, sched.New(time.Second))
+	s1.Start()
+	waitForLeader(t, s1)
+	s2.Start()
+	time.Sleep(2 * time.Second)
+	require.False(t, s2.IsLeader())
+
+	s1.Stop()
+	require.False(t, s1.IsLeader())
+	waitForLeader(t, s2)
+	s2.Stop()
+}
+
+func TestRestart(t *testing.T) {
+	kv, d, volumeID := setup(t, hourlySchedule)
+	s := New(kv, "node1", []volume.VolumeDriver{d}, sched.New(100*time.Millisecond))
+	s.Start()
+	waitForLeader(t, s)
+	time.Sleep(time.Second)
+	s.Stop()
+
+	// The first sighting of a schedule records its start time; no
+	// snapshot is due for another hour.
+	snaps, err := d.SnapEnumerate([]string{volumeID}, nil)
+	require.NoError(t, err)
+	require.Len(t, snaps, 0)
+	key := lastRunPrefix + volumeID + "/periodic-3600000000000"
+	var last time.Time
+	_, err = kv.GetVal(key, &last)
+	require.NoError(t, err)
+
+	// Pretend the scheduler was down for the last snapshot. The restarted
+	// scheduler takes it exactly once.
+	_, err = kv.Put(key, time.Now().Add(-61*time.Minute), 0)
+	require.NoError(t, err)
+	s = New(kv, "node1", []volume.VolumeDriver{d}, sched.New(100*time.Millisecond))
+	s.Start()
+	defer s.Stop()
+	waitForLeader(t, s)
+	time.Sleep(2 * time.Second)
+
+	snaps, err = d.SnapEnumerate([]string{volumeID}, nil)
+	require.NoError(t, err)
+	require.Len(t, snaps, 1)
+	_, err = kv.GetVal(key, &last)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), last, 3*time.Second)
+}
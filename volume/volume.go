@@ -36,6 +36,10 @@ var (
 	ErrNotSupported = errors.New("Operation not supported")
 	// ErrVolBusy returned when volume is in busy state
 	ErrVolBusy = errors.New("Volume is busy")
+	// ErrVolShrink returned when a smaller size is requested for a volume
+	ErrVolShrink = errors.New("Volume size cannot be reduced")
+	// ErrInvalidToken returned when a continuation token is not valid
+	ErrInvalidToken = errors.New("Invalid continuation token")
 )
 
 // Constants used by the VolumeDriver
@@ -104,6 +108,14 @@ type SnapshotDriver interface {
 	Snapshot(volumeID string, readonly bool, locator *api.VolumeLocator) (string, error)
 	// Restore restores volume to specified snapshot.
 	Restore(volumeID string, snapshotID string) error
+	// SnapDelete deletes the specified snapshot or clone.
+	// If it has snapshots or clones of its own ErrVolHasSnaps is returned,
+	// unless cascade is set in which case they are deleted first.
+	// Errors ErrEnoEnt, ErrEinval, ErrVolHasSnaps may be returned.
+	SnapDelete(snapID string, cascade bool) error
+	// Promote makes the specified clone independent of its parent.
+	// Errors ErrEnoEnt, ErrEinval may be returned.
+	Promote(cloneID string) error
 }
 
 // StatsDriver interface provides stats features
@@ -156,7 +168,8 @@ type ProtoDriver interface {
 	// Errors ErrEnoEnt, ErrVolDetached may be returned.
 	Unmount(volumeID string, mountPath string, options map[string]string) error
 	// Update not all fields of the spec are supported, ErrNotSupported will be thrown for unsupported
-	// updates.
+	// updates. A larger spec.Size expands the volume and its filesystem.
+	// Errors ErrEnoEnt, ErrVolShrink, ErrNotSupported may be returned.
 	Set(volumeID string, locator *api.VolumeLocator, spec *api.VolumeSpec) error
 	// Status returns a set of key-value pairs which give low
 	// level diagnostic status about this driver.
@@ -173,8 +186,20 @@ type Enumerator interface {
 	// Enumerate volumes that map to the volumeLocator. Locator fields may be regexp.
 	// If locator fields are left blank, this will return all volumes.
 	Enumerate(locator *api.VolumeLocator, labels map[string]string) ([]*api.Volume, error)
+	// EnumerateWithFilter returns a page of the volumes that match filter,
+	// ordered by volume ID.
+	// Errors ErrInvalidToken may be returned.
+	EnumerateWithFilter(filter *api.VolumeFilter) (*api.VolumeList, error)
 	// Enumerate snaps for specified volumes
 	SnapEnumerate(volID []string, snapLabels map[string]string) ([]*api.Volume, error)
+	// SnapTree returns the snapshots and clones descending from the specified
+	// volume.
+	// Errors ErrEnoEnt may be returned.
+	SnapTree(volumeID string) (*api.SnapshotTree, error)
+	// SnapChain returns the ancestors of the specified volume, starting with
+	// its parent and ending with the volume the lineage originates from.
+	// Errors ErrEnoEnt may be returned.
+	SnapChain(volumeID string) ([]*api.Volume, error)
 }
 
 // StoreEnumerator combines Store and Enumerator capabilities
@@ -195,6 +220,14 @@ type BlockDriver interface {
 	Detach(volumeID string, options map[string]string) error
 }
 
+// RemoteAttacher is implemented by block drivers which can attach volumes on
+// another node than the one handling the request. Their Attach honors
+// options.OptionsAttachNode and records the node in AttachedOn.
+type RemoteAttacher interface {
+	// AttachesRemotely returns true if volumes can be attached on other nodes.
+	AttachesRemotely() bool
+}
+
 // CredsDriver provides methods to handle credentials
 type CredsDriver interface {
 	// CredsCreate creates credential for a given cloud provider
@@ -228,6 +261,10 @@ type VolumeDriverRegistry interface {
 
 	// Removes driver from registry. Does nothing if driver name does not exist.
 	Remove(name string)
+
+	// Wrap replaces the VolumeDriver created for the given name with the
+	// VolumeDriver returned by wrap for it.
+	Wrap(name string, wrap func(VolumeDriver) VolumeDriver) error
 }
 
 // NewVolumeDriverRegistry constructs a new VolumeDriverRegistry.
@@ -69,6 +69,20 @@ func (v *volumeDriverRegistry) Register(name string, params map[string]string) e
 	return nil
 }
 
+func (v *volumeDriverRegistry) Wrap(name string, wrap func(VolumeDriver) VolumeDriver) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if v.isShutdown {
+		return ErrAlreadyShutdown
+	}
+	volumeDriver, ok := v.nameToVolumeDriver[name]
+	if !ok {
+		return ErrDriverNotFound
+	}
+	v.nameToVolumeDriver[name] = wrap(volumeDriver)
+	return nil
+}
+
 func (v *volumeDriverRegistry) Shutdown() error {
 	v.lock.Lock()
 	if v.isShutdown {
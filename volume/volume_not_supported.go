@@ -43,6 +43,14 @@ func (s *snapshotNotSupported) Restore(volumeID, snapshotID string) error {
 	return ErrNotSupported
 }
 
+func (s *snapshotNotSupported) SnapDelete(snapID string, cascade bool) error {
+	return ErrNotSupported
+}
+
+func (s *snapshotNotSupported) Promote(cloneID string) error {
+	return ErrNotSupported
+}
+
 type ioNotSupported struct{}
 
 func (i *ioNotSupported) Read(volumeID string, buffer []byte, size uint64, offset int64) (int64, error) {